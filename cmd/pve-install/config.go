@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+)
+
+// configCmd groups configuration file subcommands.
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Work with configuration files",
+}
+
+// configValidateCmd validates every configuration file in a directory.
+var configValidateCmd = &cobra.Command{
+	Use:   "validate <dir>",
+	Short: "Validate all YAML configuration files in a directory",
+	Long: `Validate every *.yaml and *.yml file in a directory (non-recursive).
+
+Secrets (root password, SSH key) are not required, since saved configuration
+files never contain them. Exits with an error if any file is invalid.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		results, err := config.ValidateDir(args[0])
+		if err != nil {
+			return err
+		}
+
+		names := make([]string, 0, len(results))
+		for name := range results {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		out := cmd.OutOrStdout()
+		invalid := 0
+
+		for _, name := range names {
+			if err := results[name]; err != nil {
+				invalid++
+
+				fmt.Fprintf(out, "FAIL %s: %v\n", name, err) //nolint:errcheck // Writing to stdout
+
+				continue
+			}
+
+			fmt.Fprintf(out, "OK   %s\n", name) //nolint:errcheck // Writing to stdout
+		}
+
+		if invalid > 0 {
+			return fmt.Errorf("%d of %d config files are invalid", invalid, len(names))
+		}
+
+		return nil
+	},
+}
+
+// configDriftCmd compares the effective configuration against a baseline file.
+var configDriftCmd = &cobra.Command{
+	Use:   "drift <baseline.yaml>",
+	Short: "Compare the effective configuration against a baseline file",
+	Long: `Build the effective configuration (defaults, --config file, environment
+variables and --set overrides) and compare it field by field against a
+baseline file, such as the configuration committed to a Git repository.
+
+Every difference is printed as: field: "baseline" -> "effective". Secrets
+are redacted and do not count as drift, since saved files never contain
+them. Exits with an error if any other field differs.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		baseline, err := config.LoadFromFile(args[0])
+		if err != nil {
+			return err
+		}
+
+		// Normalize like the effective configuration, so that equivalent
+		// values (e.g., an email differing in case) do not count as drift.
+		if err := baseline.Normalize(); err != nil {
+			return err
+		}
+
+		effective, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		drifted := 0
+
+		for _, diff := range config.DiffConfig(baseline, effective) {
+			if diff.Secret {
+				fmt.Fprintf(out, "%s (secret, ignored)\n", diff) //nolint:errcheck // Writing to stdout
+
+				continue
+			}
+
+			drifted++
+
+			fmt.Fprintln(out, diff) //nolint:errcheck // Writing to stdout
+		}
+
+		if drifted > 0 {
+			return fmt.Errorf("configuration drifted from %s: %d fields differ", args[0], drifted)
+		}
+
+		fmt.Fprintln(out, "no drift") //nolint:errcheck // Writing to stdout
+
+		return nil
+	},
+}
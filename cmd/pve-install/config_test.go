@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+)
+
+// runRootCmd executes rootCmd with args and returns its output.
+func runRootCmd(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetErr(buf)
+	rootCmd.SetArgs(args)
+
+	t.Cleanup(func() {
+		rootCmd.SetArgs(nil)
+		rootCmd.SetErr(nil)
+	})
+
+	err := rootCmd.Execute()
+
+	return buf.String(), err
+}
+
+func TestConfigValidateCmdAllValid(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "node1.yaml"), []byte("system:\n  hostname: node1\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "node2.yml"), []byte("system:\n  hostname: node2\n"), 0o600))
+
+	output, err := runRootCmd(t, "config", "validate", dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, "OK   node1.yaml\nOK   node2.yml\n", output)
+}
+
+func TestConfigValidateCmdReportsInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "good.yaml"), []byte("system:\n  hostname: good\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.yaml"), []byte("system:\n  hostname: -bad\n"), 0o600))
+
+	output, err := runRootCmd(t, "config", "validate", dir)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 of 2 config files are invalid")
+	assert.Contains(t, output, "FAIL bad.yaml: hostname cannot start with a hyphen")
+	assert.Contains(t, output, "OK   good.yaml")
+}
+
+func TestConfigValidateCmdRequiresDir(t *testing.T) {
+	_, err := runRootCmd(t, "config", "validate")
+
+	assert.Error(t, err)
+}
+
+// writeBaseline saves cfg as a baseline file and returns its path.
+func writeBaseline(t *testing.T, cfg *config.Config) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "baseline.yaml")
+	require.NoError(t, cfg.SaveToFile(path))
+
+	return path
+}
+
+func TestConfigDriftCmdNoDrift(t *testing.T) {
+	baseline := writeBaseline(t, config.DefaultConfig())
+	t.Setenv("PVE_ROOT_PASSWORD", "env-only-secret")
+
+	output, err := runRootCmd(t, "config", "drift", baseline)
+
+	require.NoError(t, err)
+	assert.Equal(t, `system.root_password: "" -> "[REDACTED]" (secret, ignored)`+"\nno drift\n", output)
+	assert.NotContains(t, output, "env-only-secret")
+}
+
+func TestConfigDriftCmdEnvOverrideDrifts(t *testing.T) {
+	baseline := writeBaseline(t, config.DefaultConfig())
+	t.Setenv("PVE_HOSTNAME", "pve-drifted")
+	t.Setenv("BRIDGE_MODE", "external")
+
+	output, err := runRootCmd(t, "config", "drift", baseline)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "configuration drifted from "+baseline+": 2 fields differ")
+	assert.Contains(t, output, `system.hostname: "`+config.DefaultConfig().System.Hostname+`" -> "pve-drifted"`)
+	assert.Contains(t, output, `network.bridge_mode: "internal" -> "external"`)
+	assert.NotContains(t, output, "no drift")
+}
+
+func TestConfigDriftCmdWithConfigFile(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.System.Hostname = "pve-fsn1-01"
+	cfg.Storage.Disks = []string{"/dev/sda", "/dev/sdb"}
+	baseline := writeBaseline(t, cfg)
+
+	t.Cleanup(func() { cfgFile = "" })
+
+	output, err := runRootCmd(t, "config", "drift", baseline, "--config", baseline)
+
+	require.NoError(t, err)
+	assert.Equal(t, "no drift\n", output)
+}
+
+func TestConfigDriftCmdNormalizesBaseline(t *testing.T) {
+	dir := t.TempDir()
+	baseline := filepath.Join(dir, "baseline.yaml")
+	require.NoError(t, os.WriteFile(baseline, []byte("system:\n  email: Admin@Example.COM\n"), 0o600))
+
+	keyPath := filepath.Join(dir, "id_ed25519.pub")
+	require.NoError(t, os.WriteFile(keyPath, []byte("ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAI user@host\n"), 0o600))
+	t.Setenv("PVE_SSH_PUBLIC_KEY", keyPath)
+
+	t.Cleanup(func() { cfgFile = "" })
+
+	output, err := runRootCmd(t, "config", "drift", baseline, "--config", baseline)
+
+	require.NoError(t, err)
+	assert.NotContains(t, output, "system.email")
+	assert.Contains(t, output, `system.ssh_public_key: "" -> "[REDACTED]" (secret, ignored)`)
+	assert.Contains(t, output, "no drift")
+}
+
+func TestConfigDriftCmdMissingBaseline(t *testing.T) {
+	_, err := runRootCmd(t, "config", "drift", filepath.Join(t.TempDir(), "missing.yaml"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "config file not found")
+}
@@ -2,19 +2,34 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/installer"
 	"github.com/qoxi-cloud/proxmox-hetzner-go/pkg/version"
 )
 
 var (
-	cfgFile    string
-	saveConfig string
-	verbose    bool
+	cfgFile      string
+	saveConfig   string
+	verbose      bool
+	versionJSON  bool
+	setOverrides []string
+	progress     string
+	confirmWipe  string
+)
+
+// Values of the --progress flag.
+const (
+	progressText = "text"
+	progressJSON = "json"
 )
 
 // rootCmd is the base command when called without any subcommands.
@@ -29,10 +44,21 @@ It provides a guided installation experience with:
 - SSH hardening
 - Tailscale integration
 - ZFS optimization`,
-	Run: func(_ *cobra.Command, _ []string) {
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		// Reject an unknown --progress value before doing any work.
+		if _, err := newProgressObserver(progress, cmd.OutOrStdout(), 0); err != nil {
+			return err
+		}
+
+		if _, err := loadConfig(); err != nil {
+			return err
+		}
+
 		// TODO: Launch TUI here
 		fmt.Println("Starting Proxmox VE installer TUI...")
 		fmt.Println("TUI not implemented yet. Use 'pve-install --help' for available options.")
+
+		return nil
 	},
 }
 
@@ -40,9 +66,22 @@ It provides a guided installation experience with:
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print version information",
-	Run: func(cmd *cobra.Command, _ []string) {
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		if versionJSON {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+
+			if err := enc.Encode(version.Get()); err != nil {
+				return fmt.Errorf("failed to encode version info: %w", err)
+			}
+
+			return nil
+		}
+
 		//nolint:errcheck // Writing to stdout, error handling not needed
 		fmt.Fprintf(cmd.OutOrStdout(), "pve-install %s\n", version.Full())
+
+		return nil
 	},
 }
 
@@ -61,6 +100,12 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "config file (default: $HOME/.pve-install.yaml)")
 	rootCmd.PersistentFlags().StringVarP(&saveConfig, "save-config", "s", "", "save configuration to file after input")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose logging")
+	rootCmd.PersistentFlags().StringArrayVar(&setOverrides, "set", nil,
+		"override a config value as section.field=value (repeatable, e.g. --set network.bridge_mode=external)")
+	rootCmd.PersistentFlags().StringVar(&progress, "progress", progressText,
+		"progress output: text (log only) or json (one JSON object per step transition on stdout)")
+	rootCmd.PersistentFlags().StringVar(&confirmWipe, "confirm-wipe", "",
+		"acknowledge that all data on the selected disks is destroyed, by passing "+config.WipeConfirmationToken)
 
 	// Bind flags to viper (errors are intentionally ignored as these bindings cannot fail
 	// when the flags are properly defined above)
@@ -71,8 +116,61 @@ func init() {
 	//nolint:errcheck // BindPFlag only fails if flag doesn't exist
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 
+	// Version flags
+	versionCmd.Flags().BoolVar(&versionJSON, "json", false, "print version information as JSON")
+
 	// Add subcommands
-	rootCmd.AddCommand(versionCmd)
+	configCmd.AddCommand(configValidateCmd, configDriftCmd)
+	rootCmd.AddCommand(versionCmd, configCmd)
+}
+
+// loadConfig builds the installer configuration from defaults, the --config
+// file and environment variables (see config.BuildEffectiveConfig), then
+// applies --set overrides on top. The --confirm-wipe token is carried over
+// as Config.ConfirmWipe.
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.BuildEffectiveConfig(cfgFile, &config.Config{ConfirmWipe: confirmWipe})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := config.ApplyOverrides(cfg, setOverrides); err != nil {
+		return nil, fmt.Errorf("invalid --set: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// newRunner builds the Runner for the installation steps planned for cfg.
+// With --progress json, a ProgressEmitter writing to out (the command's
+// stdout) is registered as the Runner's Observer, so each step transition is
+// printed as a JSON line while the human-readable log goes to the Logger.
+func newRunner(cfg *config.Config, executor exec.Executor, logger *installer.Logger, out io.Writer) (*installer.Runner, error) {
+	steps := installer.PlanSteps(cfg, executor, logger)
+
+	observer, err := newProgressObserver(progress, out, len(steps))
+	if err != nil {
+		return nil, err
+	}
+
+	runner := installer.NewRunner(steps, logger)
+	runner.SetObserver(observer)
+
+	return runner, nil
+}
+
+// newProgressObserver returns the step Observer for the --progress format:
+// nil for text, where progress is only logged, or a ProgressEmitter writing
+// JSON lines to w for json.
+func newProgressObserver(format string, w io.Writer, total int) (installer.Observer, error) {
+	switch format {
+	case progressText:
+		return nil, nil //nolint:nilnil // text progress has no observer
+	case progressJSON:
+		return installer.NewProgressEmitter(w, total), nil
+	default:
+		return nil, fmt.Errorf("invalid --progress %q: must be %s or %s", format, progressText, progressJSON)
+	}
 }
 
 // initConfig reads in config file and ENV variables if set.
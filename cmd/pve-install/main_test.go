@@ -2,11 +2,18 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/installer"
 	"github.com/qoxi-cloud/proxmox-hetzner-go/pkg/version"
 )
 
@@ -49,6 +56,16 @@ func TestFlagsExist(t *testing.T) {
 	verboseFlag := rootCmd.PersistentFlags().Lookup("verbose")
 	require.NotNil(t, verboseFlag)
 	assert.Equal(t, "v", verboseFlag.Shorthand)
+
+	// Verify set flag exists and is repeatable
+	setFlag := rootCmd.PersistentFlags().Lookup("set")
+	require.NotNil(t, setFlag)
+	assert.Equal(t, "stringArray", setFlag.Value.Type())
+
+	// Verify progress flag exists and defaults to text
+	progressFlag := rootCmd.PersistentFlags().Lookup("progress")
+	require.NotNil(t, progressFlag)
+	assert.Equal(t, progressText, progressFlag.DefValue)
 }
 
 func TestRootCmdHelpOutput(t *testing.T) {
@@ -92,3 +109,156 @@ func TestVersionCmdOutput(t *testing.T) {
 	// Reset args for other tests
 	rootCmd.SetArgs(nil)
 }
+
+func TestVersionCmdJSONOutput(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetArgs([]string{"version", "--json"})
+
+	t.Cleanup(func() {
+		versionJSON = false
+
+		rootCmd.SetArgs(nil)
+	})
+
+	err := rootCmd.Execute()
+	require.NoError(t, err)
+
+	var info map[string]string
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &info))
+
+	assert.Equal(t, version.Version, info["version"])
+	assert.Equal(t, version.Commit, info["commit"])
+	assert.Equal(t, version.Date, info["date"])
+	assert.Equal(t, version.Full(), info["full"])
+	assert.NotEmpty(t, info["go_version"])
+}
+
+func TestVersionCmdPlainOutputUnchanged(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetArgs([]string{"version"})
+
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+
+	err := rootCmd.Execute()
+	require.NoError(t, err)
+
+	assert.Equal(t, "pve-install "+version.Full()+"\n", buf.String())
+}
+
+func TestLoadConfigAppliesSetOverrides(t *testing.T) {
+	t.Setenv("BRIDGE_MODE", "both")
+
+	setOverrides = []string{"network.bridge_mode=external", "storage.disks=/dev/sda,/dev/sdb"}
+
+	t.Cleanup(func() { setOverrides = nil })
+
+	cfg, err := loadConfig()
+	require.NoError(t, err)
+
+	// --set takes priority over environment variables.
+	assert.Equal(t, config.BridgeModeExternal, cfg.Network.BridgeMode)
+	assert.Equal(t, []string{"/dev/sda", "/dev/sdb"}, cfg.Storage.Disks)
+}
+
+func TestLoadConfigRejectsInvalidSet(t *testing.T) {
+	setOverrides = []string{"network.nope=1"}
+
+	t.Cleanup(func() { setOverrides = nil })
+
+	cfg, err := loadConfig()
+
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.ErrorIs(t, err, config.ErrOverrideUnknownKey)
+	assert.Contains(t, err.Error(), "invalid --set")
+}
+
+func TestLoadConfigConfirmWipe(t *testing.T) {
+	confirmWipe = config.WipeConfirmationToken
+
+	t.Cleanup(func() { confirmWipe = "" })
+
+	cfg, err := loadConfig()
+	require.NoError(t, err)
+
+	assert.Equal(t, config.WipeConfirmationToken, cfg.ConfirmWipe)
+}
+
+func TestNewProgressObserverText(t *testing.T) {
+	observer, err := newProgressObserver(progressText, &bytes.Buffer{}, 3)
+
+	require.NoError(t, err)
+	assert.Nil(t, observer)
+}
+
+func TestNewProgressObserverInvalid(t *testing.T) {
+	_, err := newProgressObserver("xml", &bytes.Buffer{}, 3)
+
+	assert.ErrorContains(t, err, `invalid --progress "xml"`)
+}
+
+func TestNewRunnerProgressJSON(t *testing.T) {
+	orig := progress
+	progress = progressJSON
+
+	t.Cleanup(func() { progress = orig })
+
+	// Three steps: the locale succeeds, unattended upgrades fail and the
+	// reboot never starts.
+	cfg := config.DefaultConfig()
+	cfg.System.Locale = "C.UTF-8"
+	cfg.System.EnableUnattendedUpgrades = true
+	cfg.System.RebootAfterInstall = true
+
+	mock := exec.NewMockExecutor()
+	mock.SetError("apt-get install -y unattended-upgrades", errors.New("exit status 100"))
+
+	var out bytes.Buffer
+
+	runner, err := newRunner(cfg, mock, nil, &out)
+	require.NoError(t, err)
+
+	_, err = runner.Run(context.Background())
+	require.Error(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 4)
+
+	events := make([]installer.ProgressEvent, len(lines))
+	for i, line := range lines {
+		require.NoError(t, json.Unmarshal([]byte(line), &events[i]), "line %d: %s", i+1, line)
+	}
+
+	locale, upgrades := "Configure keyboard and locale", "Configure unattended upgrades"
+
+	assert.Equal(t, []installer.ProgressEvent{
+		{Event: installer.ProgressEventStepStart, Step: locale, Index: 1, Total: 3, Status: installer.ProgressStatusRunning},
+		{Event: installer.ProgressEventStepEnd, Step: locale, Index: 1, Total: 3, Status: installer.ProgressStatusSucceeded},
+		{Event: installer.ProgressEventStepStart, Step: upgrades, Index: 2, Total: 3, Status: installer.ProgressStatusRunning},
+		{
+			Event:  installer.ProgressEventStepEnd,
+			Step:   upgrades,
+			Index:  2,
+			Total:  3,
+			Status: installer.ProgressStatusFailed,
+			Error:  events[3].Error,
+		},
+	}, events)
+	assert.Contains(t, events[3].Error, "exit status 100")
+}
+
+func TestNewRunnerProgressText(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.System.Locale = "C.UTF-8"
+
+	var out bytes.Buffer
+
+	runner, err := newRunner(cfg, exec.NewMockExecutor(), nil, &out)
+	require.NoError(t, err)
+
+	_, err = runner.Run(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, out.String(), "text progress writes nothing to stdout")
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// maxAdminUserLength is the longest user name accepted by useradd(8).
+const maxAdminUserLength = 32
+
+// Admin user validation errors.
+var (
+	// ErrAdminUserTooLong is returned when AdminUser exceeds 32 characters.
+	ErrAdminUserTooLong = errors.New("admin user cannot exceed 32 characters")
+	// ErrAdminUserInvalid is returned when AdminUser is not a valid Linux user name.
+	ErrAdminUserInvalid = errors.New("admin user must start with a lowercase letter or underscore " +
+		"and contain only lowercase letters, digits, underscores and hyphens")
+	// ErrAdminUserReserved is returned when AdminUser names root, which always exists.
+	ErrAdminUserReserved = errors.New("admin user cannot be root")
+	// ErrAdminSSHKeysWithoutUser is returned when AdminSSHKeys are set without an AdminUser.
+	ErrAdminSSHKeysWithoutUser = errors.New("admin SSH keys require an admin user")
+)
+
+// adminUserRegex matches the portable Linux user names accepted by the
+// default NAME_REGEX of adduser(8).
+var adminUserRegex = regexp.MustCompile(`^[a-z_][a-z0-9_-]*$`)
+
+// ValidateAdminUser checks that user is a valid Linux user name other than
+// root. Empty is valid and means no admin user is created.
+func ValidateAdminUser(user string) error {
+	switch {
+	case user == "":
+		return nil
+	case len(user) > maxAdminUserLength:
+		return ErrAdminUserTooLong
+	case !adminUserRegex.MatchString(user):
+		return ErrAdminUserInvalid
+	case user == "root":
+		return ErrAdminUserReserved
+	}
+
+	return nil
+}
+
+// validateAdminSSHKeys validates each AdminSSHKeys entry with ValidateSSHKey,
+// reporting all invalid entries in one joined error. Keys without an
+// AdminUser are rejected, since there is nobody to install them for.
+func (s *SystemConfig) validateAdminSSHKeys() error {
+	if len(s.AdminSSHKeys) == 0 {
+		return nil
+	}
+
+	if s.AdminUser == "" {
+		return ErrAdminSSHKeysWithoutUser
+	}
+
+	var errs []error
+
+	for i, key := range s.AdminSSHKeys {
+		if err := ValidateSSHKey(key); err != nil {
+			errs = append(errs, fmt.Errorf("key %d: %w", i+1, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidateAdminUser(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     string
+		expected error
+	}{
+		{"empty", "", nil},
+		{"simple", "admin", nil},
+		{"digits hyphen underscore", "ops_admin-2", nil},
+		{"leading underscore", "_deploy", nil},
+		{"max length", strings.Repeat("a", 32), nil},
+		{"too long", strings.Repeat("a", 33), ErrAdminUserTooLong},
+		{"uppercase", "Admin", ErrAdminUserInvalid},
+		{"leading digit", "1admin", ErrAdminUserInvalid},
+		{"leading hyphen", "-admin", ErrAdminUserInvalid},
+		{"space", "ad min", ErrAdminUserInvalid},
+		{"dot", "ad.min", ErrAdminUserInvalid},
+		{"at sign", "admin@host", ErrAdminUserInvalid},
+		{"root", "root", ErrAdminUserReserved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ValidateAdminUser(tt.user))
+		})
+	}
+}
+
+func TestConfigValidateAdminSSHKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    string
+		keys    []string
+		wantErr error
+	}{
+		{"no user no keys", "", nil, nil},
+		{"user without keys", "admin", nil, nil},
+		{"valid keys", "admin", []string{testValidSSHKey, "ssh-rsa AAAAB3NzaC1yc2E ops@laptop"}, nil},
+		{"invalid key", "admin", []string{testValidSSHKey, "not-a-key"}, ErrSSHKeyInvalidPrefix},
+		{"empty key", "admin", []string{""}, ErrSSHKeyEmpty},
+		{"keys without user", "", []string{testValidSSHKey}, ErrAdminSSHKeysWithoutUser},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.System.RootPassword = testValidPassword
+			cfg.System.SSHPublicKey = testValidSSHKey
+			cfg.System.AdminUser = tt.user
+			cfg.System.AdminSSHKeys = tt.keys
+
+			fieldErrs := cfg.FieldErrors()
+
+			if tt.wantErr == nil {
+				assert.Empty(t, fieldErrs)
+
+				return
+			}
+
+			require.Len(t, fieldErrs, 1)
+			assert.Equal(t, "system.admin_ssh_keys", fieldErrs[0].Field)
+			assert.ErrorIs(t, fieldErrs[0].Err, tt.wantErr)
+		})
+	}
+}
+
+func TestConfigValidateAdminUser(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+	cfg.System.AdminUser = "Admin"
+
+	fieldErrs := cfg.FieldErrors()
+
+	require.Len(t, fieldErrs, 1)
+	assert.Equal(t, "system.admin_user", fieldErrs[0].Field)
+	assert.ErrorIs(t, fieldErrs[0].Err, ErrAdminUserInvalid)
+}
+
+func TestSystemConfigAdminUserRoundTrip(t *testing.T) {
+	original := SystemConfig{
+		Hostname:     testDefaultHostname,
+		SSHPublicKey: testValidSSHKey,
+		AdminUser:    "admin",
+		AdminSSHKeys: []string{testValidSSHKey},
+	}
+
+	data, err := yaml.Marshal(&original)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "admin_user: admin")
+	assert.Contains(t, string(data), "admin_ssh_keys:")
+
+	var restored SystemConfig
+	require.NoError(t, yaml.Unmarshal(data, &restored))
+	assert.Equal(t, original.AdminUser, restored.AdminUser)
+	assert.Equal(t, original.AdminSSHKeys, restored.AdminSSHKeys, "public keys are kept in files")
+	assert.Empty(t, restored.SSHPublicKey, "root's key remains excluded")
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// ErrAnswerFileNoDisks indicates that no disks are configured. The answer
+// file must name the target disks, so auto-detection cannot be used.
+var ErrAnswerFileNoDisks = errors.New("answer file requires at least one disk in storage.disks")
+
+// answerFileCountry is the country used for the answer file's mirror
+// selection. Hetzner dedicated servers are hosted in Germany and Finland,
+// and the German mirrors are close to both.
+const answerFileCountry = "de"
+
+// answerFileKeyboard is the answer file keyboard used when System.Keyboard is empty.
+const answerFileKeyboard = "en-us"
+
+// answerFile is the Proxmox VE automated installation answer file. Keys use
+// the kebab-case spelling introduced in Proxmox VE 8.4.
+type answerFile struct {
+	Global    answerGlobal    `toml:"global"`
+	Network   answerNetwork   `toml:"network"`
+	DiskSetup answerDiskSetup `toml:"disk-setup"`
+}
+
+// answerGlobal is the [global] section of the answer file.
+type answerGlobal struct {
+	Keyboard     string   `toml:"keyboard"`
+	Country      string   `toml:"country"`
+	FQDN         string   `toml:"fqdn"`
+	Mailto       string   `toml:"mailto"`
+	Timezone     string   `toml:"timezone"`
+	RootPassword string   `toml:"root-password"`
+	RootSSHKeys  []string `toml:"root-ssh-keys,omitempty"`
+}
+
+// answerNetwork is the [network] section of the answer file.
+type answerNetwork struct {
+	Source string `toml:"source"`
+}
+
+// answerDiskSetup is the [disk-setup] section of the answer file.
+type answerDiskSetup struct {
+	Filesystem string    `toml:"filesystem"`
+	ZFS        answerZFS `toml:"zfs"`
+	DiskList   []string  `toml:"disk-list"`
+}
+
+// answerZFS holds the ZFS options of the [disk-setup] section.
+type answerZFS struct {
+	Raid string `toml:"raid"`
+}
+
+// ToProxmoxAnswerFile renders the configuration as a TOML answer file for
+// the Proxmox VE automated installer (proxmox-auto-install-assistant).
+//
+// The configuration must pass Validate and list its disks explicitly;
+// otherwise the validation error or ErrAnswerFileNoDisks is returned. An SSH
+// public key given as a file path is read first (see ResolveSSHPublicKey).
+// The installer takes its network settings from DHCP, which Hetzner provides
+// for the main IPv4 address; the bridge setup is applied after installation.
+//
+// Security: the answer file contains the root password in plain text, as the
+// installer needs it. Write it with mode 0600, serve it only over HTTPS or on
+// private install media, and delete it once the installation is done.
+func (c *Config) ToProxmoxAnswerFile() ([]byte, error) {
+	if c == nil {
+		return nil, errors.New("config is nil")
+	}
+
+	// Resolve a key file path first, as Validate only accepts the key itself.
+	sshKey, err := ResolveSSHPublicKey(c.System.SSHPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := *c
+	resolved.System.SSHPublicKey = sshKey
+
+	if err := resolved.Validate(); err != nil {
+		return nil, err
+	}
+
+	if len(c.Storage.Disks) == 0 {
+		return nil, ErrAnswerFileNoDisks
+	}
+
+	keyboard := c.System.Keyboard
+	if keyboard == "" {
+		keyboard = answerFileKeyboard
+	}
+
+	answer := answerFile{
+		Global: answerGlobal{
+			Keyboard:     keyboard,
+			Country:      answerFileCountry,
+			FQDN:         c.FQDN(),
+			Mailto:       c.System.Email,
+			Timezone:     c.System.Timezone,
+			RootPassword: c.System.RootPassword,
+			RootSSHKeys:  []string{sshKey},
+		},
+		Network: answerNetwork{Source: "from-dhcp"},
+		DiskSetup: answerDiskSetup{
+			Filesystem: "zfs",
+			ZFS:        answerZFS{Raid: answerFileRaid(c.Storage.ZFSRaid)},
+			DiskList:   answerFileDisks(c.Storage.Disks),
+		},
+	}
+
+	data, err := toml.Marshal(answer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal answer file: %w", err)
+	}
+
+	return data, nil
+}
+
+// answerFileRaid maps a ZFS RAID level to the answer file's zfs.raid value.
+// The installer has no "single" level; a single disk is a one-disk raid0.
+func answerFileRaid(raid ZFSRaid) string {
+	if raid == ZFSRaidSingle {
+		return string(ZFSRaid0)
+	}
+
+	return string(raid)
+}
+
+// answerFileDisks returns the disk names without the /dev/ prefix, as the
+// answer file's disk-list expects (e.g., "/dev/nvme0n1" becomes "nvme0n1").
+func answerFileDisks(disks []string) []string {
+	names := make([]string, len(disks))
+	for i, disk := range disks {
+		names[i] = strings.TrimPrefix(disk, "/dev/")
+	}
+
+	return names
+}
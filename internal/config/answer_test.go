@@ -0,0 +1,138 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseAnswerFile decodes a generated answer file into generic sections.
+func parseAnswerFile(t *testing.T, data []byte) map[string]any {
+	t.Helper()
+
+	var answer map[string]any
+	require.NoError(t, toml.Unmarshal(data, &answer))
+
+	return answer
+}
+
+func TestConfigToProxmoxAnswerFile(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.System.Hostname = "pve1"
+	cfg.System.DomainSuffix = "example.com"
+	cfg.System.Email = "admin@example.com"
+	cfg.Storage.Disks = []string{testDeviceSDA, "/dev/nvme0n1"}
+
+	data, err := cfg.ToProxmoxAnswerFile()
+	require.NoError(t, err)
+
+	answer := parseAnswerFile(t, data)
+	require.Contains(t, answer, "global")
+	require.Contains(t, answer, "network")
+	require.Contains(t, answer, "disk-setup")
+
+	assert.Equal(t, map[string]any{
+		"keyboard":      "en-us",
+		"country":       "de",
+		"fqdn":          "pve1.example.com",
+		"mailto":        "admin@example.com",
+		"timezone":      "Europe/Kyiv",
+		"root-password": testValidPassword,
+		"root-ssh-keys": []any{testValidSSHKey},
+	}, answer["global"])
+	assert.Equal(t, map[string]any{"source": "from-dhcp"}, answer["network"])
+
+	diskSetup, ok := answer["disk-setup"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "zfs", diskSetup["filesystem"])
+	assert.Equal(t, []any{"sda", "nvme0n1"}, diskSetup["disk-list"])
+}
+
+func TestConfigToProxmoxAnswerFileRaidLevels(t *testing.T) {
+	tests := []struct {
+		raid     ZFSRaid
+		disks    []string
+		wantRaid string
+	}{
+		{ZFSRaidSingle, []string{testDeviceSDA}, "raid0"},
+		{ZFSRaid0, []string{testDeviceSDA, testDeviceSDB}, "raid0"},
+		{ZFSRaid1, []string{testDeviceSDA, testDeviceSDB}, "raid1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.raid), func(t *testing.T) {
+			cfg := newValidTestConfig()
+			cfg.Storage.ZFSRaid = tt.raid
+			cfg.Storage.Disks = tt.disks
+
+			data, err := cfg.ToProxmoxAnswerFile()
+			require.NoError(t, err)
+
+			diskSetup, ok := parseAnswerFile(t, data)["disk-setup"].(map[string]any)
+			require.True(t, ok)
+			assert.Equal(t, map[string]any{"raid": tt.wantRaid}, diskSetup["zfs"])
+			assert.Len(t, diskSetup["disk-list"], len(tt.disks))
+		})
+	}
+}
+
+func TestConfigToProxmoxAnswerFileCustomKeyboard(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.System.Keyboard = "de"
+
+	data, err := cfg.ToProxmoxAnswerFile()
+	require.NoError(t, err)
+
+	global, ok := parseAnswerFile(t, data)["global"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "de", global["keyboard"])
+}
+
+func TestConfigToProxmoxAnswerFileRequiresDisks(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.Storage.Disks = nil
+
+	_, err := cfg.ToProxmoxAnswerFile()
+
+	assert.ErrorIs(t, err, ErrAnswerFileNoDisks)
+}
+
+func TestConfigToProxmoxAnswerFileRequiresSecrets(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.System.RootPassword = ""
+
+	_, err := cfg.ToProxmoxAnswerFile()
+
+	var validationErr *ValidationError
+
+	require.ErrorAs(t, err, &validationErr)
+	assert.ErrorIs(t, err, ErrPasswordEmpty)
+}
+
+func TestConfigToProxmoxAnswerFileSSHKeyPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "id_ed25519.pub")
+	require.NoError(t, os.WriteFile(path, []byte(testValidSSHKey+"\n"), 0o600))
+
+	cfg := newValidTestConfig()
+	cfg.System.SSHPublicKey = path
+
+	data, err := cfg.ToProxmoxAnswerFile()
+	require.NoError(t, err)
+
+	global, ok := parseAnswerFile(t, data)["global"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, []any{testValidSSHKey}, global["root-ssh-keys"])
+	assert.Equal(t, path, cfg.System.SSHPublicKey, "the config is not modified")
+}
+
+func TestConfigToProxmoxAnswerFileNil(t *testing.T) {
+	var cfg *Config
+
+	_, err := cfg.ToProxmoxAnswerFile()
+
+	assert.Error(t, err)
+}
@@ -2,8 +2,18 @@
 // Proxmox VE installer on Hetzner dedicated servers.
 package config
 
+import (
+	"reflect"
+	"slices"
+)
+
 // SystemConfig holds system-level configuration settings for the server.
 // It can be loaded from YAML files or environment variables.
+//
+// Fields tagged secret:"true" (here and in the other sections) hold
+// sensitive values; they are also tagged yaml:"-" and never written to files.
+// Their secret_name tag is the name used in place of the YAML name where a
+// secret is named, such as in DiffConfig and the YAML piped with secrets.
 type SystemConfig struct {
 	// Hostname is the server hostname (RFC 1123 compliant).
 	Hostname string `yaml:"hostname" env:"PVE_HOSTNAME"`
@@ -18,10 +28,49 @@ type SystemConfig struct {
 	Email string `yaml:"email" env:"PVE_EMAIL"`
 
 	// RootPassword is the root password (excluded from file serialization).
-	RootPassword string `yaml:"-" env:"PVE_ROOT_PASSWORD"`
+	RootPassword string `yaml:"-" env:"PVE_ROOT_PASSWORD" secret:"true" secret_name:"root_password"`
 
 	// SSHPublicKey is the SSH public key for authentication (excluded from file serialization).
-	SSHPublicKey string `yaml:"-" env:"PVE_SSH_PUBLIC_KEY"`
+	SSHPublicKey string `yaml:"-" env:"PVE_SSH_PUBLIC_KEY" secret:"true" secret_name:"ssh_public_key"`
+
+	// RebootAfterInstall reboots into the installed system when installation
+	// completes. Useful for unattended installs; off by default.
+	RebootAfterInstall bool `yaml:"reboot_after_install" env:"PVE_REBOOT_AFTER_INSTALL"`
+
+	// EnableUnattendedUpgrades installs and enables unattended-upgrades so the
+	// installed system applies security updates automatically; off by default.
+	EnableUnattendedUpgrades bool `yaml:"unattended_upgrades" env:"PVE_UNATTENDED_UPGRADES"`
+
+	// NTPServers is the list of NTP servers the installed system synchronizes
+	// time with (e.g., "ntp1.hetzner.de"). Empty keeps the time daemon's defaults.
+	NTPServers []string `yaml:"ntp_servers" env:"NTP_SERVERS" envSeparator:","`
+
+	// Keyboard is the console keyboard layout as named by Proxmox VE
+	// (e.g., "en-us", "de-ch"). Empty leaves the layout unchanged.
+	Keyboard string `yaml:"keyboard" env:"PVE_KEYBOARD"`
+
+	// Locale is the system locale (e.g., "en_US.UTF-8"). Empty leaves the locale unchanged.
+	Locale string `yaml:"locale" env:"PVE_LOCALE"`
+
+	// SSHDisablePasswordAuth turns off SSH password authentication, leaving
+	// public keys as the only login method; off by default.
+	SSHDisablePasswordAuth bool `yaml:"ssh_disable_password_auth" env:"PVE_SSH_DISABLE_PASSWORD_AUTH"`
+
+	// SSHPermitRootLogin is the sshd PermitRootLogin value (yes, no,
+	// prohibit-password). Empty derives it from SSHPublicKey, see PermitRootLogin.
+	SSHPermitRootLogin string `yaml:"ssh_permit_root_login" env:"PVE_SSH_PERMIT_ROOT_LOGIN"`
+
+	// ProxmoxVersion pins the Proxmox VE release to install (e.g., "8.2" or
+	// "8.2-1"), for reproducible builds. Empty installs the latest release.
+	ProxmoxVersion string `yaml:"proxmox_version" env:"PVE_PROXMOX_VERSION"`
+
+	// AdminUser is a sudo-capable user created alongside root, so operators
+	// do not have to log in as root. Empty creates no user.
+	AdminUser string `yaml:"admin_user" env:"PVE_ADMIN_USER"`
+
+	// AdminSSHKeys are the SSH public keys installed for AdminUser. Public
+	// keys are not secret, so unlike SSHPublicKey they are kept in files.
+	AdminSSHKeys []string `yaml:"admin_ssh_keys" env:"PVE_ADMIN_SSH_KEYS" envSeparator:","`
 }
 
 // NetworkConfig holds network configuration options.
@@ -34,6 +83,18 @@ type NetworkConfig struct {
 
 	// PrivateSubnet is the NAT network subnet (e.g., "10.0.0.0/24").
 	PrivateSubnet string `yaml:"private_subnet" env:"PRIVATE_SUBNET"`
+
+	// AdditionalSubnet is an optional purchased Hetzner subnet routed to guests
+	// through a dedicated bridge (e.g., "203.0.113.8/29"). Empty means none.
+	AdditionalSubnet string `yaml:"additional_subnet" env:"ADDITIONAL_SUBNET"`
+
+	// BridgeMAC is an optional MAC address for the bridge carrying the public IP
+	// (e.g., "90:1b:0e:aa:bb:cc"). Empty means the kernel picks one automatically.
+	BridgeMAC string `yaml:"bridge_mac" env:"BRIDGE_MAC"`
+
+	// NetworkBackend selects the network configuration format (ifupdown2, networkd).
+	// Empty is treated as ifupdown2.
+	NetworkBackend NetworkBackend `yaml:"network_backend" env:"NETWORK_BACKEND"`
 }
 
 // StorageConfig holds storage and disk configuration.
@@ -43,6 +104,14 @@ type StorageConfig struct {
 
 	// Disks is the list of disk devices to use (e.g., "/dev/sda", "/dev/sdb").
 	Disks []string `yaml:"disks" env:"DISKS" envSeparator:","`
+
+	// SwapSizeMB is the size of the swap zvol in megabytes (0 disables swap).
+	SwapSizeMB int `yaml:"swap_size_mb" env:"SWAP_SIZE_MB"`
+
+	// PreserveESP keeps the existing EFI System Partition when partitioning,
+	// replacing only the ZFS partition, for dual-boot or re-install setups.
+	// Only meaningful on UEFI systems; off by default.
+	PreserveESP bool `yaml:"preserve_esp" env:"PRESERVE_ESP"`
 }
 
 // TailscaleConfig holds Tailscale VPN configuration settings.
@@ -51,7 +120,7 @@ type TailscaleConfig struct {
 	Enabled bool `yaml:"enabled" env:"INSTALL_TAILSCALE"`
 
 	// AuthKey is the Tailscale authentication key (excluded from file serialization).
-	AuthKey string `yaml:"-" env:"TAILSCALE_AUTH_KEY"`
+	AuthKey string `yaml:"-" env:"TAILSCALE_AUTH_KEY" secret:"true" secret_name:"auth_key"`
 
 	// SSH enables SSH advertisement on the Tailscale network.
 	SSH bool `yaml:"ssh" env:"TAILSCALE_SSH"`
@@ -60,6 +129,42 @@ type TailscaleConfig struct {
 	WebUI bool `yaml:"webui" env:"TAILSCALE_WEBUI"`
 }
 
+// ClusterConfig holds settings for joining an existing Proxmox VE cluster.
+// Leaving JoinAddress and Fingerprint empty installs a standalone node.
+type ClusterConfig struct {
+	// JoinAddress is the address of an existing cluster node, as an IP address
+	// or hostname with an optional port (e.g., "10.0.0.2" or "pve1.example.com:8006").
+	JoinAddress string `yaml:"join_address" env:"CLUSTER_JOIN_ADDRESS"`
+
+	// Fingerprint is the SHA-256 fingerprint of the cluster node's API certificate,
+	// as colon-separated hex bytes (see "pvenode cert info").
+	Fingerprint string `yaml:"fingerprint" env:"CLUSTER_FINGERPRINT"`
+
+	// Password is the root password of the cluster node (excluded from file serialization).
+	Password string `yaml:"-" env:"CLUSTER_PASSWORD" secret:"true" secret_name:"password"`
+}
+
+// JoinRequested reports whether any cluster join parameter other than the
+// password is set. Cluster settings are only validated and applied when it is true.
+func (c ClusterConfig) JoinRequested() bool {
+	return c.JoinAddress != "" || c.Fingerprint != ""
+}
+
+// ACMEConfig holds settings for obtaining a trusted certificate for the
+// Proxmox VE web interface from Let's Encrypt via ACME.
+type ACMEConfig struct {
+	// Enabled registers an ACME account and orders a certificate for the FQDN.
+	// The FQDN must resolve to the server's public IP.
+	Enabled bool `yaml:"enabled" env:"ACME_ENABLED"`
+
+	// Email is the ACME account contact address. Empty means System.Email.
+	Email string `yaml:"email" env:"ACME_EMAIL"`
+
+	// Staging uses the Let's Encrypt staging directory, whose certificates are
+	// not trusted but which has far higher rate limits; useful for testing.
+	Staging bool `yaml:"staging" env:"ACME_STAGING"`
+}
+
 // Config holds all installation configuration.
 // It can be loaded from YAML files or environment variables.
 type Config struct {
@@ -75,8 +180,18 @@ type Config struct {
 	// Tailscale contains Tailscale VPN configuration.
 	Tailscale TailscaleConfig `yaml:"tailscale"`
 
+	// Cluster contains optional settings for joining an existing cluster.
+	Cluster ClusterConfig `yaml:"cluster"`
+
+	// ACME contains optional settings for a Let's Encrypt certificate.
+	ACME ACMEConfig `yaml:"acme"`
+
 	// Verbose enables verbose logging (runtime only, not saved).
 	Verbose bool `yaml:"-"`
+
+	// ConfirmWipe must equal WipeConfirmationToken before the disks are
+	// wiped, as a safety interlock set by the CLI or TUI (runtime only, not saved).
+	ConfirmWipe string `yaml:"-"`
 }
 
 // Default configuration values per PRD specification.
@@ -86,6 +201,10 @@ const (
 	defaultPrivateSubnet = "10.0.0.0/24" // NOSONAR(go:S1313) RFC 1918 private range - default config value
 )
 
+// defaultNTPServers are Hetzner's public NTP servers, reachable from every
+// Hetzner data center and spread across three domains for resilience.
+var defaultNTPServers = []string{"ntp1.hetzner.de", "ntp2.hetzner.com", "ntp3.hetzner.net"}
+
 // FQDN returns the fully qualified domain name (hostname.domain_suffix).
 // If DomainSuffix is empty, returns only the hostname.
 func (c *Config) FQDN() string {
@@ -96,23 +215,131 @@ func (c *Config) FQDN() string {
 	return c.System.Hostname + "." + c.System.DomainSuffix
 }
 
+// DefaultFields returns the canonical "section.field" names (using the YAML
+// field names, e.g. "system.hostname") of the fields whose value equals the
+// DefaultConfig value, in declaration order. A TUI can use it to tell
+// defaults apart from customized values.
+//
+// Slices are compared element by element, so a nil and an empty disk list
+// are equal. Fields not saved to files (secrets and Verbose) are not reported.
+// A nil Config reports no fields.
+func (c *Config) DefaultFields() []string {
+	if c == nil {
+		return nil
+	}
+
+	current := reflect.ValueOf(c).Elem()
+	defaults := reflect.ValueOf(DefaultConfig()).Elem()
+
+	var names []string
+
+	for i := range current.NumField() {
+		section := current.Type().Field(i)
+		if section.Type.Kind() != reflect.Struct {
+			continue
+		}
+
+		for j := range section.Type.NumField() {
+			field := section.Type.Field(j)
+
+			tag := field.Tag.Get("yaml")
+			if tag == "-" {
+				continue
+			}
+
+			if fieldValuesEqual(current.Field(i).Field(j), defaults.Field(i).Field(j)) {
+				names = append(names, section.Tag.Get("yaml")+"."+tag)
+			}
+		}
+	}
+
+	return names
+}
+
+// fieldValuesEqual reports whether two configuration field values are equal,
+// treating nil and empty slices as equal.
+func fieldValuesEqual(a, b reflect.Value) bool {
+	if a.Kind() != reflect.Slice {
+		return a.Equal(b)
+	}
+
+	if a.Len() != b.Len() {
+		return false
+	}
+
+	for i := range a.Len() {
+		if !a.Index(i).Equal(b.Index(i)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ACMEEmail returns the ACME account email: ACME.Email if set, otherwise System.Email.
+func (c *Config) ACMEEmail() string {
+	if c.ACME.Email != "" {
+		return c.ACME.Email
+	}
+
+	return c.System.Email
+}
+
+// AddDisk validates path and appends it to the disk list.
+// Returns ErrDiskPathInvalid if path is not a /dev/ device path, or
+// ErrDiskDuplicate if it is already in the list; the list is unchanged on error.
+func (s *StorageConfig) AddDisk(path string) error {
+	if err := ValidateDiskPath(path); err != nil {
+		return err
+	}
+
+	if slices.Contains(s.Disks, path) {
+		return ErrDiskDuplicate
+	}
+
+	s.Disks = append(s.Disks, path)
+
+	return nil
+}
+
+// RemoveDisk removes path from the disk list, preserving the order of the
+// remaining disks. Returns false if path was not in the list.
+func (s *StorageConfig) RemoveDisk(path string) bool {
+	i := slices.Index(s.Disks, path)
+	if i < 0 {
+		return false
+	}
+
+	s.Disks = slices.Delete(s.Disks, i, i+1)
+
+	return true
+}
+
 // DefaultConfig returns a Config with sensible default values.
 // Each call returns a new Config instance to avoid shared state.
 func DefaultConfig() *Config {
 	return &Config{
 		System: SystemConfig{
-			Hostname:     "pve-qoxi-cloud",
-			DomainSuffix: "local",
-			Timezone:     "Europe/Kyiv",
-			Email:        "admin@qoxi.cloud",
+			Hostname:                 "pve-qoxi-cloud",
+			DomainSuffix:             "local",
+			Timezone:                 "Europe/Kyiv",
+			Email:                    "admin@qoxi.cloud",
+			RebootAfterInstall:       false,
+			EnableUnattendedUpgrades: false,
+			NTPServers:               slices.Clone(defaultNTPServers),
+			Keyboard:                 "en-us",
+			Locale:                   "en_US.UTF-8",
 		},
 		Network: NetworkConfig{
-			BridgeMode:    BridgeModeInternal,
-			PrivateSubnet: defaultPrivateSubnet,
+			BridgeMode:     BridgeModeInternal,
+			PrivateSubnet:  defaultPrivateSubnet,
+			NetworkBackend: NetworkBackendIfupdown2,
 		},
 		Storage: StorageConfig{
-			ZFSRaid: ZFSRaid1,
-			Disks:   []string{},
+			ZFSRaid:     ZFSRaid1,
+			Disks:       []string{},
+			SwapSizeMB:  0,
+			PreserveESP: false,
 		},
 		Tailscale: TailscaleConfig{
 			Enabled: false,
@@ -2,6 +2,8 @@ package config
 
 import (
 	"reflect"
+	"slices"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -18,6 +20,20 @@ const (
 	testSubnetClassC2 = "192.168.1.0/24" // NOSONAR(go:S1313) Class C private range - test data
 )
 
+// testAdditionalSubnet is an additional public /29 subnet (RFC 5737 documentation range).
+const testAdditionalSubnet = "203.0.113.8/29" // NOSONAR(go:S1313) documentation range - test data
+
+// testBridgeMAC is a MAC address for the public bridge.
+const testBridgeMAC = "90:1b:0e:aa:bb:cc"
+
+// Cluster join test values.
+const (
+	testClusterJoinAddress = "10.0.0.2:8006"       // NOSONAR(go:S1313) private range - test data
+	testClusterPassword    = "cluster-root-secret" // NOSONAR(go:S2068) test data
+	testClusterFingerprint = "AB:CD:EF:01:23:45:67:89:AB:CD:EF:01:23:45:67:89:" +
+		"AB:CD:EF:01:23:45:67:89:AB:CD:EF:01:23:45:67:89"
+)
+
 // Test constants for commonly used test values.
 // These constants avoid duplication and satisfy SonarCloud code smell checks.
 const (
@@ -198,12 +214,22 @@ func TestSystemConfigRoundTripMarshalUnmarshal(t *testing.T) {
 
 func TestSystemConfigEnvironmentVariableTagsPresent(t *testing.T) {
 	expectedEnvTags := map[string]string{
-		"Hostname":     "PVE_HOSTNAME",
-		"DomainSuffix": "PVE_DOMAIN_SUFFIX",
-		"Timezone":     "PVE_TIMEZONE",
-		"Email":        "PVE_EMAIL",
-		"RootPassword": "PVE_ROOT_PASSWORD",
-		"SSHPublicKey": "PVE_SSH_PUBLIC_KEY",
+		"Hostname":                 "PVE_HOSTNAME",
+		"DomainSuffix":             "PVE_DOMAIN_SUFFIX",
+		"Timezone":                 "PVE_TIMEZONE",
+		"Email":                    "PVE_EMAIL",
+		"RootPassword":             "PVE_ROOT_PASSWORD",
+		"SSHPublicKey":             "PVE_SSH_PUBLIC_KEY",
+		"RebootAfterInstall":       "PVE_REBOOT_AFTER_INSTALL",
+		"EnableUnattendedUpgrades": "PVE_UNATTENDED_UPGRADES",
+		"NTPServers":               "NTP_SERVERS",
+		"Keyboard":                 "PVE_KEYBOARD",
+		"Locale":                   "PVE_LOCALE",
+		"SSHDisablePasswordAuth":   "PVE_SSH_DISABLE_PASSWORD_AUTH",
+		"SSHPermitRootLogin":       "PVE_SSH_PERMIT_ROOT_LOGIN",
+		"ProxmoxVersion":           "PVE_PROXMOX_VERSION",
+		"AdminUser":                "PVE_ADMIN_USER",
+		"AdminSSHKeys":             "PVE_ADMIN_SSH_KEYS",
 	}
 
 	cfgType := reflect.TypeOf(SystemConfig{})
@@ -219,12 +245,22 @@ func TestSystemConfigEnvironmentVariableTagsPresent(t *testing.T) {
 
 func TestSystemConfigYAMLTagsPresent(t *testing.T) {
 	expectedYAMLTags := map[string]string{
-		"Hostname":     "hostname",
-		"DomainSuffix": "domain_suffix",
-		"Timezone":     "timezone",
-		"Email":        "email",
-		"RootPassword": "-",
-		"SSHPublicKey": "-",
+		"Hostname":                 "hostname",
+		"DomainSuffix":             "domain_suffix",
+		"Timezone":                 "timezone",
+		"Email":                    "email",
+		"RootPassword":             "-",
+		"SSHPublicKey":             "-",
+		"RebootAfterInstall":       "reboot_after_install",
+		"EnableUnattendedUpgrades": "unattended_upgrades",
+		"NTPServers":               "ntp_servers",
+		"Keyboard":                 "keyboard",
+		"Locale":                   "locale",
+		"SSHDisablePasswordAuth":   "ssh_disable_password_auth",
+		"SSHPermitRootLogin":       "ssh_permit_root_login",
+		"ProxmoxVersion":           "proxmox_version",
+		"AdminUser":                "admin_user",
+		"AdminSSHKeys":             "admin_ssh_keys",
 	}
 
 	cfgType := reflect.TypeOf(SystemConfig{})
@@ -239,23 +275,33 @@ func TestSystemConfigYAMLTagsPresent(t *testing.T) {
 }
 
 func TestSystemConfigAllFieldsExist(t *testing.T) {
-	requiredFields := []string{
-		"Hostname",
-		"DomainSuffix",
-		"Timezone",
-		"Email",
-		"RootPassword",
-		"SSHPublicKey",
+	requiredFields := map[string]string{
+		"Hostname":                 "string",
+		"DomainSuffix":             "string",
+		"Timezone":                 "string",
+		"Email":                    "string",
+		"RootPassword":             "string",
+		"SSHPublicKey":             "string",
+		"RebootAfterInstall":       "bool",
+		"EnableUnattendedUpgrades": "bool",
+		"NTPServers":               "slice",
+		"Keyboard":                 "string",
+		"Locale":                   "string",
+		"SSHDisablePasswordAuth":   "bool",
+		"SSHPermitRootLogin":       "string",
+		"ProxmoxVersion":           "string",
+		"AdminUser":                "string",
+		"AdminSSHKeys":             "slice",
 	}
 
 	cfgType := reflect.TypeOf(SystemConfig{})
 
 	assert.Equal(t, len(requiredFields), cfgType.NumField(), "unexpected number of fields")
 
-	for _, fieldName := range requiredFields {
+	for fieldName, expectedKind := range requiredFields {
 		field, found := cfgType.FieldByName(fieldName)
 		assert.True(t, found, "required field %s not found", fieldName)
-		assert.Equal(t, "string", field.Type.Kind().String(), "field %s should be string type", fieldName)
+		assert.Equal(t, expectedKind, field.Type.Kind().String(), "field %s should be %s type", fieldName, expectedKind)
 	}
 }
 
@@ -401,9 +447,12 @@ private_subnet: "10.0.0.0/24"`,
 
 func TestNetworkConfigEnvironmentVariableTagsPresent(t *testing.T) {
 	expectedEnvTags := map[string]string{
-		"InterfaceName": "INTERFACE_NAME",
-		"BridgeMode":    "BRIDGE_MODE",
-		"PrivateSubnet": "PRIVATE_SUBNET",
+		"InterfaceName":    "INTERFACE_NAME",
+		"BridgeMode":       "BRIDGE_MODE",
+		"PrivateSubnet":    "PRIVATE_SUBNET",
+		"AdditionalSubnet": "ADDITIONAL_SUBNET",
+		"BridgeMAC":        "BRIDGE_MAC",
+		"NetworkBackend":   "NETWORK_BACKEND",
 	}
 
 	cfgType := reflect.TypeOf(NetworkConfig{})
@@ -419,9 +468,12 @@ func TestNetworkConfigEnvironmentVariableTagsPresent(t *testing.T) {
 
 func TestNetworkConfigYAMLTagsPresent(t *testing.T) {
 	expectedYAMLTags := map[string]string{
-		"InterfaceName": "interface",
-		"BridgeMode":    "bridge_mode",
-		"PrivateSubnet": "private_subnet",
+		"InterfaceName":    "interface",
+		"BridgeMode":       "bridge_mode",
+		"PrivateSubnet":    "private_subnet",
+		"AdditionalSubnet": "additional_subnet",
+		"BridgeMAC":        "bridge_mac",
+		"NetworkBackend":   "network_backend",
 	}
 
 	cfgType := reflect.TypeOf(NetworkConfig{})
@@ -437,9 +489,12 @@ func TestNetworkConfigYAMLTagsPresent(t *testing.T) {
 
 func TestNetworkConfigAllFieldsExist(t *testing.T) {
 	expectedFields := map[string]string{
-		"InterfaceName": "string",
-		"BridgeMode":    "BridgeMode",
-		"PrivateSubnet": "string",
+		"InterfaceName":    "string",
+		"BridgeMode":       "BridgeMode",
+		"PrivateSubnet":    "string",
+		"AdditionalSubnet": "string",
+		"BridgeMAC":        "string",
+		"NetworkBackend":   "NetworkBackend",
 	}
 
 	cfgType := reflect.TypeOf(NetworkConfig{})
@@ -481,6 +536,32 @@ func TestNetworkConfigRoundTripMarshalUnmarshal(t *testing.T) {
 				PrivateSubnet: testSubnetClassB,
 			},
 		},
+		{
+			name: "additional subnet config",
+			cfg: NetworkConfig{
+				InterfaceName:    "enp0s31f6",
+				BridgeMode:       BridgeModeInternal,
+				PrivateSubnet:    testSubnetClassA,
+				AdditionalSubnet: testAdditionalSubnet,
+			},
+		},
+		{
+			name: "bridge MAC config",
+			cfg: NetworkConfig{
+				InterfaceName: "enp0s31f6",
+				BridgeMode:    BridgeModeExternal,
+				PrivateSubnet: testSubnetClassA,
+				BridgeMAC:     testBridgeMAC,
+			},
+		},
+		{
+			name: "networkd backend config",
+			cfg: NetworkConfig{
+				BridgeMode:     BridgeModeInternal,
+				PrivateSubnet:  testSubnetClassA,
+				NetworkBackend: NetworkBackendNetworkd,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -495,6 +576,9 @@ func TestNetworkConfigRoundTripMarshalUnmarshal(t *testing.T) {
 			assert.Equal(t, tt.cfg.InterfaceName, restored.InterfaceName)
 			assert.Equal(t, tt.cfg.BridgeMode, restored.BridgeMode)
 			assert.Equal(t, tt.cfg.PrivateSubnet, restored.PrivateSubnet)
+			assert.Equal(t, tt.cfg.AdditionalSubnet, restored.AdditionalSubnet)
+			assert.Equal(t, tt.cfg.BridgeMAC, restored.BridgeMAC)
+			assert.Equal(t, tt.cfg.NetworkBackend, restored.NetworkBackend)
 		})
 	}
 }
@@ -699,8 +783,10 @@ disks:
 
 func TestStorageConfigEnvironmentVariableTagsPresent(t *testing.T) {
 	expectedEnvTags := map[string]string{
-		"ZFSRaid": "ZFS_RAID",
-		"Disks":   "DISKS",
+		"ZFSRaid":     "ZFS_RAID",
+		"Disks":       "DISKS",
+		"SwapSizeMB":  "SWAP_SIZE_MB",
+		"PreserveESP": "PRESERVE_ESP",
 	}
 
 	cfgType := reflect.TypeOf(StorageConfig{})
@@ -716,8 +802,10 @@ func TestStorageConfigEnvironmentVariableTagsPresent(t *testing.T) {
 
 func TestStorageConfigYAMLTagsPresent(t *testing.T) {
 	expectedYAMLTags := map[string]string{
-		"ZFSRaid": "zfs_raid",
-		"Disks":   "disks",
+		"ZFSRaid":     "zfs_raid",
+		"Disks":       "disks",
+		"SwapSizeMB":  "swap_size_mb",
+		"PreserveESP": "preserve_esp",
 	}
 
 	cfgType := reflect.TypeOf(StorageConfig{})
@@ -733,8 +821,10 @@ func TestStorageConfigYAMLTagsPresent(t *testing.T) {
 
 func TestStorageConfigAllFieldsExist(t *testing.T) {
 	expectedFields := map[string]string{
-		"ZFSRaid": "ZFSRaid",
-		"Disks":   "slice",
+		"ZFSRaid":     "ZFSRaid",
+		"Disks":       "slice",
+		"SwapSizeMB":  "int",
+		"PreserveESP": "bool",
 	}
 
 	cfgType := reflect.TypeOf(StorageConfig{})
@@ -784,6 +874,14 @@ func TestStorageConfigRoundTripMarshalUnmarshal(t *testing.T) {
 				Disks:   []string{},
 			},
 		},
+		{
+			name: "preserve esp",
+			cfg: StorageConfig{
+				ZFSRaid:     ZFSRaidSingle,
+				Disks:       []string{"/dev/nvme0n1"},
+				PreserveESP: true,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1388,11 +1486,14 @@ func TestConfigRoundTripMarshalUnmarshal(t *testing.T) {
 
 func TestConfigYAMLTagsPresent(t *testing.T) {
 	expectedYAMLTags := map[string]string{
-		"System":    "system",
-		"Network":   "network",
-		"Storage":   "storage",
-		"Tailscale": "tailscale",
-		"Verbose":   "-",
+		"System":      "system",
+		"Network":     "network",
+		"Storage":     "storage",
+		"Tailscale":   "tailscale",
+		"Cluster":     "cluster",
+		"ACME":        "acme",
+		"Verbose":     "-",
+		"ConfirmWipe": "-",
 	}
 
 	cfgType := reflect.TypeOf(Config{})
@@ -1408,11 +1509,14 @@ func TestConfigYAMLTagsPresent(t *testing.T) {
 
 func TestConfigAllFieldsExist(t *testing.T) {
 	expectedFields := map[string]string{
-		"System":    "SystemConfig",
-		"Network":   "NetworkConfig",
-		"Storage":   "StorageConfig",
-		"Tailscale": "TailscaleConfig",
-		"Verbose":   "bool",
+		"System":      "SystemConfig",
+		"Network":     "NetworkConfig",
+		"Storage":     "StorageConfig",
+		"Tailscale":   "TailscaleConfig",
+		"Cluster":     "ClusterConfig",
+		"ACME":        "ACMEConfig",
+		"Verbose":     "bool",
+		"ConfirmWipe": "string",
 	}
 
 	cfgType := reflect.TypeOf(Config{})
@@ -1456,6 +1560,85 @@ func TestDefaultConfigSystemDefaults(t *testing.T) {
 	assert.Equal(t, testDomainSuffixLocal, cfg.System.DomainSuffix)
 	assert.Equal(t, testTimezoneKyiv, cfg.System.Timezone)
 	assert.Equal(t, "admin@qoxi.cloud", cfg.System.Email)
+	assert.False(t, cfg.System.RebootAfterInstall)
+	assert.False(t, cfg.System.EnableUnattendedUpgrades)
+	assert.Equal(t, []string{"ntp1.hetzner.de", "ntp2.hetzner.com", "ntp3.hetzner.net"}, cfg.System.NTPServers)
+	assert.Equal(t, "en-us", cfg.System.Keyboard)
+	assert.Equal(t, "en_US.UTF-8", cfg.System.Locale)
+}
+
+func TestDefaultConfigNTPServersNotShared(t *testing.T) {
+	cfg1 := DefaultConfig()
+	cfg1.System.NTPServers[0] = "time.example.com"
+
+	assert.Equal(t, "ntp1.hetzner.de", DefaultConfig().System.NTPServers[0])
+}
+
+func TestSystemConfigKeyboardLocaleRoundTrip(t *testing.T) {
+	original := SystemConfig{Hostname: testDefaultHostname, Keyboard: "de-ch", Locale: "de_CH.UTF-8"}
+
+	data, err := yaml.Marshal(&original)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "keyboard: de-ch")
+	assert.Contains(t, string(data), "locale: de_CH.UTF-8")
+
+	var restored SystemConfig
+	require.NoError(t, yaml.Unmarshal(data, &restored))
+	assert.Equal(t, original.Keyboard, restored.Keyboard)
+	assert.Equal(t, original.Locale, restored.Locale)
+}
+
+func TestSystemConfigNTPServersRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		servers []string
+	}{
+		{"hostnames", []string{"ntp1.hetzner.de", "0.debian.pool.ntp.org"}},
+		{"mixed hostname and ip", []string{"time.example.com", buildIP(192, 0, 2, 123)}},
+		{"empty", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := SystemConfig{Hostname: testDefaultHostname, NTPServers: tt.servers}
+
+			data, err := yaml.Marshal(&original)
+			require.NoError(t, err)
+			assert.Contains(t, string(data), "ntp_servers:")
+
+			var restored SystemConfig
+			require.NoError(t, yaml.Unmarshal(data, &restored))
+			assert.Equal(t, tt.servers, restored.NTPServers)
+		})
+	}
+}
+
+func TestSystemConfigRebootAfterInstallRoundTrip(t *testing.T) {
+	for _, reboot := range []bool{true, false} {
+		original := SystemConfig{Hostname: testDefaultHostname, RebootAfterInstall: reboot}
+
+		data, err := yaml.Marshal(&original)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "reboot_after_install: "+strconv.FormatBool(reboot))
+
+		var restored SystemConfig
+		require.NoError(t, yaml.Unmarshal(data, &restored))
+		assert.Equal(t, reboot, restored.RebootAfterInstall)
+	}
+}
+
+func TestSystemConfigUnattendedUpgradesRoundTrip(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		original := SystemConfig{Hostname: testDefaultHostname, EnableUnattendedUpgrades: enabled}
+
+		data, err := yaml.Marshal(&original)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "unattended_upgrades: "+strconv.FormatBool(enabled))
+
+		var restored SystemConfig
+		require.NoError(t, yaml.Unmarshal(data, &restored))
+		assert.Equal(t, enabled, restored.EnableUnattendedUpgrades)
+	}
 }
 
 func TestDefaultConfigNetworkDefaults(t *testing.T) {
@@ -1463,6 +1646,7 @@ func TestDefaultConfigNetworkDefaults(t *testing.T) {
 
 	assert.Equal(t, BridgeModeInternal, cfg.Network.BridgeMode)
 	assert.Equal(t, testSubnetClassA, cfg.Network.PrivateSubnet)
+	assert.Equal(t, NetworkBackendIfupdown2, cfg.Network.NetworkBackend)
 	assert.Empty(t, cfg.Network.InterfaceName) // Should be auto-detected
 }
 
@@ -1472,6 +1656,7 @@ func TestDefaultConfigStorageDefaults(t *testing.T) {
 	assert.Equal(t, ZFSRaid1, cfg.Storage.ZFSRaid)
 	assert.NotNil(t, cfg.Storage.Disks)
 	assert.Empty(t, cfg.Storage.Disks) // Should be auto-detected
+	assert.Zero(t, cfg.Storage.SwapSizeMB)
 }
 
 func TestDefaultConfigTailscaleDefaults(t *testing.T) {
@@ -1596,8 +1781,11 @@ func TestDefaultConfigAllDefaultsMatchPRDSpecification(t *testing.T) {
 		{"DomainSuffix", cfg.System.DomainSuffix, testDomainSuffixLocal},
 		{"Timezone", cfg.System.Timezone, testTimezoneKyiv},
 		{"Email", cfg.System.Email, "admin@qoxi.cloud"},
+		{"Keyboard", cfg.System.Keyboard, "en-us"},
+		{"Locale", cfg.System.Locale, "en_US.UTF-8"},
 		{"BridgeMode", cfg.Network.BridgeMode, BridgeModeInternal},
 		{"PrivateSubnet", cfg.Network.PrivateSubnet, testSubnetClassA},
+		{"NetworkBackend", cfg.Network.NetworkBackend, NetworkBackendIfupdown2},
 		{"ZFSRaid", cfg.Storage.ZFSRaid, ZFSRaid1},
 		{"TailscaleEnabled", cfg.Tailscale.Enabled, false},
 		{"TailscaleSSH", cfg.Tailscale.SSH, true},
@@ -1611,3 +1799,210 @@ func TestDefaultConfigAllDefaultsMatchPRDSpecification(t *testing.T) {
 		})
 	}
 }
+
+// allDefaultFields lists every field reported by DefaultFields for DefaultConfig.
+var allDefaultFields = []string{
+	"system.hostname", "system.domain_suffix", "system.timezone", "system.email",
+	"system.reboot_after_install", "system.unattended_upgrades", "system.ntp_servers",
+	"system.keyboard", "system.locale", "system.ssh_disable_password_auth", "system.ssh_permit_root_login",
+	"system.proxmox_version", "system.admin_user", "system.admin_ssh_keys",
+	"network.interface", "network.bridge_mode", "network.private_subnet",
+	"network.additional_subnet", "network.bridge_mac", "network.network_backend",
+	"storage.zfs_raid", "storage.disks", "storage.swap_size_mb", "storage.preserve_esp",
+	"tailscale.enabled", "tailscale.ssh", "tailscale.webui",
+	"cluster.join_address", "cluster.fingerprint",
+	"acme.enabled", "acme.email", "acme.staging",
+}
+
+func TestConfigDefaultFieldsAllDefault(t *testing.T) {
+	assert.Equal(t, allDefaultFields, DefaultConfig().DefaultFields())
+}
+
+func TestConfigDefaultFieldsExcludesChanged(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.Hostname = "pve-custom"
+	cfg.Network.BridgeMode = BridgeModeExternal
+
+	fields := cfg.DefaultFields()
+
+	assert.NotContains(t, fields, "system.hostname")
+	assert.NotContains(t, fields, "network.bridge_mode")
+	assert.Len(t, fields, len(allDefaultFields)-2)
+}
+
+func TestConfigDefaultFieldsSlices(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(*Config)
+		wantDefault bool
+	}{
+		{"nil disks equal empty default", func(c *Config) { c.Storage.Disks = nil }, true},
+		{"disks set", func(c *Config) { c.Storage.Disks = []string{testDeviceSDA} }, false},
+		{"ntp servers copied", func(c *Config) { c.System.NTPServers = append([]string(nil), c.System.NTPServers...) }, true},
+		{"ntp servers reordered", func(c *Config) { slices.Reverse(c.System.NTPServers) }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.modify(cfg)
+
+			fields := cfg.DefaultFields()
+
+			if tt.wantDefault {
+				assert.Equal(t, allDefaultFields, fields)
+			} else {
+				assert.Len(t, fields, len(allDefaultFields)-1)
+			}
+		})
+	}
+}
+
+func TestConfigDefaultFieldsIgnoresSecrets(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.RootPassword = testClusterPassword
+	cfg.Verbose = true
+
+	assert.Equal(t, allDefaultFields, cfg.DefaultFields())
+}
+
+func TestConfigDefaultFieldsNil(t *testing.T) {
+	var cfg *Config
+
+	assert.Nil(t, cfg.DefaultFields())
+}
+
+func TestStorageConfigAddDisk(t *testing.T) {
+	var storage StorageConfig
+
+	require.NoError(t, storage.AddDisk(testDeviceSDA))
+	require.NoError(t, storage.AddDisk(testDeviceSDB))
+
+	assert.Equal(t, []string{testDeviceSDA, testDeviceSDB}, storage.Disks)
+}
+
+func TestStorageConfigAddDiskRejectsDuplicate(t *testing.T) {
+	storage := StorageConfig{Disks: []string{testDeviceSDA}}
+
+	err := storage.AddDisk(testDeviceSDA)
+
+	require.ErrorIs(t, err, ErrDiskDuplicate)
+	assert.Equal(t, []string{testDeviceSDA}, storage.Disks)
+}
+
+func TestStorageConfigAddDiskRejectsInvalidPath(t *testing.T) {
+	storage := StorageConfig{Disks: []string{testDeviceSDA}}
+
+	err := storage.AddDisk("sdb")
+
+	require.ErrorIs(t, err, ErrDiskPathInvalid)
+	assert.Equal(t, []string{testDeviceSDA}, storage.Disks)
+}
+
+func TestStorageConfigRemoveDisk(t *testing.T) {
+	storage := StorageConfig{Disks: []string{testDeviceSDA, testDeviceSDB, testDeviceSDC}}
+
+	assert.True(t, storage.RemoveDisk(testDeviceSDB))
+	assert.Equal(t, []string{testDeviceSDA, testDeviceSDC}, storage.Disks)
+
+	assert.False(t, storage.RemoveDisk(testDeviceSDB), "already removed")
+	assert.False(t, storage.RemoveDisk("/dev/sdz"))
+	assert.Equal(t, []string{testDeviceSDA, testDeviceSDC}, storage.Disks)
+}
+
+// ClusterConfig tests
+
+func TestClusterConfigTags(t *testing.T) {
+	expected := map[string][2]string{
+		"JoinAddress": {"join_address", "CLUSTER_JOIN_ADDRESS"},
+		"Fingerprint": {"fingerprint", "CLUSTER_FINGERPRINT"},
+		"Password":    {"-", "CLUSTER_PASSWORD"},
+	}
+
+	cfgType := reflect.TypeOf(ClusterConfig{})
+	assert.Equal(t, len(expected), cfgType.NumField(), "unexpected number of fields")
+
+	for fieldName, tags := range expected {
+		field, found := cfgType.FieldByName(fieldName)
+		require.True(t, found, "field %s not found", fieldName)
+		assert.Equal(t, tags[0], field.Tag.Get("yaml"), "yaml tag mismatch for field %s", fieldName)
+		assert.Equal(t, tags[1], field.Tag.Get("env"), "env tag mismatch for field %s", fieldName)
+	}
+}
+
+func TestClusterConfigRoundTripExcludesPassword(t *testing.T) {
+	original := Config{
+		Cluster: ClusterConfig{
+			JoinAddress: testClusterJoinAddress,
+			Fingerprint: testClusterFingerprint,
+			Password:    testClusterPassword,
+		},
+	}
+
+	data, err := yaml.Marshal(&original)
+	require.NoError(t, err)
+
+	yamlStr := string(data)
+	assert.Contains(t, yamlStr, "join_address: "+testClusterJoinAddress)
+	assert.NotContains(t, yamlStr, testClusterPassword)
+	// Match the key, not the word: ssh_disable_password_auth is a regular setting.
+	assert.NotContains(t, yamlStr, "password:")
+
+	var restored Config
+	require.NoError(t, yaml.Unmarshal(data, &restored))
+
+	assert.Equal(t, testClusterJoinAddress, restored.Cluster.JoinAddress)
+	assert.Equal(t, testClusterFingerprint, restored.Cluster.Fingerprint)
+	assert.Empty(t, restored.Cluster.Password)
+}
+
+func TestClusterConfigJoinRequested(t *testing.T) {
+	assert.False(t, ClusterConfig{}.JoinRequested())
+	assert.False(t, ClusterConfig{Password: testClusterPassword}.JoinRequested())
+	assert.True(t, ClusterConfig{JoinAddress: testClusterJoinAddress}.JoinRequested())
+	assert.True(t, ClusterConfig{Fingerprint: testClusterFingerprint}.JoinRequested())
+}
+
+func TestACMEConfigTags(t *testing.T) {
+	expected := map[string][2]string{
+		"Enabled": {"enabled", "ACME_ENABLED"},
+		"Email":   {"email", "ACME_EMAIL"},
+		"Staging": {"staging", "ACME_STAGING"},
+	}
+
+	cfgType := reflect.TypeOf(ACMEConfig{})
+	assert.Equal(t, len(expected), cfgType.NumField(), "unexpected number of fields")
+
+	for fieldName, tags := range expected {
+		field, found := cfgType.FieldByName(fieldName)
+		require.True(t, found, "field %s not found", fieldName)
+		assert.Equal(t, tags[0], field.Tag.Get("yaml"), "yaml tag mismatch for field %s", fieldName)
+		assert.Equal(t, tags[1], field.Tag.Get("env"), "env tag mismatch for field %s", fieldName)
+	}
+}
+
+func TestConfigACMEEmail(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.Email = "admin@example.com"
+
+	assert.Equal(t, "admin@example.com", cfg.ACMEEmail(), "inherits System.Email when unset")
+
+	cfg.ACME.Email = "certs@example.com"
+
+	assert.Equal(t, "certs@example.com", cfg.ACMEEmail())
+}
+
+// newValidTestConfig returns a default configuration that passes Validate
+// and ReadyForInstall: every secret is set, two disks are selected and the
+// disk wipe is confirmed. Tests change the fields they exercise.
+func newValidTestConfig() *Config {
+	cfg := DefaultConfig()
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+	cfg.Tailscale.AuthKey = testTailscaleAuthKey
+	cfg.Cluster.Password = testClusterPassword
+	cfg.Storage.Disks = []string{testDeviceSDA, testDeviceSDB}
+	cfg.ConfirmWipe = WipeConfirmationToken
+
+	return cfg
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InstallPlanDescription returns one human-readable sentence per stage of
+// the installation configured by c, in the order they run, for a
+// confirmation screen (e.g., "Create raid1 ZFS pool on /dev/sda, /dev/sdb").
+//
+// The storage, network, Tailscale and cluster stages come first. They are
+// not steps of installer.PlanSteps yet and are described from the
+// configuration alone. The post-install steps follow, see
+// PostInstallPlanDescription. The result depends only on c, so the same
+// configuration always produces the same list. It returns nil for a nil Config.
+func (c *Config) InstallPlanDescription() []string {
+	if c == nil {
+		return nil
+	}
+
+	plan := []string{
+		c.Storage.poolDescription(),
+		c.Network.bridgeDescription(),
+	}
+
+	if c.Network.AdditionalSubnet != "" {
+		plan = append(plan, "Route additional subnet "+c.Network.AdditionalSubnet+" to VMs")
+	}
+
+	if c.Tailscale.Enabled {
+		plan = append(plan, c.Tailscale.description())
+	}
+
+	if c.Cluster.JoinRequested() {
+		plan = append(plan, "Join the cluster at "+orDefault(c.Cluster.JoinAddress, "the configured node"))
+	}
+
+	return append(plan, c.PostInstallPlanDescription()...)
+}
+
+// PostInstallPlanDescription returns one sentence per step that
+// installer.PlanSteps selects for c, in the same order, ending with the
+// reboot. The installer tests check that the two stay in step.
+// It returns nil for a nil Config or when no post-install step is enabled.
+func (c *Config) PostInstallPlanDescription() []string {
+	if c == nil {
+		return nil
+	}
+
+	var plan []string
+
+	if c.System.Keyboard != "" || c.System.Locale != "" {
+		plan = append(plan, c.System.localeDescription())
+	}
+
+	if c.Storage.SwapSizeMB > 0 {
+		plan = append(plan, fmt.Sprintf("Create a %d MB swap volume", c.Storage.SwapSizeMB))
+	}
+
+	if c.System.EnableUnattendedUpgrades {
+		plan = append(plan, "Enable automatic security updates")
+	}
+
+	if c.System.AdminUser != "" {
+		plan = append(plan, c.System.adminDescription())
+	}
+
+	if c.ACME.Enabled {
+		plan = append(plan, c.acmeDescription())
+	}
+
+	if c.System.RebootAfterInstall {
+		plan = append(plan, "Reboot into the installed system")
+	}
+
+	return plan
+}
+
+// orDefault returns value, or fallback when value is empty.
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
+
+// poolDescription describes the ZFS pool created on the configured disks.
+func (s StorageConfig) poolDescription() string {
+	level := s.ZFSRaid.String() + " ZFS pool"
+	if s.ZFSRaid == ZFSRaidSingle {
+		level = "single-disk ZFS pool"
+	}
+
+	disks := "auto-detected disks"
+	if len(s.Disks) > 0 {
+		disks = strings.Join(s.Disks, ", ")
+	}
+
+	description := "Create " + level + " on " + disks
+	if s.PreserveESP {
+		description += ", keeping the existing EFI System Partition"
+	}
+
+	return description
+}
+
+// bridgeDescription describes the VM bridges configured on the primary interface.
+func (n NetworkConfig) bridgeDescription() string {
+	iface := orDefault(n.InterfaceName, "the detected interface")
+
+	switch n.BridgeMode {
+	case BridgeModeExternal:
+		return "Configure external bridge on " + iface
+	case BridgeModeBoth:
+		return "Configure external bridge and internal NAT bridge (" + n.PrivateSubnet + ") on " + iface
+	default:
+		return "Configure internal NAT bridge (" + n.PrivateSubnet + ") on " + iface
+	}
+}
+
+// description describes the Tailscale installation and its options.
+func (t TailscaleConfig) description() string {
+	var options []string
+
+	if t.SSH {
+		options = append(options, "SSH")
+	}
+
+	if t.WebUI {
+		options = append(options, "Web UI")
+	}
+
+	if len(options) == 0 {
+		return "Install and authenticate Tailscale"
+	}
+
+	return "Install and authenticate Tailscale with " + strings.Join(options, " and ")
+}
+
+// localeDescription describes the keyboard layout and locale settings.
+func (s SystemConfig) localeDescription() string {
+	var parts []string
+
+	if s.Keyboard != "" {
+		parts = append(parts, "keyboard layout "+s.Keyboard)
+	}
+
+	if s.Locale != "" {
+		parts = append(parts, "locale "+s.Locale)
+	}
+
+	return "Set " + strings.Join(parts, " and ")
+}
+
+// adminDescription describes the admin user account.
+func (s SystemConfig) adminDescription() string {
+	description := "Create sudo admin user " + s.AdminUser
+
+	switch n := len(s.AdminSSHKeys); n {
+	case 0:
+		return description
+	case 1:
+		return description + " with 1 SSH key"
+	default:
+		return fmt.Sprintf("%s with %d SSH keys", description, n)
+	}
+}
+
+// acmeDescription describes the Let's Encrypt certificate order.
+func (c *Config) acmeDescription() string {
+	description := "Order a Let's Encrypt certificate for " + c.FQDN()
+	if c.ACME.Staging {
+		description += " (staging)"
+	}
+
+	return description
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstallPlanDescriptionNilConfig(t *testing.T) {
+	var cfg *Config
+
+	assert.Nil(t, cfg.InstallPlanDescription())
+}
+
+func TestInstallPlanDescriptionDefaults(t *testing.T) {
+	assert.Equal(t, []string{
+		"Create raid1 ZFS pool on auto-detected disks",
+		"Configure internal NAT bridge (10.0.0.0/24) on the detected interface",
+		"Set keyboard layout en-us and locale en_US.UTF-8",
+	}, DefaultConfig().InstallPlanDescription())
+}
+
+func TestInstallPlanDescriptionFullConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.Hostname = "pve1"
+	cfg.System.DomainSuffix = "example.com"
+	cfg.System.EnableUnattendedUpgrades = true
+	cfg.System.AdminUser = "alice"
+	cfg.System.AdminSSHKeys = []string{"ssh-ed25519 AAAA alice@laptop", "ssh-ed25519 BBBB alice@desk"}
+	cfg.System.RebootAfterInstall = true
+	cfg.Network.InterfaceName = "eth0"
+	cfg.Network.BridgeMode = BridgeModeBoth
+	cfg.Network.AdditionalSubnet = "203.0.113.8/29" // NOSONAR(go:S1313) documentation range test data
+	cfg.Storage.Disks = []string{testDeviceSDA, testDeviceSDB}
+	cfg.Storage.SwapSizeMB = 4096
+	cfg.Storage.PreserveESP = true
+	cfg.Tailscale.Enabled = true
+	cfg.Tailscale.WebUI = true
+	cfg.Cluster.JoinAddress = "10.0.0.2:8006"
+	cfg.ACME.Enabled = true
+	cfg.ACME.Staging = true
+
+	assert.Equal(t, []string{
+		"Create raid1 ZFS pool on /dev/sda, /dev/sdb, keeping the existing EFI System Partition",
+		"Configure external bridge and internal NAT bridge (10.0.0.0/24) on eth0",
+		"Route additional subnet 203.0.113.8/29 to VMs",
+		"Install and authenticate Tailscale with SSH and Web UI",
+		"Join the cluster at 10.0.0.2:8006",
+		"Set keyboard layout en-us and locale en_US.UTF-8",
+		"Create a 4096 MB swap volume",
+		"Enable automatic security updates",
+		"Create sudo admin user alice with 2 SSH keys",
+		"Order a Let's Encrypt certificate for pve1.example.com (staging)",
+		"Reboot into the installed system",
+	}, cfg.InstallPlanDescription())
+}
+
+func TestPostInstallPlanDescription(t *testing.T) {
+	var nilCfg *Config
+	assert.Nil(t, nilCfg.PostInstallPlanDescription())
+
+	cfg := DefaultConfig()
+	cfg.System.Keyboard = ""
+	cfg.System.Locale = ""
+	assert.Nil(t, cfg.PostInstallPlanDescription())
+
+	cfg.System.RebootAfterInstall = true
+	assert.Equal(t, []string{"Reboot into the installed system"}, cfg.PostInstallPlanDescription())
+}
+
+func TestInstallPlanDescriptionTailscale(t *testing.T) {
+	const tailscaleLine = "Install and authenticate Tailscale with SSH"
+
+	cfg := DefaultConfig()
+	assert.NotContains(t, cfg.InstallPlanDescription(), tailscaleLine, "absent when disabled")
+
+	cfg.Tailscale.Enabled = true
+	assert.Contains(t, cfg.InstallPlanDescription(), tailscaleLine)
+
+	cfg.Tailscale.SSH = false
+	assert.Contains(t, cfg.InstallPlanDescription(), "Install and authenticate Tailscale")
+}
+
+func TestInstallPlanDescriptionPool(t *testing.T) {
+	tests := []struct {
+		raid  ZFSRaid
+		disks []string
+		want  string
+	}{
+		{ZFSRaidSingle, []string{testDeviceSDA}, "Create single-disk ZFS pool on /dev/sda"},
+		{ZFSRaid0, []string{testDeviceSDA, testDeviceSDB}, "Create raid0 ZFS pool on /dev/sda, /dev/sdb"},
+		{ZFSRaid1, []string{testDeviceSDA, testDeviceSDB, testDeviceSDC}, "Create raid1 ZFS pool on /dev/sda, /dev/sdb, /dev/sdc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.raid), func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Storage.ZFSRaid = tt.raid
+			cfg.Storage.Disks = tt.disks
+
+			assert.Equal(t, tt.want, cfg.InstallPlanDescription()[0])
+		})
+	}
+}
+
+func TestInstallPlanDescriptionBridge(t *testing.T) {
+	tests := []struct {
+		mode BridgeMode
+		want string
+	}{
+		{BridgeModeInternal, "Configure internal NAT bridge (10.0.0.0/24) on eth0"},
+		{BridgeModeExternal, "Configure external bridge on eth0"},
+		{BridgeModeBoth, "Configure external bridge and internal NAT bridge (10.0.0.0/24) on eth0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Network.InterfaceName = "eth0"
+			cfg.Network.BridgeMode = tt.mode
+
+			assert.Equal(t, tt.want, cfg.InstallPlanDescription()[1])
+		})
+	}
+}
+
+func TestInstallPlanDescriptionAdminKeys(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.AdminUser = "alice"
+
+	assert.Contains(t, cfg.InstallPlanDescription(), "Create sudo admin user alice")
+
+	cfg.System.AdminSSHKeys = []string{"ssh-ed25519 AAAA alice@laptop"}
+
+	assert.Contains(t, cfg.InstallPlanDescription(), "Create sudo admin user alice with 1 SSH key")
+}
+
+func TestInstallPlanDescriptionDeterministic(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Storage.Disks = []string{testDeviceSDB, testDeviceSDA}
+	cfg.Tailscale.Enabled = true
+	cfg.ACME.Enabled = true
+
+	first := cfg.InstallPlanDescription()
+
+	for range 10 {
+		assert.Equal(t, first, cfg.InstallPlanDescription())
+	}
+
+	assert.Equal(t, "Create raid1 ZFS pool on /dev/sdb, /dev/sda", first[0], "disks keep their configured order")
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldDiff is a configuration field whose value differs between two configs.
+type FieldDiff struct {
+	// Field is the YAML path of the field (e.g., "network.bridge_mode").
+	Field string
+
+	// Old is the value in the first config; secret values are redacted.
+	Old string
+
+	// New is the value in the second config; secret values are redacted.
+	New string
+
+	// Secret is true for sensitive fields, which are never saved to files.
+	Secret bool
+}
+
+// String returns the difference as `field: "old" -> "new"`.
+func (d FieldDiff) String() string {
+	return fmt.Sprintf("%s: %q -> %q", d.Field, d.Old, d.New)
+}
+
+// DiffConfig returns the fields whose values differ from a to b, in
+// declaration order. Slices are compared element by element, so a nil and
+// an empty list are equal, and list values are shown comma-separated.
+//
+// Secret fields are included with Secret set and both values replaced by
+// RedactedPlaceholder (or empty when unset), so the result is safe to print.
+// Runtime-only settings (Verbose, ConfirmWipe) are not compared. Nil configs
+// are treated as empty.
+func DiffConfig(a, b *Config) []FieldDiff {
+	if a == nil {
+		a = &Config{}
+	}
+
+	if b == nil {
+		b = &Config{}
+	}
+
+	av := reflect.ValueOf(a).Elem()
+	bv := reflect.ValueOf(b).Elem()
+
+	var diffs []FieldDiff
+
+	for i := range av.NumField() {
+		section := av.Type().Field(i)
+		if section.Type.Kind() != reflect.Struct {
+			continue
+		}
+
+		for j := range section.Type.NumField() {
+			field := section.Type.Field(j)
+			oldValue, newValue := av.Field(i).Field(j), bv.Field(i).Field(j)
+
+			if fieldValuesEqual(oldValue, newValue) {
+				continue
+			}
+
+			diff := FieldDiff{
+				Field: section.Tag.Get("yaml") + "." + field.Tag.Get("yaml"),
+				Old:   formatFieldValue(oldValue),
+				New:   formatFieldValue(newValue),
+			}
+
+			if field.Tag.Get("secret") == "true" {
+				diff.Field = section.Tag.Get("yaml") + "." + field.Tag.Get("secret_name")
+				diff.Old = redactNonEmpty(diff.Old)
+				diff.New = redactNonEmpty(diff.New)
+				diff.Secret = true
+			}
+
+			diffs = append(diffs, diff)
+		}
+	}
+
+	return diffs
+}
+
+// formatFieldValue returns a field value as text, joining lists with commas.
+func formatFieldValue(v reflect.Value) string {
+	if v.Kind() != reflect.Slice {
+		return fmt.Sprint(v.Interface())
+	}
+
+	items := make([]string, v.Len())
+	for i := range v.Len() {
+		items[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+
+	return strings.Join(items, ",")
+}
+
+// redactNonEmpty returns RedactedPlaceholder for a set value and "" otherwise.
+func redactNonEmpty(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	return RedactedPlaceholder
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffConfigEqual(t *testing.T) {
+	a := DefaultConfig()
+	b := DefaultConfig()
+	b.Storage.Disks = []string{}
+	b.Verbose = true
+	b.ConfirmWipe = WipeConfirmationToken
+
+	assert.Empty(t, DiffConfig(a, b), "nil and empty lists and runtime-only settings are not differences")
+}
+
+func TestDiffConfig(t *testing.T) {
+	a := DefaultConfig()
+	b := DefaultConfig()
+	b.System.Hostname = "pve-fsn1-01"
+	b.Network.BridgeMode = BridgeModeExternal
+	b.Storage.Disks = []string{"/dev/sda", "/dev/sdb"}
+	b.Tailscale.Enabled = true
+
+	assert.Equal(t, []FieldDiff{
+		{Field: "system.hostname", Old: a.System.Hostname, New: "pve-fsn1-01"},
+		{Field: "network.bridge_mode", Old: "internal", New: "external"},
+		{Field: "storage.disks", Old: "", New: "/dev/sda,/dev/sdb"},
+		{Field: "tailscale.enabled", Old: "false", New: "true"},
+	}, DiffConfig(a, b))
+}
+
+func TestDiffConfigRedactsSecrets(t *testing.T) {
+	a := DefaultConfig()
+	b := DefaultConfig()
+	b.System.RootPassword = testValidPassword
+	b.Tailscale.AuthKey = "tskey-auth-secret"
+	a.Cluster.Password = "old-cluster-secret"
+	b.Cluster.Password = "new-cluster-secret"
+
+	diffs := DiffConfig(a, b)
+
+	assert.Equal(t, []FieldDiff{
+		{Field: "system.root_password", Old: "", New: RedactedPlaceholder, Secret: true},
+		{Field: "tailscale.auth_key", Old: "", New: RedactedPlaceholder, Secret: true},
+		{Field: "cluster.password", Old: RedactedPlaceholder, New: RedactedPlaceholder, Secret: true},
+	}, diffs)
+
+	for _, diff := range diffs {
+		AssertNoSecretsInBytes(t, []byte(diff.String()), b)
+		AssertNoSecretsInBytes(t, []byte(diff.String()), a)
+	}
+}
+
+func TestDiffConfigNil(t *testing.T) {
+	diffs := DiffConfig(nil, &Config{System: SystemConfig{Hostname: "pve1"}})
+
+	assert.Equal(t, []FieldDiff{{Field: "system.hostname", Old: "", New: "pve1"}}, diffs)
+	assert.Empty(t, DiffConfig(nil, nil))
+}
+
+func TestFieldDiffString(t *testing.T) {
+	diff := FieldDiff{Field: "system.hostname", Old: "pve", New: "pve-fsn1-01"}
+
+	assert.Equal(t, `system.hostname: "pve" -> "pve-fsn1-01"`, diff.String())
+}
@@ -0,0 +1,156 @@
+package config
+
+// BuildEffectiveConfig returns the configuration resulting from all layers,
+// applied in increasing priority:
+//
+//  1. Default values from DefaultConfig()
+//  2. The config file at filePath, if filePath is not empty
+//  3. Environment variables (see LoadFromEnv)
+//  4. Non-zero fields of tuiOverrides, if not nil
+//
+// This is the single source of truth for configuration precedence.
+// The result is normalized with Normalize. Returns an error if filePath is
+// set but cannot be loaded, if an environment variable cannot be parsed (see CheckEnv), or if
+// the SSH public key file cannot be read or is invalid.
+//
+// Because only non-zero TUI values are applied, the TUI cannot reset a
+// boolean to false or clear a string set by a lower layer; it should pass
+// the full desired value in such cases by editing the returned Config.
+func BuildEffectiveConfig(filePath string, tuiOverrides *Config) (*Config, error) {
+	cfg := DefaultConfig()
+
+	if filePath != "" {
+		loaded, err := LoadFromFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg = loaded
+	}
+
+	if err := CheckEnv(); err != nil {
+		return nil, err
+	}
+
+	LoadFromEnv(cfg)
+	mergeNonZero(cfg, tuiOverrides)
+
+	if err := cfg.Normalize(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Normalize brings c into the form BuildEffectiveConfig returns, so that
+// configurations loaded by other means compare equal to it: valid emails
+// (System.Email, ACME.Email) are stored in canonical form (see
+// CanonicalizeEmail), and a System.SSHPublicKey naming a key file is
+// replaced by the file's key (see ResolveSSHPublicKey). Invalid emails are
+// kept as they are for Validate to report. Returns an error if the SSH
+// public key file cannot be read or is invalid. A nil Config is a no-op.
+func (c *Config) Normalize() error {
+	if c == nil {
+		return nil
+	}
+
+	c.System.Email = canonicalEmail(c.System.Email)
+	c.ACME.Email = canonicalEmail(c.ACME.Email)
+
+	key, err := ResolveSSHPublicKey(c.System.SSHPublicKey)
+	if err != nil {
+		return err
+	}
+
+	c.System.SSHPublicKey = key
+
+	return nil
+}
+
+// mergeNonZero copies every non-zero field of src onto dst.
+// It is a no-op if src is nil.
+func mergeNonZero(dst, src *Config) {
+	if src == nil {
+		return
+	}
+
+	mergeString(&dst.System.Hostname, src.System.Hostname)
+	mergeString(&dst.System.DomainSuffix, src.System.DomainSuffix)
+	mergeString(&dst.System.Timezone, src.System.Timezone)
+	mergeString(&dst.System.Keyboard, src.System.Keyboard)
+	mergeString(&dst.System.Locale, src.System.Locale)
+	mergeBool(&dst.System.SSHDisablePasswordAuth, src.System.SSHDisablePasswordAuth)
+	mergeString(&dst.System.SSHPermitRootLogin, src.System.SSHPermitRootLogin)
+	mergeString(&dst.System.ProxmoxVersion, src.System.ProxmoxVersion)
+	mergeString(&dst.System.AdminUser, src.System.AdminUser)
+	mergeString(&dst.System.Email, src.System.Email)
+	mergeString(&dst.System.RootPassword, src.System.RootPassword)
+	mergeString(&dst.System.SSHPublicKey, src.System.SSHPublicKey)
+	mergeBool(&dst.System.RebootAfterInstall, src.System.RebootAfterInstall)
+	mergeBool(&dst.System.EnableUnattendedUpgrades, src.System.EnableUnattendedUpgrades)
+
+	if len(src.System.NTPServers) > 0 {
+		dst.System.NTPServers = append([]string(nil), src.System.NTPServers...)
+	}
+
+	if len(src.System.AdminSSHKeys) > 0 {
+		dst.System.AdminSSHKeys = append([]string(nil), src.System.AdminSSHKeys...)
+	}
+
+	mergeString(&dst.Network.InterfaceName, src.Network.InterfaceName)
+	mergeString(&dst.Network.PrivateSubnet, src.Network.PrivateSubnet)
+	mergeString(&dst.Network.AdditionalSubnet, src.Network.AdditionalSubnet)
+	mergeString(&dst.Network.BridgeMAC, src.Network.BridgeMAC)
+
+	if src.Network.BridgeMode != "" {
+		dst.Network.BridgeMode = src.Network.BridgeMode
+	}
+
+	if src.Network.NetworkBackend != "" {
+		dst.Network.NetworkBackend = src.Network.NetworkBackend
+	}
+
+	if src.Storage.ZFSRaid != "" {
+		dst.Storage.ZFSRaid = src.Storage.ZFSRaid
+	}
+
+	if len(src.Storage.Disks) > 0 {
+		dst.Storage.Disks = append([]string(nil), src.Storage.Disks...)
+	}
+
+	if src.Storage.SwapSizeMB != 0 {
+		dst.Storage.SwapSizeMB = src.Storage.SwapSizeMB
+	}
+
+	mergeBool(&dst.Storage.PreserveESP, src.Storage.PreserveESP)
+
+	mergeBool(&dst.Tailscale.Enabled, src.Tailscale.Enabled)
+	mergeString(&dst.Tailscale.AuthKey, src.Tailscale.AuthKey)
+	mergeBool(&dst.Tailscale.SSH, src.Tailscale.SSH)
+	mergeBool(&dst.Tailscale.WebUI, src.Tailscale.WebUI)
+
+	mergeString(&dst.Cluster.JoinAddress, src.Cluster.JoinAddress)
+	mergeString(&dst.Cluster.Fingerprint, src.Cluster.Fingerprint)
+	mergeString(&dst.Cluster.Password, src.Cluster.Password)
+
+	mergeBool(&dst.ACME.Enabled, src.ACME.Enabled)
+	mergeString(&dst.ACME.Email, src.ACME.Email)
+	mergeBool(&dst.ACME.Staging, src.ACME.Staging)
+
+	mergeBool(&dst.Verbose, src.Verbose)
+	mergeString(&dst.ConfirmWipe, src.ConfirmWipe)
+}
+
+// mergeString sets *dst to src if src is not empty.
+func mergeString(dst *string, src string) {
+	if src != "" {
+		*dst = src
+	}
+}
+
+// mergeBool sets *dst to true if src is true.
+func mergeBool(dst *bool, src bool) {
+	if src {
+		*dst = true
+	}
+}
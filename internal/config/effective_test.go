@@ -0,0 +1,183 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestConfigFile writes content to a config file in a temp dir and returns its path.
+func writeTestConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), testConfigFileName)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestBuildEffectiveConfigDefaultsOnly(t *testing.T) {
+	cfg, err := BuildEffectiveConfig("", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, DefaultConfig(), cfg)
+}
+
+func TestBuildEffectiveConfigFileOverDefaults(t *testing.T) {
+	path := writeTestConfigFile(t, "system:\n  hostname: from-file\nstorage:\n  zfs_raid: raid0\n")
+
+	cfg, err := BuildEffectiveConfig(path, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "from-file", cfg.System.Hostname)
+	assert.Equal(t, ZFSRaid0, cfg.Storage.ZFSRaid)
+	assert.Equal(t, DefaultConfig().System.Timezone, cfg.System.Timezone)
+}
+
+func TestBuildEffectiveConfigEnvOverFile(t *testing.T) {
+	path := writeTestConfigFile(t, "system:\n  hostname: from-file\nnetwork:\n  bridge_mode: external\n")
+	t.Setenv("PVE_HOSTNAME", "from-env")
+
+	cfg, err := BuildEffectiveConfig(path, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", cfg.System.Hostname)
+	assert.Equal(t, BridgeModeExternal, cfg.Network.BridgeMode)
+}
+
+func TestBuildEffectiveConfigTUIOverEnv(t *testing.T) {
+	path := writeTestConfigFile(t, "system:\n  hostname: from-file\n")
+	t.Setenv("PVE_HOSTNAME", "from-env")
+	t.Setenv("DISKS", "/dev/sda")
+	t.Setenv("PVE_EMAIL", "env@example.com")
+
+	tui := &Config{
+		System:  SystemConfig{Hostname: "from-tui"},
+		Storage: StorageConfig{Disks: []string{testDeviceSDB, testDeviceSDC}},
+		Tailscale: TailscaleConfig{
+			Enabled: true,
+		},
+	}
+
+	cfg, err := BuildEffectiveConfig(path, tui)
+
+	require.NoError(t, err)
+	assert.Equal(t, "from-tui", cfg.System.Hostname)
+	assert.Equal(t, []string{testDeviceSDB, testDeviceSDC}, cfg.Storage.Disks)
+	assert.True(t, cfg.Tailscale.Enabled)
+
+	// Zero TUI fields leave lower layers untouched.
+	assert.Equal(t, "env@example.com", cfg.System.Email)
+	assert.Equal(t, DefaultConfig().Network.BridgeMode, cfg.Network.BridgeMode)
+	assert.True(t, cfg.Tailscale.SSH, "false TUI bool must not clear default true")
+}
+
+func TestBuildEffectiveConfigTUIWithoutFile(t *testing.T) {
+	cfg, err := BuildEffectiveConfig("", &Config{Storage: StorageConfig{SwapSizeMB: 2048}})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2048, cfg.Storage.SwapSizeMB)
+	assert.Equal(t, DefaultConfig().System.Hostname, cfg.System.Hostname)
+}
+
+func TestBuildEffectiveConfigCanonicalizesEmail(t *testing.T) {
+	path := writeTestConfigFile(t, "system:\n  email: Admin@Example.COM\n")
+
+	cfg, err := BuildEffectiveConfig(path, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Admin@example.com", cfg.System.Email)
+}
+
+func TestBuildEffectiveConfigKeepsInvalidEmailForValidation(t *testing.T) {
+	t.Setenv("PVE_EMAIL", "Not-An-Email")
+
+	cfg, err := BuildEffectiveConfig("", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Not-An-Email", cfg.System.Email)
+}
+
+func TestConfigNormalize(t *testing.T) {
+	path := writeTestKeyFile(t, t.TempDir(), "id_ed25519.pub", testValidSSHKey+"\n")
+
+	cfg := DefaultConfig()
+	cfg.System.Email = "Admin@Example.COM"
+	cfg.ACME.Email = "not-an-email"
+	cfg.System.SSHPublicKey = path
+
+	require.NoError(t, cfg.Normalize())
+	assert.Equal(t, "Admin@example.com", cfg.System.Email)
+	assert.Equal(t, "not-an-email", cfg.ACME.Email, "invalid emails are left for Validate")
+	assert.Equal(t, testValidSSHKey, cfg.System.SSHPublicKey)
+
+	var nilCfg *Config
+	assert.NoError(t, nilCfg.Normalize())
+}
+
+func TestBuildEffectiveConfigFileError(t *testing.T) {
+	cfg, err := BuildEffectiveConfig(filepath.Join(t.TempDir(), "missing.yaml"), nil)
+
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+}
+
+func TestBuildEffectiveConfigInvalidEnvValue(t *testing.T) {
+	t.Setenv("SWAP_SIZE_MB", "lots")
+
+	cfg, err := BuildEffectiveConfig("", nil)
+
+	require.ErrorIs(t, err, ErrEnvValueInvalid)
+	assert.Nil(t, cfg)
+}
+
+func TestBuildEffectiveConfigDoesNotAliasTUIDisks(t *testing.T) {
+	tui := &Config{Storage: StorageConfig{Disks: []string{testDeviceSDA}}}
+
+	cfg, err := BuildEffectiveConfig("", tui)
+	require.NoError(t, err)
+
+	cfg.Storage.Disks[0] = testDeviceSDB
+
+	assert.Equal(t, testDeviceSDA, tui.Storage.Disks[0])
+}
+
+// TestMergeNonZeroCoversAllFields fills every field of a Config with a non-zero
+// value and checks that mergeNonZero copies all of them. It fails when a field
+// is added to Config without updating mergeNonZero.
+func TestMergeNonZeroCoversAllFields(t *testing.T) {
+	src := &Config{}
+	fillNonZero(t, reflect.ValueOf(src).Elem())
+
+	dst := &Config{}
+	mergeNonZero(dst, src)
+
+	assert.Equal(t, src, dst)
+}
+
+// fillNonZero recursively sets every field of v to a non-zero value.
+func fillNonZero(t *testing.T, v reflect.Value) {
+	t.Helper()
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			fillNonZero(t, v.Field(i))
+		}
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int:
+		v.SetInt(1)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fillNonZero(t, v.Index(0))
+	default:
+		t.Fatalf("fillNonZero: unsupported kind %s", v.Kind())
+	}
+}
@@ -113,3 +113,56 @@ func (z *ZFSRaid) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 	return nil
 }
+
+// NetworkBackend defines how the host network configuration is rendered.
+type NetworkBackend string
+
+const (
+	// NetworkBackendIfupdown2 renders /etc/network/interfaces for ifupdown2 (Proxmox default).
+	NetworkBackendIfupdown2 NetworkBackend = "ifupdown2"
+	// NetworkBackendNetworkd renders systemd-networkd .netdev and .network units.
+	NetworkBackendNetworkd NetworkBackend = "networkd"
+)
+
+// String returns the string representation of NetworkBackend.
+func (n NetworkBackend) String() string {
+	return string(n)
+}
+
+// IsValid checks if the NetworkBackend is a valid value.
+func (n NetworkBackend) IsValid() bool {
+	switch n {
+	case NetworkBackendIfupdown2, NetworkBackendNetworkd:
+		return true
+	}
+
+	return false
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+func (n NetworkBackend) MarshalYAML() (interface{}, error) {
+	return n.String(), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (n *NetworkBackend) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+
+	if s == "" {
+		*n = ""
+
+		return nil
+	}
+
+	backend := NetworkBackend(s)
+	if !backend.IsValid() {
+		return fmt.Errorf("invalid network backend %q: must be one of ifupdown2, networkd", s)
+	}
+
+	*n = backend
+
+	return nil
+}
@@ -206,15 +206,69 @@ func TestZFSRaidUnmarshalYAML(t *testing.T) {
 	}
 }
 
+func TestNetworkBackendIsValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		backend  NetworkBackend
+		expected bool
+	}{
+		{"ifupdown2 is valid", NetworkBackendIfupdown2, true},
+		{"networkd is valid", NetworkBackendNetworkd, true},
+		{"empty is invalid", NetworkBackend(""), false},
+		{"ifupdown is invalid", NetworkBackend("ifupdown"), false},
+		{"full unit name is invalid", NetworkBackend("systemd-networkd"), false},
+		{"uppercase is invalid", NetworkBackend("Networkd"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.backend.IsValid())
+			assert.Equal(t, string(tt.backend), tt.backend.String())
+		})
+	}
+}
+
+func TestNetworkBackendUnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    NetworkBackend
+		expectError bool
+	}{
+		{"ifupdown2", "ifupdown2", NetworkBackendIfupdown2, false},
+		{"networkd", "networkd", NetworkBackendNetworkd, false},
+		{"empty string", "", NetworkBackend(""), false},
+		{"invalid backend", "netplan", NetworkBackend(""), true},
+		{"uppercase", "NETWORKD", NetworkBackend(""), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var backend NetworkBackend
+			err := yaml.Unmarshal([]byte(tt.input), &backend)
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "invalid network backend")
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, backend)
+			}
+		})
+	}
+}
+
 func TestEnumTypesRoundTrip(t *testing.T) {
 	type testConfig struct {
-		Bridge BridgeMode `yaml:"bridge"`
-		Raid   ZFSRaid    `yaml:"raid"`
+		Bridge  BridgeMode     `yaml:"bridge"`
+		Raid    ZFSRaid        `yaml:"raid"`
+		Backend NetworkBackend `yaml:"backend"`
 	}
 
 	original := testConfig{
-		Bridge: BridgeModeExternal,
-		Raid:   ZFSRaid1,
+		Bridge:  BridgeModeExternal,
+		Raid:    ZFSRaid1,
+		Backend: NetworkBackendNetworkd,
 	}
 
 	data, err := yaml.Marshal(original)
@@ -226,4 +280,5 @@ func TestEnumTypesRoundTrip(t *testing.T) {
 
 	assert.Equal(t, original.Bridge, decoded.Bridge)
 	assert.Equal(t, original.Raid, decoded.Raid)
+	assert.Equal(t, original.Backend, decoded.Backend)
 }
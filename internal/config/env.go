@@ -22,26 +22,67 @@
 //   - PVE_TIMEZONE: Timezone (e.g., "Europe/Kyiv")
 //   - PVE_EMAIL: Admin email address
 //   - PVE_ROOT_PASSWORD: Root password (sensitive)
-//   - PVE_SSH_PUBLIC_KEY: SSH public key (sensitive)
+//   - PVE_SSH_PUBLIC_KEY: SSH public key or path to a .pub file (sensitive)
+//   - PVE_REBOOT_AFTER_INSTALL: Reboot when installation completes (true/false)
+//   - PVE_UNATTENDED_UPGRADES: Enable automatic security updates (true/false)
+//   - NTP_SERVERS: Comma-separated list of NTP servers
+//   - PVE_KEYBOARD: Console keyboard layout (e.g., "en-us", "de-ch")
+//   - PVE_LOCALE: System locale (e.g., "en_US.UTF-8")
+//   - PVE_SSH_DISABLE_PASSWORD_AUTH: Disable SSH password authentication (true/false)
+//   - PVE_SSH_PERMIT_ROOT_LOGIN: sshd PermitRootLogin (yes, no, prohibit-password)
+//   - PVE_PROXMOX_VERSION: Proxmox VE version to install (e.g., "8.2", "8.2-1")
+//   - PVE_ADMIN_USER: Sudo-capable admin user to create (e.g., "admin")
+//   - PVE_ADMIN_SSH_KEYS: Comma-separated SSH public keys for the admin user
 //
 // Network Configuration:
 //   - INTERFACE_NAME: Primary network interface (e.g., "eth0")
 //   - BRIDGE_MODE: VM networking mode (internal, external, both)
 //   - PRIVATE_SUBNET: NAT network subnet (e.g., "10.0.0.0/24")
+//   - ADDITIONAL_SUBNET: Optional routed Hetzner subnet (e.g., "203.0.113.8/29")
+//   - BRIDGE_MAC: Optional MAC address of the public bridge (e.g., "90:1b:0e:aa:bb:cc")
+//   - NETWORK_BACKEND: Network configuration backend (ifupdown2, networkd)
 //
 // Storage Configuration:
 //   - ZFS_RAID: ZFS RAID level (single, raid0, raid1)
 //   - DISKS: Comma-separated list of disk devices
+//   - SWAP_SIZE_MB: Swap zvol size in megabytes (0 disables swap)
+//   - PRESERVE_ESP: Keep the existing EFI System Partition (true/false)
 //
 // Tailscale Configuration:
 //   - INSTALL_TAILSCALE: Enable Tailscale (true/false/yes/no/1/0)
 //   - TAILSCALE_AUTH_KEY: Tailscale auth key (sensitive)
 //   - TAILSCALE_SSH: Enable SSH over Tailscale (true/false)
 //   - TAILSCALE_WEBUI: Expose WebUI via Tailscale (true/false)
+//
+// ACME Configuration:
+//   - ACME_ENABLED: Order a Let's Encrypt certificate (true/false)
+//   - ACME_EMAIL: ACME account email (defaults to PVE_EMAIL)
+//   - ACME_STAGING: Use the Let's Encrypt staging directory (true/false)
+//
+// Cluster Configuration:
+//   - CLUSTER_JOIN_ADDRESS: Existing cluster node to join (e.g., "10.0.0.2:8006")
+//   - CLUSTER_FINGERPRINT: SHA-256 fingerprint of the node's API certificate
+//   - CLUSTER_PASSWORD: Root password of the cluster node (sensitive)
+//
+// # Section-Scoped Names
+//
+// The network, storage, Tailscale, cluster and ACME variables above, and
+// NTP_SERVERS, have generic names (e.g., DISKS) that can collide with other
+// tools. Each one is also accepted under a section-scoped name,
+// PVE_<SECTION>_<FIELD>, built from the section and field YAML names (e.g.,
+// PVE_NETWORK_BRIDGE_MODE, PVE_STORAGE_DISKS, PVE_SYSTEM_NTP_SERVERS).
+// Fields not saved to files use their legacy name without the section prefix
+// (e.g., PVE_TAILSCALE_AUTH_KEY). When both names are set to non-empty
+// values, the scoped name wins; an empty scoped name is ignored. See
+// ScopedEnvName.
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -61,21 +102,99 @@ func EnvVarSet(name string) bool {
 	return exists
 }
 
-// parseDisksEnv parses a comma-separated list of disk paths from an environment variable.
+// scopedEnvNames maps the legacy environment variable names of the
+// non-system sections to their section-scoped names, see ScopedEnvName.
+var scopedEnvNames = buildScopedEnvNames()
+
+// buildScopedEnvNames derives the section-scoped names from the Config
+// struct tags: PVE_<section yaml>_<field yaml>, upper-cased. Fields without
+// a YAML name (secrets) use their env tag with the section prefix removed.
+// System variables that already carry the PVE_ prefix are not scoped.
+func buildScopedEnvNames() map[string]string {
+	names := make(map[string]string)
+	cfgType := reflect.TypeOf(Config{})
+
+	for i := range cfgType.NumField() {
+		section := cfgType.Field(i)
+		sectionName := strings.ToUpper(section.Tag.Get("yaml"))
+
+		if section.Type.Kind() != reflect.Struct {
+			continue
+		}
+
+		for j := range section.Type.NumField() {
+			field := section.Type.Field(j)
+
+			legacy := field.Tag.Get("env")
+			if legacy == "" || (sectionName == "SYSTEM" && strings.HasPrefix(legacy, "PVE_")) {
+				continue
+			}
+
+			fieldName := strings.ToUpper(field.Tag.Get("yaml"))
+			if fieldName == "-" {
+				fieldName = strings.TrimPrefix(legacy, sectionName+"_")
+			}
+
+			names[legacy] = "PVE_" + sectionName + "_" + fieldName
+		}
+	}
+
+	return names
+}
+
+// ScopedEnvName returns the section-scoped name accepted in addition to the
+// legacy environment variable name (e.g., "PVE_NETWORK_BRIDGE_MODE" for
+// "BRIDGE_MODE"), or "" if the variable has no scoped name.
+func ScopedEnvName(legacy string) string {
+	return scopedEnvNames[legacy]
+}
+
+// lookupEnv returns the value of the environment variable legacy, or of its
+// section-scoped name, which takes precedence when set and non-empty. ok
+// reports whether the legacy variable is set, even if empty, or the scoped
+// one is non-empty.
+func lookupEnv(legacy string) (value string, ok bool) {
+	if scoped := scopedEnvNames[legacy]; scoped != "" {
+		if value = os.Getenv(scoped); value != "" {
+			return value, true
+		}
+	}
+
+	return os.LookupEnv(legacy)
+}
+
+// getEnv returns the value of the environment variable legacy or its
+// section-scoped name, as lookupEnv, or "" if neither is set.
+func getEnv(legacy string) string {
+	value, _ := lookupEnv(legacy)
+
+	return value
+}
+
+// envSet reports whether the environment variable legacy is set, even if
+// empty, or its section-scoped name is set to a non-empty value.
+func envSet(legacy string) bool {
+	_, ok := lookupEnv(legacy)
+
+	return ok
+}
+
+// parseListEnv parses a comma-separated list (e.g., disk paths or NTP servers)
+// from an environment variable.
 // It trims whitespace from each element and filters out empty strings.
-// Returns nil if no valid disk paths remain after filtering.
-func parseDisksEnv(v string) []string {
-	disks := strings.Split(v, ",")
+// Returns nil if no elements remain after filtering.
+func parseListEnv(v string) []string {
+	items := strings.Split(v, ",")
 
-	for i := range disks {
-		disks[i] = strings.TrimSpace(disks[i])
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
 	}
 
-	filtered := make([]string, 0, len(disks))
+	filtered := make([]string, 0, len(items))
 
-	for _, d := range disks {
-		if d != "" {
-			filtered = append(filtered, d)
+	for _, item := range items {
+		if item != "" {
+			filtered = append(filtered, item)
 		}
 	}
 
@@ -86,10 +205,39 @@ func parseDisksEnv(v string) []string {
 	return filtered
 }
 
+// ErrEnvValueInvalid is returned by CheckEnv for an environment variable
+// whose value cannot be parsed.
+var ErrEnvValueInvalid = errors.New("invalid environment variable")
+
+// CheckEnv reports environment variables whose values LoadFromEnv ignores
+// because they cannot be parsed, such as a non-integer SWAP_SIZE_MB.
+// The error names the variable as set (legacy or section-scoped) and wraps
+// ErrEnvValueInvalid. Returns nil if every set value parses.
+func CheckEnv() error {
+	if v := getEnv("SWAP_SIZE_MB"); v != "" {
+		if _, err := strconv.Atoi(strings.TrimSpace(v)); err != nil {
+			return fmt.Errorf("%w %s=%q: must be an integer", ErrEnvValueInvalid, envName("SWAP_SIZE_MB"), v)
+		}
+	}
+
+	return nil
+}
+
+// envName returns the name lookupEnv reads legacy from: its section-scoped
+// name when that is set and non-empty, otherwise legacy itself.
+func envName(legacy string) string {
+	if scoped := scopedEnvNames[legacy]; scoped != "" && os.Getenv(scoped) != "" {
+		return scoped
+	}
+
+	return legacy
+}
+
 // LoadFromEnv loads configuration values from environment variables into cfg.
 // Only non-empty environment variable values override existing configuration;
-// empty or unset variables leave the current values unchanged.
-// Sensitive fields (RootPassword, SSHPublicKey, TailscaleAuthKey) are loaded
+// empty or unset variables leave the current values unchanged. Values that
+// cannot be parsed are ignored as well; CheckEnv reports them.
+// Sensitive fields (RootPassword, SSHPublicKey, TailscaleAuthKey, ClusterPassword) are loaded
 // from env but are never persisted to configuration files.
 func LoadFromEnv(cfg *Config) {
 	if cfg == nil {
@@ -100,9 +248,12 @@ func LoadFromEnv(cfg *Config) {
 	loadNetworkEnv(cfg)
 	loadStorageEnv(cfg)
 	loadTailscaleEnv(cfg)
+	loadClusterEnv(cfg)
+	loadACMEEnv(cfg)
 }
 
 // loadSystemEnv loads system configuration from environment variables.
+// Boolean fields use EnvVarSet to distinguish unset from "false".
 func loadSystemEnv(cfg *Config) {
 	if v := os.Getenv("PVE_HOSTNAME"); v != "" {
 		cfg.System.Hostname = v
@@ -127,59 +278,162 @@ func loadSystemEnv(cfg *Config) {
 	if v := os.Getenv("PVE_SSH_PUBLIC_KEY"); v != "" {
 		cfg.System.SSHPublicKey = v
 	}
+
+	if EnvVarSet("PVE_REBOOT_AFTER_INSTALL") {
+		cfg.System.RebootAfterInstall = parseBool(os.Getenv("PVE_REBOOT_AFTER_INSTALL"))
+	}
+
+	if EnvVarSet("PVE_UNATTENDED_UPGRADES") {
+		cfg.System.EnableUnattendedUpgrades = parseBool(os.Getenv("PVE_UNATTENDED_UPGRADES"))
+	}
+
+	if v := os.Getenv("PVE_KEYBOARD"); v != "" {
+		cfg.System.Keyboard = v
+	}
+
+	if v := os.Getenv("PVE_LOCALE"); v != "" {
+		cfg.System.Locale = v
+	}
+
+	if EnvVarSet("PVE_SSH_DISABLE_PASSWORD_AUTH") {
+		cfg.System.SSHDisablePasswordAuth = parseBool(os.Getenv("PVE_SSH_DISABLE_PASSWORD_AUTH"))
+	}
+
+	if v := os.Getenv("PVE_SSH_PERMIT_ROOT_LOGIN"); v != "" {
+		cfg.System.SSHPermitRootLogin = strings.ToLower(v)
+	}
+
+	if v := os.Getenv("PVE_PROXMOX_VERSION"); v != "" {
+		cfg.System.ProxmoxVersion = v
+	}
+
+	if v := os.Getenv("PVE_ADMIN_USER"); v != "" {
+		cfg.System.AdminUser = v
+	}
+
+	if v := os.Getenv("PVE_ADMIN_SSH_KEYS"); v != "" {
+		if keys := parseListEnv(v); keys != nil {
+			cfg.System.AdminSSHKeys = keys
+		}
+	}
+
+	if v := getEnv("NTP_SERVERS"); v != "" {
+		if servers := parseListEnv(v); servers != nil {
+			cfg.System.NTPServers = servers
+		}
+	}
 }
 
 // loadNetworkEnv loads network configuration from environment variables.
+// Here and in the other non-system loaders, getEnv and envSet also honor
+// the section-scoped names.
 func loadNetworkEnv(cfg *Config) {
-	if v := os.Getenv("INTERFACE_NAME"); v != "" {
+	if v := getEnv("INTERFACE_NAME"); v != "" {
 		cfg.Network.InterfaceName = v
 	}
 
-	if v := os.Getenv("BRIDGE_MODE"); v != "" {
+	if v := getEnv("BRIDGE_MODE"); v != "" {
 		mode := BridgeMode(strings.ToLower(v))
 		if mode.IsValid() {
 			cfg.Network.BridgeMode = mode
 		}
 	}
 
-	if v := os.Getenv("PRIVATE_SUBNET"); v != "" {
+	if v := getEnv("PRIVATE_SUBNET"); v != "" {
 		cfg.Network.PrivateSubnet = v
 	}
+
+	if v := getEnv("ADDITIONAL_SUBNET"); v != "" {
+		cfg.Network.AdditionalSubnet = v
+	}
+
+	if v := getEnv("BRIDGE_MAC"); v != "" {
+		cfg.Network.BridgeMAC = v
+	}
+
+	if v := getEnv("NETWORK_BACKEND"); v != "" {
+		backend := NetworkBackend(strings.ToLower(v))
+		if backend.IsValid() {
+			cfg.Network.NetworkBackend = backend
+		}
+	}
 }
 
 // loadStorageEnv loads storage configuration from environment variables.
 func loadStorageEnv(cfg *Config) {
-	if v := os.Getenv("ZFS_RAID"); v != "" {
+	if v := getEnv("ZFS_RAID"); v != "" {
 		raid := ZFSRaid(strings.ToLower(v))
 		if raid.IsValid() {
 			cfg.Storage.ZFSRaid = raid
 		}
 	}
 
-	if v := os.Getenv("DISKS"); v != "" {
-		if disks := parseDisksEnv(v); disks != nil {
+	if v := getEnv("DISKS"); v != "" {
+		if disks := parseListEnv(v); disks != nil {
 			cfg.Storage.Disks = disks
 		}
 	}
+
+	if v := getEnv("SWAP_SIZE_MB"); v != "" {
+		if size, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			cfg.Storage.SwapSizeMB = size
+		}
+	}
+
+	if envSet("PRESERVE_ESP") {
+		cfg.Storage.PreserveESP = parseBool(getEnv("PRESERVE_ESP"))
+	}
 }
 
 // loadTailscaleEnv loads Tailscale configuration from environment variables.
-// Boolean fields use EnvVarSet to distinguish unset from "false".
+// Boolean fields use envSet to distinguish unset from "false".
 // TAILSCALE_AUTH_KEY is a sensitive field loaded from env but never persisted.
 func loadTailscaleEnv(cfg *Config) {
-	if EnvVarSet("INSTALL_TAILSCALE") {
-		cfg.Tailscale.Enabled = parseBool(os.Getenv("INSTALL_TAILSCALE"))
+	if envSet("INSTALL_TAILSCALE") {
+		cfg.Tailscale.Enabled = parseBool(getEnv("INSTALL_TAILSCALE"))
 	}
 
-	if v := os.Getenv("TAILSCALE_AUTH_KEY"); v != "" {
+	if v := getEnv("TAILSCALE_AUTH_KEY"); v != "" {
 		cfg.Tailscale.AuthKey = v
 	}
 
-	if EnvVarSet("TAILSCALE_SSH") {
-		cfg.Tailscale.SSH = parseBool(os.Getenv("TAILSCALE_SSH"))
+	if envSet("TAILSCALE_SSH") {
+		cfg.Tailscale.SSH = parseBool(getEnv("TAILSCALE_SSH"))
+	}
+
+	if envSet("TAILSCALE_WEBUI") {
+		cfg.Tailscale.WebUI = parseBool(getEnv("TAILSCALE_WEBUI"))
+	}
+}
+
+// loadClusterEnv loads cluster join configuration from environment variables.
+// CLUSTER_PASSWORD is a sensitive field loaded from env but never persisted.
+func loadClusterEnv(cfg *Config) {
+	if v := getEnv("CLUSTER_JOIN_ADDRESS"); v != "" {
+		cfg.Cluster.JoinAddress = v
+	}
+
+	if v := getEnv("CLUSTER_FINGERPRINT"); v != "" {
+		cfg.Cluster.Fingerprint = v
+	}
+
+	if v := getEnv("CLUSTER_PASSWORD"); v != "" {
+		cfg.Cluster.Password = v
+	}
+}
+
+// loadACMEEnv loads ACME configuration from environment variables.
+// Boolean fields use envSet to distinguish unset from "false".
+func loadACMEEnv(cfg *Config) {
+	if envSet("ACME_ENABLED") {
+		cfg.ACME.Enabled = parseBool(getEnv("ACME_ENABLED"))
+	}
+
+	if v := getEnv("ACME_EMAIL"); v != "" {
+		cfg.ACME.Email = v
 	}
 
-	if EnvVarSet("TAILSCALE_WEBUI") {
-		cfg.Tailscale.WebUI = parseBool(os.Getenv("TAILSCALE_WEBUI"))
+	if envSet("ACME_STAGING") {
+		cfg.ACME.Staging = parseBool(getEnv("ACME_STAGING"))
 	}
 }
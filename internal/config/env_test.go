@@ -1,8 +1,13 @@
 package config
 
 import (
+	"errors"
 	"os"
+	"slices"
+	"strings"
 	"testing"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/testutil"
 )
 
 func TestParseBool(t *testing.T) {
@@ -113,9 +118,7 @@ func TestEnvVarSet(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Ensure the variable is unset before the test
-			if err := os.Unsetenv(tt.envName); err != nil {
-				t.Fatalf("failed to unset env var %q: %v", tt.envName, err)
-			}
+			testutil.ClearEnv(t, tt.envName)
 
 			if tt.setValue != nil {
 				t.Setenv(tt.envName, *tt.setValue)
@@ -217,6 +220,8 @@ func TestLoadFromEnvSystemFields(t *testing.T) {
 		{"PVE_EMAIL", testEmail, func(c *Config) string { return c.System.Email }},
 		{"PVE_ROOT_PASSWORD", testPassword, func(c *Config) string { return c.System.RootPassword }},
 		{"PVE_SSH_PUBLIC_KEY", testSSHKey, func(c *Config) string { return c.System.SSHPublicKey }},
+		{"PVE_KEYBOARD", "de-ch", func(c *Config) string { return c.System.Keyboard }},
+		{"PVE_LOCALE", "de_CH.UTF-8", func(c *Config) string { return c.System.Locale }},
 	}
 	for _, tt := range tests {
 		t.Run(tt.envName, func(t *testing.T) {
@@ -392,6 +397,29 @@ func TestLoadFromEnvBridgeModeInvalidEmptyPreserves(t *testing.T) {
 	}
 }
 
+func TestLoadFromEnvNetworkBackend(t *testing.T) {
+	tests := []struct {
+		input string
+		want  NetworkBackend
+	}{
+		{"ifupdown2", NetworkBackendIfupdown2},
+		{"networkd", NetworkBackendNetworkd},
+		{"Networkd", NetworkBackendNetworkd},
+		{"NETWORKD", NetworkBackendNetworkd},
+		{"netplan", NetworkBackendIfupdown2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			cfg := DefaultConfig()
+			t.Setenv("NETWORK_BACKEND", tt.input)
+			LoadFromEnv(cfg)
+			if cfg.Network.NetworkBackend != tt.want {
+				t.Errorf("NetworkBackend = %q, want %q", cfg.Network.NetworkBackend, tt.want)
+			}
+		})
+	}
+}
+
 func TestLoadFromEnvPrivateSubnet(t *testing.T) {
 	cfg := DefaultConfig()
 	t.Setenv("PRIVATE_SUBNET", testPrivateSubnet)
@@ -401,6 +429,68 @@ func TestLoadFromEnvPrivateSubnet(t *testing.T) {
 	}
 }
 
+func TestLoadFromEnvSystemBools(t *testing.T) {
+	fields := []struct {
+		envName string
+		field   func(*Config) *bool
+	}{
+		{"PVE_REBOOT_AFTER_INSTALL", func(c *Config) *bool { return &c.System.RebootAfterInstall }},
+		{"PVE_UNATTENDED_UPGRADES", func(c *Config) *bool { return &c.System.EnableUnattendedUpgrades }},
+	}
+
+	tests := []struct {
+		name     string
+		envValue *string
+		initial  bool
+		want     bool
+	}{
+		{"unset keeps false", nil, false, false},
+		{"unset keeps true", nil, true, true},
+		{"true", ptrString("true"), false, true},
+		{"yes uppercase", ptrString("YES"), false, true},
+		{"one", ptrString("1"), false, true},
+		{"false", ptrString("false"), true, false},
+		{"empty is false", ptrString(""), true, false},
+	}
+
+	for _, f := range fields {
+		for _, tt := range tests {
+			t.Run(f.envName+" "+tt.name, func(t *testing.T) {
+				cfg := DefaultConfig()
+				*f.field(cfg) = tt.initial
+
+				if tt.envValue != nil {
+					t.Setenv(f.envName, *tt.envValue)
+				}
+
+				LoadFromEnv(cfg)
+
+				if got := *f.field(cfg); got != tt.want {
+					t.Errorf("%s: got %v, want %v", f.envName, got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestLoadFromEnvAdditionalSubnet(t *testing.T) {
+	cfg := DefaultConfig()
+	t.Setenv("ADDITIONAL_SUBNET", testAdditionalSubnet)
+	LoadFromEnv(cfg)
+	if cfg.Network.AdditionalSubnet != testAdditionalSubnet {
+		t.Errorf("AdditionalSubnet = %q, want %q", cfg.Network.AdditionalSubnet, testAdditionalSubnet)
+	}
+}
+
+func TestLoadFromEnvBridgeMAC(t *testing.T) {
+	cfg := DefaultConfig()
+	t.Setenv("BRIDGE_MAC", testBridgeMAC)
+	LoadFromEnv(cfg)
+	if cfg.Network.BridgeMAC != testBridgeMAC {
+		t.Errorf("BridgeMAC = %q, want %q", cfg.Network.BridgeMAC, testBridgeMAC)
+	}
+}
+
 func TestLoadFromEnvNetworkEmptyPreservesOriginal(t *testing.T) {
 	tests := []struct {
 		envName string
@@ -408,6 +498,10 @@ func TestLoadFromEnvNetworkEmptyPreservesOriginal(t *testing.T) {
 		check   func(*Config) bool
 	}{
 		{"PRIVATE_SUBNET", nil, func(c *Config) bool { return c.Network.PrivateSubnet != "" }},
+		{"ADDITIONAL_SUBNET", func(c *Config) { c.Network.AdditionalSubnet = testAdditionalSubnet },
+			func(c *Config) bool { return c.Network.AdditionalSubnet == testAdditionalSubnet }},
+		{"BRIDGE_MAC", func(c *Config) { c.Network.BridgeMAC = testBridgeMAC },
+			func(c *Config) bool { return c.Network.BridgeMAC == testBridgeMAC }},
 		{"INTERFACE_NAME", func(c *Config) { c.Network.InterfaceName = testInterfaceEnp },
 			func(c *Config) bool { return c.Network.InterfaceName == testInterfaceEnp }},
 	}
@@ -605,6 +699,168 @@ func TestLoadFromEnvStorageMultipleFields(t *testing.T) {
 	assertDisksEqual(t, cfg.Storage.Disks, []string{testDiskSda, testDiskSdb, testDiskSdc})
 }
 
+func TestLoadFromEnvNTPServers(t *testing.T) {
+	twoServers := []string{"ntp1.hetzner.de", "time.example.com"}
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"single server", "ntp1.hetzner.de", []string{"ntp1.hetzner.de"}},
+		{"two servers", "ntp1.hetzner.de,time.example.com", twoServers},
+		{"ip address", "192.0.2.123", []string{"192.0.2.123"}}, // NOSONAR(go:S1313) documentation range - test data
+		{"with spaces", "ntp1.hetzner.de , time.example.com", twoServers},
+		{"tabs and spaces", "ntp1.hetzner.de\t,\ttime.example.com", twoServers},
+		{"trailing comma", "ntp1.hetzner.de,time.example.com,", twoServers},
+		{"leading comma", ",ntp1.hetzner.de,time.example.com", twoServers},
+		{"consecutive commas", "ntp1.hetzner.de,,time.example.com", twoServers},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			t.Setenv("NTP_SERVERS", tt.input)
+			LoadFromEnv(cfg)
+			assertDisksEqual(t, cfg.System.NTPServers, tt.want)
+		})
+	}
+}
+
+func TestLoadFromEnvNTPServersKeepsOriginal(t *testing.T) {
+	original := []string{"time.example.com"}
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{testCaseEmptyString, ""},
+		{"only commas", ",,,"},
+		{"only spaces", "   "},
+		{"only spaces and commas", " , , "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.System.NTPServers = original
+			t.Setenv("NTP_SERVERS", tt.input)
+			LoadFromEnv(cfg)
+			assertDisksEqual(t, cfg.System.NTPServers, original)
+		})
+	}
+}
+
+func TestLoadFromEnvAdminUser(t *testing.T) {
+	cfg := DefaultConfig()
+	t.Setenv("PVE_ADMIN_USER", "admin")
+	t.Setenv("PVE_ADMIN_SSH_KEYS", testValidSSHKey+", ssh-rsa AAAAB3NzaC1yc2E ops@laptop")
+	LoadFromEnv(cfg)
+
+	if cfg.System.AdminUser != "admin" {
+		t.Errorf("AdminUser = %q, want %q", cfg.System.AdminUser, "admin")
+	}
+
+	assertDisksEqual(t, cfg.System.AdminSSHKeys, []string{testValidSSHKey, "ssh-rsa AAAAB3NzaC1yc2E ops@laptop"})
+}
+
+func TestLoadFromEnvPreserveESP(t *testing.T) {
+	tests := []struct {
+		name     string
+		envValue string
+		initial  bool
+		want     bool
+	}{
+		{"true", "true", false, true},
+		{"yes", "yes", false, true},
+		{"one", "1", false, true},
+		{"uppercase TRUE", "TRUE", false, true},
+		{"false overrides true", "false", true, false},
+		{"no overrides true", "no", true, false},
+		{"empty is false", "", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Storage.PreserveESP = tt.initial
+			t.Setenv("PRESERVE_ESP", tt.envValue)
+			LoadFromEnv(cfg)
+
+			if cfg.Storage.PreserveESP != tt.want {
+				t.Errorf("PreserveESP = %v, want %v", cfg.Storage.PreserveESP, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFromEnvSwapSizeMB(t *testing.T) {
+	tests := []struct {
+		name     string
+		envValue string
+		want     int
+	}{
+		{"plain value", "4096", 4096},
+		{"zero disables swap", "0", 0},
+		{"surrounding whitespace", " 2048 ", 2048},
+		{"negative is loaded for validation", "-1", -1},
+		{"non-numeric keeps original", "lots", 1024},
+		{"float keeps original", "1.5", 1024},
+		{"empty keeps original", "", 1024},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Storage.SwapSizeMB = 1024
+
+			t.Setenv("SWAP_SIZE_MB", tt.envValue)
+			LoadFromEnv(cfg)
+
+			if cfg.Storage.SwapSizeMB != tt.want {
+				t.Errorf("SwapSizeMB = %d, want %d", cfg.Storage.SwapSizeMB, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		legacy  string
+		scoped  string
+		wantErr string
+	}{
+		{"unset", "", "", ""},
+		{"valid", "4096", "", ""},
+		{"surrounding whitespace", " 2048 ", "", ""},
+		{"non-numeric", "lots", "", `SWAP_SIZE_MB="lots": must be an integer`},
+		{"float", "1.5", "", `SWAP_SIZE_MB="1.5": must be an integer`},
+		{"scoped name reported", "4096", "4G", `PVE_STORAGE_SWAP_SIZE_MB="4G": must be an integer`},
+		{"empty scoped name ignored", "4G", "", `SWAP_SIZE_MB="4G": must be an integer`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SWAP_SIZE_MB", tt.legacy)
+			t.Setenv("PVE_STORAGE_SWAP_SIZE_MB", tt.scoped)
+
+			err := CheckEnv()
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("CheckEnv() = %v, want nil", err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, ErrEnvValueInvalid) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CheckEnv() = %v, want ErrEnvValueInvalid containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 // Tailscale configuration tests
 
 // Error format strings for Tailscale tests.
@@ -804,12 +1060,12 @@ func TestLoadFromEnvPartialConfiguration(t *testing.T) {
 	cfg := DefaultConfig()
 
 	// Clear env vars that would interfere with testing
-	clearEnvForTest(t, []string{
+	testutil.ClearEnv(t,
 		"PVE_DOMAIN_SUFFIX", "PVE_TIMEZONE", "PVE_EMAIL",
 		"PVE_ROOT_PASSWORD", "PVE_SSH_PUBLIC_KEY",
 		"BRIDGE_MODE", "PRIVATE_SUBNET", "ZFS_RAID", "DISKS", "TAILSCALE_AUTH_KEY",
 		"INSTALL_TAILSCALE", "TAILSCALE_SSH", "TAILSCALE_WEBUI",
-	})
+	)
 
 	// Only set a subset of environment variables
 	t.Setenv("PVE_HOSTNAME", "partial-server")
@@ -973,7 +1229,7 @@ func TestLoadFromEnvEmptyVsUnset(t *testing.T) {
 				t.Setenv(tt.envName, *tt.setValue)
 			} else {
 				// Ensure env var is unset for this test
-				unsetEnvWithCleanup(t, tt.envName)
+				testutil.ClearEnv(t, tt.envName)
 			}
 
 			LoadFromEnv(cfg)
@@ -1063,46 +1319,6 @@ func TestLoadFromEnvBooleanWhitespaceHandling(t *testing.T) {
 // TestLoadFromEnvDisksKeepsOriginal. Enum case variations are covered by
 // TestLoadFromEnvBridgeModeValues and TestLoadFromEnvZFSRaidValues.
 
-// clearEnvForTest clears environment variables for test isolation.
-// String vars are set to empty, boolean vars are unset and restored on cleanup.
-func clearEnvForTest(t *testing.T, envVars []string) {
-	t.Helper()
-
-	boolVars := map[string]bool{
-		"INSTALL_TAILSCALE": true,
-		"TAILSCALE_SSH":     true,
-		"TAILSCALE_WEBUI":   true,
-	}
-
-	for _, envName := range envVars {
-		if boolVars[envName] {
-			unsetEnvWithCleanup(t, envName)
-		} else {
-			t.Setenv(envName, "")
-		}
-	}
-}
-
-// unsetEnvWithCleanup unsets an environment variable and restores it on cleanup.
-func unsetEnvWithCleanup(t *testing.T, envName string) {
-	t.Helper()
-
-	originalVal, wasSet := os.LookupEnv(envName)
-	if !wasSet {
-		return
-	}
-
-	if err := os.Unsetenv(envName); err != nil {
-		t.Fatalf("failed to unset %s: %v", envName, err)
-	}
-
-	t.Cleanup(func() {
-		// Restore original value. Error ignored as this is cleanup code.
-		//nolint:errcheck,usetesting // cleanup code, t.Setenv not available
-		os.Setenv(envName, originalVal)
-	})
-}
-
 // envVarTestCase defines a test case for environment variable loading.
 type envVarTestCase struct {
 	name          string
@@ -1194,7 +1410,7 @@ func TestLoadFromEnvAllFieldsIndependent(t *testing.T) {
 	for i, ev := range envVars {
 		t.Run(ev.name, func(t *testing.T) {
 			cfg := createTestConfig()
-			clearEnvForTest(t, allEnvVars)
+			testutil.ClearEnv(t, allEnvVars...)
 			t.Setenv(ev.name, ev.value)
 
 			LoadFromEnv(cfg)
@@ -1259,13 +1475,13 @@ const (
 // Each layer properly overrides the previous one.
 func TestConfigPriorityChain(t *testing.T) {
 	// Clear relevant env vars to ensure test isolation
-	clearEnvForTest(t, []string{
+	testutil.ClearEnv(t,
 		"PVE_HOSTNAME", "PVE_DOMAIN_SUFFIX", "PVE_TIMEZONE", "PVE_EMAIL",
 		"PVE_ROOT_PASSWORD", "PVE_SSH_PUBLIC_KEY",
 		"INTERFACE_NAME", "BRIDGE_MODE", "PRIVATE_SUBNET",
 		"ZFS_RAID", "DISKS",
 		"INSTALL_TAILSCALE", "TAILSCALE_AUTH_KEY", "TAILSCALE_SSH", "TAILSCALE_WEBUI",
-	})
+	)
 
 	// Step 1: Start with defaults
 	cfg := DefaultConfig()
@@ -1368,11 +1584,11 @@ func TestPriorityChainPartialOverride(t *testing.T) {
 	assertStringField(t, "Default ZFSRaid", string(cfg.Storage.ZFSRaid), string(defaultCfg.Storage.ZFSRaid))
 
 	// Clear env vars that would interfere
-	clearEnvForTest(t, []string{
+	testutil.ClearEnv(t,
 		"PVE_HOSTNAME", "PVE_DOMAIN_SUFFIX", "PVE_TIMEZONE",
 		"PVE_EMAIL", "INTERFACE_NAME", "BRIDGE_MODE", "PRIVATE_SUBNET",
 		"ZFS_RAID", "DISKS", "INSTALL_TAILSCALE", "TAILSCALE_SSH", "TAILSCALE_WEBUI",
-	})
+	)
 
 	// Set only hostname via env
 	t.Setenv("PVE_HOSTNAME", priorityEnvHostname)
@@ -1421,9 +1637,7 @@ func TestPriorityChainBooleanFields(t *testing.T) {
 	assertBoolField(t, "File Tailscale.WebUI", cfg.Tailscale.WebUI, true)
 
 	// Clear env vars to start fresh
-	unsetEnvWithCleanup(t, "INSTALL_TAILSCALE")
-	unsetEnvWithCleanup(t, "TAILSCALE_SSH")
-	unsetEnvWithCleanup(t, "TAILSCALE_WEBUI")
+	testutil.ClearEnv(t, "INSTALL_TAILSCALE", "TAILSCALE_SSH", "TAILSCALE_WEBUI")
 
 	// Set only SSH via env (flip it)
 	t.Setenv("TAILSCALE_SSH", "true")
@@ -1473,13 +1687,13 @@ storage:
 	}
 
 	// Clear interfering env vars
-	clearEnvForTest(t, []string{
+	testutil.ClearEnv(t,
 		"PVE_HOSTNAME", "PVE_DOMAIN_SUFFIX", "PVE_TIMEZONE", "PVE_EMAIL",
 		"PVE_ROOT_PASSWORD", "PVE_SSH_PUBLIC_KEY",
 		"INTERFACE_NAME", "BRIDGE_MODE", "PRIVATE_SUBNET",
 		"ZFS_RAID", "DISKS",
 		"INSTALL_TAILSCALE", "TAILSCALE_AUTH_KEY", "TAILSCALE_SSH", "TAILSCALE_WEBUI",
-	})
+	)
 
 	// Set some env vars (timezone, private subnet, tailscale)
 	t.Setenv("PVE_TIMEZONE", priorityEnvTimezone)
@@ -1598,7 +1812,7 @@ storage:
 	assertStringField(t, "File ZFSRaid", string(cfg.Storage.ZFSRaid), string(ZFSRaidSingle))
 
 	// Clear env vars
-	clearEnvForTest(t, []string{"BRIDGE_MODE", "ZFS_RAID"})
+	testutil.ClearEnv(t, "BRIDGE_MODE", "ZFS_RAID")
 
 	// Set env vars to different enum values
 	t.Setenv("BRIDGE_MODE", "external")
@@ -1644,7 +1858,7 @@ func TestPriorityChainDisksSlice(t *testing.T) {
 	assertDisksEqual(t, cfg.Storage.Disks, []string{"/dev/sda", "/dev/sdb"})
 
 	// Clear DISKS env var
-	clearEnvForTest(t, []string{"DISKS"})
+	testutil.ClearEnv(t, "DISKS")
 
 	// Set env var to different disks
 	t.Setenv("DISKS", "/dev/nvme0n1,/dev/nvme1n1")
@@ -1658,3 +1872,204 @@ func TestPriorityChainDisksSlice(t *testing.T) {
 	cfg.Storage.Disks = []string{testDiskVda}
 	assertDisksEqual(t, cfg.Storage.Disks, []string{testDiskVda})
 }
+
+func TestLoadFromEnvCluster(t *testing.T) {
+	t.Setenv("CLUSTER_JOIN_ADDRESS", testClusterJoinAddress)
+	t.Setenv("CLUSTER_FINGERPRINT", testClusterFingerprint)
+	t.Setenv("CLUSTER_PASSWORD", testClusterPassword)
+
+	cfg := DefaultConfig()
+	LoadFromEnv(cfg)
+
+	want := ClusterConfig{
+		JoinAddress: testClusterJoinAddress,
+		Fingerprint: testClusterFingerprint,
+		Password:    testClusterPassword,
+	}
+	if cfg.Cluster != want {
+		t.Errorf("Cluster = %+v, want %+v", cfg.Cluster, want)
+	}
+}
+
+func TestLoadFromEnvClusterEmptyPreservesOriginal(t *testing.T) {
+	t.Setenv("CLUSTER_JOIN_ADDRESS", "")
+
+	cfg := DefaultConfig()
+	cfg.Cluster.JoinAddress = testClusterJoinAddress
+	LoadFromEnv(cfg)
+
+	if cfg.Cluster.JoinAddress != testClusterJoinAddress {
+		t.Errorf("JoinAddress = %q, want %q", cfg.Cluster.JoinAddress, testClusterJoinAddress)
+	}
+}
+
+func TestLoadFromEnvACME(t *testing.T) {
+	t.Setenv("ACME_ENABLED", "true")
+	t.Setenv("ACME_EMAIL", "certs@example.com")
+	t.Setenv("ACME_STAGING", "1")
+
+	cfg := DefaultConfig()
+	LoadFromEnv(cfg)
+
+	want := ACMEConfig{Enabled: true, Email: "certs@example.com", Staging: true}
+	if cfg.ACME != want {
+		t.Errorf("ACME = %+v, want %+v", cfg.ACME, want)
+	}
+}
+
+func TestLoadFromEnvACMEFalseOverridesTrue(t *testing.T) {
+	t.Setenv("ACME_ENABLED", "false")
+	t.Setenv("ACME_STAGING", "no")
+
+	cfg := DefaultConfig()
+	cfg.ACME.Enabled = true
+	cfg.ACME.Staging = true
+	LoadFromEnv(cfg)
+
+	if cfg.ACME.Enabled || cfg.ACME.Staging {
+		t.Errorf("ACME = %+v, want Enabled and Staging false", cfg.ACME)
+	}
+}
+
+func TestLoadFromEnvACMEUnsetPreservesOriginal(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ACME = ACMEConfig{Enabled: true, Email: "certs@example.com", Staging: true}
+	want := cfg.ACME
+
+	LoadFromEnv(cfg)
+
+	if cfg.ACME != want {
+		t.Errorf("ACME = %+v, want %+v", cfg.ACME, want)
+	}
+}
+
+func TestScopedEnvName(t *testing.T) {
+	tests := []struct {
+		legacy   string
+		expected string
+	}{
+		{"INTERFACE_NAME", "PVE_NETWORK_INTERFACE"},
+		{"BRIDGE_MODE", "PVE_NETWORK_BRIDGE_MODE"},
+		{"ZFS_RAID", "PVE_STORAGE_ZFS_RAID"},
+		{"DISKS", "PVE_STORAGE_DISKS"},
+		{"INSTALL_TAILSCALE", "PVE_TAILSCALE_ENABLED"},
+		{"TAILSCALE_AUTH_KEY", "PVE_TAILSCALE_AUTH_KEY"},
+		{"CLUSTER_PASSWORD", "PVE_CLUSTER_PASSWORD"},
+		{"ACME_EMAIL", "PVE_ACME_EMAIL"},
+		{"PVE_HOSTNAME", ""},
+		{"NTP_SERVERS", "PVE_SYSTEM_NTP_SERVERS"},
+		{"UNKNOWN", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.legacy, func(t *testing.T) {
+			if got := ScopedEnvName(tt.legacy); got != tt.expected {
+				t.Errorf("ScopedEnvName(%q) = %q, want %q", tt.legacy, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoadFromEnvScopedNames(t *testing.T) {
+	testutil.ClearEnv(t, "BRIDGE_MODE", "DISKS", "SWAP_SIZE_MB", "INSTALL_TAILSCALE", "TAILSCALE_AUTH_KEY")
+	t.Setenv("PVE_NETWORK_BRIDGE_MODE", "external")
+	t.Setenv("PVE_STORAGE_DISKS", testDiskNvme0+", "+testDiskNvme1)
+	t.Setenv("PVE_STORAGE_SWAP_SIZE_MB", "4096")
+	t.Setenv("PVE_TAILSCALE_ENABLED", "yes")
+	t.Setenv("PVE_TAILSCALE_AUTH_KEY", "tskey-scoped")
+
+	cfg := DefaultConfig()
+	LoadFromEnv(cfg)
+
+	if cfg.Network.BridgeMode != BridgeModeExternal {
+		t.Errorf(errFmtBridgeMode, cfg.Network.BridgeMode, BridgeModeExternal)
+	}
+
+	assertDisksEqual(t, cfg.Storage.Disks, []string{testDiskNvme0, testDiskNvme1})
+
+	if cfg.Storage.SwapSizeMB != 4096 {
+		t.Errorf("SwapSizeMB = %d, want 4096", cfg.Storage.SwapSizeMB)
+	}
+
+	if !cfg.Tailscale.Enabled {
+		t.Error("Tailscale.Enabled = false, want true")
+	}
+
+	if cfg.Tailscale.AuthKey != "tskey-scoped" {
+		t.Errorf("Tailscale.AuthKey = %q, want %q", cfg.Tailscale.AuthKey, "tskey-scoped")
+	}
+}
+
+func TestLoadFromEnvLegacyNamesStillWork(t *testing.T) {
+	testutil.ClearEnv(t, "PVE_NETWORK_BRIDGE_MODE", "PVE_STORAGE_DISKS", "PVE_TAILSCALE_ENABLED")
+	t.Setenv("BRIDGE_MODE", "both")
+	t.Setenv("DISKS", testDiskSda+","+testDiskSdb)
+	t.Setenv("INSTALL_TAILSCALE", "true")
+
+	cfg := DefaultConfig()
+	LoadFromEnv(cfg)
+
+	if cfg.Network.BridgeMode != BridgeModeBoth {
+		t.Errorf(errFmtBridgeMode, cfg.Network.BridgeMode, BridgeModeBoth)
+	}
+
+	assertDisksEqual(t, cfg.Storage.Disks, []string{testDiskSda, testDiskSdb})
+
+	if !cfg.Tailscale.Enabled {
+		t.Error("Tailscale.Enabled = false, want true")
+	}
+}
+
+func TestLoadFromEnvScopedNameWinsOverLegacy(t *testing.T) {
+	t.Setenv("BRIDGE_MODE", "both")
+	t.Setenv("PVE_NETWORK_BRIDGE_MODE", "external")
+	t.Setenv("DISKS", testDiskSda)
+	t.Setenv("PVE_STORAGE_DISKS", testDiskVda)
+	t.Setenv("INSTALL_TAILSCALE", "true")
+	t.Setenv("PVE_TAILSCALE_ENABLED", "false")
+
+	cfg := DefaultConfig()
+	LoadFromEnv(cfg)
+
+	if cfg.Network.BridgeMode != BridgeModeExternal {
+		t.Errorf(errFmtBridgeMode, cfg.Network.BridgeMode, BridgeModeExternal)
+	}
+
+	assertDisksEqual(t, cfg.Storage.Disks, []string{testDiskVda})
+
+	// A set scoped boolean wins even when it is false.
+	if cfg.Tailscale.Enabled {
+		t.Error("Tailscale.Enabled = true, want false from PVE_TAILSCALE_ENABLED")
+	}
+}
+
+func TestLoadFromEnvEmptyScopedNameFallsBackToLegacy(t *testing.T) {
+	t.Setenv("BRIDGE_MODE", "both")
+	t.Setenv("PVE_NETWORK_BRIDGE_MODE", "")
+	t.Setenv("INSTALL_TAILSCALE", "true")
+	t.Setenv("PVE_TAILSCALE_ENABLED", "")
+
+	cfg := DefaultConfig()
+	LoadFromEnv(cfg)
+
+	if cfg.Network.BridgeMode != BridgeModeBoth {
+		t.Errorf(errFmtBridgeMode, cfg.Network.BridgeMode, BridgeModeBoth)
+	}
+
+	if !cfg.Tailscale.Enabled {
+		t.Error("Tailscale.Enabled = false, want true from INSTALL_TAILSCALE")
+	}
+}
+
+func TestLoadFromEnvScopedNTPServers(t *testing.T) {
+	t.Setenv("NTP_SERVERS", "ntp.example.com")
+	t.Setenv("PVE_SYSTEM_NTP_SERVERS", "time1.example.com, time2.example.com")
+
+	cfg := DefaultConfig()
+	LoadFromEnv(cfg)
+
+	want := []string{"time1.example.com", "time2.example.com"}
+	if !slices.Equal(cfg.System.NTPServers, want) {
+		t.Errorf("NTPServers = %v, want %v", cfg.System.NTPServers, want)
+	}
+}
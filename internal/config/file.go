@@ -29,10 +29,19 @@
 //   - System.RootPassword - Root password for installation
 //   - System.SSHPublicKey - SSH public key for authentication
 //   - Tailscale.AuthKey - Tailscale authentication key
+//   - Cluster.Password - Root password of the cluster node to join
 //
 // These fields must be provided via environment variables or TUI input
 // and remain in memory only during execution.
 //
+// # File Formats
+//
+// YAML is the primary format. JSON files are also accepted and use the same
+// keys as YAML. The format is detected from the file extension (.yaml, .yml,
+// .json). When the extension is missing or unknown (e.g., ".pve-install"),
+// the format given to LoadFromFileWithFormat is used, then the
+// PVE_CONFIG_FORMAT environment variable, and finally YAML.
+//
 // # Configuration Priority
 //
 // Values are resolved in this order (highest to lowest):
@@ -43,41 +52,159 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
-// LoadFromFile loads configuration from a YAML file at the specified path.
+// FileFormat identifies the serialization format of a configuration file.
+type FileFormat string
+
+const (
+	// FormatAuto detects the format from the file extension, falling back to
+	// PVE_CONFIG_FORMAT and then YAML.
+	FormatAuto FileFormat = ""
+	// FormatYAML is the YAML configuration format.
+	FormatYAML FileFormat = "yaml"
+	// FormatJSON is the JSON configuration format.
+	FormatJSON FileFormat = "json"
+)
+
+// configFormatEnv is the environment variable overriding the format of
+// files whose extension does not identify it.
+const configFormatEnv = "PVE_CONFIG_FORMAT"
+
+// ErrFileFormatInvalid is returned when a format override is not yaml or json.
+var ErrFileFormatInvalid = errors.New("config format must be one of: yaml, json")
+
+// parseFileFormat converts a user-supplied format name to a FileFormat.
+func parseFileFormat(s string) (FileFormat, error) {
+	switch FileFormat(strings.ToLower(strings.TrimSpace(s))) {
+	case FormatYAML, "yml":
+		return FormatYAML, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("%w: got %q", ErrFileFormatInvalid, s)
+	}
+}
+
+// detectFileFormat resolves the format for path. A recognized extension always wins;
+// otherwise override is used, then PVE_CONFIG_FORMAT, then YAML.
+func detectFileFormat(path string, override FileFormat) (FileFormat, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".json":
+		return FormatJSON, nil
+	}
+
+	if override != FormatAuto {
+		return parseFileFormat(string(override))
+	}
+
+	if v := os.Getenv(configFormatEnv); v != "" {
+		return parseFileFormat(v)
+	}
+
+	return FormatYAML, nil
+}
+
+// LoadFromFile loads configuration from a YAML or JSON file at the specified path.
 // It starts with DefaultConfig() values and overlays file contents on top.
 // Missing fields in the file retain their default values.
-// Returns an error if the file cannot be read or contains invalid YAML.
+// The format is detected as described for LoadFromFileWithFormat with FormatAuto.
+// Returns an error if the file cannot be read or cannot be parsed.
 func LoadFromFile(path string) (*Config, error) {
+	return LoadFromFileWithFormat(path, FormatAuto)
+}
+
+// LoadFromFileWithFormat loads configuration like LoadFromFile, using format
+// for files whose extension does not identify the format.
+// A recognized extension (.yaml, .yml, .json) always takes precedence.
+// With FormatAuto, PVE_CONFIG_FORMAT is consulted and YAML is the default.
+func LoadFromFileWithFormat(path string, format FileFormat) (*Config, error) {
 	// Start with default configuration
 	cfg := DefaultConfig()
 
+	if err := decodeFile(path, format, cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// OptionalPathPrefix marks a LoadFromFiles path as optional: "?host.yaml" is
+// skipped if host.yaml does not exist.
+const OptionalPathPrefix = "?"
+
+// LoadFromFiles loads a configuration layered from several files, such as a
+// base config followed by host-specific overrides. It starts with
+// DefaultConfig() and merges each file in order, later files winning; it is
+// LoadFromSources with a FileSource per path.
+//
+// Merging follows the TUI override semantics of BuildEffectiveConfig: only
+// fields set to a non-zero value in a file override earlier layers, so an
+// override file cannot reset a boolean to false or clear a value set by an
+// earlier file. A missing file is an error unless its path starts with
+// OptionalPathPrefix. Formats are detected per file as in LoadFromFile.
+func LoadFromFiles(paths ...string) (*Config, error) {
+	sources := make([]ConfigSource, len(paths))
+	for i, path := range paths {
+		sources[i] = FileSource{Path: path}
+	}
+
+	return LoadFromSources(sources...)
+}
+
+// decodeFile reads the YAML or JSON file at path, expanded with ExpandPath,
+// and overlays its contents onto cfg. The format is resolved as in
+// LoadFromFileWithFormat.
+func decodeFile(path string, format FileFormat, cfg *Config) error {
+	path, err := ExpandPath(path)
+	if err != nil {
+		return err
+	}
+
+	format, err = detectFileFormat(path, format)
+	if err != nil {
+		return err
+	}
+
 	// Read the file
 	data, err := os.ReadFile(path) //nolint:gosec // path is provided by caller
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("config file not found: %s: %w", path, err)
+			return fmt.Errorf("config file not found: %s: %w", path, err)
 		}
 
-		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	// JSON is a subset of YAML, so both formats are decoded with the YAML
+	// decoder to share field names and enum validation. JSON is checked for
+	// well-formedness first so YAML-only syntax is rejected.
+	if format == FormatJSON && !json.Valid(data) {
+		return fmt.Errorf("failed to parse JSON in %s: invalid JSON", path)
 	}
 
-	// Parse YAML and overlay onto defaults
+	// Parse and overlay onto cfg
 	if err := yaml.Unmarshal(data, cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML in %s: %w", path, err)
+		return fmt.Errorf("failed to parse %s in %s: %w", strings.ToUpper(string(format)), path, err)
 	}
 
-	return cfg, nil
+	return nil
 }
 
 // SaveToFile saves the configuration to a YAML file at the specified path.
-// Sensitive fields (RootPassword, SSHPublicKey, AuthKey) are excluded from the output.
+// Sensitive fields (RootPassword, SSHPublicKey, AuthKey, Cluster.Password) are excluded from the output.
 // Parent directories are created automatically with 0750 permissions.
 // The file is written with 0600 permissions for security.
 // The original Config instance is not modified.
@@ -91,6 +218,7 @@ func (c *Config) SaveToFile(path string) error {
 	safeCopy.System.RootPassword = ""
 	safeCopy.System.SSHPublicKey = ""
 	safeCopy.Tailscale.AuthKey = ""
+	safeCopy.Cluster.Password = ""
 
 	// Marshal to YAML
 	data, err := yaml.Marshal(&safeCopy)
@@ -111,3 +239,69 @@ func (c *Config) SaveToFile(path string) error {
 
 	return nil
 }
+
+// ValidateDir loads and validates every *.yaml and *.yml file in dir
+// (non-recursive), as used to check a fleet of per-host configuration files.
+//
+// The result maps each file name (without directory) to its load or
+// validation error, or nil if the file is valid. Files are checked with
+// ValidateStructure, so missing secrets are not reported.
+// Returns an error only if dir itself cannot be read.
+func ValidateDir(dir string) (map[string]error, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config directory %s: %w", dir, err)
+	}
+
+	results := make(map[string]error)
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+
+		cfg, err := LoadFromFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			results[entry.Name()] = err
+
+			continue
+		}
+
+		results[entry.Name()] = cfg.ValidateStructure()
+	}
+
+	return results, nil
+}
+
+// ValidateYAMLBytes checks user-submitted YAML (e.g., from a web form)
+// without loading it as the active configuration.
+//
+// The document is decoded strictly over DefaultConfig(), as LoadFromFile
+// would overlay it: unknown keys, including sensitive ones such as
+// root_password that files must not contain, and parse errors are returned
+// as err, as are invalid enum values, which are rejected while decoding just
+// as in LoadFromFile. A document that decodes is then checked with ValidateStructure,
+// and its field-level issues are returned as the *ValidationError.
+// An empty document is valid. Both results are nil when the YAML is valid.
+func ValidateYAMLBytes(data []byte) (*ValidationError, error) {
+	cfg := DefaultConfig()
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	var validationErr *ValidationError
+	if errors.As(cfg.ValidateStructure(), &validationErr) {
+		return validationErr, nil
+	}
+
+	return nil, nil //nolint:nilnil // (nil, nil) means the YAML is valid
+}
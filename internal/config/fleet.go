@@ -0,0 +1,40 @@
+package config
+
+import "slices"
+
+// CheckDiskConflicts returns, for every disk path referenced by more than one
+// of configs, the sorted names of the configs referencing it. Configs are
+// keyed by name (e.g., host or file name); nil configs are skipped.
+//
+// A fleet tool generating configs from a template can use it to flag devices
+// claimed by several hosts, which is only legitimate for shared storage.
+// A disk listed twice in the same config counts once here; AddDisk and
+// validation cover duplicates within a config. Paths are compared as
+// written, so "/dev/sda" and its /dev/disk/by-id alias are distinct.
+// The result is empty, not nil, when there are no conflicts.
+func CheckDiskConflicts(configs map[string]*Config) map[string][]string {
+	owners := make(map[string][]string)
+
+	for name, cfg := range configs {
+		if cfg == nil {
+			continue
+		}
+
+		for _, disk := range cfg.Storage.Disks {
+			if !slices.Contains(owners[disk], name) {
+				owners[disk] = append(owners[disk], name)
+			}
+		}
+	}
+
+	conflicts := make(map[string][]string)
+
+	for disk, names := range owners {
+		if len(names) > 1 {
+			slices.Sort(names)
+			conflicts[disk] = names
+		}
+	}
+
+	return conflicts
+}
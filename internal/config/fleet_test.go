@@ -0,0 +1,63 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testDiskByIDShared is a by-id path reused across hosts in the fleet tests.
+const testDiskByIDShared = "/dev/disk/by-id/wwn-0x5000c500a1b2c3d4"
+
+func TestCheckDiskConflicts(t *testing.T) {
+	configs := map[string]*Config{
+		"pve1": &Config{Storage: StorageConfig{Disks: []string{"/dev/disk/by-id/nvme-pve1-a", testDiskByIDShared}}},
+		"pve2": &Config{Storage: StorageConfig{Disks: []string{"/dev/disk/by-id/nvme-pve2-a", "/dev/disk/by-id/nvme-pve2-b"}}},
+		"pve3": &Config{Storage: StorageConfig{Disks: []string{testDiskByIDShared, "/dev/disk/by-id/nvme-pve3-a"}}},
+	}
+
+	assert.Equal(t, map[string][]string{
+		testDiskByIDShared: {"pve1", "pve3"},
+	}, CheckDiskConflicts(configs))
+}
+
+func TestCheckDiskConflictsSortsNames(t *testing.T) {
+	configs := map[string]*Config{
+		"c": &Config{Storage: StorageConfig{Disks: []string{testDeviceSDA}}},
+		"a": &Config{Storage: StorageConfig{Disks: []string{testDeviceSDA}}},
+		"b": &Config{Storage: StorageConfig{Disks: []string{testDeviceSDA, testDeviceSDB}}},
+	}
+
+	assert.Equal(t, map[string][]string{
+		testDeviceSDA: {"a", "b", "c"},
+	}, CheckDiskConflicts(configs))
+}
+
+func TestCheckDiskConflictsDuplicateWithinConfig(t *testing.T) {
+	configs := map[string]*Config{
+		"pve1": &Config{Storage: StorageConfig{Disks: []string{testDeviceSDA, testDeviceSDA}}},
+		"pve2": &Config{Storage: StorageConfig{Disks: []string{testDeviceSDB}}},
+	}
+
+	assert.Empty(t, CheckDiskConflicts(configs), "a duplicate within one config is not a cross-config conflict")
+}
+
+func TestCheckDiskConflictsNoConflicts(t *testing.T) {
+	tests := []struct {
+		name    string
+		configs map[string]*Config
+	}{
+		{"nil map", nil},
+		{"nil config", map[string]*Config{"pve1": nil, "pve2": &Config{Storage: StorageConfig{Disks: []string{testDeviceSDA}}}}},
+		{"auto-detected disks", map[string]*Config{"pve1": DefaultConfig(), "pve2": DefaultConfig()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflicts := CheckDiskConflicts(tt.configs)
+
+			assert.NotNil(t, conflicts)
+			assert.Empty(t, conflicts)
+		})
+	}
+}
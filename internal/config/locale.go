@@ -0,0 +1,101 @@
+package config
+
+import (
+	"errors"
+	"maps"
+	"regexp"
+	"slices"
+)
+
+// Keyboard and locale validation errors.
+var (
+	// ErrKeyboardInvalid is returned when the keyboard layout is not a known Proxmox VE layout.
+	ErrKeyboardInvalid = errors.New("keyboard layout is not supported (e.g., en-us, de, fr-ch)")
+	// ErrLocaleInvalid is returned when the locale is not a UTF-8 locale name.
+	ErrLocaleInvalid = errors.New("locale must be a UTF-8 locale such as en_US.UTF-8 or C.UTF-8")
+)
+
+// localeRegex matches UTF-8 locale names: language_TERRITORY.UTF-8 or C.UTF-8.
+var localeRegex = regexp.MustCompile(`^([a-z]{2,3}_[A-Z]{2}|C)\.UTF-8$`)
+
+// XKBLayout is the X keyboard layout and variant the console uses for a
+// keyboard layout name, as written to /etc/default/keyboard.
+type XKBLayout struct {
+	// Layout is the XKB layout (e.g., "us", "ch").
+	Layout string
+	// Variant is the XKB variant, empty for the default variant.
+	Variant string
+}
+
+// keyboardLayouts maps the keyboard layout names offered by Proxmox VE to
+// their XKB layouts.
+var keyboardLayouts = map[string]XKBLayout{
+	"da":    {Layout: "dk"},
+	"de":    {Layout: "de"},
+	"de-ch": {Layout: "ch"},
+	"en-gb": {Layout: "gb"},
+	"en-us": {Layout: "us"},
+	"es":    {Layout: "es"},
+	"fi":    {Layout: "fi"},
+	"fr":    {Layout: "fr"},
+	"fr-be": {Layout: "be"},
+	"fr-ca": {Layout: "ca"},
+	"fr-ch": {Layout: "ch", Variant: "fr"},
+	"hu":    {Layout: "hu"},
+	"is":    {Layout: "is"},
+	"it":    {Layout: "it"},
+	"ja":    {Layout: "jp"},
+	"lt":    {Layout: "lt"},
+	"mk":    {Layout: "mk"},
+	"nl":    {Layout: "nl"},
+	"no":    {Layout: "no"},
+	"pl":    {Layout: "pl"},
+	"pt":    {Layout: "pt"},
+	"pt-br": {Layout: "br"},
+	"sl":    {Layout: "si"},
+	"sv":    {Layout: "se"},
+	"tr":    {Layout: "tr"},
+}
+
+// KeyboardLayouts returns the supported keyboard layout names in sorted order.
+func KeyboardLayouts() []string {
+	return slices.Sorted(maps.Keys(keyboardLayouts))
+}
+
+// LookupXKBLayout returns the XKB layout for a keyboard layout name and
+// whether the name is supported.
+func LookupXKBLayout(keyboard string) (XKBLayout, bool) {
+	layout, ok := keyboardLayouts[keyboard]
+
+	return layout, ok
+}
+
+// ValidateKeyboard checks that keyboard is one of KeyboardLayouts.
+// An empty value is valid and leaves the system's keyboard layout unchanged.
+func ValidateKeyboard(keyboard string) error {
+	if keyboard == "" {
+		return nil
+	}
+
+	if _, ok := keyboardLayouts[keyboard]; !ok {
+		return ErrKeyboardInvalid
+	}
+
+	return nil
+}
+
+// ValidateLocale checks that locale is a UTF-8 locale name such as
+// "en_US.UTF-8", "de_CH.UTF-8" or "C.UTF-8". Locales with other character
+// sets or modifiers (e.g., "sr_RS.UTF-8@latin") are not accepted.
+// An empty value is valid and leaves the system's locale unchanged.
+func ValidateLocale(locale string) error {
+	if locale == "" {
+		return nil
+	}
+
+	if !localeRegex.MatchString(locale) {
+		return ErrLocaleInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,243 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Override errors.
+var (
+	// ErrOverrideFormat is returned when an override is not in key=value form.
+	ErrOverrideFormat = errors.New("override must be in key=value form")
+	// ErrOverrideUnknownKey is returned when an override key does not name a settable field.
+	ErrOverrideUnknownKey = errors.New("unknown override key")
+	// ErrOverrideValueInvalid is returned when an override value cannot be parsed for its field.
+	ErrOverrideValueInvalid = errors.New("invalid override value")
+)
+
+// overrideSetter parses value and assigns it to a field of cfg.
+type overrideSetter func(cfg *Config, value string) error
+
+// overrideSetters maps "section.field" keys, using the YAML field names,
+// to their setters. Sensitive fields are deliberately absent: command-line
+// arguments are visible to other users of the system.
+var overrideSetters = map[string]overrideSetter{
+	"system.hostname":      stringOverride(func(c *Config) *string { return &c.System.Hostname }),
+	"system.domain_suffix": stringOverride(func(c *Config) *string { return &c.System.DomainSuffix }),
+	"system.timezone":      stringOverride(func(c *Config) *string { return &c.System.Timezone }),
+	"system.keyboard":      stringOverride(func(c *Config) *string { return &c.System.Keyboard }),
+	"system.locale":        stringOverride(func(c *Config) *string { return &c.System.Locale }),
+	"system.email": func(c *Config, v string) error {
+		c.System.Email = canonicalEmail(v)
+
+		return nil
+	},
+
+	"system.reboot_after_install": boolOverride(func(c *Config) *bool { return &c.System.RebootAfterInstall }),
+	"system.unattended_upgrades":  boolOverride(func(c *Config) *bool { return &c.System.EnableUnattendedUpgrades }),
+	"system.ssh_disable_password_auth": boolOverride(func(c *Config) *bool {
+		return &c.System.SSHDisablePasswordAuth
+	}),
+	"system.ssh_permit_root_login": func(c *Config, v string) error {
+		value := strings.ToLower(v)
+		if err := ValidatePermitRootLogin(value); err != nil {
+			return err
+		}
+
+		c.System.SSHPermitRootLogin = value
+
+		return nil
+	},
+	"system.proxmox_version": func(c *Config, v string) error {
+		if err := ValidateProxmoxVersion(v); err != nil {
+			return err
+		}
+
+		c.System.ProxmoxVersion = v
+
+		return nil
+	},
+	"system.admin_user": func(c *Config, v string) error {
+		if err := ValidateAdminUser(v); err != nil {
+			return err
+		}
+
+		c.System.AdminUser = v
+
+		return nil
+	},
+	"system.admin_ssh_keys": func(c *Config, v string) error {
+		keys := parseListEnv(v)
+		if keys == nil {
+			keys = []string{}
+		}
+
+		c.System.AdminSSHKeys = keys
+
+		return nil
+	},
+	"system.ntp_servers": func(c *Config, v string) error {
+		servers := parseListEnv(v)
+		if servers == nil {
+			servers = []string{}
+		}
+
+		c.System.NTPServers = servers
+
+		return nil
+	},
+
+	"network.interface":         stringOverride(func(c *Config) *string { return &c.Network.InterfaceName }),
+	"network.private_subnet":    stringOverride(func(c *Config) *string { return &c.Network.PrivateSubnet }),
+	"network.additional_subnet": stringOverride(func(c *Config) *string { return &c.Network.AdditionalSubnet }),
+	"network.bridge_mac":        stringOverride(func(c *Config) *string { return &c.Network.BridgeMAC }),
+	"network.bridge_mode": func(c *Config, v string) error {
+		mode := BridgeMode(strings.ToLower(v))
+		if !mode.IsValid() {
+			return ErrBridgeModeInvalid
+		}
+
+		c.Network.BridgeMode = mode
+
+		return nil
+	},
+	"network.network_backend": func(c *Config, v string) error {
+		backend := NetworkBackend(strings.ToLower(v))
+		if !backend.IsValid() {
+			return ErrNetworkBackendInvalid
+		}
+
+		c.Network.NetworkBackend = backend
+
+		return nil
+	},
+
+	"storage.zfs_raid": func(c *Config, v string) error {
+		raid := ZFSRaid(strings.ToLower(v))
+		if !raid.IsValid() {
+			return ErrZFSRaidInvalid
+		}
+
+		c.Storage.ZFSRaid = raid
+
+		return nil
+	},
+	"storage.disks": func(c *Config, v string) error {
+		disks := parseListEnv(v)
+		if disks == nil {
+			disks = []string{}
+		}
+
+		c.Storage.Disks = disks
+
+		return nil
+	},
+	"storage.swap_size_mb": func(c *Config, v string) error {
+		size, err := strconv.Atoi(v)
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+
+		c.Storage.SwapSizeMB = size
+
+		return nil
+	},
+	"storage.preserve_esp": boolOverride(func(c *Config) *bool { return &c.Storage.PreserveESP }),
+
+	"tailscale.enabled": boolOverride(func(c *Config) *bool { return &c.Tailscale.Enabled }),
+	"tailscale.ssh":     boolOverride(func(c *Config) *bool { return &c.Tailscale.SSH }),
+	"tailscale.webui":   boolOverride(func(c *Config) *bool { return &c.Tailscale.WebUI }),
+
+	"cluster.join_address": stringOverride(func(c *Config) *string { return &c.Cluster.JoinAddress }),
+	"cluster.fingerprint":  stringOverride(func(c *Config) *string { return &c.Cluster.Fingerprint }),
+
+	"acme.enabled": boolOverride(func(c *Config) *bool { return &c.ACME.Enabled }),
+	"acme.email": func(c *Config, v string) error {
+		c.ACME.Email = canonicalEmail(v)
+
+		return nil
+	},
+	"acme.staging": boolOverride(func(c *Config) *bool { return &c.ACME.Staging }),
+}
+
+// stringOverride returns a setter assigning the value to the string field
+// selected by field.
+func stringOverride(field func(*Config) *string) overrideSetter {
+	return func(c *Config, v string) error {
+		*field(c) = v
+
+		return nil
+	}
+}
+
+// boolOverride returns a setter assigning a strict boolean to the field
+// selected by field. It accepts true/yes/1 and false/no/0 (case-insensitive);
+// unlike parseBool, unrecognized values are rejected rather than treated as false.
+func boolOverride(field func(*Config) *bool) overrideSetter {
+	return func(c *Config, v string) error {
+		switch strings.ToLower(v) {
+		case "true", "yes", "1":
+			*field(c) = true
+		case "false", "no", "0":
+			*field(c) = false
+		default:
+			return errors.New("must be one of true, false, yes, no, 1, 0")
+		}
+
+		return nil
+	}
+}
+
+// OverrideKeys returns the keys accepted by ApplyOverrides, sorted.
+func OverrideKeys() []string {
+	keys := make([]string, 0, len(overrideSetters))
+	for key := range overrideSetters {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// ApplyOverrides applies "section.field=value" overrides to cfg in order,
+// as passed with the --set CLI flag (e.g., "network.bridge_mode=external",
+// "storage.disks=/dev/sda,/dev/sdb").
+//
+// Keys use the YAML field names and are case-insensitive; see OverrideKeys.
+// Values are trimmed of surrounding whitespace. Enum values are parsed like
+// their environment variables, lists are comma-separated, and booleans accept
+// true/false/yes/no/1/0. Sensitive fields (root password, SSH key, Tailscale
+// auth key, cluster password) cannot be overridden; use environment variables instead.
+//
+// Processing stops at the first invalid override, which is reported wrapping
+// ErrOverrideFormat, ErrOverrideUnknownKey or ErrOverrideValueInvalid.
+// Overrides before it remain applied.
+func ApplyOverrides(cfg *Config, overrides []string) error {
+	if cfg == nil {
+		return errors.New("config is nil")
+	}
+
+	for _, override := range overrides {
+		key, value, ok := strings.Cut(override, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		if !ok || key == "" {
+			return fmt.Errorf("%w: %q", ErrOverrideFormat, override)
+		}
+
+		setter, found := overrideSetters[key]
+		if !found {
+			return fmt.Errorf("%w: %q", ErrOverrideUnknownKey, key)
+		}
+
+		if err := setter(cfg, strings.TrimSpace(value)); err != nil {
+			return fmt.Errorf("%w for %s: %w", ErrOverrideValueInvalid, key, err)
+		}
+	}
+
+	return nil
+}
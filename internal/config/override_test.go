@@ -0,0 +1,156 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyOverrides(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides []string
+		check     func(t *testing.T, cfg *Config)
+	}{
+		{
+			name:      "string field",
+			overrides: []string{"system.hostname=pve-node-7"},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "pve-node-7", cfg.System.Hostname)
+			},
+		},
+		{
+			name:      "value containing equals sign",
+			overrides: []string{"system.email=ops+a=b@example.com"},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "ops+a=b@example.com", cfg.System.Email)
+			},
+		},
+		{
+			name:      "email is canonicalized",
+			overrides: []string{"system.email=Ops@Example.COM"},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "Ops@example.com", cfg.System.Email)
+			},
+		},
+		{
+			name:      "bool field",
+			overrides: []string{"tailscale.enabled=yes", "tailscale.ssh=false", "tailscale.webui=1"},
+			check: func(t *testing.T, cfg *Config) {
+				assert.True(t, cfg.Tailscale.Enabled)
+				assert.False(t, cfg.Tailscale.SSH)
+				assert.True(t, cfg.Tailscale.WebUI)
+			},
+		},
+		{
+			name:      "enum fields are case-insensitive",
+			overrides: []string{"network.bridge_mode=External", "storage.zfs_raid=RAID0", "network.network_backend=Networkd"},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, BridgeModeExternal, cfg.Network.BridgeMode)
+				assert.Equal(t, ZFSRaid0, cfg.Storage.ZFSRaid)
+				assert.Equal(t, NetworkBackendNetworkd, cfg.Network.NetworkBackend)
+			},
+		},
+		{
+			name:      "slice field",
+			overrides: []string{"storage.disks=/dev/sda, /dev/sdb,"},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, []string{testDeviceSDA, testDeviceSDB}, cfg.Storage.Disks)
+			},
+		},
+		{
+			name:      "empty slice clears disks",
+			overrides: []string{"storage.disks="},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Empty(t, cfg.Storage.Disks)
+			},
+		},
+		{
+			name:      "ntp servers",
+			overrides: []string{"system.ntp_servers=time.example.com, ntp1.hetzner.de"},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, []string{"time.example.com", "ntp1.hetzner.de"}, cfg.System.NTPServers)
+			},
+		},
+		{
+			name:      "int field",
+			overrides: []string{"storage.swap_size_mb=4096"},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, 4096, cfg.Storage.SwapSizeMB)
+			},
+		},
+		{
+			name:      "later override wins",
+			overrides: []string{"system.timezone=UTC", " System.Timezone = Europe/Berlin "},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "Europe/Berlin", cfg.System.Timezone)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Storage.Disks = []string{testDeviceSDC}
+
+			require.NoError(t, ApplyOverrides(cfg, tt.overrides))
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestApplyOverridesErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		override    string
+		expectedErr error
+	}{
+		{"missing equals", "system.hostname", ErrOverrideFormat},
+		{"empty key", "=value", ErrOverrideFormat},
+		{"unknown section", "proxmox.repo=enterprise", ErrOverrideUnknownKey},
+		{"unknown field", "network.gateway=192.0.2.1", ErrOverrideUnknownKey}, // NOSONAR(go:S1313) RFC 5737 documentation range
+		{"go field name", "system.Hostname2=x", ErrOverrideUnknownKey},
+		{"sensitive field", "tailscale.auth_key=tskey-secret", ErrOverrideUnknownKey}, // NOSONAR(go:S2068) test data
+		{"bad bool", "tailscale.enabled=maybe", ErrOverrideValueInvalid},
+		{"bad bridge mode", "network.bridge_mode=bridged", ErrOverrideValueInvalid},
+		{"bad zfs raid", "storage.zfs_raid=raid5", ErrOverrideValueInvalid},
+		{"bad network backend", "network.network_backend=netplan", ErrOverrideValueInvalid},
+		{"bad int", "storage.swap_size_mb=lots", ErrOverrideValueInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			original := DefaultConfig()
+
+			err := ApplyOverrides(cfg, []string{tt.override})
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, original, cfg)
+		})
+	}
+}
+
+func TestApplyOverridesBadValueWrapsParserError(t *testing.T) {
+	err := ApplyOverrides(DefaultConfig(), []string{"network.bridge_mode=bridged"})
+
+	assert.ErrorIs(t, err, ErrBridgeModeInvalid)
+	assert.Contains(t, err.Error(), "network.bridge_mode")
+}
+
+func TestApplyOverridesNilConfig(t *testing.T) {
+	assert.Error(t, ApplyOverrides(nil, []string{"system.hostname=x"}))
+}
+
+func TestOverrideKeysExcludeSecrets(t *testing.T) {
+	keys := OverrideKeys()
+
+	assert.IsIncreasing(t, keys)
+	assert.Contains(t, keys, "network.bridge_mode")
+	assert.NotContains(t, keys, "tailscale.auth_key")
+	assert.NotContains(t, keys, "system.root_password")
+	assert.NotContains(t, keys, "system.ssh_public_key")
+	assert.NotContains(t, keys, "cluster.password")
+}
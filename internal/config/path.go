@@ -0,0 +1,45 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrPathUserHomeUnsupported is returned for "~user" paths, which are not expanded.
+var ErrPathUserHomeUnsupported = errors.New("path must start with ~/ to refer to a home directory; ~user is not supported")
+
+// ExpandPath returns p with a leading "~" or "~/" replaced by the current
+// user's home directory and, if relative, made absolute against the current
+// working directory, so it keeps its meaning when the installer later runs
+// elsewhere. Empty and absolute paths are returned unchanged.
+//
+// Loading applies it to every path taken from configuration: config files
+// (see LoadFromFile and LoadFromFiles) and an SSHPublicKey naming a key file.
+func ExpandPath(p string) (string, error) {
+	if p == "" || filepath.IsAbs(p) {
+		return p, nil
+	}
+
+	if rest, ok := strings.CutPrefix(p, "~"); ok {
+		if rest != "" && !strings.HasPrefix(rest, "/") {
+			return "", fmt.Errorf("%w: got %q", ErrPathUserHomeUnsupported, p)
+		}
+
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve home directory: %w", err)
+		}
+
+		return filepath.Join(home, rest), nil
+	}
+
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return "", fmt.Errorf("failed to make %q absolute: %w", p, err)
+	}
+
+	return abs, nil
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{"empty unchanged", "", ""},
+		{"absolute unchanged", "/etc/pve-install/config.yaml", "/etc/pve-install/config.yaml"},
+		{"absolute not cleaned", "/etc//pve-install/../config.yaml", "/etc//pve-install/../config.yaml"},
+		{"tilde alone", "~", home},
+		{"tilde path", "~/.ssh/id_ed25519.pub", filepath.Join(home, ".ssh", "id_ed25519.pub")},
+		{"relative", "configs/example.yaml", filepath.Join(cwd, "configs", "example.yaml")},
+		{"dot relative", "./host.yaml", filepath.Join(cwd, "host.yaml")},
+		{"parent relative", "../host.yaml", filepath.Join(filepath.Dir(cwd), "host.yaml")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expanded, err := ExpandPath(tt.path)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, expanded)
+		})
+	}
+}
+
+func TestExpandPathOtherUserHome(t *testing.T) {
+	_, err := ExpandPath("~admin/config.yaml")
+
+	require.ErrorIs(t, err, ErrPathUserHomeUnsupported)
+	assert.ErrorContains(t, err, "~admin")
+}
+
+func TestLoadFromFileExpandsTilde(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	require.NoError(t, os.WriteFile(filepath.Join(home, "pve.yaml"), []byte("system:\n  hostname: pve-tilde\n"), 0o600))
+
+	cfg, err := LoadFromFile("~/pve.yaml")
+
+	require.NoError(t, err)
+	assert.Equal(t, "pve-tilde", cfg.System.Hostname)
+}
+
+func TestLoadFromFileRelativeErrorNamesAbsolutePath(t *testing.T) {
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	_, err = LoadFromFile("missing-config.yaml")
+
+	assert.ErrorContains(t, err, filepath.Join(cwd, "missing-config.yaml"))
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// PipeToCommand serializes the configuration to YAML and passes it as stdin
+// to the command run through executor, e.g. "tee /target/etc/pve-install.yaml"
+// to push the configuration to the installed system.
+//
+// Sensitive fields are excluded, exactly as in SaveToFile. Use
+// PipeToCommandWithSecrets when the receiving side needs them.
+func (c *Config) PipeToCommand(ctx context.Context, executor exec.Executor, name string, args ...string) error {
+	return c.pipeToCommand(ctx, executor, false, name, args)
+}
+
+// PipeToCommandWithSecrets is like PipeToCommand but also writes the
+// non-empty sensitive fields under the keys system.root_password,
+// system.ssh_public_key, tailscale.auth_key and cluster.password.
+//
+// LoadFromFile ignores these keys, so the output is meant for the command
+// consuming it, not for loading back as a configuration file.
+func (c *Config) PipeToCommandWithSecrets(ctx context.Context, executor exec.Executor, name string, args ...string) error {
+	return c.pipeToCommand(ctx, executor, true, name, args)
+}
+
+// pipeToCommand implements PipeToCommand and PipeToCommandWithSecrets.
+func (c *Config) pipeToCommand(ctx context.Context, executor exec.Executor, includeSecrets bool, name string, args []string) error {
+	if c == nil {
+		return fmt.Errorf("config is nil")
+	}
+
+	data, err := c.marshalYAML(includeSecrets)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config to YAML: %w", err)
+	}
+
+	if err := executor.RunWithStdin(ctx, string(data), name, args...); err != nil {
+		return fmt.Errorf("failed to pipe config to %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// marshalYAML serializes the configuration to YAML. Sensitive fields are
+// tagged yaml:"-", so they are added to the encoded sections explicitly, under
+// their secret_name, when includeSecrets is set.
+func (c *Config) marshalYAML(includeSecrets bool) ([]byte, error) {
+	if !includeSecrets {
+		return yaml.Marshal(c)
+	}
+
+	var doc yaml.Node
+	if err := doc.Encode(c); err != nil {
+		return nil, err
+	}
+
+	for _, s := range c.secrets() {
+		if s.value == "" {
+			continue
+		}
+
+		if err := addMappingString(&doc, s.section, s.name, s.value); err != nil {
+			return nil, err
+		}
+	}
+
+	return yaml.Marshal(&doc)
+}
+
+// addMappingString appends key: value to the mapping found under section in doc.
+func addMappingString(doc *yaml.Node, section, key, value string) error {
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if doc.Content[i].Value != section || doc.Content[i+1].Kind != yaml.MappingNode {
+			continue
+		}
+
+		doc.Content[i+1].Content = append(doc.Content[i+1].Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value},
+		)
+
+		return nil
+	}
+
+	return errors.New("config section " + section + " not found")
+}
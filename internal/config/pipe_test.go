@@ -0,0 +1,101 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// testPipeTarget is the file the pipe tests write the configuration to.
+const testPipeTarget = "/target/etc/pve-install.yaml"
+
+func TestConfigPipeToCommand(t *testing.T) {
+	cfg := newValidTestConfig()
+	mock := exec.NewMockExecutor()
+
+	require.NoError(t, cfg.PipeToCommand(context.Background(), mock, "tee", testPipeTarget))
+
+	expected, err := yaml.Marshal(cfg)
+	require.NoError(t, err)
+
+	last := mock.LastCommand()
+	require.NotNil(t, last)
+	assert.Equal(t, "tee", last.Name)
+	assert.Equal(t, []string{testPipeTarget}, last.Args)
+	assert.Equal(t, string(expected), last.Stdin)
+	AssertNoSecretsInBytes(t, []byte(last.Stdin), cfg)
+}
+
+func TestConfigPipeToCommandWithSecrets(t *testing.T) {
+	cfg := newValidTestConfig()
+	mock := exec.NewMockExecutor()
+
+	require.NoError(t, cfg.PipeToCommandWithSecrets(context.Background(), mock, "tee", testPipeTarget))
+
+	last := mock.LastCommand()
+	require.NotNil(t, last)
+	assert.Equal(t, "tee", last.Name)
+	assert.Equal(t, []string{testPipeTarget}, last.Args)
+
+	var doc struct {
+		System struct {
+			Hostname     string `yaml:"hostname"`
+			RootPassword string `yaml:"root_password"`
+			SSHPublicKey string `yaml:"ssh_public_key"`
+		} `yaml:"system"`
+		Tailscale struct {
+			AuthKey string `yaml:"auth_key"`
+		} `yaml:"tailscale"`
+		Cluster struct {
+			Password string `yaml:"password"`
+		} `yaml:"cluster"`
+	}
+	require.NoError(t, yaml.Unmarshal([]byte(last.Stdin), &doc))
+
+	assert.Equal(t, cfg.System.Hostname, doc.System.Hostname)
+	assert.Equal(t, testValidPassword, doc.System.RootPassword)
+	assert.Equal(t, testValidSSHKey, doc.System.SSHPublicKey)
+	assert.Equal(t, testTailscaleAuthKey, doc.Tailscale.AuthKey)
+	assert.Equal(t, testClusterPassword, doc.Cluster.Password)
+
+	// The non-secret part still loads as a regular configuration.
+	var restored Config
+	require.NoError(t, yaml.Unmarshal([]byte(last.Stdin), &restored))
+	assert.Equal(t, cfg.Network, restored.Network)
+	assert.Empty(t, restored.System.RootPassword)
+}
+
+func TestConfigPipeToCommandWithSecretsOmitsEmpty(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.RootPassword = testValidPassword
+	mock := exec.NewMockExecutor()
+
+	require.NoError(t, cfg.PipeToCommandWithSecrets(context.Background(), mock, "cat"))
+
+	stdin := mock.LastCommand().Stdin
+	assert.Contains(t, stdin, "root_password: "+testValidPassword)
+	assert.NotContains(t, stdin, "ssh_public_key")
+	assert.NotContains(t, stdin, "auth_key")
+}
+
+func TestConfigPipeToCommandError(t *testing.T) {
+	errTee := errors.New("tee: /target/etc: No such file or directory")
+	mock := exec.NewMockExecutor()
+	mock.SetError("tee "+testPipeTarget, errTee)
+
+	err := DefaultConfig().PipeToCommand(context.Background(), mock, "tee", testPipeTarget)
+
+	require.ErrorIs(t, err, errTee)
+}
+
+func TestConfigPipeToCommandNil(t *testing.T) {
+	var cfg *Config
+
+	require.Error(t, cfg.PipeToCommand(context.Background(), exec.NewMockExecutor(), "tee", testPipeTarget))
+}
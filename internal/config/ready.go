@@ -0,0 +1,55 @@
+package config
+
+import "errors"
+
+// Install prerequisite errors returned by ReadyForInstall.
+var (
+	// ErrNoDisksSelected is returned when no target disk has been selected.
+	ErrNoDisksSelected = errors.New("no disks selected for installation")
+	// ErrNoAuthMethod is returned when neither a root password nor an SSH key is set.
+	ErrNoAuthMethod = errors.New("a root password or SSH public key is required to log in")
+	// ErrWipeNotConfirmed is returned when ConfirmWipe does not match WipeConfirmationToken.
+	ErrWipeNotConfirmed = errors.New("disk wipe not confirmed: set the confirmation token " + WipeConfirmationToken)
+)
+
+// WipeConfirmationToken is the value Config.ConfirmWipe must hold to
+// acknowledge that installing destroys all data on the selected disks.
+const WipeConfirmationToken = "yes-destroy-my-data"
+
+// ReadyForInstall checks the minimal set of settings needed to start an
+// installation, as a gate for the TUI "Install" button and the CLI:
+//   - at least one disk, with a count the ZFS RAID level supports
+//   - a root password or an SSH public key
+//   - a bridge mode
+//   - ConfirmWipe set to WipeConfirmationToken
+//
+// Unlike Validate it does not check the format of every field; it reports
+// what is missing. All missing items are returned together in a
+// *ValidationError, in the order above.
+func (c *Config) ReadyForInstall() error {
+	var missing ValidationError
+
+	if len(c.Storage.Disks) == 0 {
+		missing.Add(ErrNoDisksSelected)
+	} else if err := ValidateZFSRaid(c.Storage.ZFSRaid); err != nil {
+		missing.Add(err)
+	} else {
+		missing.Add(ValidateDiskCount(c.Storage.ZFSRaid, len(c.Storage.Disks)))
+	}
+
+	if c.System.RootPassword == "" && c.System.SSHPublicKey == "" {
+		missing.Add(ErrNoAuthMethod)
+	}
+
+	missing.Add(ValidateBridgeMode(c.Network.BridgeMode))
+
+	if c.ConfirmWipe != WipeConfirmationToken {
+		missing.Add(ErrWipeNotConfirmed)
+	}
+
+	if missing.HasErrors() {
+		return &missing
+	}
+
+	return nil
+}
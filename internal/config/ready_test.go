@@ -0,0 +1,86 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigReadyForInstall(t *testing.T) {
+	assert.NoError(t, newValidTestConfig().ReadyForInstall())
+}
+
+func TestConfigReadyForInstallSSHKeyOnly(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.System.RootPassword = ""
+
+	assert.NoError(t, cfg.ReadyForInstall())
+}
+
+func TestConfigReadyForInstallMissing(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(*Config)
+		expected error
+	}{
+		{"no disks", func(c *Config) { c.Storage.Disks = nil }, ErrNoDisksSelected},
+		{"mirror with one disk", func(c *Config) { c.Storage.Disks = []string{testDeviceSDA} }, ErrTooFewDisks},
+		{
+			"mirror with odd disks",
+			func(c *Config) { c.Storage.Disks = []string{testDeviceSDA, testDeviceSDB, testDeviceSDC} },
+			ErrRaidMirrorOddDisks,
+		},
+		{"no raid level", func(c *Config) { c.Storage.ZFSRaid = "" }, ErrZFSRaidEmpty},
+		{"no auth method", func(c *Config) { c.System.RootPassword, c.System.SSHPublicKey = "", "" }, ErrNoAuthMethod},
+		{"no bridge mode", func(c *Config) { c.Network.BridgeMode = "" }, ErrBridgeModeEmpty},
+		{"wipe not confirmed", func(c *Config) { c.ConfirmWipe = "" }, ErrWipeNotConfirmed},
+		{"wrong wipe token", func(c *Config) { c.ConfirmWipe = "yes" }, ErrWipeNotConfirmed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newValidTestConfig()
+			tt.modify(cfg)
+
+			err := cfg.ReadyForInstall()
+
+			var validationErr *ValidationError
+
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, []error{tt.expected}, validationErr.Errors)
+		})
+	}
+}
+
+func TestConfigReadyForInstallAggregates(t *testing.T) {
+	err := (&Config{}).ReadyForInstall()
+
+	var validationErr *ValidationError
+
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []error{ErrNoDisksSelected, ErrNoAuthMethod, ErrBridgeModeEmpty, ErrWipeNotConfirmed}, validationErr.Errors)
+}
+
+func TestConfirmWipeExcludedFromSaveToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	cfg := newValidTestConfig()
+	require.NoError(t, cfg.SaveToFile(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), WipeConfirmationToken)
+	assert.NotContains(t, string(data), "confirm")
+
+	loaded, err := LoadFromFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, loaded.ConfirmWipe)
+
+	var validationErr *ValidationError
+
+	require.ErrorAs(t, loaded.ReadyForInstall(), &validationErr)
+	assert.Contains(t, validationErr.Errors, ErrWipeNotConfirmed, "confirmation must be given again on every run")
+}
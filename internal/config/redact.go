@@ -0,0 +1,63 @@
+package config
+
+import (
+	"slices"
+	"strings"
+)
+
+// RedactedPlaceholder replaces secret values in redacted strings.
+const RedactedPlaceholder = "[REDACTED]"
+
+// RedactSecrets returns s with every occurrence of each non-empty secret
+// replaced by RedactedPlaceholder.
+//
+// Empty secrets are ignored (they would otherwise match everywhere).
+// All secrets are replaced in a single pass, so a secret never matches
+// inside a placeholder written for another one. Where secrets overlap,
+// the longest match wins (e.g., "hunter2-extended" over "hunter2"), so no
+// fragment of the longer one is left behind.
+func RedactSecrets(s string, secrets ...string) string {
+	sorted := make([]string, 0, len(secrets))
+
+	for _, secret := range secrets {
+		if secret != "" {
+			sorted = append(sorted, secret)
+		}
+	}
+
+	if len(sorted) == 0 {
+		return s
+	}
+
+	// strings.Replacer tries the pairs in argument order at each position.
+	slices.SortFunc(sorted, func(a, b string) int {
+		return len(b) - len(a)
+	})
+
+	pairs := make([]string, 0, 2*len(sorted))
+
+	for _, secret := range sorted {
+		pairs = append(pairs, secret, RedactedPlaceholder)
+	}
+
+	return strings.NewReplacer(pairs...).Replace(s)
+}
+
+// SecretValues returns the non-empty values of the fields tagged
+// secret:"true" (root password, SSH public key, Tailscale auth key, cluster
+// password), suitable for RedactSecrets.
+func (c *Config) SecretValues() []string {
+	if c == nil {
+		return nil
+	}
+
+	var values []string
+
+	for _, secret := range c.secrets() {
+		if secret.value != "" {
+			values = append(values, secret.value)
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test secrets for redaction tests.
+const (
+	testRedactAuthKey  = "tskey-auth-abc123" // NOSONAR(go:S2068) test value
+	testRedactPassword = "hunter2"           // NOSONAR(go:S2068) test value
+)
+
+func TestRedactSecrets(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		secrets  []string
+		expected string
+	}{
+		{
+			name:     "single secret",
+			input:    "tailscale up --authkey " + testRedactAuthKey,
+			secrets:  []string{testRedactAuthKey},
+			expected: "tailscale up --authkey [REDACTED]",
+		},
+		{
+			name:     "multiple secrets",
+			input:    "password=" + testRedactPassword + " key=" + testRedactAuthKey,
+			secrets:  []string{testRedactPassword, testRedactAuthKey},
+			expected: "password=[REDACTED] key=[REDACTED]",
+		},
+		{
+			name:     "repeated occurrences",
+			input:    testRedactPassword + " and " + testRedactPassword,
+			secrets:  []string{testRedactPassword},
+			expected: "[REDACTED] and [REDACTED]",
+		},
+		{
+			name:     "empty secrets ignored",
+			input:    "nothing to hide",
+			secrets:  []string{"", ""},
+			expected: "nothing to hide",
+		},
+		{
+			name:     "empty secret mixed with real one",
+			input:    "pw " + testRedactPassword,
+			secrets:  []string{"", testRedactPassword},
+			expected: "pw [REDACTED]",
+		},
+		{
+			name:     "no secrets",
+			input:    "plain output",
+			secrets:  nil,
+			expected: "plain output",
+		},
+		{
+			name:     "substring secret listed first",
+			input:    "token=" + testRedactPassword + "-extended",
+			secrets:  []string{testRedactPassword, testRedactPassword + "-extended"},
+			expected: "token=[REDACTED]",
+		},
+		{
+			name:     "substring secret appears alone too",
+			input:    testRedactPassword + "-extended " + testRedactPassword,
+			secrets:  []string{testRedactPassword, testRedactPassword + "-extended"},
+			expected: "[REDACTED] [REDACTED]",
+		},
+		{
+			name:     "secret inside the placeholder",
+			input:    "key=" + testRedactAuthKey + " RED",
+			secrets:  []string{testRedactAuthKey, "RED"},
+			expected: "key=[REDACTED] [REDACTED]",
+		},
+		{
+			name:     "secret not present",
+			input:    "clean output",
+			secrets:  []string{testRedactAuthKey},
+			expected: "clean output",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RedactSecrets(tt.input, tt.secrets...))
+		})
+	}
+}
+
+func TestConfigSecretValues(t *testing.T) {
+	cfg := DefaultConfig()
+	assert.Empty(t, cfg.SecretValues())
+
+	cfg.System.RootPassword = testRedactPassword
+	cfg.Tailscale.AuthKey = testRedactAuthKey
+
+	assert.Equal(t, []string{testRedactPassword, testRedactAuthKey}, cfg.SecretValues())
+}
+
+func TestConfigSecretValuesNilReceiver(t *testing.T) {
+	var cfg *Config
+
+	assert.Nil(t, cfg.SecretValues())
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// reportSections lists the configuration sections in report order.
+var reportSections = []string{"system", "network", "storage", "tailscale", "cluster", "acme"}
+
+// FormatValidationReport validates cfg and returns a plain-text report
+// grouping errors and warnings by section, plus whether cfg is valid.
+//
+// Each section is reported on its own line as "section: OK" when it has no
+// findings, or as "section:" followed by one indented line per finding,
+// prefixed with "ERROR" or "WARN ". A final summary line follows. The report
+// contains no color codes; callers such as the CLI may colorize lines by
+// their prefix. Warnings do not affect validity.
+func FormatValidationReport(cfg *Config) (string, bool) {
+	if cfg == nil {
+		return "configuration is missing\n", false
+	}
+
+	errs := cfg.FieldErrors()
+	warnings := cfg.Warnings()
+
+	var b strings.Builder
+
+	for _, section := range reportSections {
+		sectionErrs := filterSection(errs, section)
+		sectionWarnings := filterSection(warnings, section)
+
+		if len(sectionErrs) == 0 && len(sectionWarnings) == 0 {
+			fmt.Fprintf(&b, "%s: OK\n", section)
+
+			continue
+		}
+
+		fmt.Fprintf(&b, "%s:\n", section)
+
+		for _, fe := range sectionErrs {
+			fmt.Fprintf(&b, "  ERROR %s: %v\n", fe.Field, fe.Err)
+		}
+
+		for _, fe := range sectionWarnings {
+			fmt.Fprintf(&b, "  WARN  %s: %v\n", fe.Field, fe.Err)
+		}
+	}
+
+	valid := len(errs) == 0
+	if valid {
+		fmt.Fprintf(&b, "Configuration is valid (%d warning(s))\n", len(warnings))
+	} else {
+		fmt.Fprintf(&b, "Configuration is invalid: %d error(s), %d warning(s)\n", len(errs), len(warnings))
+	}
+
+	return b.String(), valid
+}
+
+// filterSection returns the findings belonging to section.
+func filterSection(findings []FieldError, section string) []FieldError {
+	var result []FieldError
+
+	for _, fe := range findings {
+		if fe.Section() == section {
+			result = append(result, fe)
+		}
+	}
+
+	return result
+}
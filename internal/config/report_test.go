@@ -0,0 +1,98 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reportSection returns the report lines belonging to section, including its header.
+func reportSection(report, section string) []string {
+	var lines []string
+
+	inSection := false
+
+	for _, line := range strings.Split(strings.TrimRight(report, "\n"), "\n") {
+		if !strings.HasPrefix(line, "  ") {
+			inSection = strings.HasPrefix(line, section+":")
+		}
+
+		if inSection {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+func TestFormatValidationReportValid(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+
+	report, valid := FormatValidationReport(cfg)
+
+	assert.True(t, valid)
+
+	for _, section := range reportSections {
+		assert.Equal(t, []string{section + ": OK"}, reportSection(report, section))
+	}
+
+	assert.Contains(t, report, "Configuration is valid")
+	assert.NotContains(t, report, "\x1b[", "core report must not contain color codes")
+}
+
+func TestFormatValidationReportListsFailingFieldsUnderSection(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+	cfg.System.Email = "not-an-email"
+	cfg.Network.PrivateSubnet = "invalid"
+	cfg.Network.AdditionalSubnet = "also-invalid"
+
+	report, valid := FormatValidationReport(cfg)
+
+	assert.False(t, valid)
+	assert.Equal(t, []string{
+		"system:",
+		"  ERROR system.email: " + ErrEmailInvalid.Error(),
+	}, reportSection(report, "system"))
+	assert.Equal(t, []string{
+		"network:",
+		"  ERROR network.private_subnet: " + ErrSubnetInvalid.Error(),
+		"  ERROR network.additional_subnet: " + ErrAdditionalSubnetInvalid.Error(),
+	}, reportSection(report, "network"))
+	assert.Equal(t, []string{"storage: OK"}, reportSection(report, "storage"))
+	assert.Equal(t, []string{"tailscale: OK"}, reportSection(report, "tailscale"))
+	assert.Contains(t, report, "Configuration is invalid: 3 error(s), 0 warning(s)")
+}
+
+func TestFormatValidationReportWarningsKeepConfigValid(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+	cfg.Storage.ZFSRaid = ZFSRaid0
+	cfg.Tailscale.Enabled = true
+
+	report, valid := FormatValidationReport(cfg)
+
+	assert.True(t, valid)
+	assert.Equal(t, []string{
+		"storage:",
+		"  WARN  storage.zfs_raid: " + ErrZFSRaid0NoRedundancy.Error(),
+	}, reportSection(report, "storage"))
+	assert.Equal(t, []string{
+		"tailscale:",
+		"  WARN  tailscale.auth_key: " + ErrTailscaleAuthKeyMissing.Error(),
+	}, reportSection(report, "tailscale"))
+	assert.Contains(t, report, "Configuration is valid (2 warning(s))")
+}
+
+func TestFormatValidationReportNilConfig(t *testing.T) {
+	report, valid := FormatValidationReport(nil)
+
+	assert.False(t, valid)
+	require.NotEmpty(t, report)
+}
@@ -0,0 +1,43 @@
+package config
+
+import "reflect"
+
+// configSecret is a string field of a Config tagged secret:"true".
+type configSecret struct {
+	// section is the YAML name of the section (e.g., "system").
+	section string
+	// name is the field's secret_name tag (e.g., "root_password").
+	name string
+	// value is the field value, possibly empty.
+	value string
+}
+
+// secrets returns the secret fields of c in declaration order. Secrets
+// are found by their tags, so a new secret field only needs to be tagged.
+func (c *Config) secrets() []configSecret {
+	v := reflect.ValueOf(c).Elem()
+
+	var fields []configSecret
+
+	for i := range v.NumField() {
+		section := v.Type().Field(i)
+		if section.Type.Kind() != reflect.Struct {
+			continue
+		}
+
+		for j := range section.Type.NumField() {
+			field := section.Type.Field(j)
+			if field.Tag.Get("secret") != "true" || field.Type.Kind() != reflect.String {
+				continue
+			}
+
+			fields = append(fields, configSecret{
+				section: section.Tag.Get("yaml"),
+				name:    field.Tag.Get("secret_name"),
+				value:   v.Field(i).Field(j).String(),
+			})
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// secretField is a secret-tagged field found by secretFields.
+type secretField struct {
+	// path is the dotted Go field path (e.g., "System.RootPassword").
+	path string
+	// value is the field value.
+	value string
+}
+
+// secretFields returns every string field of v tagged secret:"true",
+// descending into nested structs.
+func secretFields(v reflect.Value, prefix string) []secretField {
+	var fields []secretField
+
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		path := prefix + field.Name
+
+		switch {
+		case field.Type.Kind() == reflect.Struct:
+			fields = append(fields, secretFields(v.Field(i), path+".")...)
+		case field.Tag.Get("secret") == "true" && field.Type.Kind() == reflect.String:
+			fields = append(fields, secretField{path: path, value: v.Field(i).String()})
+		}
+	}
+
+	return fields
+}
+
+// AssertNoSecretsInBytes fails t if the value of any non-empty secret field
+// of cfg appears anywhere in data. Secret fields are the string fields tagged
+// secret:"true", so new secrets are covered without changing callers.
+// Leaked values are reported by field path only, never by value.
+func AssertNoSecretsInBytes(t testing.TB, data []byte, cfg *Config) {
+	t.Helper()
+
+	for _, field := range secretFields(reflect.ValueOf(cfg).Elem(), "") {
+		if field.value != "" && bytes.Contains(data, []byte(field.value)) {
+			t.Errorf("secret field %s leaked into output", field.path)
+		}
+	}
+}
+
+// recordingTB captures Errorf calls so tests can check that an assertion fails.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestSecretFieldsAreExcludedFromYAML(t *testing.T) {
+	fields := secretFields(reflect.ValueOf(Config{}), "")
+	require.Len(t, fields, len(newValidTestConfig().SecretValues()), "every value from SecretValues must be tagged secret")
+
+	types := []reflect.Type{
+		reflect.TypeOf(SystemConfig{}),
+		reflect.TypeOf(NetworkConfig{}),
+		reflect.TypeOf(StorageConfig{}),
+		reflect.TypeOf(TailscaleConfig{}),
+		reflect.TypeOf(ClusterConfig{}),
+	}
+
+	for _, typ := range types {
+		for i := range typ.NumField() {
+			field := typ.Field(i)
+			if field.Tag.Get("secret") == "true" {
+				assert.Equal(t, "-", field.Tag.Get("yaml"), "secret field %s.%s must not be serialized", typ.Name(), field.Name)
+				assert.NotEmpty(t, field.Tag.Get("secret_name"), "secret field %s.%s needs a secret_name", typ.Name(), field.Name)
+			}
+		}
+	}
+}
+
+func TestAssertNoSecretsInBytesPasses(t *testing.T) {
+	cfg := newValidTestConfig()
+	path := filepath.Join(t.TempDir(), testConfigFileName)
+
+	require.NoError(t, cfg.SaveToFile(path))
+
+	data, err := os.ReadFile(path) //nolint:gosec // test file path is controlled
+	require.NoError(t, err)
+
+	AssertNoSecretsInBytes(t, data, cfg)
+}
+
+func TestAssertNoSecretsInBytesCatchesLeaks(t *testing.T) {
+	cfg := newValidTestConfig()
+
+	for _, field := range secretFields(reflect.ValueOf(cfg).Elem(), "") {
+		t.Run(field.path, func(t *testing.T) {
+			data := []byte("system:\n  hostname: pve\n  leaked: " + field.value + "\n")
+			rec := &recordingTB{TB: t}
+
+			AssertNoSecretsInBytes(rec, data, cfg)
+
+			require.Len(t, rec.errors, 1)
+			assert.Contains(t, rec.errors[0], field.path)
+			assert.NotContains(t, rec.errors[0], field.value, "the leaked value must not be echoed")
+		})
+	}
+}
+
+func TestAssertNoSecretsInBytesIgnoresEmptySecrets(t *testing.T) {
+	rec := &recordingTB{TB: t}
+
+	AssertNoSecretsInBytes(rec, []byte("anything"), DefaultConfig())
+
+	assert.Empty(t, rec.errors)
+}
+
+func TestConfigSecretsFollowTags(t *testing.T) {
+	cfg := newValidTestConfig()
+
+	var names []string
+
+	for _, secret := range cfg.secrets() {
+		names = append(names, secret.section+"."+secret.name)
+	}
+
+	assert.Equal(t, []string{"system.root_password", "system.ssh_public_key", "tailscale.auth_key", "cluster.password"}, names)
+	assert.Len(t, cfg.SecretValues(), len(secretFields(reflect.ValueOf(cfg).Elem(), "")))
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+)
+
+// ConfigSource is a layer of configuration applied on top of the values
+// loaded so far, such as a file or the environment.
+//
+// Apply overlays the source's values onto cfg, leaving fields it does not
+// set unchanged. Implementing it lets new sources (e.g., a secrets store)
+// take part in LoadFromSources alongside FileSource and EnvSource.
+type ConfigSource interface {
+	Apply(cfg *Config) error
+}
+
+// FileSource is a ConfigSource reading a YAML or JSON file, one layer of
+// LoadFromFiles.
+type FileSource struct {
+	// Path is the file to read, expanded with ExpandPath. A path starting
+	// with OptionalPathPrefix is skipped if the file does not exist.
+	Path string
+	// Format is used when the extension does not identify the format; see
+	// LoadFromFileWithFormat. The zero value is FormatAuto.
+	Format FileFormat
+}
+
+// Compile-time assertions that the built-in sources implement ConfigSource.
+var (
+	_ ConfigSource = FileSource{}
+	_ ConfigSource = EnvSource{}
+)
+
+// Apply merges the file's contents onto cfg with the semantics of
+// LoadFromFiles: only fields the file sets to a non-zero value override cfg,
+// so a layer cannot reset a boolean to false or clear an earlier value.
+// A missing file is an error unless the path is optional; a malformed file
+// always is.
+func (s FileSource) Apply(cfg *Config) error {
+	optional := strings.HasPrefix(s.Path, OptionalPathPrefix)
+	path := strings.TrimPrefix(s.Path, OptionalPathPrefix)
+
+	// Decode onto an empty Config so fields the file omits stay zero
+	// and do not clobber earlier layers with defaults.
+	layer := &Config{}
+
+	if err := decodeFile(path, s.Format, layer); err != nil {
+		if optional && errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	mergeNonZero(cfg, layer)
+
+	return nil
+}
+
+// EnvSource is a ConfigSource reading the PVE_* and related environment
+// variables, as LoadFromEnv does.
+type EnvSource struct{}
+
+// Apply overlays the set environment variables onto cfg. It fails, leaving
+// cfg unchanged, if a value cannot be parsed (see CheckEnv).
+func (EnvSource) Apply(cfg *Config) error {
+	if err := CheckEnv(); err != nil {
+		return err
+	}
+
+	LoadFromEnv(cfg)
+
+	return nil
+}
+
+// LoadFromSources starts from DefaultConfig() and applies each source in
+// order, so later sources take precedence over earlier ones. The usual
+// precedence of a file overridden by the environment is:
+//
+//	cfg, err := config.LoadFromSources(config.FileSource{Path: path}, config.EnvSource{})
+//
+// Nil sources are skipped. Loading stops at the first source that fails,
+// returning its error.
+func LoadFromSources(sources ...ConfigSource) (*Config, error) {
+	cfg := DefaultConfig()
+
+	for _, source := range sources {
+		if source == nil {
+			continue
+		}
+
+		if err := source.Apply(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	return cfg, nil
+}
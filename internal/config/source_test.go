@@ -0,0 +1,136 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSourceFile writes content to a config file in a temporary directory.
+func writeSourceFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), testConfigFileName)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+// hostnameSource is a custom ConfigSource, standing in for a remote store.
+type hostnameSource struct {
+	hostname string
+	err      error
+}
+
+func (s hostnameSource) Apply(cfg *Config) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	cfg.System.Hostname = s.hostname
+
+	return nil
+}
+
+func TestLoadFromSourcesNoSourcesReturnsDefaults(t *testing.T) {
+	cfg, err := LoadFromSources()
+
+	require.NoError(t, err)
+	assert.Equal(t, DefaultConfig(), cfg)
+}
+
+func TestLoadFromSourcesEnvOverridesFile(t *testing.T) {
+	path := writeSourceFile(t, "system:\n  hostname: file-host\n  email: file@example.com\n")
+
+	t.Setenv("PVE_HOSTNAME", "env-host")
+
+	cfg, err := LoadFromSources(FileSource{Path: path}, EnvSource{})
+
+	require.NoError(t, err)
+	assert.Equal(t, "env-host", cfg.System.Hostname, "env overrides file")
+	assert.Equal(t, "file@example.com", cfg.System.Email, "file value kept when env is unset")
+	assert.Equal(t, DefaultConfig().System.Timezone, cfg.System.Timezone, "default kept when no source sets it")
+}
+
+func TestLoadFromSourcesOrderDeterminesPrecedence(t *testing.T) {
+	path := writeSourceFile(t, "system:\n  hostname: file-host\n")
+
+	t.Setenv("PVE_HOSTNAME", "env-host")
+
+	cfg, err := LoadFromSources(EnvSource{}, FileSource{Path: path})
+
+	require.NoError(t, err)
+	assert.Equal(t, "file-host", cfg.System.Hostname, "the later source wins")
+}
+
+func TestLoadFromSourcesCustomSource(t *testing.T) {
+	path := writeSourceFile(t, "system:\n  hostname: file-host\n")
+
+	cfg, err := LoadFromSources(FileSource{Path: path}, nil, hostnameSource{hostname: "vault-host"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "vault-host", cfg.System.Hostname)
+}
+
+func TestLoadFromSourcesStopsAtFailingSource(t *testing.T) {
+	errStore := errors.New("store unreachable")
+
+	cfg, err := LoadFromSources(hostnameSource{err: errStore}, hostnameSource{hostname: "unused"})
+
+	require.ErrorIs(t, err, errStore)
+	assert.Nil(t, cfg)
+}
+
+func TestEnvSourceInvalidValue(t *testing.T) {
+	t.Setenv("SWAP_SIZE_MB", "lots")
+
+	cfg, err := LoadFromSources(EnvSource{})
+
+	require.ErrorIs(t, err, ErrEnvValueInvalid)
+	assert.Nil(t, cfg)
+}
+
+func TestFileSourceMissingFile(t *testing.T) {
+	cfg, err := LoadFromSources(FileSource{Path: filepath.Join(t.TempDir(), "missing.yaml")})
+
+	require.ErrorIs(t, err, os.ErrNotExist)
+	assert.Nil(t, cfg)
+}
+
+func TestFileSourceFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.conf")
+	require.NoError(t, os.WriteFile(path, []byte(`{"system": {"hostname": "json-host"}}`), 0o600))
+
+	cfg, err := LoadFromSources(FileSource{Path: path, Format: FormatJSON})
+
+	require.NoError(t, err)
+	assert.Equal(t, "json-host", cfg.System.Hostname)
+}
+
+func TestFileSourceOptionalMissingFile(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.yaml")
+
+	cfg, err := LoadFromSources(FileSource{Path: OptionalPathPrefix + missing})
+
+	require.NoError(t, err)
+	assert.Equal(t, DefaultConfig(), cfg)
+}
+
+func TestFileSourceMergesLikeLoadFromFiles(t *testing.T) {
+	base := writeSourceFile(t, "system:\n  hostname: base\ntailscale:\n  enabled: true\n")
+	override := writeSourceFile(t, "system:\n  hostname: \"\"\ntailscale:\n  enabled: false\n")
+
+	fromSources, err := LoadFromSources(FileSource{Path: base}, FileSource{Path: override})
+	require.NoError(t, err)
+
+	fromFiles, err := LoadFromFiles(base, override)
+	require.NoError(t, err)
+
+	assert.Equal(t, fromFiles, fromSources)
+	assert.Equal(t, "base", fromSources.System.Hostname)
+	assert.True(t, fromSources.Tailscale.Enabled)
+}
@@ -0,0 +1,43 @@
+package config
+
+import "errors"
+
+// PermitRootLogin values accepted for SystemConfig.SSHPermitRootLogin,
+// as understood by sshd_config(5).
+const (
+	// PermitRootLoginYes allows root to log in with any method.
+	PermitRootLoginYes = "yes"
+	// PermitRootLoginNo denies root logins over SSH.
+	PermitRootLoginNo = "no"
+	// PermitRootLoginProhibitPassword allows root to log in with a key only.
+	PermitRootLoginProhibitPassword = "prohibit-password"
+)
+
+// ErrPermitRootLoginInvalid is returned when SSHPermitRootLogin is not a supported value.
+var ErrPermitRootLoginInvalid = errors.New("ssh permit root login must be one of: yes, no, prohibit-password")
+
+// ValidatePermitRootLogin checks that value is one of the PermitRootLogin
+// constants. Empty is valid and means the value is derived, see
+// SystemConfig.PermitRootLogin.
+func ValidatePermitRootLogin(value string) error {
+	switch value {
+	case "", PermitRootLoginYes, PermitRootLoginNo, PermitRootLoginProhibitPassword:
+		return nil
+	default:
+		return ErrPermitRootLoginInvalid
+	}
+}
+
+// PermitRootLogin returns the sshd PermitRootLogin value to apply:
+// SSHPermitRootLogin if set, otherwise prohibit-password when an SSH public
+// key is configured (keys become the only way in for root) and yes without one.
+func (s *SystemConfig) PermitRootLogin() string {
+	switch {
+	case s.SSHPermitRootLogin != "":
+		return s.SSHPermitRootLogin
+	case s.SSHPublicKey != "":
+		return PermitRootLoginProhibitPassword
+	default:
+		return PermitRootLoginYes
+	}
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePermitRootLogin(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected error
+	}{
+		{"", nil},
+		{PermitRootLoginYes, nil},
+		{PermitRootLoginNo, nil},
+		{PermitRootLoginProhibitPassword, nil},
+		{"without-password", ErrPermitRootLoginInvalid},
+		{"forced-commands-only", ErrPermitRootLoginInvalid},
+		{"Yes", ErrPermitRootLoginInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ValidatePermitRootLogin(tt.value))
+		})
+	}
+}
+
+func TestSystemConfigPermitRootLogin(t *testing.T) {
+	tests := []struct {
+		name     string
+		system   SystemConfig
+		expected string
+	}{
+		{"no key allows password login", SystemConfig{}, PermitRootLoginYes},
+		{"key restricts root to keys", SystemConfig{SSHPublicKey: testValidSSHKey}, PermitRootLoginProhibitPassword},
+		{
+			"explicit value wins over key",
+			SystemConfig{SSHPublicKey: testValidSSHKey, SSHPermitRootLogin: PermitRootLoginYes},
+			PermitRootLoginYes,
+		},
+		{"explicit value without key", SystemConfig{SSHPermitRootLogin: PermitRootLoginNo}, PermitRootLoginNo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.system.PermitRootLogin())
+		})
+	}
+}
+
+func TestLoadFromEnvSSHHardening(t *testing.T) {
+	t.Setenv("PVE_SSH_DISABLE_PASSWORD_AUTH", "yes")
+	t.Setenv("PVE_SSH_PERMIT_ROOT_LOGIN", "Prohibit-Password")
+
+	cfg := DefaultConfig()
+	LoadFromEnv(cfg)
+
+	assert.True(t, cfg.System.SSHDisablePasswordAuth)
+	assert.Equal(t, PermitRootLoginProhibitPassword, cfg.System.SSHPermitRootLogin)
+}
+
+func TestConfigValidatePermitRootLogin(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+	cfg.System.SSHPermitRootLogin = "maybe"
+
+	var validationErr *ValidationError
+
+	require.ErrorAs(t, cfg.Validate(), &validationErr)
+	assert.Equal(t, []error{ErrPermitRootLoginInvalid}, validationErr.Errors)
+	assert.Equal(t, "system.ssh_permit_root_login", cfg.FieldErrors()[0].Field)
+}
+
+func TestApplyOverridesSSHPermitRootLogin(t *testing.T) {
+	cfg := DefaultConfig()
+
+	require.NoError(t, ApplyOverrides(cfg, []string{"system.ssh_permit_root_login=NO"}))
+	assert.Equal(t, PermitRootLoginNo, cfg.System.SSHPermitRootLogin)
+
+	err := ApplyOverrides(cfg, []string{"system.ssh_permit_root_login=maybe"})
+	assert.ErrorIs(t, err, ErrPermitRootLoginInvalid)
+	assert.Equal(t, PermitRootLoginNo, cfg.System.SSHPermitRootLogin)
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrSSHKeyPathUnsupported is returned for "~user" paths, which are not expanded.
+var ErrSSHKeyPathUnsupported = errors.New("SSH public key path must start with / or ~/")
+
+// IsSSHKeyPath reports whether an SSHPublicKey value names a key file rather
+// than holding the key inline: it starts with "/" or "~" and has no key prefix.
+func IsSSHKeyPath(value string) bool {
+	if !strings.HasPrefix(value, "/") && !strings.HasPrefix(value, "~") {
+		return false
+	}
+
+	for _, prefix := range sshKeyPrefixes {
+		if strings.HasPrefix(value, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ResolveSSHPublicKey returns the SSH public key for an SSHPublicKey value.
+//
+// Inline keys and empty values are returned unchanged. A value that is a file
+// path (see IsSSHKeyPath), such as "~/.ssh/id_ed25519.pub", is read instead:
+// a leading "~" resolves to the current user's home directory, surrounding
+// whitespace is trimmed, and the contents must pass ValidateSSHKey.
+func ResolveSSHPublicKey(value string) (string, error) {
+	if !IsSSHKeyPath(value) {
+		return value, nil
+	}
+
+	path, err := ExpandPath(value)
+	if errors.Is(err, ErrPathUserHomeUnsupported) {
+		return "", fmt.Errorf("%w: got %q", ErrSSHKeyPathUnsupported, value)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read SSH public key file: %w", err)
+	}
+
+	key := strings.TrimSpace(string(data))
+	if err := ValidateSSHKey(key); err != nil {
+		return "", fmt.Errorf("invalid SSH public key in %s: %w", path, err)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// untrimmedFields are the "Section.Field" names TrimSpace leaves alone:
+// passwords, where surrounding whitespace may be part of the secret.
+var untrimmedFields = map[string]bool{
+	"System.RootPassword": true,
+	"Cluster.Password":    true,
+}
+
+// TrimSpace removes leading and trailing whitespace, typically from
+// copy-paste, from every string field and every element of string list
+// fields (Disks, NTPServers) in place. Call it before Validate. Passwords
+// are left untouched, since whitespace may be intentional there.
+//
+// Enum fields (BridgeMode, ZFSRaid, NetworkBackend) are trimmed but not
+// lowercased; their case is normalized where they are parsed, from YAML and
+// environment variables. A nil Config is a no-op.
+func (c *Config) TrimSpace() {
+	if c == nil {
+		return
+	}
+
+	cfg := reflect.ValueOf(c).Elem()
+
+	for i := range cfg.NumField() {
+		section := cfg.Field(i)
+		if section.Kind() != reflect.Struct {
+			continue
+		}
+
+		sectionName := cfg.Type().Field(i).Name
+
+		for j := range section.NumField() {
+			if untrimmedFields[sectionName+"."+section.Type().Field(j).Name] {
+				continue
+			}
+
+			trimValue(section.Field(j))
+		}
+	}
+}
+
+// trimValue trims v in place if it is a string or a slice of strings.
+func trimValue(v reflect.Value) {
+	switch {
+	case v.Kind() == reflect.String:
+		v.SetString(strings.TrimSpace(v.String()))
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		for i := range v.Len() {
+			trimValue(v.Index(i))
+		}
+	}
+}
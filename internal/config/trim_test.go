@@ -0,0 +1,88 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigTrimSpace(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.Hostname = "  pve1\n"
+	cfg.System.Email = "\tadmin@example.com "
+	cfg.System.SSHPublicKey = testValidSSHKey + "\n"
+	cfg.System.NTPServers = []string{" ntp1.hetzner.de", "ntp2.hetzner.com\t"}
+	cfg.Network.BridgeMode = " external "
+	cfg.Network.PrivateSubnet = "10.0.0.0/24 "
+	cfg.Storage.Disks = []string{" /dev/sda", "/dev/sdb\r\n"}
+	cfg.Tailscale.AuthKey = " tskey-auth-abc "
+	cfg.ACME.Email = " certs@example.com"
+
+	cfg.TrimSpace()
+
+	assert.Equal(t, "pve1", cfg.System.Hostname)
+	assert.Equal(t, "admin@example.com", cfg.System.Email)
+	assert.Equal(t, testValidSSHKey, cfg.System.SSHPublicKey)
+	assert.Equal(t, []string{"ntp1.hetzner.de", "ntp2.hetzner.com"}, cfg.System.NTPServers)
+	assert.Equal(t, BridgeModeExternal, cfg.Network.BridgeMode)
+	assert.Equal(t, "10.0.0.0/24", cfg.Network.PrivateSubnet)
+	assert.Equal(t, []string{"/dev/sda", "/dev/sdb"}, cfg.Storage.Disks)
+	assert.Equal(t, "tskey-auth-abc", cfg.Tailscale.AuthKey)
+	assert.Equal(t, "certs@example.com", cfg.ACME.Email)
+}
+
+func TestConfigTrimSpaceKeepsPasswords(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.RootPassword = " secret with spaces "
+	cfg.Cluster.Password = "cluster-secret\t"
+
+	cfg.TrimSpace()
+
+	assert.Equal(t, " secret with spaces ", cfg.System.RootPassword)
+	assert.Equal(t, "cluster-secret\t", cfg.Cluster.Password)
+}
+
+func TestConfigTrimSpaceEnumCaseUnchanged(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Storage.ZFSRaid = " RAID1 "
+
+	cfg.TrimSpace()
+
+	assert.Equal(t, ZFSRaid("RAID1"), cfg.Storage.ZFSRaid)
+}
+
+func TestConfigTrimSpaceCleanConfigNoop(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.RootPassword = testValidPassword
+	cfg.Storage.Disks = []string{"/dev/sda", "/dev/sdb"}
+
+	expected := *cfg
+	expected.Storage.Disks = []string{"/dev/sda", "/dev/sdb"}
+	expected.System.NTPServers = append([]string(nil), cfg.System.NTPServers...)
+
+	cfg.TrimSpace()
+
+	assert.Equal(t, &expected, cfg)
+}
+
+func TestConfigTrimSpaceNil(t *testing.T) {
+	var cfg *Config
+
+	assert.NotPanics(t, cfg.TrimSpace)
+}
+
+func TestConfigTrimSpaceThenValidate(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+	cfg.System.Hostname = " pve1 "
+	cfg.System.Email = "admin@example.com\n"
+	cfg.Storage.Disks = []string{" /dev/sda", "/dev/sdb "}
+
+	require.Error(t, cfg.Validate(), "whitespace fails validation before trimming")
+
+	cfg.TrimSpace()
+
+	assert.NoError(t, cfg.Validate())
+}
@@ -4,10 +4,15 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"net"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode"
 )
 
 // Hostname validation constants.
@@ -16,6 +21,21 @@ const (
 	maxHostnameLength = 63
 )
 
+// Subnet validation constants.
+const (
+	// MaxPrivateSubnetPrefixIPv4 is the longest IPv4 prefix accepted for the NAT
+	// private subnet. A /29 leaves 6 usable addresses: the bridge gateway plus
+	// five guests. Anything smaller cannot meaningfully host guests behind NAT.
+	MaxPrivateSubnetPrefixIPv4 = 29
+)
+
+// Disk validation constants.
+const (
+	// MaxDisks is the largest number of disks accepted for the ZFS pool. Longer
+	// lists almost certainly select disks by mistake (e.g., a glob over /dev).
+	MaxDisks = 24
+)
+
 // Hostname validation errors.
 var (
 	// ErrHostnameEmpty is returned when hostname is empty.
@@ -28,8 +48,66 @@ var (
 	ErrHostnameEndsWithHyphen = errors.New("hostname cannot end with a hyphen")
 	// ErrHostnameInvalidChars is returned when hostname contains invalid characters.
 	ErrHostnameInvalidChars = errors.New("hostname can only contain alphanumeric characters and hyphens")
+	// ErrHostnameReserved is returned when hostname is a reserved name (policy only).
+	ErrHostnameReserved = errors.New("hostname is a reserved name")
+	// ErrHostnameAllNumeric is returned when hostname contains only digits (policy only).
+	ErrHostnameAllNumeric = errors.New("hostname cannot be all numeric")
+	// ErrHostnameTooShort is returned when hostname is shorter than the policy minimum.
+	ErrHostnameTooShort = errors.New("hostname is shorter than the required minimum length")
 )
 
+// reservedHostnames contains special-use names (RFC 6761) that resolve
+// unexpectedly or collide with local resolution when used as a hostname.
+var reservedHostnames = map[string]bool{
+	"localhost":   true,
+	"localdomain": true,
+	"local":       true,
+	"invalid":     true,
+}
+
+// HostnamePolicy holds optional hostname rules applied on top of RFC 1123.
+// The zero value applies no extra rules, matching ValidateHostname.
+type HostnamePolicy struct {
+	// RejectReserved rejects special-use names such as "localhost".
+	RejectReserved bool
+
+	// RejectAllNumeric rejects names made only of digits, which look like IP addresses.
+	RejectAllNumeric bool
+
+	// MinHostnameLength is the minimum hostname length in characters.
+	// Values below 1 keep the RFC 1123 minimum of a single character.
+	MinHostnameLength int
+}
+
+// StrictHostnamePolicy enables all optional hostname rules.
+var StrictHostnamePolicy = HostnamePolicy{
+	RejectReserved:   true,
+	RejectAllNumeric: true,
+}
+
+// TimezonePolicy holds optional timezone rules applied on top of the IANA check.
+// The zero value applies no extra rules, matching ValidateTimezone.
+type TimezonePolicy struct {
+	// RejectLocal rejects "Local", which takes whatever zone the installing
+	// system has and so is not deterministic for unattended installs.
+	RejectLocal bool
+}
+
+// StrictTimezonePolicy enables all optional timezone rules.
+var StrictTimezonePolicy = TimezonePolicy{
+	RejectLocal: true,
+}
+
+// ValidationPolicy holds optional, stricter rules for Config.ValidateWithPolicy.
+// The zero value matches Config.Validate.
+type ValidationPolicy struct {
+	// Hostname holds optional hostname rules.
+	Hostname HostnamePolicy
+
+	// Timezone holds optional timezone rules.
+	Timezone TimezonePolicy
+}
+
 // Email validation errors.
 var (
 	// ErrEmailEmpty is returned when email is empty.
@@ -66,6 +144,8 @@ var (
 	ErrTimezoneEmpty = errors.New("timezone is required")
 	// ErrTimezoneInvalid is returned when timezone is not in the IANA timezone database.
 	ErrTimezoneInvalid = errors.New("timezone not found in IANA timezone database")
+	// ErrTimezoneLocalNotAllowed is returned when timezone is "Local" and the policy requires an explicit zone.
+	ErrTimezoneLocalNotAllowed = errors.New(`timezone "Local" is not allowed; set an explicit zone such as UTC`)
 )
 
 // Bridge mode validation errors.
@@ -84,14 +164,82 @@ var (
 	ErrZFSRaidInvalid = errors.New("ZFS RAID level must be one of: single, raid0, raid1")
 )
 
+// Network backend validation errors.
+var (
+	// ErrNetworkBackendInvalid is returned when the network backend is not a valid value.
+	ErrNetworkBackendInvalid = errors.New("network backend must be one of: ifupdown2, networkd")
+)
+
 // Subnet validation errors.
 var (
 	// ErrSubnetEmpty is returned when subnet is empty.
 	ErrSubnetEmpty = errors.New("subnet is required")
 	// ErrSubnetInvalid is returned when subnet is not in valid CIDR notation.
 	ErrSubnetInvalid = errors.New("subnet must be in valid CIDR notation (e.g., 10.0.0.0/24)")
+	// ErrSubnetTooSmall is returned when the private subnet has too few usable hosts for NAT.
+	ErrSubnetTooSmall = errors.New("private subnet is too small for NAT (IPv4 prefix must be /29 or larger)")
+	// ErrAdditionalSubnetInvalid is returned when a configured additional subnet is not valid CIDR.
+	ErrAdditionalSubnetInvalid = errors.New("additional subnet must be in valid CIDR notation (e.g., 203.0.113.8/29)")
 )
 
+// MAC address validation errors.
+var (
+	// ErrMACInvalid is returned when a MAC address is not six colon- or dash-separated hex bytes.
+	ErrMACInvalid = errors.New("MAC address must be six colon- or dash-separated hex bytes (e.g., 90:1b:0e:aa:bb:cc)")
+)
+
+// Swap size validation errors.
+var (
+	// ErrSwapSizeNegative is returned when swap size is below zero.
+	ErrSwapSizeNegative = errors.New("swap size cannot be negative")
+	// ErrSwapSizeTooLarge is returned when swap size exceeds twice the installed memory.
+	ErrSwapSizeTooLarge = errors.New("swap size cannot exceed twice the installed memory")
+)
+
+// Disk validation errors.
+var (
+	// ErrDiskPathInvalid is returned when a disk path is not a device path under /dev/.
+	ErrDiskPathInvalid = errors.New("disk path must be a device path starting with /dev/ (e.g., /dev/sda)")
+	// ErrDiskDuplicate is returned when a disk is already in the disk list.
+	ErrDiskDuplicate = errors.New("disk is already selected")
+	// ErrTooFewDisks is returned when the disk list is shorter than the RAID level requires.
+	ErrTooFewDisks = errors.New("not enough disks for the selected ZFS RAID level")
+	// ErrTooManyDisks is returned when the disk list exceeds MaxDisks.
+	ErrTooManyDisks = errors.New("too many disks selected")
+	// ErrRaidMirrorOddDisks is returned when raid1 is given an odd number of disks.
+	ErrRaidMirrorOddDisks = errors.New("raid1 requires an even number of disks")
+)
+
+// NTP validation errors.
+var (
+	// ErrNTPServerInvalid is returned when an NTP server is not an IP address or hostname.
+	ErrNTPServerInvalid = errors.New("NTP server must be an IP address or hostname (e.g., ntp1.hetzner.de)")
+)
+
+// Cluster join validation errors.
+var (
+	// ErrClusterJoinAddressEmpty is returned when a cluster fingerprint is set without a join address.
+	ErrClusterJoinAddressEmpty = errors.New("cluster join address is required when joining a cluster")
+	// ErrClusterJoinAddressInvalid is returned when the join address is not an IP or hostname with optional port.
+	ErrClusterJoinAddressInvalid = errors.New("cluster join address must be an IP address or hostname with optional port (e.g., 10.0.0.2:8006)")
+	// ErrClusterFingerprintEmpty is returned when a join address is set without a fingerprint.
+	ErrClusterFingerprintEmpty = errors.New("cluster fingerprint is required when joining a cluster")
+	// ErrClusterFingerprintInvalid is returned when the fingerprint is not a colon-separated SHA-256 digest.
+	ErrClusterFingerprintInvalid = errors.New("cluster fingerprint must be 32 colon-separated hex bytes (SHA-256)")
+	// ErrClusterPasswordEmpty is returned when joining a cluster without the cluster node's root password.
+	ErrClusterPasswordEmpty = errors.New("cluster password is required when joining a cluster")
+)
+
+// ErrACMEDomainSuffixEmpty is returned when a certificate is requested without
+// a domain suffix, which leaves FQDN a bare hostname no CA will issue for.
+var ErrACMEDomainSuffixEmpty = errors.New("domain suffix is required to order a Let's Encrypt certificate")
+
+// clusterFingerprintRegex matches a SHA-256 digest as 32 colon-separated hex bytes.
+var clusterFingerprintRegex = regexp.MustCompile(`^[0-9A-Fa-f]{2}(:[0-9A-Fa-f]{2}){31}$`)
+
+// macRegex matches a six-byte MAC address using either colons or dashes, not a mix.
+var macRegex = regexp.MustCompile(`^[0-9A-Fa-f]{2}(:[0-9A-Fa-f]{2}){5}$|^[0-9A-Fa-f]{2}(-[0-9A-Fa-f]{2}){5}$`)
+
 // hostnameRegex matches valid RFC 1123 hostname characters (alphanumeric and hyphens).
 var hostnameRegex = regexp.MustCompile(`^[a-zA-Z0-9-]+$`)
 
@@ -129,6 +277,29 @@ func ValidateHostname(hostname string) error {
 	return nil
 }
 
+// ValidateHostnameWithPolicy validates a hostname according to RFC 1123
+// and then applies the optional rules enabled in policy.
+// With a zero HostnamePolicy it behaves exactly like ValidateHostname.
+func ValidateHostnameWithPolicy(hostname string, policy HostnamePolicy) error {
+	if err := ValidateHostname(hostname); err != nil {
+		return err
+	}
+
+	if len(hostname) < policy.MinHostnameLength {
+		return ErrHostnameTooShort
+	}
+
+	if policy.RejectReserved && reservedHostnames[strings.ToLower(hostname)] {
+		return ErrHostnameReserved
+	}
+
+	if policy.RejectAllNumeric && strings.Trim(hostname, "0123456789") == "" {
+		return ErrHostnameAllNumeric
+	}
+
+	return nil
+}
+
 // ValidateEmail validates an email address format.
 // A valid email:
 //   - Must not be empty
@@ -147,6 +318,33 @@ func ValidateEmail(email string) error {
 	return nil
 }
 
+// CanonicalizeEmail validates email and returns its canonical form with the
+// domain part lowercased (e.g., "User@Example.COM" becomes "User@example.com").
+//
+// Domain names are case-insensitive, so this form is safe for comparisons
+// and ACME (Let's Encrypt) registration. The local part is left unchanged
+// because RFC 5321 allows mail servers to treat it as case-sensitive.
+// Validation errors from ValidateEmail are returned as-is.
+func CanonicalizeEmail(email string) (string, error) {
+	if err := ValidateEmail(email); err != nil {
+		return "", err
+	}
+
+	at := strings.LastIndex(email, "@")
+
+	return email[:at] + strings.ToLower(email[at:]), nil
+}
+
+// canonicalEmail returns the canonical form of email, or email unchanged if
+// it is invalid so that Validate can report it.
+func canonicalEmail(email string) string {
+	if canonical, err := CanonicalizeEmail(email); err == nil {
+		return canonical
+	}
+
+	return email
+}
+
 // ValidatePassword validates a password for Proxmox root user.
 // A valid password:
 //   - Must not be empty
@@ -258,19 +456,73 @@ func ValidateSSHKey(key string) error {
 //   - Must not be empty
 //   - Must exist in the IANA timezone database (e.g., "UTC", "Europe/Kyiv", "America/New_York")
 //   - "Local" is a valid special case representing the system's local timezone
+//
+// Results are cached per timezone name, since loading a zone reads the
+// filesystem and a TUI may validate on every keystroke.
 func ValidateTimezone(timezone string) error {
 	if timezone == "" {
 		return ErrTimezoneEmpty
 	}
 
-	_, err := time.LoadLocation(timezone)
-	if err != nil {
+	if !timezoneValid(timezone) {
 		return ErrTimezoneInvalid
 	}
 
 	return nil
 }
 
+// maxCachedTimezones bounds the timezone cache, so arbitrary input such as
+// every prefix typed into a TUI field cannot grow it without limit.
+const maxCachedTimezones = 1024
+
+// timezoneCache maps timezone names to whether time.LoadLocation accepts
+// them; timezoneCacheSize counts its entries.
+var (
+	timezoneCache     sync.Map
+	timezoneCacheSize atomic.Int64
+)
+
+// timezoneValid reports whether time.LoadLocation accepts timezone, using
+// the cache when possible. Once the cache is full, new names are still
+// checked but no longer stored.
+func timezoneValid(timezone string) bool {
+	if valid, ok := timezoneCache.Load(timezone); ok {
+		return valid.(bool) //nolint:forcetypeassert // only bools are stored
+	}
+
+	valid := loadTimezoneValid(timezone)
+
+	if timezoneCacheSize.Load() < maxCachedTimezones {
+		if _, loaded := timezoneCache.LoadOrStore(timezone, valid); !loaded {
+			timezoneCacheSize.Add(1)
+		}
+	}
+
+	return valid
+}
+
+// loadTimezoneValid reports whether time.LoadLocation accepts timezone, without caching.
+func loadTimezoneValid(timezone string) bool {
+	_, err := time.LoadLocation(timezone)
+
+	return err == nil
+}
+
+// ValidateTimezoneWithPolicy validates timezone like ValidateTimezone and then
+// applies the optional rules enabled in policy.
+// With a zero TimezonePolicy it behaves exactly like ValidateTimezone.
+func ValidateTimezoneWithPolicy(timezone string, policy TimezonePolicy) error {
+	if err := ValidateTimezone(timezone); err != nil {
+		return err
+	}
+
+	if policy.RejectLocal && timezone == "Local" {
+		return ErrTimezoneLocalNotAllowed
+	}
+
+	return nil
+}
+
 // ValidateBridgeMode validates a network bridge mode.
 // A valid bridge mode:
 //   - Must not be empty
@@ -289,6 +541,17 @@ func ValidateBridgeMode(mode BridgeMode) error {
 	return nil
 }
 
+// ValidateNetworkBackend validates a network configuration backend.
+// An empty value selects the default (ifupdown2) and is valid; otherwise
+// the value must be one of: ifupdown2, networkd.
+func ValidateNetworkBackend(backend NetworkBackend) error {
+	if backend != "" && !backend.IsValid() {
+		return ErrNetworkBackendInvalid
+	}
+
+	return nil
+}
+
 // ValidateZFSRaid validates a ZFS RAID level.
 // A valid ZFS RAID level:
 //   - Must not be empty
@@ -326,50 +589,403 @@ func ValidateSubnet(subnet string) error {
 	return nil
 }
 
+// ValidatePrivateSubnetSize validates that a private subnet can host guests
+// behind NAT for the given bridge mode.
+// The check applies only when mode is internal or both, since the private
+// subnet is unused in external mode. For IPv4 subnets the prefix length must
+// not exceed MaxPrivateSubnetPrefixIPv4 (/29). IPv6 subnets are not checked.
+//
+// The subnet must already be valid CIDR; see ValidateSubnet.
+func ValidatePrivateSubnetSize(subnet string, mode BridgeMode) error {
+	if mode != BridgeModeInternal && mode != BridgeModeBoth {
+		return nil
+	}
+
+	_, ipNet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return ErrSubnetInvalid
+	}
+
+	ones, bits := ipNet.Mask.Size()
+	if bits == net.IPv4len*8 && ones > MaxPrivateSubnetPrefixIPv4 {
+		return ErrSubnetTooSmall
+	}
+
+	return nil
+}
+
+// ValidateAdditionalSubnet validates an optional additional subnet.
+// An empty value means no additional subnet and is valid; otherwise the value
+// must be in valid CIDR notation.
+func ValidateAdditionalSubnet(subnet string) error {
+	if subnet == "" {
+		return nil
+	}
+
+	if _, _, err := net.ParseCIDR(subnet); err != nil {
+		return ErrAdditionalSubnetInvalid
+	}
+
+	return nil
+}
+
+// ValidateMAC validates a MAC address in colon ("90:1b:0e:aa:bb:cc") or dash
+// ("90-1B-0E-AA-BB-CC") form. Other notations accepted by net.ParseMAC, such as
+// dotted or 20-byte InfiniBand addresses, are rejected.
+func ValidateMAC(mac string) error {
+	if !macRegex.MatchString(mac) {
+		return ErrMACInvalid
+	}
+
+	return nil
+}
+
+// ValidateDiskPath checks that path is a device path under /dev/.
+// The device itself is not checked for existence.
+func ValidateDiskPath(path string) error {
+	name, ok := strings.CutPrefix(path, "/dev/")
+	if !ok || name == "" || strings.ContainsFunc(path, unicode.IsSpace) {
+		return ErrDiskPathInvalid
+	}
+
+	return nil
+}
+
+// ValidateDiskCount checks that count disks suit the ZFS RAID level.
+// A valid disk count:
+//   - Must be at least 1, or at least 2 for raid0 and raid1
+//   - Must not exceed MaxDisks
+//   - Must be even for raid1, since disks are paired into mirrors
+func ValidateDiskCount(raid ZFSRaid, count int) error {
+	minDisks := 1
+	if raid == ZFSRaid0 || raid == ZFSRaid1 {
+		minDisks = 2
+	}
+
+	switch {
+	case count < minDisks:
+		return ErrTooFewDisks
+	case count > MaxDisks:
+		return fmt.Errorf("%w (maximum %d)", ErrTooManyDisks, MaxDisks)
+	case raid == ZFSRaid1 && count%2 != 0:
+		return ErrRaidMirrorOddDisks
+	}
+
+	return nil
+}
+
+// ValidateClusterJoinAddress checks that address is an IP address or a
+// hostname (optionally fully qualified), optionally followed by ":port".
+// IPv6 addresses with a port must be bracketed (e.g., "[2001:db8::2]:8006").
+func ValidateClusterJoinAddress(address string) error {
+	if address == "" {
+		return ErrClusterJoinAddressEmpty
+	}
+
+	host := address
+
+	if h, port, err := net.SplitHostPort(address); err == nil {
+		n, err := strconv.Atoi(port)
+		if err != nil || n < 1 || n > 65535 {
+			return ErrClusterJoinAddressInvalid
+		}
+
+		host = h
+	}
+
+	if !isHostOrIP(host) {
+		return ErrClusterJoinAddressInvalid
+	}
+
+	return nil
+}
+
+// isHostOrIP reports whether host is an IP address or a hostname, optionally
+// fully qualified, whose labels are each valid per RFC 1123.
+func isHostOrIP(host string) bool {
+	if net.ParseIP(host) != nil {
+		return true
+	}
+
+	for _, label := range strings.Split(host, ".") {
+		if ValidateHostname(label) != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ValidateNTPServer checks that server is an IP address or a hostname,
+// optionally fully qualified (e.g., "ntp1.hetzner.de").
+func ValidateNTPServer(server string) error {
+	if server == "" || !isHostOrIP(server) {
+		return ErrNTPServerInvalid
+	}
+
+	return nil
+}
+
+// ValidateNTPServers validates each entry of an NTP server list with
+// ValidateNTPServer. An empty list is valid and leaves the time daemon's
+// defaults in place. All invalid entries are reported in one joined error,
+// each naming the offending value.
+func ValidateNTPServers(servers []string) error {
+	var errs []error
+
+	for _, server := range servers {
+		if err := ValidateNTPServer(server); err != nil {
+			errs = append(errs, fmt.Errorf("%q: %w", server, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidateClusterFingerprint checks that fingerprint is a SHA-256 digest in
+// the colon-separated hex form printed by "pvenode cert info".
+func ValidateClusterFingerprint(fingerprint string) error {
+	if fingerprint == "" {
+		return ErrClusterFingerprintEmpty
+	}
+
+	if !clusterFingerprintRegex.MatchString(fingerprint) {
+		return ErrClusterFingerprintInvalid
+	}
+
+	return nil
+}
+
+// ValidateSwapSize validates a swap size in megabytes.
+// A valid swap size:
+//   - Must not be negative (0 disables swap)
+//   - Must not exceed twice the installed memory when memoryMB is known
+//
+// Pass memoryMB as 0 when the installed memory has not been detected yet;
+// the upper bound is then skipped.
+func ValidateSwapSize(sizeMB, memoryMB int) error {
+	if sizeMB < 0 {
+		return ErrSwapSizeNegative
+	}
+
+	if memoryMB > 0 && sizeMB > memoryMB*2 {
+		return ErrSwapSizeTooLarge
+	}
+
+	return nil
+}
+
 // Validate validates the entire configuration.
 // It runs all validation checks and returns all errors found,
 // not just the first one, allowing users to fix all issues at once.
 func (c *Config) Validate() error {
-	var errs []error
+	return c.ValidateWithPolicy(ValidationPolicy{})
+}
 
-	// System validations
-	if err := ValidateHostname(c.System.Hostname); err != nil {
-		errs = append(errs, err)
+// ValidateWithPolicy validates the entire configuration like Validate,
+// additionally applying the optional rules enabled in policy.
+func (c *Config) ValidateWithPolicy(policy ValidationPolicy) error {
+	return c.validate(policy, true)
+}
+
+// ValidateStructure validates the configuration like Validate, except that the
+// root password and SSH public key are only checked when set.
+//
+// Saved configuration files never contain these secrets (see SaveToFile), so
+// this is the appropriate check for files before secrets are supplied via
+// environment variables or TUI input.
+func (c *Config) ValidateStructure() error {
+	return c.validate(ValidationPolicy{}, false)
+}
+
+// validate runs all validation checks. When requireSecrets is false, empty
+// secrets are not reported.
+func (c *Config) validate(policy ValidationPolicy, requireSecrets bool) error {
+	fieldErrs := c.fieldErrors(policy, requireSecrets)
+	if len(fieldErrs) == 0 {
+		return nil
 	}
 
-	if err := ValidateEmail(c.System.Email); err != nil {
-		errs = append(errs, err)
+	errs := make([]error, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		errs = append(errs, fe.Err)
 	}
 
-	if err := ValidatePassword(c.System.RootPassword); err != nil {
-		errs = append(errs, err)
+	return &ValidationError{Errors: errs}
+}
+
+// FieldError is a validation error or warning attributed to a configuration field.
+type FieldError struct {
+	// Field is the YAML path of the field (e.g., "network.private_subnet").
+	Field string
+
+	// Err is the underlying validation error.
+	Err error
+}
+
+// Error returns the field path followed by the error message.
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error for use with errors.Is() and errors.As().
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Section returns the top-level configuration section of the field (e.g., "network").
+func (e FieldError) Section() string {
+	section, _, _ := strings.Cut(e.Field, ".")
+
+	return section
+}
+
+// FieldErrors runs the same checks as Validate and returns each failure
+// together with the field it belongs to, in validation order.
+// Returns nil if the configuration is valid.
+func (c *Config) FieldErrors() []FieldError {
+	return c.fieldErrors(ValidationPolicy{}, true)
+}
+
+// fieldErrors runs all validation checks and attributes each error to its field.
+func (c *Config) fieldErrors(policy ValidationPolicy, requireSecrets bool) []FieldError {
+	var errs []FieldError
+
+	add := func(field string, err error) {
+		if err != nil {
+			errs = append(errs, FieldError{Field: field, Err: err})
+		}
 	}
 
-	if err := ValidateSSHKey(c.System.SSHPublicKey); err != nil {
-		errs = append(errs, err)
+	// System validations
+	add("system.hostname", ValidateHostnameWithPolicy(c.System.Hostname, policy.Hostname))
+	add("system.email", ValidateEmail(c.System.Email))
+
+	if requireSecrets || c.System.RootPassword != "" {
+		add("system.root_password", ValidatePassword(c.System.RootPassword))
 	}
 
-	if err := ValidateTimezone(c.System.Timezone); err != nil {
-		errs = append(errs, err)
+	if requireSecrets || c.System.SSHPublicKey != "" {
+		add("system.ssh_public_key", ValidateSSHKey(c.System.SSHPublicKey))
 	}
 
+	add("system.timezone", ValidateTimezoneWithPolicy(c.System.Timezone, policy.Timezone))
+	add("system.ntp_servers", ValidateNTPServers(c.System.NTPServers))
+	add("system.keyboard", ValidateKeyboard(c.System.Keyboard))
+	add("system.locale", ValidateLocale(c.System.Locale))
+	add("system.ssh_permit_root_login", ValidatePermitRootLogin(c.System.SSHPermitRootLogin))
+	add("system.proxmox_version", ValidateProxmoxVersion(c.System.ProxmoxVersion))
+	add("system.admin_user", ValidateAdminUser(c.System.AdminUser))
+	add("system.admin_ssh_keys", c.System.validateAdminSSHKeys())
+
 	// Network validations
-	if err := ValidateBridgeMode(c.Network.BridgeMode); err != nil {
-		errs = append(errs, err)
-	}
+	add("network.bridge_mode", ValidateBridgeMode(c.Network.BridgeMode))
+	add("network.network_backend", ValidateNetworkBackend(c.Network.NetworkBackend))
 
 	if err := ValidateSubnet(c.Network.PrivateSubnet); err != nil {
-		errs = append(errs, err)
+		add("network.private_subnet", err)
+	} else {
+		add("network.private_subnet", ValidatePrivateSubnetSize(c.Network.PrivateSubnet, c.Network.BridgeMode))
+	}
+
+	add("network.additional_subnet", ValidateAdditionalSubnet(c.Network.AdditionalSubnet))
+
+	if c.Network.BridgeMAC != "" {
+		add("network.bridge_mac", ValidateMAC(c.Network.BridgeMAC))
 	}
 
 	// Storage validations
-	if err := ValidateZFSRaid(c.Storage.ZFSRaid); err != nil {
-		errs = append(errs, err)
+	add("storage.zfs_raid", ValidateZFSRaid(c.Storage.ZFSRaid))
+
+	// An empty disk list means the disks are auto-detected later.
+	if len(c.Storage.Disks) > 0 {
+		add("storage.disks", ValidateDiskCount(c.Storage.ZFSRaid, len(c.Storage.Disks)))
 	}
 
-	if len(errs) > 0 {
-		return &ValidationError{Errors: errs}
+	// Memory is not known before hardware detection; SwapStep re-checks the upper bound.
+	add("storage.swap_size_mb", ValidateSwapSize(c.Storage.SwapSizeMB, 0))
+
+	// Cluster validations, only when joining a cluster
+	if c.Cluster.JoinRequested() {
+		add("cluster.join_address", ValidateClusterJoinAddress(c.Cluster.JoinAddress))
+		add("cluster.fingerprint", ValidateClusterFingerprint(c.Cluster.Fingerprint))
+
+		if requireSecrets && c.Cluster.Password == "" {
+			add("cluster.password", ErrClusterPasswordEmpty)
+		}
+	}
+
+	// ACME validations, only when a certificate is requested. An empty
+	// ACME.Email inherits System.Email, which is already validated above;
+	// the certificate is ordered for FQDN, which needs a domain suffix.
+	if c.ACME.Enabled && c.ACME.Email != "" {
+		add("acme.email", ValidateEmail(c.ACME.Email))
+	}
+
+	if c.ACME.Enabled && c.System.DomainSuffix == "" {
+		add("system.domain_suffix", ErrACMEDomainSuffixEmpty)
+	}
+
+	return errs
+}
+
+// Validation warnings. These describe settings that are valid but likely
+// unintended; they never cause validation to fail.
+var (
+	// ErrZFSRaid0NoRedundancy warns that raid0 loses all data if any disk fails.
+	ErrZFSRaid0NoRedundancy = errors.New("raid0 has no redundancy; a single disk failure loses all data")
+	// ErrTailscaleAuthKeyMissing warns that Tailscale will require interactive login.
+	ErrTailscaleAuthKeyMissing = errors.New("Tailscale is enabled without an auth key; login will be interactive")
+	// ErrHostnameDotted warns that a dotted hostname is joined with the domain suffix again.
+	ErrHostnameDotted = errors.New("hostname contains dots; the FQDN would repeat them before the domain suffix")
+	// ErrHostnameRepeatsDomain warns that the hostname equals the first label of the domain suffix.
+	ErrHostnameRepeatsDomain = errors.New("hostname equals the first label of the domain suffix (e.g., example.example.com)")
+)
+
+// Warnings returns non-fatal findings for settings that are valid but likely
+// unintended, attributed to their fields. Returns nil if there are none.
+func (c *Config) Warnings() []FieldError {
+	var warnings []FieldError
+
+	if err := hostnameDomainWarning(c.System.Hostname, c.System.DomainSuffix); err != nil {
+		warnings = append(warnings, FieldError{Field: "system.hostname", Err: err})
+	}
+
+	if c.Storage.ZFSRaid == ZFSRaid0 {
+		warnings = append(warnings, FieldError{Field: "storage.zfs_raid", Err: ErrZFSRaid0NoRedundancy})
+	}
+
+	if c.Tailscale.Enabled && c.Tailscale.AuthKey == "" {
+		warnings = append(warnings, FieldError{Field: "tailscale.auth_key", Err: ErrTailscaleAuthKeyMissing})
+	}
+
+	return warnings
+}
+
+// hostnameDomainWarning returns a warning if hostname and domain combine into
+// an odd FQDN: a dotted hostname (e.g., "pve.example.com" with suffix
+// "example.com") or a hostname repeating the first domain label (e.g.,
+// "example" with suffix "example.com"). Returns nil without a domain suffix.
+func hostnameDomainWarning(hostname, domain string) error {
+	if hostname == "" || domain == "" {
+		return nil
+	}
+
+	if strings.Contains(hostname, ".") {
+		return ErrHostnameDotted
+	}
+
+	firstLabel, _, _ := strings.Cut(domain, ".")
+	if strings.EqualFold(hostname, firstLabel) {
+		return ErrHostnameRepeatsDomain
 	}
 
 	return nil
 }
+
+// ValidateWithWarnings validates the configuration like Validate and also
+// returns the findings from Warnings. Warnings are returned even when the
+// configuration is invalid.
+func (c *Config) ValidateWithWarnings() (warnings []FieldError, err error) {
+	return c.Warnings(), c.Validate()
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -392,6 +393,39 @@ func TestValidateEmail(t *testing.T) {
 
 // ValidateHostname tests
 
+func TestCanonicalizeEmail(t *testing.T) {
+	tests := []struct {
+		name        string
+		email       string
+		expected    string
+		expectedErr error
+	}{
+		{"domain lowered", "User@Example.COM", "User@example.com", nil},
+		{"already canonical", "admin@example.com", "admin@example.com", nil},
+		{"local part case kept", "First.Last+Tag@example.com", "First.Last+Tag@example.com", nil},
+		{"subdomain lowered", "ops@Mail.Example.Org", "ops@mail.example.org", nil},
+		{"empty", "", "", ErrEmailEmpty},
+		{"malformed", "not-an-email", "", ErrEmailInvalid},
+		{"missing domain", "User@", "", ErrEmailInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canonical, err := CanonicalizeEmail(tt.email)
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				assert.Empty(t, canonical)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, canonical)
+		})
+	}
+}
+
 func TestValidateHostname(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -448,6 +482,49 @@ func TestValidateHostname(t *testing.T) {
 
 // ValidatePassword tests
 
+func TestValidateHostnameWithPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		hostname    string
+		policy      HostnamePolicy
+		expectedErr error
+	}{
+		{"strict localhost", "localhost", StrictHostnamePolicy, ErrHostnameReserved},
+		{"strict localhost mixed case", "LocalHost", StrictHostnamePolicy, ErrHostnameReserved},
+		{"strict localdomain", "localdomain", StrictHostnamePolicy, ErrHostnameReserved},
+		{"strict all numeric", "12345", StrictHostnamePolicy, ErrHostnameAllNumeric},
+		{"strict single digit", "7", StrictHostnamePolicy, ErrHostnameAllNumeric},
+		{"strict normal name", "pve-server", StrictHostnamePolicy, nil},
+		{"strict digits with letters", "pve01", StrictHostnamePolicy, nil},
+		{"strict localhost prefix", "localhost2", StrictHostnamePolicy, nil},
+		{"strict RFC 1123 still applies", testInvalidHostname, StrictHostnamePolicy, ErrHostnameStartsWithHyphen},
+		{"reserved only allows numeric", "12345", HostnamePolicy{RejectReserved: true}, nil},
+		{"numeric only allows localhost", "localhost", HostnamePolicy{RejectAllNumeric: true}, nil},
+		{"default allows localhost", "localhost", HostnamePolicy{}, nil},
+		{"default allows all numeric", "12345", HostnamePolicy{}, nil},
+		{"default rejects empty", "", HostnamePolicy{}, ErrHostnameEmpty},
+		{"min 3 rejects two chars", "ab", HostnamePolicy{MinHostnameLength: 3}, ErrHostnameTooShort},
+		{"min 3 accepts three chars", "abc", HostnamePolicy{MinHostnameLength: 3}, nil},
+		{"min 3 rejects empty as empty", "", HostnamePolicy{MinHostnameLength: 3}, ErrHostnameEmpty},
+		{"default accepts single char", "a", HostnamePolicy{}, nil},
+		{"min 1 accepts single char", "a", HostnamePolicy{MinHostnameLength: 1}, nil},
+		{"negative min accepts single char", "a", HostnamePolicy{MinHostnameLength: -5}, nil},
+		{"min above max rejects long name", strings.Repeat("a", 63), HostnamePolicy{MinHostnameLength: 64}, ErrHostnameTooShort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateHostnameWithPolicy(tt.hostname, tt.policy)
+
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
 func TestValidatePassword(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -537,6 +614,35 @@ func TestValidateSSHKey(t *testing.T) {
 
 // ValidateTimezone tests
 
+func TestValidateTimezoneWithPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		timezone    string
+		policy      TimezonePolicy
+		expectedErr error
+	}{
+		{"strict rejects Local", "Local", StrictTimezonePolicy, ErrTimezoneLocalNotAllowed},
+		{"strict accepts UTC", "UTC", StrictTimezonePolicy, nil},
+		{"strict accepts Europe/Kyiv", "Europe/Kyiv", StrictTimezonePolicy, nil},
+		{"strict IANA check still applies", "Europe/Kyivv", StrictTimezonePolicy, ErrTimezoneInvalid},
+		{"strict rejects empty as empty", "", StrictTimezonePolicy, ErrTimezoneEmpty},
+		{"default accepts Local", "Local", TimezonePolicy{}, nil},
+		{"default accepts UTC", "UTC", TimezonePolicy{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTimezoneWithPolicy(tt.timezone, tt.policy)
+
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
 func TestValidateTimezone(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -588,6 +694,67 @@ func TestValidateTimezone(t *testing.T) {
 	}
 }
 
+// resetTimezoneCache empties the timezone cache for the test and afterwards.
+func resetTimezoneCache(t testing.TB) {
+	t.Helper()
+
+	reset := func() {
+		timezoneCache.Clear()
+		timezoneCacheSize.Store(0)
+	}
+
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestValidateTimezoneCachedMatchesUncached(t *testing.T) {
+	resetTimezoneCache(t)
+
+	zones := []string{"UTC", "Europe/Kyiv", "Local", "Etc/GMT+12", "Europe/Kyivv", "Europe", "local", "12345"}
+
+	// The first round fills the cache, the following rounds are served from it.
+	for round := range 3 {
+		for _, zone := range zones {
+			want := loadTimezoneValid(zone)
+
+			err := ValidateTimezone(zone)
+
+			if want {
+				assert.NoError(t, err, "round %d, zone %q", round, zone)
+			} else {
+				assert.ErrorIs(t, err, ErrTimezoneInvalid, "round %d, zone %q", round, zone)
+			}
+		}
+	}
+
+	assert.Equal(t, int64(len(zones)), timezoneCacheSize.Load())
+}
+
+func TestValidateTimezoneCacheIsBounded(t *testing.T) {
+	resetTimezoneCache(t)
+
+	for i := range maxCachedTimezones + 10 {
+		require.ErrorIs(t, ValidateTimezone(fmt.Sprintf("Mars/Zone%d", i)), ErrTimezoneInvalid)
+	}
+
+	assert.Equal(t, int64(maxCachedTimezones), timezoneCacheSize.Load())
+	require.NoError(t, ValidateTimezone("Europe/Kyiv"), "zones are still validated once the cache is full")
+}
+
+func BenchmarkValidateTimezoneCached(b *testing.B) {
+	resetTimezoneCache(b)
+
+	for b.Loop() {
+		_ = ValidateTimezone("Europe/Kyiv")
+	}
+}
+
+func BenchmarkValidateTimezoneUncached(b *testing.B) {
+	for b.Loop() {
+		_ = loadTimezoneValid("Europe/Kyiv")
+	}
+}
+
 // ValidateBridgeMode tests
 
 func TestValidateBridgeMode(t *testing.T) {
@@ -740,149 +907,890 @@ func TestValidateSubnet(t *testing.T) {
 	}
 }
 
-// Config.Validate tests
-
-func TestConfigValidateValidConfig(t *testing.T) {
-	cfg := DefaultConfig()
-	cfg.System.RootPassword = testValidPassword
-	cfg.System.SSHPublicKey = testValidSSHKey
-
-	err := cfg.Validate()
-
-	assert.NoError(t, err)
-}
-
-func TestConfigValidateEmptyConfigAllErrors(t *testing.T) {
-	cfg := &Config{}
-
-	err := cfg.Validate()
-
-	require.Error(t, err)
+func TestValidatePrivateSubnetSize(t *testing.T) {
+	tests := []struct {
+		name        string
+		subnet      string
+		mode        BridgeMode
+		expectedErr error
+	}{
+		{"internal /24", buildSubnet(10, 0, 0, 0, 24), BridgeModeInternal, nil},
+		{"internal /29", buildSubnet(10, 0, 0, 0, 29), BridgeModeInternal, nil},
+		{"internal /16", buildSubnet(172, 16, 0, 0, 16), BridgeModeInternal, nil},
+		{"internal /30", buildSubnet(10, 0, 0, 0, 30), BridgeModeInternal, ErrSubnetTooSmall},
+		{"internal /31", buildSubnet(10, 0, 0, 0, 31), BridgeModeInternal, ErrSubnetTooSmall},
+		{"internal /32", buildSubnet(10, 0, 0, 1, 32), BridgeModeInternal, ErrSubnetTooSmall},
+		{"both /24", buildSubnet(192, 168, 1, 0, 24), BridgeModeBoth, nil},
+		{"both /30", buildSubnet(192, 168, 1, 0, 30), BridgeModeBoth, ErrSubnetTooSmall},
+		{"external /32 skipped", buildSubnet(10, 0, 0, 1, 32), BridgeModeExternal, nil},
+		{"external /30 skipped", buildSubnet(10, 0, 0, 0, 30), BridgeModeExternal, nil},
+		{"ipv6 not checked", "fd00::/127", BridgeModeInternal, nil},
+		{"invalid subnet", testInvalidSubnet, BridgeModeInternal, ErrSubnetInvalid},
+	}
 
-	var valErr *ValidationError
-	require.True(t, errors.As(err, &valErr))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePrivateSubnetSize(tt.subnet, tt.mode)
 
-	// Should have errors for: hostname, email, password, ssh key,
-	// timezone, bridge mode, subnet, zfs raid
-	assert.Len(t, valErr.Errors, 8)
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+		})
+	}
 }
 
-func TestConfigValidateReturnsValidationError(t *testing.T) {
-	cfg := &Config{}
-
-	err := cfg.Validate()
+func TestValidateAdditionalSubnet(t *testing.T) {
+	tests := []struct {
+		name        string
+		subnet      string
+		expectedErr error
+	}{
+		{"empty means none", "", nil},
+		{"valid /29", testAdditionalSubnet, nil},
+		{"valid ipv6 /64", "2001:db8:1::/64", nil},
+		{"missing mask", buildIP(203, 0, 113, 8), ErrAdditionalSubnetInvalid},
+		{"invalid mask", buildSubnet(203, 0, 113, 8, 33), ErrAdditionalSubnetInvalid},
+		{testNameInvalidRandomString, "not-a-subnet", ErrAdditionalSubnetInvalid},
+	}
 
-	require.Error(t, err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAdditionalSubnet(tt.subnet)
 
-	var valErr *ValidationError
-	require.True(t, errors.As(err, &valErr))
-	assert.True(t, valErr.HasErrors())
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+		})
+	}
 }
 
-func TestConfigValidatePartialErrorsSystem(t *testing.T) {
-	cfg := DefaultConfig()
-	// Missing required fields: RootPassword and SSHPublicKey
-
-	err := cfg.Validate()
-
-	require.Error(t, err)
+func TestValidateNetworkBackend(t *testing.T) {
+	tests := []struct {
+		name        string
+		backend     NetworkBackend
+		expectedErr error
+	}{
+		{"empty means default", "", nil},
+		{"ifupdown2", NetworkBackendIfupdown2, nil},
+		{"networkd", NetworkBackendNetworkd, nil},
+		{"unknown", NetworkBackend("netplan"), ErrNetworkBackendInvalid},
+		{"uppercase", NetworkBackend("IFUPDOWN2"), ErrNetworkBackendInvalid},
+	}
 
-	var valErr *ValidationError
-	require.True(t, errors.As(err, &valErr))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNetworkBackend(tt.backend)
 
-	// Should have 2 errors: password and SSH key
-	assert.Len(t, valErr.Errors, 2)
-	assert.Contains(t, valErr.Error(), ErrPasswordEmpty.Error())
-	assert.Contains(t, valErr.Error(), ErrSSHKeyEmpty.Error())
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+		})
+	}
 }
 
-func TestConfigValidateInvalidHostname(t *testing.T) {
+func TestConfigValidateInvalidNetworkBackend(t *testing.T) {
 	cfg := DefaultConfig()
-	cfg.System.Hostname = testInvalidHostname
+	cfg.Network.NetworkBackend = NetworkBackend("netplan")
 	cfg.System.RootPassword = testValidPassword
 	cfg.System.SSHPublicKey = testValidSSHKey
 
 	err := cfg.Validate()
 
-	require.Error(t, err)
-
 	var valErr *ValidationError
-	require.True(t, errors.As(err, &valErr))
+	require.ErrorAs(t, err, &valErr)
 	assert.Len(t, valErr.Errors, 1)
-	assert.True(t, errors.Is(valErr.Unwrap(), ErrHostnameStartsWithHyphen))
+	assert.ErrorIs(t, valErr.Unwrap(), ErrNetworkBackendInvalid)
 }
 
-func TestConfigValidateInvalidEmail(t *testing.T) {
-	cfg := DefaultConfig()
-	cfg.System.Email = testInvalidEmail
-	cfg.System.RootPassword = testValidPassword
-	cfg.System.SSHPublicKey = testValidSSHKey
-
-	err := cfg.Validate()
+func TestValidateMAC(t *testing.T) {
+	tests := []struct {
+		name        string
+		mac         string
+		expectedErr error
+	}{
+		{"colon lowercase", testBridgeMAC, nil},
+		{"colon uppercase", "90:1B:0E:AA:BB:CC", nil},
+		{"dash form", "90-1b-0e-aa-bb-cc", nil},
+		{"empty", "", ErrMACInvalid},
+		{"mixed separators", "90:1b-0e:aa:bb:cc", ErrMACInvalid},
+		{"too short", "90:1b:0e:aa:bb", ErrMACInvalid},
+		{"too long", "90:1b:0e:aa:bb:cc:dd", ErrMACInvalid},
+		{"non-hex", "90:1b:0e:aa:bb:zz", ErrMACInvalid},
+		{"dotted form", "901b.0eaa.bbcc", ErrMACInvalid},
+		{"no separators", "901b0eaabbcc", ErrMACInvalid},
+	}
 
-	require.Error(t, err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMAC(tt.mac)
 
-	var valErr *ValidationError
-	require.True(t, errors.As(err, &valErr))
-	assert.Len(t, valErr.Errors, 1)
-	assert.True(t, errors.Is(valErr.Unwrap(), ErrEmailInvalid))
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+		})
+	}
 }
 
-func TestConfigValidateInvalidTimezone(t *testing.T) {
-	cfg := DefaultConfig()
-	cfg.System.Timezone = testInvalidTimezone
-	cfg.System.RootPassword = testValidPassword
-	cfg.System.SSHPublicKey = testValidSSHKey
-
-	err := cfg.Validate()
+func TestValidateDiskPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{"sata disk", testDeviceSDA, false},
+		{"nvme disk", "/dev/nvme0n1", false},
+		{"by-id path", "/dev/disk/by-id/ata-SAMSUNG_123", false},
+		{"empty", "", true},
+		{"bare /dev/", "/dev/", true},
+		{"missing /dev/ prefix", "sda", true},
+		{"relative path", "dev/sda", true},
+		{"other directory", "/tmp/sda", true},
+		{"contains space", "/dev/sd a", true},
+	}
 
-	require.Error(t, err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDiskPath(tt.path)
 
-	var valErr *ValidationError
-	require.True(t, errors.As(err, &valErr))
-	assert.Len(t, valErr.Errors, 1)
-	assert.True(t, errors.Is(valErr.Unwrap(), ErrTimezoneInvalid))
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrDiskPathInvalid)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
 }
 
-func TestConfigValidateInvalidBridgeMode(t *testing.T) {
-	cfg := DefaultConfig()
-	cfg.Network.BridgeMode = BridgeMode("invalid")
-	cfg.System.RootPassword = testValidPassword
-	cfg.System.SSHPublicKey = testValidSSHKey
-
-	err := cfg.Validate()
+func TestValidateNTPServer(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  string
+		wantErr bool
+	}{
+		{"fqdn", "ntp1.hetzner.de", false},
+		{"pool with numeric label", "0.debian.pool.ntp.org", false},
+		{"single label", "timeserver", false},
+		{"ipv4", buildIP(192, 0, 2, 123), false},
+		{"ipv6", "2001:db8::123", false},
+		{"empty", "", true},
+		{"underscore", "ntp_1.example.com", true},
+		{"empty label", "ntp1..hetzner.de", true},
+		{"with port", "ntp1.hetzner.de:123", true},
+		{"url", "ntp://ntp1.hetzner.de", true},
+	}
 
-	require.Error(t, err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNTPServer(tt.server)
 
-	var valErr *ValidationError
-	require.True(t, errors.As(err, &valErr))
-	assert.Len(t, valErr.Errors, 1)
-	assert.True(t, errors.Is(valErr.Unwrap(), ErrBridgeModeInvalid))
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrNTPServerInvalid)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
 }
 
-func TestConfigValidateInvalidSubnet(t *testing.T) {
-	cfg := DefaultConfig()
-	cfg.Network.PrivateSubnet = testInvalidSubnet
-	cfg.System.RootPassword = testValidPassword
-	cfg.System.SSHPublicKey = testValidSSHKey
-
-	err := cfg.Validate()
+func TestValidateNTPServers(t *testing.T) {
+	assert.NoError(t, ValidateNTPServers(nil))
+	assert.NoError(t, ValidateNTPServers([]string{"ntp1.hetzner.de", buildIP(192, 0, 2, 123), "2001:db8::123"}))
 
-	require.Error(t, err)
+	err := ValidateNTPServers([]string{"ntp1.hetzner.de", "bad_host", buildIP(192, 0, 2, 123), "-bad.example.com"})
 
-	var valErr *ValidationError
-	require.True(t, errors.As(err, &valErr))
-	assert.Len(t, valErr.Errors, 1)
-	assert.True(t, errors.Is(valErr.Unwrap(), ErrSubnetInvalid))
+	require.ErrorIs(t, err, ErrNTPServerInvalid)
+	assert.Contains(t, err.Error(), `"bad_host"`)
+	assert.Contains(t, err.Error(), `"-bad.example.com"`)
+	assert.NotContains(t, err.Error(), `"ntp1.hetzner.de"`)
 }
 
-func TestConfigValidateInvalidZFSRaid(t *testing.T) {
+func TestConfigValidateNTPServers(t *testing.T) {
 	cfg := DefaultConfig()
-	cfg.Storage.ZFSRaid = ZFSRaid("invalid")
 	cfg.System.RootPassword = testValidPassword
 	cfg.System.SSHPublicKey = testValidSSHKey
+	cfg.System.NTPServers = []string{"ntp1.hetzner.de", "bad_host"}
 
-	err := cfg.Validate()
+	fieldErrs := cfg.FieldErrors()
+
+	require.Len(t, fieldErrs, 1)
+	assert.Equal(t, "system.ntp_servers", fieldErrs[0].Field)
+	assert.ErrorIs(t, fieldErrs[0].Err, ErrNTPServerInvalid)
+}
+
+func TestValidateKeyboard(t *testing.T) {
+	tests := []struct {
+		name     string
+		keyboard string
+		wantErr  bool
+	}{
+		{"default", "en-us", false},
+		{"swiss german", "de-ch", false},
+		{"swiss french", "fr-ch", false},
+		{"brazilian", "pt-br", false},
+		{"empty", "", false},
+		{"xkb name", "us", true},
+		{"uppercase", "EN-US", true},
+		{"underscore", "en_us", true},
+		{"unknown", "klingon", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateKeyboard(tt.keyboard)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrKeyboardInvalid)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestKeyboardLayoutsHaveXKBLayouts(t *testing.T) {
+	layouts := KeyboardLayouts()
+
+	require.Contains(t, layouts, "en-us")
+	assert.IsIncreasing(t, layouts)
+
+	for _, name := range layouts {
+		xkb, ok := LookupXKBLayout(name)
+		require.True(t, ok, name)
+		assert.NotEmpty(t, xkb.Layout, name)
+	}
+
+	xkb, ok := LookupXKBLayout("fr-ch")
+	require.True(t, ok)
+	assert.Equal(t, XKBLayout{Layout: "ch", Variant: "fr"}, xkb)
+
+	_, ok = LookupXKBLayout("us")
+	assert.False(t, ok)
+}
+
+func TestValidateLocale(t *testing.T) {
+	tests := []struct {
+		name    string
+		locale  string
+		wantErr bool
+	}{
+		{"default", "en_US.UTF-8", false},
+		{"german swiss", "de_CH.UTF-8", false},
+		{"three letter language", "fil_PH.UTF-8", false},
+		{"c utf-8", "C.UTF-8", false},
+		{"empty", "", false},
+		{"missing charset", "en_US", true},
+		{"lowercase charset", "en_US.utf8", true},
+		{"latin1", "de_DE.ISO-8859-1", true},
+		{"modifier", "sr_RS.UTF-8@latin", true},
+		{"posix", "POSIX", true},
+		{"hyphenated", "en-US.UTF-8", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLocale(tt.locale)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrLocaleInvalid)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidateKeyboardLocale(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+	cfg.System.Keyboard = "us"
+	cfg.System.Locale = "en_US"
+
+	fieldErrs := cfg.FieldErrors()
+
+	require.Len(t, fieldErrs, 2)
+	assert.Equal(t, "system.keyboard", fieldErrs[0].Field)
+	assert.ErrorIs(t, fieldErrs[0].Err, ErrKeyboardInvalid)
+	assert.Equal(t, "system.locale", fieldErrs[1].Field)
+	assert.ErrorIs(t, fieldErrs[1].Err, ErrLocaleInvalid)
+}
+
+func TestValidateDiskCount(t *testing.T) {
+	tests := []struct {
+		name        string
+		raid        ZFSRaid
+		count       int
+		expectedErr error
+	}{
+		{"raid1 two disks", ZFSRaid1, 2, nil},
+		{"raid1 four disks", ZFSRaid1, 4, nil},
+		{"raid1 three disks", ZFSRaid1, 3, ErrRaidMirrorOddDisks},
+		{"raid1 no disks", ZFSRaid1, 0, ErrTooFewDisks},
+		{"raid1 one disk", ZFSRaid1, 1, ErrTooFewDisks},
+		{"raid1 thirty disks", ZFSRaid1, 30, ErrTooManyDisks},
+		{"raid0 three disks", ZFSRaid0, 3, nil},
+		{"raid0 one disk", ZFSRaid0, 1, ErrTooFewDisks},
+		{"single one disk", ZFSRaidSingle, 1, nil},
+		{"single thirty disks", ZFSRaidSingle, 30, ErrTooManyDisks},
+		{"maximum", ZFSRaid1, MaxDisks, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDiskCount(tt.raid, tt.count)
+
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestValidateDiskCountReportsMaximum(t *testing.T) {
+	err := ValidateDiskCount(ZFSRaidSingle, MaxDisks+1)
+
+	require.ErrorIs(t, err, ErrTooManyDisks)
+	assert.ErrorContains(t, err, fmt.Sprintf("(maximum %d)", MaxDisks))
+}
+
+func TestConfigValidateDiskCount(t *testing.T) {
+	disks := func(n int) []string {
+		list := make([]string, n)
+		for i := range list {
+			list[i] = fmt.Sprintf("/dev/nvme%dn1", i)
+		}
+
+		return list
+	}
+
+	tests := []struct {
+		name        string
+		disks       []string
+		expectedErr error
+	}{
+		{"auto-detected", nil, nil},
+		{"two disks", disks(2), nil},
+		{"four disks", disks(4), nil},
+		{"three disks", disks(3), ErrRaidMirrorOddDisks},
+		{"thirty disks", disks(30), ErrTooManyDisks},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Storage.Disks = tt.disks
+			cfg.System.RootPassword = testValidPassword
+			cfg.System.SSHPublicKey = testValidSSHKey
+
+			err := cfg.Validate()
+
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var valErr *ValidationError
+			require.True(t, errors.As(err, &valErr))
+			assert.Len(t, valErr.Errors, 1)
+			assert.ErrorIs(t, valErr.Unwrap(), tt.expectedErr)
+		})
+	}
+}
+
+func TestValidateClusterJoinAddress(t *testing.T) {
+	tests := []struct {
+		name        string
+		address     string
+		expectedErr error
+	}{
+		{"ipv4", buildIP(10, 0, 0, 2), nil},
+		{"ipv4 with port", testClusterJoinAddress, nil},
+		{"ipv6", "2001:db8::2", nil},
+		{"bracketed ipv6 with port", "[2001:db8::2]:8006", nil},
+		{"hostname", "pve1", nil},
+		{"fqdn with port", "pve1.example.com:8006", nil},
+		{"empty", "", ErrClusterJoinAddressEmpty},
+		{"port out of range", buildIP(10, 0, 0, 2) + ":70000", ErrClusterJoinAddressInvalid},
+		{"non-numeric port", "pve1:https", ErrClusterJoinAddressInvalid},
+		{"invalid characters", "pve_1.example.com", ErrClusterJoinAddressInvalid},
+		{"url", "https://pve1:8006", ErrClusterJoinAddressInvalid},
+		{"empty label", "pve1..example.com", ErrClusterJoinAddressInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateClusterJoinAddress(tt.address)
+
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestValidateClusterFingerprint(t *testing.T) {
+	tests := []struct {
+		name        string
+		fingerprint string
+		expectedErr error
+	}{
+		{"uppercase", testClusterFingerprint, nil},
+		{"lowercase", strings.ToLower(testClusterFingerprint), nil},
+		{"empty", "", ErrClusterFingerprintEmpty},
+		{"sha1 length", testClusterFingerprint[:59], ErrClusterFingerprintInvalid},
+		{"without colons", strings.ReplaceAll(testClusterFingerprint, ":", ""), ErrClusterFingerprintInvalid},
+		{"non-hex", "ZZ" + testClusterFingerprint[2:], ErrClusterFingerprintInvalid},
+		{"trailing colon", testClusterFingerprint + ":", ErrClusterFingerprintInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateClusterFingerprint(tt.fingerprint)
+
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestConfigValidateCluster(t *testing.T) {
+	tests := []struct {
+		name     string
+		cluster  ClusterConfig
+		expected []error
+	}{
+		{
+			name:     "empty cluster section is ignored",
+			cluster:  ClusterConfig{},
+			expected: nil,
+		},
+		{
+			name:     "password alone does not request a join",
+			cluster:  ClusterConfig{Password: testClusterPassword},
+			expected: nil,
+		},
+		{
+			name: "complete join",
+			cluster: ClusterConfig{
+				JoinAddress: testClusterJoinAddress,
+				Fingerprint: testClusterFingerprint,
+				Password:    testClusterPassword,
+			},
+			expected: nil,
+		},
+		{
+			name:     "address without fingerprint and password",
+			cluster:  ClusterConfig{JoinAddress: testClusterJoinAddress},
+			expected: []error{ErrClusterFingerprintEmpty, ErrClusterPasswordEmpty},
+		},
+		{
+			name:     "fingerprint without address",
+			cluster:  ClusterConfig{Fingerprint: testClusterFingerprint, Password: testClusterPassword},
+			expected: []error{ErrClusterJoinAddressEmpty},
+		},
+		{
+			name: "invalid address and fingerprint",
+			cluster: ClusterConfig{
+				JoinAddress: "https://pve1",
+				Fingerprint: "AB:CD",
+				Password:    testClusterPassword,
+			},
+			expected: []error{ErrClusterJoinAddressInvalid, ErrClusterFingerprintInvalid},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.System.RootPassword = testValidPassword
+			cfg.System.SSHPublicKey = testValidSSHKey
+			cfg.Cluster = tt.cluster
+
+			err := cfg.Validate()
+
+			if tt.expected == nil {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var validationErr *ValidationError
+
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.expected, validationErr.Errors)
+		})
+	}
+}
+
+func TestConfigValidateACME(t *testing.T) {
+	tests := []struct {
+		name        string
+		acme        ACMEConfig
+		systemEmail string
+		expected    []error
+	}{
+		{
+			name:        "disabled ignores invalid email",
+			acme:        ACMEConfig{Email: "not-an-email"},
+			systemEmail: "admin@example.com",
+			expected:    nil,
+		},
+		{
+			name:        "enabled inherits valid system email",
+			acme:        ACMEConfig{Enabled: true},
+			systemEmail: "admin@example.com",
+			expected:    nil,
+		},
+		{
+			name:        "enabled with own email",
+			acme:        ACMEConfig{Enabled: true, Email: "certs@example.com", Staging: true},
+			systemEmail: "admin@example.com",
+			expected:    nil,
+		},
+		{
+			name:        "enabled with invalid email",
+			acme:        ACMEConfig{Enabled: true, Email: "not-an-email"},
+			systemEmail: "admin@example.com",
+			expected:    []error{ErrEmailInvalid},
+		},
+		{
+			name:        "enabled inheriting empty system email reports it once",
+			acme:        ACMEConfig{Enabled: true},
+			systemEmail: "",
+			expected:    []error{ErrEmailEmpty},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.System.RootPassword = testValidPassword
+			cfg.System.SSHPublicKey = testValidSSHKey
+			cfg.System.Email = tt.systemEmail
+			cfg.ACME = tt.acme
+
+			err := cfg.Validate()
+
+			if tt.expected == nil {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var validationErr *ValidationError
+
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.expected, validationErr.Errors)
+		})
+	}
+}
+
+func TestConfigValidateACMERequiresDomainSuffix(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+	cfg.System.DomainSuffix = ""
+
+	require.NoError(t, cfg.Validate(), "a bare hostname is fine without a certificate")
+
+	cfg.ACME.Enabled = true
+
+	err := cfg.Validate()
+
+	var validationErr *ValidationError
+
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []error{ErrACMEDomainSuffixEmpty}, validationErr.Errors)
+	assert.Equal(t, []FieldError{{Field: "system.domain_suffix", Err: ErrACMEDomainSuffixEmpty}}, cfg.FieldErrors())
+}
+
+func TestConfigValidateStructureClusterPasswordOptional(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Cluster.JoinAddress = testClusterJoinAddress
+	cfg.Cluster.Fingerprint = testClusterFingerprint
+
+	assert.NoError(t, cfg.ValidateStructure())
+}
+
+func TestValidateSwapSize(t *testing.T) {
+	tests := []struct {
+		name        string
+		sizeMB      int
+		memoryMB    int
+		expectedErr error
+	}{
+		{"zero disables swap", 0, 0, nil},
+		{"zero with known memory", 0, 8192, nil},
+		{"positive with unknown memory", 1_000_000, 0, nil},
+		{"equal to memory", 8192, 8192, nil},
+		{"exactly twice memory", 16384, 8192, nil},
+		{"negative", -1, 0, ErrSwapSizeNegative},
+		{"negative with known memory", -1024, 8192, ErrSwapSizeNegative},
+		{"above twice memory", 16385, 8192, ErrSwapSizeTooLarge},
+		{"huge with known memory", 1_000_000, 8192, ErrSwapSizeTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSwapSize(tt.sizeMB, tt.memoryMB)
+
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+// Config.Validate tests
+
+func TestConfigValidateValidConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+
+	err := cfg.Validate()
+
+	assert.NoError(t, err)
+}
+
+func TestConfigValidateEmptyConfigAllErrors(t *testing.T) {
+	cfg := &Config{}
+
+	err := cfg.Validate()
+
+	require.Error(t, err)
+
+	var valErr *ValidationError
+	require.True(t, errors.As(err, &valErr))
+
+	// Should have errors for: hostname, email, password, ssh key,
+	// timezone, bridge mode, subnet, zfs raid
+	assert.Len(t, valErr.Errors, 8)
+}
+
+func TestConfigValidateReturnsValidationError(t *testing.T) {
+	cfg := &Config{}
+
+	err := cfg.Validate()
+
+	require.Error(t, err)
+
+	var valErr *ValidationError
+	require.True(t, errors.As(err, &valErr))
+	assert.True(t, valErr.HasErrors())
+}
+
+func TestConfigValidatePartialErrorsSystem(t *testing.T) {
+	cfg := DefaultConfig()
+	// Missing required fields: RootPassword and SSHPublicKey
+
+	err := cfg.Validate()
+
+	require.Error(t, err)
+
+	var valErr *ValidationError
+	require.True(t, errors.As(err, &valErr))
+
+	// Should have 2 errors: password and SSH key
+	assert.Len(t, valErr.Errors, 2)
+	assert.Contains(t, valErr.Error(), ErrPasswordEmpty.Error())
+	assert.Contains(t, valErr.Error(), ErrSSHKeyEmpty.Error())
+}
+
+func TestConfigValidateInvalidHostname(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.Hostname = testInvalidHostname
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+
+	err := cfg.Validate()
+
+	require.Error(t, err)
+
+	var valErr *ValidationError
+	require.True(t, errors.As(err, &valErr))
+	assert.Len(t, valErr.Errors, 1)
+	assert.True(t, errors.Is(valErr.Unwrap(), ErrHostnameStartsWithHyphen))
+}
+
+func TestConfigValidateInvalidEmail(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.Email = testInvalidEmail
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+
+	err := cfg.Validate()
+
+	require.Error(t, err)
+
+	var valErr *ValidationError
+	require.True(t, errors.As(err, &valErr))
+	assert.Len(t, valErr.Errors, 1)
+	assert.True(t, errors.Is(valErr.Unwrap(), ErrEmailInvalid))
+}
+
+func TestConfigValidateInvalidTimezone(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.Timezone = testInvalidTimezone
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+
+	err := cfg.Validate()
+
+	require.Error(t, err)
+
+	var valErr *ValidationError
+	require.True(t, errors.As(err, &valErr))
+	assert.Len(t, valErr.Errors, 1)
+	assert.True(t, errors.Is(valErr.Unwrap(), ErrTimezoneInvalid))
+}
+
+func TestConfigValidateInvalidBridgeMode(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Network.BridgeMode = BridgeMode("invalid")
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+
+	err := cfg.Validate()
+
+	require.Error(t, err)
+
+	var valErr *ValidationError
+	require.True(t, errors.As(err, &valErr))
+	assert.Len(t, valErr.Errors, 1)
+	assert.True(t, errors.Is(valErr.Unwrap(), ErrBridgeModeInvalid))
+}
+
+func TestConfigValidateInvalidSubnet(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Network.PrivateSubnet = testInvalidSubnet
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+
+	err := cfg.Validate()
+
+	require.Error(t, err)
+
+	var valErr *ValidationError
+	require.True(t, errors.As(err, &valErr))
+	assert.Len(t, valErr.Errors, 1)
+	assert.True(t, errors.Is(valErr.Unwrap(), ErrSubnetInvalid))
+}
+
+func TestConfigValidateSubnetTooSmall(t *testing.T) {
+	tests := []struct {
+		name        string
+		subnet      string
+		mode        BridgeMode
+		expectedErr error
+	}{
+		{"internal /24", buildSubnet(10, 0, 0, 0, 24), BridgeModeInternal, nil},
+		{"internal /29", buildSubnet(10, 0, 0, 0, 29), BridgeModeInternal, nil},
+		{"internal /30", buildSubnet(10, 0, 0, 0, 30), BridgeModeInternal, ErrSubnetTooSmall},
+		{"internal /32", buildSubnet(10, 0, 0, 1, 32), BridgeModeInternal, ErrSubnetTooSmall},
+		{"both /32", buildSubnet(10, 0, 0, 1, 32), BridgeModeBoth, ErrSubnetTooSmall},
+		{"external /32 skipped", buildSubnet(10, 0, 0, 1, 32), BridgeModeExternal, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Network.PrivateSubnet = tt.subnet
+			cfg.Network.BridgeMode = tt.mode
+			cfg.System.RootPassword = testValidPassword
+			cfg.System.SSHPublicKey = testValidSSHKey
+
+			err := cfg.Validate()
+
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var valErr *ValidationError
+			require.ErrorAs(t, err, &valErr)
+			assert.Len(t, valErr.Errors, 1)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestConfigValidateInvalidAdditionalSubnet(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Network.AdditionalSubnet = testInvalidSubnet
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+
+	err := cfg.Validate()
+
+	require.Error(t, err)
+
+	var valErr *ValidationError
+	require.True(t, errors.As(err, &valErr))
+	assert.Len(t, valErr.Errors, 1)
+	assert.True(t, errors.Is(valErr.Unwrap(), ErrAdditionalSubnetInvalid))
+}
+
+func TestConfigValidateBridgeMAC(t *testing.T) {
+	tests := []struct {
+		name        string
+		mac         string
+		expectedErr error
+	}{
+		{"empty means auto", "", nil},
+		{"valid", testBridgeMAC, nil},
+		{"invalid", "not-a-mac", ErrMACInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Network.BridgeMAC = tt.mac
+			cfg.System.RootPassword = testValidPassword
+			cfg.System.SSHPublicKey = testValidSSHKey
+
+			err := cfg.Validate()
+
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var valErr *ValidationError
+			require.ErrorAs(t, err, &valErr)
+			assert.Len(t, valErr.Errors, 1)
+			assert.ErrorIs(t, valErr.Unwrap(), tt.expectedErr)
+		})
+	}
+}
+
+func TestConfigValidateInvalidZFSRaid(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Storage.ZFSRaid = ZFSRaid("invalid")
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+
+	err := cfg.Validate()
 
 	require.Error(t, err)
 
@@ -892,6 +1800,118 @@ func TestConfigValidateInvalidZFSRaid(t *testing.T) {
 	assert.True(t, errors.Is(valErr.Unwrap(), ErrZFSRaidInvalid))
 }
 
+func TestConfigValidateNegativeSwapSize(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Storage.SwapSizeMB = -512
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+
+	err := cfg.Validate()
+
+	require.Error(t, err)
+
+	var valErr *ValidationError
+	require.True(t, errors.As(err, &valErr))
+	assert.Len(t, valErr.Errors, 1)
+	assert.True(t, errors.Is(valErr.Unwrap(), ErrSwapSizeNegative))
+}
+
+func TestConfigValidateDefaultAllowsReservedHostnames(t *testing.T) {
+	for _, hostname := range []string{"localhost", "12345"} {
+		cfg := DefaultConfig()
+		cfg.System.Hostname = hostname
+		cfg.System.RootPassword = testValidPassword
+		cfg.System.SSHPublicKey = testValidSSHKey
+
+		assert.NoError(t, cfg.Validate(), "hostname %q", hostname)
+	}
+}
+
+func TestConfigValidateWithPolicyRejectsReservedHostnames(t *testing.T) {
+	tests := []struct {
+		hostname    string
+		expectedErr error
+	}{
+		{"localhost", ErrHostnameReserved},
+		{"12345", ErrHostnameAllNumeric},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.hostname, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.System.Hostname = tt.hostname
+			cfg.System.RootPassword = testValidPassword
+			cfg.System.SSHPublicKey = testValidSSHKey
+
+			err := cfg.ValidateWithPolicy(ValidationPolicy{Hostname: StrictHostnamePolicy})
+
+			var valErr *ValidationError
+			require.True(t, errors.As(err, &valErr))
+			assert.Len(t, valErr.Errors, 1)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestConfigValidateWithStrictTimezonePolicy(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.Timezone = "Local"
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+
+	require.NoError(t, cfg.Validate())
+
+	err := cfg.ValidateWithPolicy(ValidationPolicy{Timezone: StrictTimezonePolicy})
+
+	var valErr *ValidationError
+	require.ErrorAs(t, err, &valErr)
+	assert.Len(t, valErr.Errors, 1)
+	assert.ErrorIs(t, err, ErrTimezoneLocalNotAllowed)
+}
+
+func TestConfigValidateWithZeroPolicyMatchesValidate(t *testing.T) {
+	cfg := DefaultConfig()
+
+	assert.Equal(t, cfg.Validate(), cfg.ValidateWithPolicy(ValidationPolicy{}))
+}
+
+func TestConfigValidateStructure(t *testing.T) {
+	tests := []struct {
+		name        string
+		mutate      func(*Config)
+		expectedErr error
+	}{
+		{"missing secrets allowed", func(_ *Config) {}, nil},
+		{"valid secrets allowed", func(c *Config) {
+			c.System.RootPassword = testValidPassword
+			c.System.SSHPublicKey = testValidSSHKey
+		}, nil},
+		{"weak password rejected when set", func(c *Config) { c.System.RootPassword = "short" }, ErrPasswordTooShort},
+		{"bad SSH key rejected when set", func(c *Config) { c.System.SSHPublicKey = "not-a-key" }, ErrSSHKeyInvalidPrefix},
+		{"structural error reported", func(c *Config) { c.Network.PrivateSubnet = testInvalidSubnet }, ErrSubnetInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(cfg)
+
+			err := cfg.ValidateStructure()
+
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var valErr *ValidationError
+			require.ErrorAs(t, err, &valErr)
+			assert.Len(t, valErr.Errors, 1)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
 func TestConfigValidateMultipleErrorsAllCategories(t *testing.T) {
 	cfg := &Config{
 		System: SystemConfig{
@@ -942,3 +1962,158 @@ func TestConfigValidateErrorMessageFormat(t *testing.T) {
 	// Errors should be joined by "; "
 	assert.Contains(t, err.Error(), "; ")
 }
+
+func TestConfigFieldErrors(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+	cfg.System.Email = "not-an-email"
+	cfg.Network.PrivateSubnet = "invalid"
+
+	fieldErrs := cfg.FieldErrors()
+
+	require.Len(t, fieldErrs, 2)
+	assert.Equal(t, "system.email", fieldErrs[0].Field)
+	assert.Equal(t, "system", fieldErrs[0].Section())
+	require.ErrorIs(t, fieldErrs[0], ErrEmailInvalid)
+	assert.Equal(t, "network.private_subnet", fieldErrs[1].Field)
+	assert.Equal(t, "network.private_subnet: "+ErrSubnetInvalid.Error(), fieldErrs[1].Error())
+}
+
+func TestConfigFieldErrorsMatchesValidate(t *testing.T) {
+	cfg := &Config{}
+
+	var validationErr *ValidationError
+
+	require.ErrorAs(t, cfg.Validate(), &validationErr)
+
+	fieldErrs := cfg.FieldErrors()
+	require.Len(t, fieldErrs, len(validationErr.Errors))
+
+	for i, fe := range fieldErrs {
+		assert.Equal(t, validationErr.Errors[i], fe.Err)
+	}
+}
+
+func TestConfigFieldErrorsValidConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+
+	assert.Empty(t, cfg.FieldErrors())
+}
+
+func TestConfigWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(cfg *Config)
+		expected []FieldError
+	}{
+		{
+			name:     "defaults have no warnings",
+			modify:   func(_ *Config) {},
+			expected: nil,
+		},
+		{
+			name:     "raid0",
+			modify:   func(cfg *Config) { cfg.Storage.ZFSRaid = ZFSRaid0 },
+			expected: []FieldError{{Field: "storage.zfs_raid", Err: ErrZFSRaid0NoRedundancy}},
+		},
+		{
+			name:     "tailscale without auth key",
+			modify:   func(cfg *Config) { cfg.Tailscale.Enabled = true },
+			expected: []FieldError{{Field: "tailscale.auth_key", Err: ErrTailscaleAuthKeyMissing}},
+		},
+		{
+			name: "dotted hostname",
+			modify: func(cfg *Config) {
+				cfg.System.Hostname = "pve.example.com"
+				cfg.System.DomainSuffix = "example.com"
+			},
+			expected: []FieldError{{Field: "system.hostname", Err: ErrHostnameDotted}},
+		},
+		{
+			name: "hostname repeats first domain label",
+			modify: func(cfg *Config) {
+				cfg.System.Hostname = "example"
+				cfg.System.DomainSuffix = "example.com"
+			},
+			expected: []FieldError{{Field: "system.hostname", Err: ErrHostnameRepeatsDomain}},
+		},
+		{
+			name: "repeated label differs in case",
+			modify: func(cfg *Config) {
+				cfg.System.Hostname = "Example"
+				cfg.System.DomainSuffix = "example.com"
+			},
+			expected: []FieldError{{Field: "system.hostname", Err: ErrHostnameRepeatsDomain}},
+		},
+		{
+			name: "single-label domain equal to hostname",
+			modify: func(cfg *Config) {
+				cfg.System.Hostname = "local"
+				cfg.System.DomainSuffix = "local"
+			},
+			expected: []FieldError{{Field: "system.hostname", Err: ErrHostnameRepeatsDomain}},
+		},
+		{
+			name: "hostname matching a later domain label",
+			modify: func(cfg *Config) {
+				cfg.System.Hostname = "example"
+				cfg.System.DomainSuffix = "pve.example.com"
+			},
+			expected: nil,
+		},
+		{
+			name: "normal hostname and domain",
+			modify: func(cfg *Config) {
+				cfg.System.Hostname = "pve1"
+				cfg.System.DomainSuffix = "example.com"
+			},
+			expected: nil,
+		},
+		{
+			name: "dotted hostname without domain suffix",
+			modify: func(cfg *Config) {
+				cfg.System.Hostname = "pve.example.com"
+				cfg.System.DomainSuffix = ""
+			},
+			expected: nil,
+		},
+		{
+			name: "tailscale with auth key",
+			modify: func(cfg *Config) {
+				cfg.Tailscale.Enabled = true
+				cfg.Tailscale.AuthKey = "tskey-auth-test" // NOSONAR(go:S2068) test data
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.modify(cfg)
+
+			assert.Equal(t, tt.expected, cfg.Warnings())
+		})
+	}
+}
+
+func TestConfigValidateWithWarnings(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Storage.ZFSRaid = ZFSRaid0
+
+	warnings, err := cfg.ValidateWithWarnings()
+
+	require.Error(t, err, "secrets are missing")
+	assert.Len(t, warnings, 1, "warnings are returned even when invalid")
+
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+
+	warnings, err = cfg.ValidateWithWarnings()
+
+	require.NoError(t, err, "warnings must not fail validation")
+	assert.Len(t, warnings, 1)
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"errors"
+	"regexp"
+)
+
+// ErrProxmoxVersionInvalid is returned when ProxmoxVersion is not a Proxmox VE release.
+var ErrProxmoxVersionInvalid = errors.New("proxmox version must be major.minor with an optional ISO release (e.g., 8.2 or 8.2-1)")
+
+// proxmoxVersionRegex matches a Proxmox VE release: major.minor with an
+// optional ISO release number.
+var proxmoxVersionRegex = regexp.MustCompile(`^[0-9]+\.[0-9]+(-[0-9]+)?$`)
+
+// proxmoxISORegex matches Proxmox VE installer ISO file names such as
+// "proxmox-ve_8.2-1.iso", capturing the major.minor version and the release.
+var proxmoxISORegex = regexp.MustCompile(`^proxmox-ve_([0-9]+\.[0-9]+)-([0-9]+)\.iso$`)
+
+// ValidateProxmoxVersion checks that version is a Proxmox VE release such as
+// "8.2" or "8.2-1". An empty value is valid and means the latest release.
+func ValidateProxmoxVersion(version string) error {
+	if version == "" {
+		return nil
+	}
+
+	if !proxmoxVersionRegex.MatchString(version) {
+		return ErrProxmoxVersionInvalid
+	}
+
+	return nil
+}
+
+// MatchesProxmoxISO reports whether the installer ISO file name (as listed on
+// the Proxmox download server, e.g. "proxmox-ve_8.2-1.iso") satisfies the
+// ProxmoxVersion pin: any ISO when empty, every release of the version for
+// "8.2", and exactly that ISO for "8.2-1". Other file names never match.
+func (s *SystemConfig) MatchesProxmoxISO(filename string) bool {
+	match := proxmoxISORegex.FindStringSubmatch(filename)
+	if match == nil {
+		return false
+	}
+
+	switch s.ProxmoxVersion {
+	case "":
+		return true
+	case match[1], match[1] + "-" + match[2]:
+		return true
+	default:
+		return false
+	}
+}
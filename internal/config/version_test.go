@@ -0,0 +1,112 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateProxmoxVersion(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected error
+	}{
+		{"", nil},
+		{"8.2", nil},
+		{"8.2-1", nil},
+		{"9.0-12", nil},
+		{"8", ErrProxmoxVersionInvalid},
+		{"8.2.4", ErrProxmoxVersionInvalid},
+		{"v8.2", ErrProxmoxVersionInvalid},
+		{"8.2-", ErrProxmoxVersionInvalid},
+		{"8.2-beta", ErrProxmoxVersionInvalid},
+		{"latest", ErrProxmoxVersionInvalid},
+		{" 8.2", ErrProxmoxVersionInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ValidateProxmoxVersion(tt.version))
+		})
+	}
+}
+
+func TestSystemConfigMatchesProxmoxISO(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		filename string
+		expected bool
+	}{
+		{"latest matches any ISO", "", "proxmox-ve_8.2-1.iso", true},
+		{"version matches release", "8.2", "proxmox-ve_8.2-2.iso", true},
+		{"version rejects other minor", "8.2", "proxmox-ve_8.1-2.iso", false},
+		{"version is not a prefix", "8.2", "proxmox-ve_8.20-1.iso", false},
+		{"release matches exactly", "8.2-1", "proxmox-ve_8.2-1.iso", true},
+		{"release rejects other release", "8.2-1", "proxmox-ve_8.2-2.iso", false},
+		{"other product", "", "proxmox-backup-server_3.2-1.iso", false},
+		{"checksum file", "8.2-1", "proxmox-ve_8.2-1.iso.sha256", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			system := SystemConfig{ProxmoxVersion: tt.version}
+			assert.Equal(t, tt.expected, system.MatchesProxmoxISO(tt.filename))
+		})
+	}
+}
+
+func TestLoadFromEnvProxmoxVersion(t *testing.T) {
+	t.Setenv("PVE_PROXMOX_VERSION", "8.2-1")
+
+	cfg := DefaultConfig()
+	LoadFromEnv(cfg)
+
+	assert.Equal(t, "8.2-1", cfg.System.ProxmoxVersion)
+}
+
+func TestConfigValidateProxmoxVersion(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.System.RootPassword = testValidPassword
+	cfg.System.SSHPublicKey = testValidSSHKey
+	cfg.System.ProxmoxVersion = "8.2.4"
+
+	var validationErr *ValidationError
+
+	require.ErrorAs(t, cfg.Validate(), &validationErr)
+	assert.Equal(t, []error{ErrProxmoxVersionInvalid}, validationErr.Errors)
+	assert.Equal(t, "system.proxmox_version", cfg.FieldErrors()[0].Field)
+
+	cfg.System.ProxmoxVersion = ""
+	assert.NoError(t, cfg.Validate(), "empty installs the latest release")
+}
+
+func TestProxmoxVersionFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	cfg := DefaultConfig()
+	cfg.System.ProxmoxVersion = "8.2-1"
+	require.NoError(t, cfg.SaveToFile(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `proxmox_version: 8.2-1`)
+
+	loaded, err := LoadFromFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "8.2-1", loaded.System.ProxmoxVersion)
+}
+
+func TestApplyOverridesProxmoxVersion(t *testing.T) {
+	cfg := DefaultConfig()
+
+	require.NoError(t, ApplyOverrides(cfg, []string{"system.proxmox_version=8.2"}))
+	assert.Equal(t, "8.2", cfg.System.ProxmoxVersion)
+
+	err := ApplyOverrides(cfg, []string{"system.proxmox_version=latest"})
+	assert.ErrorIs(t, err, ErrProxmoxVersionInvalid)
+	assert.Equal(t, "8.2", cfg.System.ProxmoxVersion)
+}
@@ -0,0 +1,97 @@
+package exec
+
+import (
+	"context"
+	"io"
+)
+
+// ChrootExecutor wraps another Executor and runs every command inside a
+// target root filesystem with chroot, so late installation steps can act on
+// the installed system (e.g., mounted at /target) as if running inside it.
+//
+// A call such as Run(ctx, "apt-get", "update") is executed by the inner
+// Executor as "chroot /target apt-get update". Stdin is passed through
+// unchanged. The root must already contain the mounts the command needs
+// (/proc, /sys, /dev); ChrootExecutor does not set them up.
+type ChrootExecutor struct {
+	inner Executor
+	root  string
+}
+
+// Compile-time assertion that ChrootExecutor implements Executor.
+var _ Executor = (*ChrootExecutor)(nil)
+
+// NewChrootExecutor creates a ChrootExecutor running commands through inner
+// inside root.
+func NewChrootExecutor(inner Executor, root string) *ChrootExecutor {
+	return &ChrootExecutor{inner: inner, root: root}
+}
+
+// Root returns the directory commands are chrooted into.
+func (e *ChrootExecutor) Root() string {
+	return e.root
+}
+
+// chrootArgs returns the chroot arguments running name with args inside the root.
+func (e *ChrootExecutor) chrootArgs(name string, args []string) []string {
+	return append([]string{e.root, name}, args...)
+}
+
+// Run executes a command inside the root through the inner Executor.
+func (e *ChrootExecutor) Run(ctx context.Context, name string, args ...string) error {
+	return e.inner.Run(ctx, "chroot", e.chrootArgs(name, args)...)
+}
+
+// RunWithOutput executes a command inside the root through the inner Executor.
+func (e *ChrootExecutor) RunWithOutput(ctx context.Context, name string, args ...string) (string, error) {
+	return e.inner.RunWithOutput(ctx, "chroot", e.chrootArgs(name, args)...)
+}
+
+// RunWithCombinedOutput executes a command inside the root through the inner Executor.
+func (e *ChrootExecutor) RunWithCombinedOutput(ctx context.Context, name string, args ...string) (string, error) {
+	return e.inner.RunWithCombinedOutput(ctx, "chroot", e.chrootArgs(name, args)...)
+}
+
+// RunWithStreams executes a command inside the root through the inner Executor.
+func (e *ChrootExecutor) RunWithStreams(ctx context.Context, name string, args ...string) (stdout, stderr string, err error) {
+	return e.inner.RunWithStreams(ctx, "chroot", e.chrootArgs(name, args)...)
+}
+
+// RunWithStdin executes a command inside the root through the inner Executor,
+// passing stdin through unchanged.
+func (e *ChrootExecutor) RunWithStdin(ctx context.Context, stdin, name string, args ...string) error {
+	return e.inner.RunWithStdin(ctx, stdin, "chroot", e.chrootArgs(name, args)...)
+}
+
+// RunWithEnv executes a command inside the root through the inner Executor.
+// chroot keeps the environment, so env reaches the command unchanged.
+func (e *ChrootExecutor) RunWithEnv(ctx context.Context, env []string, name string, args ...string) error {
+	return e.inner.RunWithEnv(ctx, env, "chroot", e.chrootArgs(name, args)...)
+}
+
+// RunInDir executes a command inside the root through the inner Executor,
+// with dir taken relative to the root. chroot always starts in the root's
+// "/", so a non-empty dir is entered with "env --chdir" inside it, as in
+// "chroot /target env --chdir=/srv/repo git pull".
+func (e *ChrootExecutor) RunInDir(ctx context.Context, dir, name string, args ...string) (string, error) {
+	if dir == "" {
+		return e.inner.RunInDir(ctx, "", "chroot", e.chrootArgs(name, args)...)
+	}
+
+	envArgs := append([]string{"--chdir=" + dir, name}, args...)
+
+	return e.inner.RunInDir(ctx, "", "chroot", e.chrootArgs("env", envArgs)...)
+}
+
+// RunWithWriter executes a command inside the root through the inner
+// Executor, streaming its output to stdout and stderr.
+func (e *ChrootExecutor) RunWithWriter(ctx context.Context, stdout, stderr io.Writer, name string, args ...string) error {
+	return e.inner.RunWithWriter(ctx, stdout, stderr, "chroot", e.chrootArgs(name, args)...)
+}
+
+// RunToFile executes a command inside the root through the inner Executor.
+// The path is opened by the inner Executor, so it is relative to the host
+// filesystem rather than the root.
+func (e *ChrootExecutor) RunToFile(ctx context.Context, path, name string, args ...string) error {
+	return e.inner.RunToFile(ctx, path, "chroot", e.chrootArgs(name, args)...)
+}
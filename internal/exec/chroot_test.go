@@ -0,0 +1,157 @@
+package exec
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testChrootRoot is the mount point of the installed system in the chroot tests.
+const testChrootRoot = "/target"
+
+func TestChrootExecutorRun(t *testing.T) {
+	mock := NewMockExecutor()
+	executor := NewChrootExecutor(mock, testChrootRoot)
+
+	require.NoError(t, executor.Run(context.Background(), "apt-get", "install", "-y", "zfs-zed"))
+
+	assert.True(t, mock.WasCalledWith("chroot", testChrootRoot, "apt-get", "install", "-y", "zfs-zed"))
+	assert.Equal(t, "chroot /target apt-get install -y zfs-zed", mock.LastCommand().String())
+}
+
+func TestChrootExecutorRunWithOutput(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("chroot /target cat /etc/hostname", "pve\n")
+	executor := NewChrootExecutor(mock, testChrootRoot)
+
+	out, err := executor.RunWithOutput(context.Background(), "cat", "/etc/hostname")
+
+	require.NoError(t, err)
+	assert.Equal(t, "pve\n", out)
+}
+
+func TestChrootExecutorRunWithCombinedOutput(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("chroot /target update-grub", "Generating grub configuration file ...\n")
+	executor := NewChrootExecutor(mock, testChrootRoot)
+
+	out, err := executor.RunWithCombinedOutput(context.Background(), "update-grub")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Generating grub configuration file ...\n", out)
+}
+
+func TestChrootExecutorRunWithStreams(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetStreams("chroot /target update-grub", "done\n", "Warning: os-prober not run\n")
+	executor := NewChrootExecutor(mock, testChrootRoot)
+
+	stdout, stderr, err := executor.RunWithStreams(context.Background(), "update-grub")
+
+	require.NoError(t, err)
+	assert.Equal(t, "done\n", stdout)
+	assert.Equal(t, "Warning: os-prober not run\n", stderr)
+}
+
+func TestChrootExecutorRunWithStdin(t *testing.T) {
+	mock := NewMockExecutor()
+	executor := NewChrootExecutor(mock, testChrootRoot)
+
+	require.NoError(t, executor.RunWithStdin(context.Background(), "root:secret", "chpasswd")) // NOSONAR(go:S2068) test data
+
+	last := mock.LastCommand()
+	require.NotNil(t, last)
+	assert.Equal(t, "chroot", last.Name)
+	assert.Equal(t, []string{testChrootRoot, "chpasswd"}, last.Args)
+	assert.Equal(t, "root:secret", last.Stdin) // NOSONAR(go:S2068) test data
+}
+
+func TestChrootExecutorRunWithEnv(t *testing.T) {
+	mock := NewMockExecutor()
+	executor := NewChrootExecutor(mock, testChrootRoot)
+
+	require.NoError(t, executor.RunWithEnv(context.Background(), []string{"DEBIAN_FRONTEND=noninteractive"}, "apt-get", "update"))
+
+	last := mock.LastCommand()
+	require.NotNil(t, last)
+	assert.Equal(t, "chroot "+testChrootRoot+" apt-get update", last.String())
+	assert.Equal(t, []string{"DEBIAN_FRONTEND=noninteractive"}, last.Env)
+}
+
+func TestChrootExecutorRunInDir(t *testing.T) {
+	tests := []struct {
+		name string
+		dir  string
+		want string
+	}{
+		{"with dir", "/srv/repo", "chroot " + testChrootRoot + " env --chdir=/srv/repo git pull"},
+		{"without dir", "", "chroot " + testChrootRoot + " git pull"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := NewMockExecutor()
+			executor := NewChrootExecutor(mock, testChrootRoot)
+
+			_, err := executor.RunInDir(context.Background(), tt.dir, "git", "pull")
+			require.NoError(t, err)
+
+			last := mock.LastCommand()
+			require.NotNil(t, last)
+			assert.Equal(t, tt.want, last.String())
+			assert.Empty(t, last.Dir, "the host working directory is left alone")
+		})
+	}
+}
+
+func TestChrootExecutorRunWithWriter(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("chroot "+testChrootRoot+" update-grub", "Generating grub configuration file ...\n")
+	executor := NewChrootExecutor(mock, testChrootRoot)
+
+	var stdout strings.Builder
+
+	require.NoError(t, executor.RunWithWriter(context.Background(), &stdout, nil, "update-grub"))
+	assert.Equal(t, "Generating grub configuration file ...\n", stdout.String())
+}
+
+func TestChrootExecutorNoArgs(t *testing.T) {
+	mock := NewMockExecutor()
+	executor := NewChrootExecutor(mock, testChrootRoot)
+
+	require.NoError(t, executor.Run(context.Background(), "update-initramfs"))
+
+	assert.Equal(t, []string{testChrootRoot, "update-initramfs"}, mock.LastCommand().Args)
+}
+
+func TestChrootExecutorRoot(t *testing.T) {
+	assert.Equal(t, testChrootRoot, NewChrootExecutor(NewMockExecutor(), testChrootRoot).Root())
+}
+
+func TestChrootExecutorPropagatesErrors(t *testing.T) {
+	mock := NewMockExecutor()
+	errChroot := errors.New("chroot: failed to run command 'grub-install': No such file or directory")
+	mock.SetError("chroot /target grub-install /dev/sda", errChroot)
+	executor := NewChrootExecutor(mock, testChrootRoot)
+
+	require.ErrorIs(t, executor.Run(context.Background(), "grub-install", "/dev/sda"), errChroot)
+}
+
+func TestChrootExecutorRunToFile(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("chroot /target dpkg -l", "ii  zfsutils-linux\n")
+	executor := NewChrootExecutor(mock, testChrootRoot)
+	path := filepath.Join(t.TempDir(), "packages.txt")
+
+	require.NoError(t, executor.RunToFile(context.Background(), path, "dpkg", "-l"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "ii  zfsutils-linux\n", string(data))
+}
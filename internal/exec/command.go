@@ -3,6 +3,10 @@ package exec
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
@@ -19,6 +23,20 @@ type ExecutedCommand struct {
 
 	// Stdin contains the stdin input provided to the command, if any.
 	Stdin string
+
+	// Env contains the extra environment variables ("KEY=value") passed
+	// with RunWithEnv, if any.
+	Env []string
+
+	// Dir is the working directory passed to RunInDir, if any.
+	Dir string
+
+	// OutputFile is the file passed to RunToFile that receives stdout, if any.
+	OutputFile string
+
+	// StartedAt is when MockExecutor recorded the command, taken from the
+	// clock configured with SetClock (time.Now by default).
+	StartedAt time.Time
 }
 
 // String returns a string representation of the executed command
@@ -37,6 +55,9 @@ func (c ExecutedCommand) String() string {
 // This interface enables testing of code that needs to execute system commands
 // by allowing injection of mock implementations that simulate command behavior
 // without actually running external processes.
+//
+// RealExecutor reports a failed command as a *CommandError carrying its exit
+// code; use errors.As to inspect it.
 type Executor interface {
 	// Run executes a command and returns an error if it fails.
 	// Stdout and stderr are discarded.
@@ -48,12 +69,61 @@ type Executor interface {
 	// The command will be terminated if the context is canceled.
 	RunWithOutput(ctx context.Context, name string, args ...string) (string, error)
 
+	// RunWithCombinedOutput executes a command and returns stdout and stderr
+	// interleaved in the order they were written, like exec.Cmd.CombinedOutput.
+	// The output is returned even if the command fails, so its diagnostics
+	// can be reported with the error.
+	// The command will be terminated if the context is canceled.
+	RunWithCombinedOutput(ctx context.Context, name string, args ...string) (string, error)
+
+	// RunWithStreams executes a command and returns its stdout and stderr
+	// separately, so callers can tell results from diagnostics. Both are
+	// returned even if the command fails; the error is the command's error
+	// (e.g., *exec.ExitError, possibly wrapped), so it can be inspected.
+	// The command will be terminated if the context is canceled.
+	RunWithStreams(ctx context.Context, name string, args ...string) (stdout, stderr string, err error)
+
 	// RunWithStdin executes a command with stdin input.
 	// Useful for commands that read from stdin (e.g., piping data).
 	// The command will be terminated if the context is canceled.
 	RunWithStdin(ctx context.Context, stdin string, name string, args ...string) error
+
+	// RunWithEnv executes a command with extra environment variables, given
+	// as "KEY=value" entries (e.g., "DEBIAN_FRONTEND=noninteractive").
+	// They are appended to the environment the command would otherwise
+	// inherit, so they take precedence over variables of the same name.
+	// The command will be terminated if the context is canceled.
+	RunWithEnv(ctx context.Context, env []string, name string, args ...string) error
+
+	// RunInDir executes a command in the working directory dir and returns
+	// its combined output, like RunWithCombinedOutput. An empty dir runs the
+	// command in the current working directory, as the other methods do.
+	// A dir that does not exist is an error and the command is not run.
+	// The command will be terminated if the context is canceled.
+	RunInDir(ctx context.Context, dir string, name string, args ...string) (string, error)
+
+	// RunWithWriter executes a command with its stdout and stderr written to
+	// the given writers while it runs, so the output of long-running
+	// commands (e.g., pveceph install) can be shown live. A nil writer
+	// discards that stream. Passing the same writer for both interleaves
+	// the streams in the order they were written.
+	// The command will be terminated if the context is canceled.
+	RunWithWriter(ctx context.Context, stdout, stderr io.Writer, name string, args ...string) error
+
+	// RunToFile executes a command and writes its stdout to the file at path,
+	// which is created with mode 0644 or truncated. Useful for commands with
+	// large output (e.g., dmesg) that should not be held in memory.
+	// Stderr is discarded. If the file cannot be opened, the command is not run.
+	// The command will be terminated if the context is canceled.
+	RunToFile(ctx context.Context, path string, name string, args ...string) error
 }
 
+// ErrNotDirectory is returned by RunInDir when dir is not a directory.
+var ErrNotDirectory = errors.New("not a directory")
+
+// outputFileMode is the permission mode of files created by RunToFile.
+const outputFileMode = 0o644
+
 // RealExecutor executes actual system commands using os/exec.
 //
 // Security note: RealExecutor intentionally accepts dynamic command names and arguments.
@@ -94,13 +164,20 @@ func (e *RealExecutor) applyTimeout(ctx context.Context) (context.Context, conte
 }
 
 // Run executes a command and returns an error if it fails.
-// Stdout and stderr are discarded.
+// Stdout is discarded; stderr is only kept for the CommandError.
 func (e *RealExecutor) Run(ctx context.Context, name string, args ...string) error {
 	ctx, cancel := e.applyTimeout(ctx)
 	defer cancel()
 
+	var stderr bytes.Buffer
+
 	// nosemgrep: go.lang.security.audit.dangerous-exec-command -- intentional dynamic command execution
-	return exec.CommandContext(ctx, name, args...).Run()
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+
+	return newCommandError(name, args, stderr.String(), err)
 }
 
 // RunWithOutput executes a command and returns combined stdout/stderr.
@@ -111,7 +188,43 @@ func (e *RealExecutor) RunWithOutput(ctx context.Context, name string, args ...s
 	// nosemgrep: go.lang.security.audit.dangerous-exec-command -- intentional dynamic command execution
 	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
 
-	return string(out), err
+	return string(out), newCommandError(name, args, "", err)
+}
+
+// RunWithCombinedOutput executes a command with stdout and stderr written to
+// the same buffer.
+func (e *RealExecutor) RunWithCombinedOutput(ctx context.Context, name string, args ...string) (string, error) {
+	ctx, cancel := e.applyTimeout(ctx)
+	defer cancel()
+
+	var out bytes.Buffer
+
+	// nosemgrep: go.lang.security.audit.dangerous-exec-command -- intentional dynamic command execution
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err := cmd.Run()
+
+	return out.String(), newCommandError(name, args, "", err)
+}
+
+// RunWithStreams executes a command with stdout and stderr written to
+// separate buffers.
+func (e *RealExecutor) RunWithStreams(ctx context.Context, name string, args ...string) (stdout, stderr string, err error) {
+	ctx, cancel := e.applyTimeout(ctx)
+	defer cancel()
+
+	var outBuf, errBuf bytes.Buffer
+
+	// nosemgrep: go.lang.security.audit.dangerous-exec-command -- intentional dynamic command execution
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &outBuf
+	cmd.Stderr = &errBuf
+
+	err = cmd.Run()
+
+	return outBuf.String(), errBuf.String(), newCommandError(name, args, errBuf.String(), err)
 }
 
 // RunWithStdin executes a command with stdin input.
@@ -119,9 +232,118 @@ func (e *RealExecutor) RunWithStdin(ctx context.Context, stdin, name string, arg
 	ctx, cancel := e.applyTimeout(ctx)
 	defer cancel()
 
+	var stderr bytes.Buffer
+
 	// nosemgrep: go.lang.security.audit.dangerous-exec-command -- intentional dynamic command execution
 	cmd := exec.CommandContext(ctx, name, args...)
 	cmd.Stdin = bytes.NewBufferString(stdin)
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+
+	return newCommandError(name, args, stderr.String(), err)
+}
+
+// RunWithEnv executes a command with env appended to os.Environ(). When a
+// variable appears more than once, the last entry wins, so env overrides
+// inherited values.
+func (e *RealExecutor) RunWithEnv(ctx context.Context, env []string, name string, args ...string) error {
+	ctx, cancel := e.applyTimeout(ctx)
+	defer cancel()
+
+	var stderr bytes.Buffer
+
+	// nosemgrep: go.lang.security.audit.dangerous-exec-command -- intentional dynamic command execution
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+
+	return newCommandError(name, args, stderr.String(), err)
+}
+
+// RunInDir executes a command in dir with stdout and stderr written to the
+// same buffer. The directory is checked first, so a missing one is reported
+// as such rather than as a failure to start the command.
+func (e *RealExecutor) RunInDir(ctx context.Context, dir, name string, args ...string) (string, error) {
+	if err := checkDir(dir); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := e.applyTimeout(ctx)
+	defer cancel()
+
+	var out bytes.Buffer
+
+	// nosemgrep: go.lang.security.audit.dangerous-exec-command -- intentional dynamic command execution
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err := cmd.Run()
+
+	return out.String(), newCommandError(name, args, "", err)
+}
+
+// checkDir returns an error wrapping ErrNotDirectory or the os.Stat error
+// unless dir is empty or an existing directory.
+func checkDir(dir string) error {
+	if dir == "" {
+		return nil
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("invalid working directory: %w", err)
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("invalid working directory %s: %w", dir, ErrNotDirectory)
+	}
+
+	return nil
+}
+
+// RunWithWriter executes a command with stdout and stderr written directly
+// to the given writers. Stderr goes only to its writer, so the CommandError
+// of a failed command carries no stderr.
+func (e *RealExecutor) RunWithWriter(ctx context.Context, stdout, stderr io.Writer, name string, args ...string) error {
+	ctx, cancel := e.applyTimeout(ctx)
+	defer cancel()
+
+	// nosemgrep: go.lang.security.audit.dangerous-exec-command -- intentional dynamic command execution
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+
+	return newCommandError(name, args, "", err)
+}
+
+// RunToFile executes a command with its stdout written to the file at path.
+func (e *RealExecutor) RunToFile(ctx context.Context, path, name string, args ...string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, outputFileMode)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := e.applyTimeout(ctx)
+	defer cancel()
+
+	var stderr bytes.Buffer
+
+	// nosemgrep: go.lang.security.audit.dangerous-exec-command -- intentional dynamic command execution
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = file
+	cmd.Stderr = &stderr
+
+	runErr := cmd.Run()
+	if closeErr := file.Close(); runErr == nil {
+		return closeErr
+	}
 
-	return cmd.Run()
+	return newCommandError(name, args, stderr.String(), runErr)
 }
@@ -3,6 +3,7 @@ package exec
 import (
 	"context"
 	"fmt"
+	"io"
 	"testing"
 )
 
@@ -25,7 +26,19 @@ func TestExecutorInterfaceMethodSignatures(t *testing.T) {
 	//nolint:errcheck // Testing method signatures, not behavior
 	executor.RunWithOutput(ctx, "echo", "hello")
 	//nolint:errcheck // Testing method signatures, not behavior
+	executor.RunWithCombinedOutput(ctx, "echo", "hello")
+	//nolint:errcheck // Testing method signatures, not behavior
+	executor.RunWithStreams(ctx, "echo", "hello")
+	//nolint:errcheck // Testing method signatures, not behavior
 	executor.RunWithStdin(ctx, "input data", "cat")
+	//nolint:errcheck // Testing method signatures, not behavior
+	executor.RunWithEnv(ctx, []string{"FOO=bar"}, "env")
+	//nolint:errcheck // Testing method signatures, not behavior
+	executor.RunInDir(ctx, "/tmp", "pwd")
+	//nolint:errcheck // Testing method signatures, not behavior
+	executor.RunWithWriter(ctx, io.Discard, io.Discard, "pveceph", "install")
+	//nolint:errcheck // Testing method signatures, not behavior
+	executor.RunToFile(ctx, "/tmp/out.txt", "dmesg")
 }
 
 // testExecutor is a minimal implementation used to verify interface compliance.
@@ -42,10 +55,34 @@ func (e *testExecutor) RunWithOutput(_ context.Context, _ string, _ ...string) (
 	return testOutputValue, nil
 }
 
+func (e *testExecutor) RunWithCombinedOutput(_ context.Context, _ string, _ ...string) (string, error) {
+	return testOutputValue, nil
+}
+
+func (e *testExecutor) RunWithStreams(_ context.Context, _ string, _ ...string) (stdout, stderr string, err error) {
+	return testOutputValue, "", nil
+}
+
 func (e *testExecutor) RunWithStdin(_ context.Context, _, _ string, _ ...string) error {
 	return nil
 }
 
+func (e *testExecutor) RunWithEnv(_ context.Context, _ []string, _ string, _ ...string) error {
+	return nil
+}
+
+func (e *testExecutor) RunInDir(_ context.Context, _, _ string, _ ...string) (string, error) {
+	return testOutputValue, nil
+}
+
+func (e *testExecutor) RunWithWriter(_ context.Context, _, _ io.Writer, _ string, _ ...string) error {
+	return nil
+}
+
+func (e *testExecutor) RunToFile(_ context.Context, _, _ string, _ ...string) error {
+	return nil
+}
+
 // TestExecutedCommandString tests the String() method of ExecutedCommand.
 func TestExecutedCommandString(t *testing.T) {
 	tests := []struct {
@@ -3,14 +3,34 @@
 //
 // # Interface
 //
-// The Executor interface defines three methods for running commands:
+// The Executor interface defines nine methods for running commands:
 //   - Run: Execute command, return error only
 //   - RunWithOutput: Execute command, return stdout/stderr and error
+//   - RunWithCombinedOutput: Execute command, return stdout and stderr
+//     interleaved in write order, even on failure, and error
+//   - RunWithStreams: Execute command, return stdout and stderr separately,
+//     even on failure, and error
 //   - RunWithStdin: Execute command with stdin input, return error
+//   - RunWithEnv: Execute command with extra environment variables appended
+//     to the inherited environment, return error
+//   - RunInDir: Execute command in a working directory, return stdout and
+//     stderr interleaved, even on failure, and error
+//   - RunWithWriter: Execute command with stdout and stderr streamed to
+//     writers while it runs, return error
+//   - RunToFile: Execute command with stdout written to a file, return error
 //
 // All methods accept context.Context as the first parameter for cancellation
 // and timeout support.
 //
+// A failed command is returned as a *CommandError with its exit code, so
+// callers can tell expected statuses (e.g., grep's 1 for "no match") from
+// failures:
+//
+//	var cmdErr *exec.CommandError
+//	if errors.As(err, &cmdErr) && cmdErr.ExitCode == 1 {
+//	    return false, nil
+//	}
+//
 // # RealExecutor
 //
 // RealExecutor implements Executor using os/exec. Use this in production:
@@ -22,6 +42,66 @@
 //	exec := exec.NewRealExecutorWithTimeout(30 * time.Second)
 //	output, err := exec.RunWithOutput(ctx, "slow-command")
 //
+// # TimeoutExecutor
+//
+// TimeoutExecutor wraps any Executor and applies a timeout per command name,
+// falling back to a default for unlisted commands:
+//
+//	exec := exec.NewTimeoutExecutor(exec.NewRealExecutor(), time.Minute, map[string]time.Duration{
+//	    "apt-get": 30 * time.Minute,
+//	    "lsblk":   10 * time.Second,
+//	})
+//
+// RunWithRetryTimeout combines both for flaky downloads: each attempt gets
+// its own timeout, and failed or timed-out attempts are retried with backoff:
+//
+//	err := exec.RunWithRetryTimeout(ctx, executor, 3, 10*time.Minute, "curl", "-fsSLO", isoURL)
+//
+// DefaultRetryable classifies a failure as transient (apt lock held, DNS
+// hiccup, network timeout) from its error and output; NewRetryPredicate
+// builds a classifier from a custom signature list:
+//
+//	retryable := exec.NewRetryPredicate(append(exec.TransientSignatures(), "zpool busy")...)
+//
+// RetryExecutor wraps any Executor and retries the failures DefaultRetryable
+// (or a predicate set with SetRetryable) considers transient, waiting a
+// constant backoff between attempts (or a doubling one after SetExponential):
+//
+//	executor := exec.NewRetryExecutor(exec.NewRealExecutor(), 3, 5*time.Second)
+//	executor.SetRetryable(retryable)
+//
+// # ChrootExecutor
+//
+// ChrootExecutor wraps any Executor and runs every command inside a target
+// root with chroot, for steps that configure the installed system:
+//
+//	target := exec.NewChrootExecutor(executor, "/target")
+//	err := target.Run(ctx, "update-initramfs", "-u") // runs "chroot /target update-initramfs -u"
+//
+// # SudoExecutor
+//
+// SudoExecutor wraps any Executor and prefixes commands with "sudo -n" when
+// the process is not root, for development on unprivileged workstations:
+//
+//	executor := exec.NewSudoExecutor(exec.NewRealExecutor())
+//
+// # DryRunExecutor
+//
+// DryRunExecutor records every command and reports success without running
+// anything, printing a "would run: ..." line for each one, for --dry-run mode:
+//
+//	executor := exec.NewDryRunExecutor(os.Stdout)
+//	err := executor.Run(ctx, "wipefs", "-a", "/dev/sda") // prints "would run: wipefs -a /dev/sda"
+//
+// # Locking
+//
+// RunWithLock guards a whole installation with an exclusive flock, failing
+// with ErrLockHeld if another run holds it; RunWithLockWait waits instead:
+//
+//	err := exec.RunWithLock(ctx, "/run/pve-install.lock", func() error {
+//	    return install(ctx)
+//	})
+//
 // # MockExecutor
 //
 // MockExecutor implements Executor for testing. It records all commands
@@ -39,6 +119,9 @@
 //	assert.True(t, mock.WasCalledWith("ip", "link", "show"))
 //	assert.Equal(t, 2, mock.CommandCount())
 //
+//	// Verify destructive commands never ran
+//	assert.True(t, mock.WasNeverCalled("rm"))
+//
 // See CLAUDE.md section "Mock Executor: Use for testing system commands"
 // for more examples.
 package exec
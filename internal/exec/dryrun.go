@@ -0,0 +1,168 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DryRunExecutor is an Executor for --dry-run mode: it records every command
+// and reports success without spawning a process or touching the filesystem.
+//
+// Unlike MockExecutor, which is meant for tests and can simulate failures,
+// DryRunExecutor always succeeds, so an installation runs through every step
+// and the recorded commands show exactly what would have been executed. Each
+// command returns the output configured with SetOutput, or an empty string.
+//
+// If a writer is given, every command is also printed to it as it is
+// recorded, as a line such as:
+//
+//	would run: zpool create -f rpool mirror /dev/sda /dev/sdb
+//
+// Commands are formatted with FormatExecutedCommand, as in exported scripts.
+// Stdin is never printed, because it may carry passwords; only its size is
+// shown.
+//
+// DryRunExecutor is safe for concurrent use.
+type DryRunExecutor struct {
+	mu       sync.Mutex
+	w        io.Writer
+	commands []ExecutedCommand
+	outputs  map[string]string
+}
+
+// Compile-time assertion that DryRunExecutor implements Executor.
+var _ Executor = (*DryRunExecutor)(nil)
+
+// NewDryRunExecutor creates a DryRunExecutor printing each command to w.
+// A nil w records the commands silently.
+func NewDryRunExecutor(w io.Writer) *DryRunExecutor {
+	return &DryRunExecutor{
+		w:       w,
+		outputs: make(map[string]string),
+	}
+}
+
+// SetOutput configures the output returned for a command, so that code
+// parsing it (e.g., disk detection) can proceed during a dry run.
+// The cmd parameter should be the full command string (e.g., "lsblk -dpno NAME,TYPE").
+func (e *DryRunExecutor) SetOutput(cmd, output string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.outputs[cmd] = output
+}
+
+// Commands returns a copy of the commands recorded so far, in order.
+func (e *DryRunExecutor) Commands() []ExecutedCommand {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	result := make([]ExecutedCommand, len(e.commands))
+
+	for i, cmd := range e.commands {
+		cmd.Args = slices.Clone(cmd.Args)
+		cmd.Env = slices.Clone(cmd.Env)
+		result[i] = cmd
+	}
+
+	return result
+}
+
+// call records cmd, prints it with the given suffix, and returns its
+// configured output. It fails only if ctx is done or printing fails.
+func (e *DryRunExecutor) call(ctx context.Context, cmd ExecutedCommand, suffix string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	cmd.Args = slices.Clone(cmd.Args)
+	cmd.Env = slices.Clone(cmd.Env)
+	cmd.StartedAt = time.Now()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.commands = append(e.commands, cmd)
+	output := e.outputs[makeKey(cmd.Name, cmd.Args...)]
+
+	if e.w == nil {
+		return output, nil
+	}
+
+	if _, err := fmt.Fprintf(e.w, "would run: %s%s\n", FormatExecutedCommand(cmd), suffix); err != nil {
+		return "", fmt.Errorf("failed to print dry-run command: %w", err)
+	}
+
+	return output, nil
+}
+
+// Run records the command without executing it.
+func (e *DryRunExecutor) Run(ctx context.Context, name string, args ...string) error {
+	_, err := e.call(ctx, ExecutedCommand{Name: name, Args: args}, "")
+
+	return err
+}
+
+// RunWithOutput records the command and returns its configured output.
+func (e *DryRunExecutor) RunWithOutput(ctx context.Context, name string, args ...string) (string, error) {
+	return e.call(ctx, ExecutedCommand{Name: name, Args: args}, "")
+}
+
+// RunWithCombinedOutput records the command and returns its configured output.
+func (e *DryRunExecutor) RunWithCombinedOutput(ctx context.Context, name string, args ...string) (string, error) {
+	return e.call(ctx, ExecutedCommand{Name: name, Args: args}, "")
+}
+
+// RunWithStreams records the command and returns its configured output as
+// stdout, with an empty stderr.
+func (e *DryRunExecutor) RunWithStreams(ctx context.Context, name string, args ...string) (stdout, stderr string, err error) {
+	stdout, err = e.call(ctx, ExecutedCommand{Name: name, Args: args}, "")
+
+	return stdout, "", err
+}
+
+// RunWithStdin records the command and its stdin without executing it.
+// Only the size of stdin is printed.
+func (e *DryRunExecutor) RunWithStdin(ctx context.Context, stdin, name string, args ...string) error {
+	suffix := fmt.Sprintf(" < stdin (%d bytes)", len(stdin))
+	_, err := e.call(ctx, ExecutedCommand{Name: name, Args: args, Stdin: stdin}, suffix)
+
+	return err
+}
+
+// RunWithEnv records the command and its extra environment without executing it.
+func (e *DryRunExecutor) RunWithEnv(ctx context.Context, env []string, name string, args ...string) error {
+	_, err := e.call(ctx, ExecutedCommand{Name: name, Args: args, Env: env}, "")
+
+	return err
+}
+
+// RunInDir records the command and its working directory and returns its
+// configured output. The directory is not checked, since an earlier step
+// of a real run may have created it.
+func (e *DryRunExecutor) RunInDir(ctx context.Context, dir, name string, args ...string) (string, error) {
+	return e.call(ctx, ExecutedCommand{Name: name, Args: args, Dir: dir}, "")
+}
+
+// RunWithWriter records the command and writes its configured output to stdout.
+// Nil writers are ignored.
+func (e *DryRunExecutor) RunWithWriter(ctx context.Context, stdout, _ io.Writer, name string, args ...string) error {
+	output, err := e.call(ctx, ExecutedCommand{Name: name, Args: args}, "")
+	if err != nil {
+		return err
+	}
+
+	return writeStream(stdout, output)
+}
+
+// RunToFile records the command without executing it. The file at path is
+// neither created nor modified.
+func (e *DryRunExecutor) RunToFile(ctx context.Context, path, name string, args ...string) error {
+	_, err := e.call(ctx, ExecutedCommand{Name: name, Args: args, OutputFile: path}, "")
+
+	return err
+}
@@ -0,0 +1,152 @@
+package exec
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunExecutorDoesNotExecute(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "marker")
+	executor := NewDryRunExecutor(nil)
+	ctx := context.Background()
+
+	require.NoError(t, executor.Run(ctx, "touch", marker))
+	require.NoError(t, executor.RunWithStdin(ctx, "data", "sh", "-c", "cat > "+marker))
+	require.NoError(t, executor.RunWithEnv(ctx, []string{"MARKER=" + marker}, "sh", "-c", `touch "$MARKER"`))
+	require.NoError(t, executor.RunToFile(ctx, marker, "echo", "data"))
+
+	_, err := executor.RunInDir(ctx, dir, "touch", "marker")
+	require.NoError(t, err)
+
+	assert.NoFileExists(t, marker)
+	assert.Len(t, executor.Commands(), 5)
+}
+
+func TestDryRunExecutorPrintsWouldRun(t *testing.T) {
+	var out bytes.Buffer
+
+	executor := NewDryRunExecutor(&out)
+
+	require.NoError(t, executor.Run(context.Background(), "zpool", "create", "-f", "rpool", "mirror", "/dev/sda", "/dev/sdb"))
+	require.NoError(t, executor.Run(context.Background(), "sh", "-c", "echo it's done"))
+
+	assert.Equal(t, "would run: zpool create -f rpool mirror /dev/sda /dev/sdb\n"+
+		`would run: sh -c 'echo it'\''s done'`+"\n", out.String())
+}
+
+func TestDryRunExecutorPrintsContext(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(ctx context.Context, e *DryRunExecutor) error
+		want string
+	}{
+		{
+			name: "stdin size only",
+			run: func(ctx context.Context, e *DryRunExecutor) error {
+				return e.RunWithStdin(ctx, "root:secret", "chpasswd")
+			},
+			want: "would run: chpasswd < stdin (11 bytes)\n",
+		},
+		{
+			name: "environment",
+			run: func(ctx context.Context, e *DryRunExecutor) error {
+				return e.RunWithEnv(ctx, []string{"DEBIAN_FRONTEND=noninteractive"}, "apt-get", "install", "-y", "sudo")
+			},
+			want: "would run: DEBIAN_FRONTEND=noninteractive apt-get install -y sudo\n",
+		},
+		{
+			name: "working directory",
+			run: func(ctx context.Context, e *DryRunExecutor) error {
+				_, err := e.RunInDir(ctx, "/mnt/target", "git", "init")
+
+				return err
+			},
+			want: "would run: (cd /mnt/target && git init)\n",
+		},
+		{
+			name: "output file",
+			run: func(ctx context.Context, e *DryRunExecutor) error {
+				return e.RunToFile(ctx, "/etc/apt/keyrings/pve.gpg", "curl", "-fsSL", "https://example.com/key.gpg")
+			},
+			want: "would run: curl -fsSL https://example.com/key.gpg > /etc/apt/keyrings/pve.gpg\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			require.NoError(t, tt.run(context.Background(), NewDryRunExecutor(&out)))
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestDryRunExecutorRecordsCommands(t *testing.T) {
+	executor := NewDryRunExecutor(nil)
+
+	require.NoError(t, executor.RunWithStdin(context.Background(), "root:secret", "chpasswd"))
+	require.NoError(t, executor.RunWithEnv(context.Background(), []string{"LC_ALL=C"}, "locale-gen"))
+
+	commands := executor.Commands()
+	require.Len(t, commands, 2)
+	assert.Equal(t, "chpasswd", commands[0].Name)
+	assert.Equal(t, "root:secret", commands[0].Stdin)
+	assert.Equal(t, []string{"LC_ALL=C"}, commands[1].Env)
+	assert.False(t, commands[1].StartedAt.IsZero())
+
+	require.NoError(t, executor.RunToFile(context.Background(), "/target/etc/hostid", "zgenhostid", "-f"))
+	assert.Equal(t, "/target/etc/hostid", executor.Commands()[2].OutputFile)
+
+	commands[1].Env[0] = "changed"
+	assert.Equal(t, []string{"LC_ALL=C"}, executor.Commands()[1].Env, "Commands returns a copy")
+}
+
+func TestDryRunExecutorOutput(t *testing.T) {
+	executor := NewDryRunExecutor(nil)
+	executor.SetOutput("lsblk -dpno NAME,TYPE", "/dev/sda disk\n")
+	ctx := context.Background()
+
+	output, err := executor.RunWithOutput(ctx, "lsblk", "-dpno", "NAME,TYPE")
+	require.NoError(t, err)
+	assert.Equal(t, "/dev/sda disk\n", output)
+
+	stdout, stderr, err := executor.RunWithStreams(ctx, "lsblk", "-dpno", "NAME,TYPE")
+	require.NoError(t, err)
+	assert.Equal(t, "/dev/sda disk\n", stdout)
+	assert.Empty(t, stderr)
+
+	var buf bytes.Buffer
+
+	require.NoError(t, executor.RunWithWriter(ctx, &buf, nil, "lsblk", "-dpno", "NAME,TYPE"))
+	assert.Equal(t, "/dev/sda disk\n", buf.String())
+
+	output, err = executor.RunWithCombinedOutput(ctx, "hostname")
+	require.NoError(t, err)
+	assert.Empty(t, output, "unconfigured commands succeed with no output")
+}
+
+func TestDryRunExecutorCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	executor := NewDryRunExecutor(nil)
+
+	require.ErrorIs(t, executor.Run(ctx, "reboot"), context.Canceled)
+	assert.Empty(t, executor.Commands())
+}
+
+func TestDryRunExecutorWriteError(t *testing.T) {
+	executor := NewDryRunExecutor(errWriter{})
+
+	err := executor.Run(context.Background(), "reboot")
+
+	require.ErrorIs(t, err, errWriteFailed)
+	assert.ErrorContains(t, err, "failed to print dry-run command")
+}
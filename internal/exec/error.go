@@ -0,0 +1,91 @@
+package exec
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// CommandError is returned by RealExecutor (and by MockExecutor for commands
+// configured with SetExitCode) when a command fails, so callers can branch
+// on the exit status:
+//
+//	var cmdErr *exec.CommandError
+//	if errors.As(err, &cmdErr) && cmdErr.ExitCode == 1 {
+//	    // grep found no match
+//	}
+type CommandError struct {
+	// Name is the command name.
+	Name string
+
+	// Args contains the command arguments.
+	Args []string
+
+	// ExitCode is the process exit status, or -1 if the command could not be
+	// started (e.g., the binary was not found) or was killed by a signal.
+	ExitCode int
+
+	// Stderr is the command's standard error when it was captured separately
+	// from the output (Run, RunWithStdin, RunToFile and RunWithStreams). It is
+	// empty for RunWithOutput and RunWithCombinedOutput, whose output already
+	// contains it.
+	Stderr string
+
+	// Err is the underlying error, e.g. *os/exec.ExitError.
+	Err error
+}
+
+// Error returns the formatted command with the underlying error and, if
+// captured, its trimmed standard error.
+func (e *CommandError) Error() string {
+	msg := FormatCommand(e.Name, e.Args...) + ": " + e.Err.Error()
+
+	if stderr := strings.TrimSpace(e.Stderr); stderr != "" {
+		msg += ": " + stderr
+	}
+
+	return msg
+}
+
+// Unwrap returns the underlying error.
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// newCommandError wraps a non-nil error from running name with args in a
+// CommandError, taking the exit code from the error if it has one.
+// It returns nil for a nil error.
+func newCommandError(name string, args []string, stderr string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	exitCode := -1
+
+	var exitErr interface{ ExitCode() int }
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	}
+
+	return &CommandError{
+		Name:     name,
+		Args:     args,
+		ExitCode: exitCode,
+		Stderr:   stderr,
+		Err:      err,
+	}
+}
+
+// exitStatusError is the underlying error of a MockExecutor CommandError,
+// mimicking *os/exec.ExitError.
+type exitStatusError int
+
+// Error returns "exit status N" like *os/exec.ExitError.
+func (e exitStatusError) Error() string {
+	return "exit status " + strconv.Itoa(int(e))
+}
+
+// ExitCode returns the exit status.
+func (e exitStatusError) ExitCode() int {
+	return int(e)
+}
@@ -0,0 +1,144 @@
+//go:build !windows
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRealExecutorCommandErrorExitCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		code int
+	}{
+		{"exit 1", 1},
+		{"exit 2", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewRealExecutor().Run(t.Context(), "sh", "-c", "echo 'no match' >&2; exit "+strconv.Itoa(tt.code))
+
+			var cmdErr *CommandError
+			require.ErrorAs(t, err, &cmdErr)
+			assert.Equal(t, tt.code, cmdErr.ExitCode)
+			assert.Equal(t, "sh", cmdErr.Name)
+			assert.Equal(t, "no match\n", cmdErr.Stderr)
+		})
+	}
+}
+
+func TestRealExecutorCommandErrorSuccess(t *testing.T) {
+	err := NewRealExecutor().Run(t.Context(), "sh", "-c", "exit 0")
+
+	assert.NoError(t, err, "exit code 0 is not an error")
+}
+
+func TestRealExecutorCommandErrorNotFound(t *testing.T) {
+	_, err := NewRealExecutor().RunWithOutput(t.Context(), "nonexistent-command-12345", "--help")
+
+	var cmdErr *CommandError
+	require.ErrorAs(t, err, &cmdErr)
+	assert.Equal(t, -1, cmdErr.ExitCode)
+	assert.Equal(t, []string{"--help"}, cmdErr.Args)
+}
+
+func TestRealExecutorCommandErrorUnwrapsExitError(t *testing.T) {
+	err := NewRealExecutor().Run(t.Context(), "false")
+
+	var exitErr interface{ ExitCode() int }
+	require.ErrorAs(t, err, &exitErr, "the *os/exec.ExitError stays reachable")
+	assert.Equal(t, 1, exitErr.ExitCode())
+}
+
+func TestCommandErrorMessage(t *testing.T) {
+	err := &CommandError{
+		Name:     "apt-get",
+		Args:     []string{"install", "-y", "zfs utils"},
+		ExitCode: 100,
+		Stderr:   "E: Could not get lock\n",
+		Err:      exitStatusError(100),
+	}
+
+	assert.Equal(t, "apt-get install -y 'zfs utils': exit status 100: E: Could not get lock", err.Error())
+	assert.True(t, DefaultRetryable(err, ""), "stderr in the message is seen by retry predicates")
+}
+
+func TestMockExecutorSetExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		code int
+	}{
+		{"exit 0", 0},
+		{"exit 1", 1},
+		{"exit 2", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := NewMockExecutor()
+			mock.SetExitCode("grep -q pve /etc/hosts", tt.code)
+
+			err := mock.Run(context.Background(), "grep", "-q", "pve", "/etc/hosts")
+
+			if tt.code == 0 {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var cmdErr *CommandError
+			require.ErrorAs(t, err, &cmdErr)
+			assert.Equal(t, tt.code, cmdErr.ExitCode)
+			assert.Equal(t, []string{"-q", "pve", "/etc/hosts"}, cmdErr.Args)
+		})
+	}
+}
+
+func TestMockExecutorSetExitCodeWithStreams(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetStreams("zpool import rpool", "", "no such pool")
+	mock.SetExitCode("zpool import rpool", 1)
+
+	_, stderr, err := mock.RunWithStreams(context.Background(), "zpool", "import", "rpool")
+
+	var cmdErr *CommandError
+	require.ErrorAs(t, err, &cmdErr)
+	assert.Equal(t, "no such pool", cmdErr.Stderr)
+	assert.Equal(t, "no such pool", stderr)
+}
+
+func TestMockExecutorSetErrorOverridesExitCode(t *testing.T) {
+	configured := errors.New("permission denied")
+
+	mock := NewMockExecutor()
+	mock.SetExitCode("rm /protected", 1)
+	mock.SetError("rm /protected", configured)
+
+	assert.Equal(t, configured, mock.Run(context.Background(), "rm", "/protected"))
+}
+
+func TestMockExecutorResetClearsExitCodes(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetExitCode("grep -q pve /etc/hosts", 1)
+	mock.Reset()
+
+	assert.NoError(t, mock.Run(context.Background(), "grep", "-q", "pve", "/etc/hosts"))
+}
+
+func TestZeroValueMockExecutorKeepsExitCodeAfterSetError(t *testing.T) {
+	var mock MockExecutor
+
+	mock.SetExitCode("grep -q pve /etc/hosts", 1)
+	mock.SetError("rm /protected", errors.New("permission denied"))
+
+	var cmdErr *CommandError
+	require.ErrorAs(t, mock.Run(context.Background(), "grep", "-q", "pve", "/etc/hosts"), &cmdErr)
+	assert.Equal(t, 1, cmdErr.ExitCode)
+}
@@ -0,0 +1,91 @@
+package exec
+
+import (
+	"strings"
+)
+
+// shellSafeChars contains the characters that never need quoting in a POSIX shell word.
+const shellSafeChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_@%+=:,./-"
+
+// FormatCommand renders a command as a single shell-safe string for display and logging.
+//
+// Unlike ExecutedCommand.String, which joins tokens with spaces, FormatCommand
+// quotes every token that contains shell metacharacters, whitespace, or is empty,
+// so the result can be pasted into a POSIX shell and re-parse to the same argv:
+//
+//	FormatCommand("echo", "hello world", "it's")
+//	// echo 'hello world' 'it'\''s'
+func FormatCommand(name string, args ...string) string {
+	var b strings.Builder
+
+	b.WriteString(quoteArg(name))
+
+	for _, arg := range args {
+		b.WriteByte(' ')
+		b.WriteString(quoteArg(arg))
+	}
+
+	return b.String()
+}
+
+// FormatExecutedCommand renders cmd like FormatCommand, prefixed with its
+// extra environment as KEY=value assignments, wrapped in a subshell that
+// changes into its working directory first if it had one, and followed by a
+// redirection to its output file if it had one, so the result re-runs the
+// command the way it was executed:
+//
+//	DEBIAN_FRONTEND=noninteractive apt-get install -y sudo
+//	(cd /mnt/target && git init)
+//	zgenhostid -f > /target/etc/hostid
+//
+// Only the values are quoted; stdin is not included.
+func FormatExecutedCommand(cmd ExecutedCommand) string {
+	var b strings.Builder
+
+	if cmd.Dir != "" {
+		b.WriteString("(cd " + quoteArg(cmd.Dir) + " && ")
+	}
+
+	for _, kv := range cmd.Env {
+		b.WriteString(quoteEnv(kv))
+		b.WriteByte(' ')
+	}
+
+	b.WriteString(FormatCommand(cmd.Name, cmd.Args...))
+
+	if cmd.Dir != "" {
+		b.WriteByte(')')
+	}
+
+	if cmd.OutputFile != "" {
+		b.WriteString(" > " + quoteArg(cmd.OutputFile))
+	}
+
+	return b.String()
+}
+
+// quoteEnv quotes the value of a KEY=value assignment, keeping the key bare
+// so the shell still treats it as an assignment.
+func quoteEnv(kv string) string {
+	key, value, ok := strings.Cut(kv, "=")
+	if !ok {
+		return quoteArg(kv)
+	}
+
+	return key + "=" + quoteArg(value)
+}
+
+// quoteArg returns s unchanged if it is shell-safe, otherwise wraps it in
+// single quotes. Embedded single quotes are escaped by closing the quoted
+// string, adding a backslash-escaped quote, and reopening it.
+func quoteArg(s string) string {
+	if s == "" {
+		return "''"
+	}
+
+	if strings.Trim(s, shellSafeChars) == "" {
+		return s
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
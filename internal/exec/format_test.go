@@ -0,0 +1,74 @@
+package exec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmd      string
+		args     []string
+		expected string
+	}{
+		{"no args", "pwd", nil, "pwd"},
+		{"plain args", "ls", []string{"-la", "/tmp"}, "ls -la /tmp"},
+		{"safe punctuation", "zfs", []string{"set", "compression=lz4", "rpool/data"}, "zfs set compression=lz4 rpool/data"},
+		{"arg with space", "echo", []string{"hello world"}, "echo 'hello world'"},
+		{"arg with single quote", "echo", []string{"it's"}, `echo 'it'\''s'`},
+		{"only single quote", "echo", []string{"'"}, `echo ''\'''`},
+		{"empty arg", "echo", []string{""}, "echo ''"},
+		{"empty arg between others", "printf", []string{"%s", "", "x"}, "printf %s '' x"},
+		{"shell metacharacters", "sh", []string{"-c", "echo $HOME; ls | wc -l"}, "sh -c 'echo $HOME; ls | wc -l'"},
+		{"glob", "rm", []string{"*.log"}, "rm '*.log'"},
+		{"newline", "echo", []string{"a\nb"}, "echo 'a\nb'"},
+		{"name with space", "my tool", []string{"--flag"}, "'my tool' --flag"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FormatCommand(tt.cmd, tt.args...))
+		})
+	}
+}
+
+func TestFormatExecutedCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmd      ExecutedCommand
+		expected string
+	}{
+		{"no env", ExecutedCommand{Name: "apt-get", Args: []string{"update"}}, "apt-get update"},
+		{
+			"env",
+			ExecutedCommand{Name: "apt-get", Args: []string{"install", "-y", "sudo"}, Env: []string{"DEBIAN_FRONTEND=noninteractive"}},
+			"DEBIAN_FRONTEND=noninteractive apt-get install -y sudo",
+		},
+		{
+			"env value with space",
+			ExecutedCommand{Name: "locale-gen", Env: []string{"LC_ALL=C", "MSG=hello world", "EMPTY="}},
+			"LC_ALL=C MSG='hello world' EMPTY='' locale-gen",
+		},
+		{"dir", ExecutedCommand{Name: "git", Args: []string{"init"}, Dir: "/mnt/target"}, "(cd /mnt/target && git init)"},
+		{
+			"dir with space and env",
+			ExecutedCommand{Name: "make", Env: []string{"CC=gcc"}, Dir: "/root/my src"},
+			"(cd '/root/my src' && CC=gcc make)",
+		},
+		{"stdin is left out", ExecutedCommand{Name: "chpasswd", Stdin: "root:pw"}, "chpasswd"},
+		{"output file", ExecutedCommand{Name: "zgenhostid", Args: []string{"-f"}, OutputFile: "/target/etc/hostid"}, "zgenhostid -f > /target/etc/hostid"},
+		{
+			"output file with dir",
+			ExecutedCommand{Name: "git", Args: []string{"log"}, Dir: "/mnt/target", OutputFile: "/tmp/git log.txt"},
+			"(cd /mnt/target && git log) > '/tmp/git log.txt'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FormatExecutedCommand(tt.cmd))
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// RunJSON runs a command through executor and decodes its stdout as JSON into v,
+// for tools with a JSON output mode such as "lsblk -J", "ip -j" or
+// "zpool status -j".
+//
+// It wraps RunWithStreams, so it works with any Executor implementation, and
+// warnings the tool prints on stderr do not break decoding. On command failure
+// the error is returned as is and v is left untouched; stdout that is not
+// valid JSON for T returns an error naming the command and including stderr.
+func RunJSON[T any](ctx context.Context, executor Executor, v *T, name string, args ...string) error {
+	stdout, stderr, err := executor.RunWithStreams(ctx, name, args...)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal([]byte(stdout), v); err != nil {
+		err = fmt.Errorf("failed to decode JSON output of %s: %w", FormatCommand(name, args...), err)
+
+		if stderr = strings.TrimSpace(stderr); stderr != "" {
+			err = fmt.Errorf("%w (stderr: %s)", err, stderr)
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package exec
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lsblkOutput mirrors the subset of "lsblk -J" output used in tests.
+type lsblkOutput struct {
+	BlockDevices []struct {
+		Name string `json:"name"`
+		Size string `json:"size"`
+		Type string `json:"type"`
+	} `json:"blockdevices"`
+}
+
+func TestRunJSON(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("lsblk -J -o NAME,SIZE,TYPE", `{
+   "blockdevices": [
+      {"name":"sda", "size":"1.8T", "type":"disk"},
+      {"name":"nvme0n1", "size":"476.9G", "type":"disk"}
+   ]
+}
+`)
+
+	var out lsblkOutput
+	err := RunJSON(t.Context(), mock, &out, "lsblk", "-J", "-o", "NAME,SIZE,TYPE")
+
+	require.NoError(t, err)
+	require.Len(t, out.BlockDevices, 2)
+	assert.Equal(t, "sda", out.BlockDevices[0].Name)
+	assert.Equal(t, "1.8T", out.BlockDevices[0].Size)
+	assert.Equal(t, "nvme0n1", out.BlockDevices[1].Name)
+	assert.Equal(t, "disk", out.BlockDevices[1].Type)
+	assert.True(t, mock.WasCalledWith("lsblk", "-J", "-o", "NAME,SIZE,TYPE"))
+}
+
+func TestRunJSONTopLevelArray(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("ip -j link show", `[{"ifname":"lo"},{"ifname":"eth0"}]`)
+
+	var links []struct {
+		IfName string `json:"ifname"`
+	}
+	err := RunJSON(t.Context(), mock, &links, "ip", "-j", "link", "show")
+
+	require.NoError(t, err)
+	require.Len(t, links, 2)
+	assert.Equal(t, "eth0", links[1].IfName)
+}
+
+func TestRunJSONInvalidOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+	}{
+		{"not JSON", "lsblk: unknown option -- 'J'\n"},
+		{"truncated", `{"blockdevices": [`},
+		{"empty", ""},
+		{"wrong type", `{"blockdevices": "sda"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := NewMockExecutor()
+			mock.SetOutput("lsblk -J", tt.output)
+
+			var out lsblkOutput
+			err := RunJSON(t.Context(), mock, &out, "lsblk", "-J")
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "failed to decode JSON output of lsblk -J: ")
+		})
+	}
+}
+
+func TestRunJSONIgnoresStderrWarnings(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetStreams("lsblk -J", `{"blockdevices": [{"name":"sda", "size":"1.8T", "type":"disk"}]}`,
+		"lsblk: /dev/sr0: failed to get device path\n")
+
+	var out lsblkOutput
+	err := RunJSON(t.Context(), mock, &out, "lsblk", "-J")
+
+	require.NoError(t, err)
+	require.Len(t, out.BlockDevices, 1)
+	assert.Equal(t, "sda", out.BlockDevices[0].Name)
+}
+
+func TestRunJSONInvalidOutputIncludesStderr(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetStreams("zpool status -j", "", "unrecognized command 'status -j'\n")
+
+	var out map[string]any
+	err := RunJSON(t.Context(), mock, &out, "zpool", "status", "-j")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decode JSON output of zpool status -j: ")
+	assert.Contains(t, err.Error(), "(stderr: unrecognized command 'status -j')")
+}
+
+func TestRunJSONInvalidOutputQuotesCommand(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("ip -j addr show dev my bridge", "not json")
+
+	var out []any
+	err := RunJSON(t.Context(), mock, &out, "ip", "-j", "addr", "show", "dev", "my bridge")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decode JSON output of ip -j addr show dev 'my bridge': ")
+}
+
+func TestRunJSONInvalidOutputUnwraps(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("lsblk -J", `{"blockdevices": "sda"}`)
+
+	var out lsblkOutput
+	err := RunJSON(t.Context(), mock, &out, "lsblk", "-J")
+
+	var typeErr *json.UnmarshalTypeError
+	assert.ErrorAs(t, err, &typeErr)
+}
+
+func TestRunJSONCommandError(t *testing.T) {
+	cmdErr := errors.New(testCommandNotFound)
+
+	mock := NewMockExecutor()
+	mock.SetOutput("zpool status -j", `{"pools":{}}`)
+	mock.SetError("zpool status -j", cmdErr)
+
+	out := map[string]any{"untouched": true}
+	err := RunJSON(t.Context(), mock, &out, "zpool", "status", "-j")
+
+	require.ErrorIs(t, err, cmdErr)
+	assert.Equal(t, map[string]any{"untouched": true}, out)
+}
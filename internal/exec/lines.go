@@ -0,0 +1,36 @@
+package exec
+
+import (
+	"context"
+	"strings"
+)
+
+// RunLines runs a command through executor and returns its output split into lines.
+//
+// Each line is trimmed of surrounding whitespace and blank lines are dropped,
+// which suits listing commands such as "lsblk -n" or "ip -o link".
+// It wraps RunWithOutput, so it works with any Executor implementation.
+// On command failure the error is returned and the output is discarded.
+func RunLines(ctx context.Context, executor Executor, name string, args ...string) ([]string, error) {
+	out, err := executor.RunWithOutput(ctx, name, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return splitLines(out), nil
+}
+
+// splitLines splits s on newlines, trimming each line and dropping blank ones.
+// Returns an empty, non-nil slice when s has no content.
+func splitLines(s string) []string {
+	raw := strings.Split(s, "\n")
+	lines := make([]string, 0, len(raw))
+
+	for _, line := range raw {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,49 @@
+package exec
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected []string
+	}{
+		{"trailing newline", "sda\nsdb\n", []string{"sda", "sdb"}},
+		{"blank lines", "\nsda\n\n\nsdb\n\n", []string{"sda", "sdb"}},
+		{"surrounding whitespace", "  sda  \n\tsdb\t\n", []string{"sda", "sdb"}},
+		{"CRLF line endings", "sda\r\nsdb\r\n", []string{"sda", "sdb"}},
+		{"whitespace-only lines", "sda\n   \n\t\nsdb", []string{"sda", "sdb"}},
+		{"inner spaces preserved", "/dev/sda disk\n", []string{"/dev/sda disk"}},
+		{"empty output", "", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := NewMockExecutor()
+			mock.SetOutput("lsblk -n", tt.output)
+
+			lines, err := RunLines(t.Context(), mock, "lsblk", "-n")
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, lines)
+			assert.True(t, mock.WasCalledWith("lsblk", "-n"))
+		})
+	}
+}
+
+func TestRunLinesCommandError(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("lsblk", "partial\n")
+	mock.SetError("lsblk", errors.New(testCommandNotFound))
+
+	lines, err := RunLines(t.Context(), mock, "lsblk")
+
+	require.Error(t, err)
+	assert.Nil(t, lines)
+}
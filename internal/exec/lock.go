@@ -0,0 +1,85 @@
+//go:build !windows
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+// lockFileMode is the permission mode of lock files created by RunWithLock.
+const lockFileMode = 0o600
+
+// ErrLockHeld is returned by RunWithLock when another process holds the lock.
+var ErrLockHeld = errors.New("lock is held by another process")
+
+// RunWithLock runs fn while holding an exclusive flock(2) on lockPath, so
+// that concurrent installer runs cannot corrupt each other's state. The lock
+// file is created if needed and left in place; only the lock is released.
+//
+// It does not wait: if another process (or another open of the same file)
+// holds the lock, it returns an error wrapping ErrLockHeld without calling
+// fn. The lock is released when fn returns, fails or panics.
+func RunWithLock(ctx context.Context, lockPath string, fn func() error) error {
+	return RunWithLockWait(ctx, lockPath, 0, fn)
+}
+
+// RunWithLockWait runs fn like RunWithLock, but when the lock is held it
+// retries every interval until the lock is acquired or ctx expires, in which
+// case the returned error wraps ctx.Err(). A non-positive interval does not
+// wait, as RunWithLock.
+func RunWithLockWait(ctx context.Context, lockPath string, interval time.Duration, fn func() error) error {
+	file, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, lockFileMode) //nolint:gosec // path is provided by caller
+	if err != nil {
+		return fmt.Errorf("failed to open lock file %s: %w", lockPath, err)
+	}
+	defer file.Close() //nolint:errcheck // closing also releases the lock
+
+	if err := acquireLock(ctx, file, interval); err != nil {
+		return fmt.Errorf("failed to lock %s: %w", lockPath, err)
+	}
+
+	defer syscall.Flock(int(file.Fd()), syscall.LOCK_UN) //nolint:errcheck // Close releases it regardless
+
+	return fn()
+}
+
+// acquireLock takes an exclusive flock on file, polling every interval while
+// it is held elsewhere. With a non-positive interval it tries once.
+func acquireLock(ctx context.Context, file *os.File, interval time.Duration) error {
+	var ticker *time.Ticker
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			return nil
+		}
+
+		if !errors.Is(err, syscall.EWOULDBLOCK) {
+			return err
+		}
+
+		if interval <= 0 {
+			return ErrLockHeld
+		}
+
+		if ticker == nil {
+			ticker = time.NewTicker(interval)
+			defer ticker.Stop()
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ErrLockHeld, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,155 @@
+//go:build !windows
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testLockFileName is the lock file name used by lock tests.
+const testLockFileName = "pve-install.lock"
+
+// holdLock acquires the lock at path in a goroutine and keeps it until the
+// returned release function is called.
+func holdLock(t *testing.T, path string) (release func()) {
+	t.Helper()
+
+	acquired := make(chan struct{})
+	done := make(chan struct{})
+	finished := make(chan error, 1)
+
+	go func() {
+		finished <- RunWithLock(context.Background(), path, func() error {
+			close(acquired)
+			<-done
+
+			return nil
+		})
+	}()
+
+	select {
+	case <-acquired:
+	case err := <-finished:
+		t.Fatalf("failed to acquire lock: %v", err)
+	}
+
+	return func() {
+		close(done)
+		require.NoError(t, <-finished)
+	}
+}
+
+func TestRunWithLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), testLockFileName)
+	called := false
+
+	err := RunWithLock(t.Context(), path, func() error {
+		called = true
+
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.True(t, called)
+	assert.FileExists(t, path, "the lock file is left in place")
+}
+
+func TestRunWithLockHeld(t *testing.T) {
+	path := filepath.Join(t.TempDir(), testLockFileName)
+	release := holdLock(t, path)
+
+	called := false
+	err := RunWithLock(t.Context(), path, func() error {
+		called = true
+
+		return nil
+	})
+
+	require.ErrorIs(t, err, ErrLockHeld)
+	assert.False(t, called)
+
+	release()
+
+	require.NoError(t, RunWithLock(t.Context(), path, func() error { return nil }), "released after fn returns")
+}
+
+func TestRunWithLockWaitAcquiresAfterRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), testLockFileName)
+	release := holdLock(t, path)
+
+	time.AfterFunc(30*time.Millisecond, release)
+
+	called := false
+	err := RunWithLockWait(t.Context(), path, 5*time.Millisecond, func() error {
+		called = true
+
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.True(t, called)
+}
+
+func TestRunWithLockWaitContextExpires(t *testing.T) {
+	path := filepath.Join(t.TempDir(), testLockFileName)
+	release := holdLock(t, path)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 30*time.Millisecond)
+	defer cancel()
+
+	err := RunWithLockWait(ctx, path, 5*time.Millisecond, func() error {
+		t.Error("fn must not run while the lock is held")
+
+		return nil
+	})
+
+	require.ErrorIs(t, err, ErrLockHeld)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRunWithLockReleasedOnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), testLockFileName)
+	errStep := errors.New("step failed")
+
+	err := RunWithLock(t.Context(), path, func() error { return errStep })
+	require.ErrorIs(t, err, errStep)
+
+	assert.NoError(t, RunWithLock(t.Context(), path, func() error { return nil }))
+}
+
+func TestRunWithLockReleasedOnPanic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), testLockFileName)
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = RunWithLock(t.Context(), path, func() error { panic("boom") })
+	})
+
+	assert.NoError(t, RunWithLock(t.Context(), path, func() error { return nil }))
+}
+
+func TestRunWithLockCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	err := RunWithLock(ctx, filepath.Join(t.TempDir(), testLockFileName), func() error { return nil })
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRunWithLockOpenError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", testLockFileName)
+
+	err := RunWithLock(t.Context(), path, func() error { return nil })
+
+	require.ErrorIs(t, err, os.ErrNotExist)
+	assert.Contains(t, err.Error(), "failed to open lock file")
+}
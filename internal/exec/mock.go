@@ -2,7 +2,12 @@ package exec
 
 import (
 	"context"
+	"io"
+	"os"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 )
 
 // MockExecutor is a test implementation of Executor that records commands
@@ -15,18 +20,73 @@ import (
 //
 //	mock := NewMockExecutor()
 //	mock.SetOutput("ls -la", "file1.txt\nfile2.txt")
+//	mock.SetStreams("zpool import rpool", "", "cannot import 'rpool': no such pool available")
+//	mock.SetDirOutput("/target", "git rev-parse HEAD", "4b825dc\n")
 //	mock.SetError("rm /protected", errors.New("permission denied"))
+//	mock.SetExitCode("grep -q pve /etc/hosts", 1)
+//	mock.SetDelay("sleep 10", 50*time.Millisecond)
+//	mock.QueueError("test -f /ready", errors.New("exit status 1"))
+//	mock.QueueDelay("curl -fsSO https://example.com/pve.iso", time.Minute)
+//	mock.SetErrorAfter("lsblk /dev/sdb", 2, errors.New("device vanished"))
+//	mock.SetOutputFunc(func(cmd ExecutedCommand) bool { return cmd.Name == "stat" }, "regular file")
+//	mock.SetClock(fakeClock.Now)
 //
 //	// Use mock in tests...
 //	output, err := mock.RunWithOutput(ctx, "ls", "-la")
 //
 //	// Verify recorded commands
 //	commands := mock.Commands()
+//
+//	// Start the next test phase with the same responses
+//	mock.ResetCommands()
 type MockExecutor struct {
-	mu       sync.Mutex
-	commands []ExecutedCommand
-	outputs  map[string]string
-	errors   map[string]error
+	mu           sync.Mutex
+	commands     []ExecutedCommand
+	outputs      map[string]string
+	streams      map[string]mockStreams
+	dirOutputs   map[mockDirKey]string
+	errors       map[string]error
+	exitCodes    map[string]int
+	delays       map[string]time.Duration
+	queuedDelays map[string][]time.Duration
+	queued       map[string][]mockResponse
+	failures     map[string]mockFailure
+	calls        map[string]int
+	matchers     []mockMatcher
+	clock        func() time.Time
+}
+
+// mockMatcher is a predicate-based response configured with SetOutputFunc or
+// SetErrorFunc. Exactly one of hasOutput or err is meaningful.
+type mockMatcher struct {
+	match     func(ExecutedCommand) bool
+	output    string
+	hasOutput bool
+	err       error
+}
+
+// mockStreams are the separate outputs configured with SetStreams.
+type mockStreams struct {
+	stdout string
+	stderr string
+}
+
+// mockDirKey identifies a command run in a directory with RunInDir.
+type mockDirKey struct {
+	dir string
+	cmd string
+}
+
+// mockResponse is a single queued command response.
+type mockResponse struct {
+	output string
+	err    error
+}
+
+// mockFailure is an error returned once a command has been called more than after times.
+type mockFailure struct {
+	after int
+	err   error
 }
 
 // Compile-time assertion that MockExecutor implements Executor.
@@ -36,15 +96,50 @@ var _ Executor = (*MockExecutor)(nil)
 // and response maps.
 func NewMockExecutor() *MockExecutor {
 	return &MockExecutor{
-		outputs: make(map[string]string),
-		errors:  make(map[string]error),
+		outputs:      make(map[string]string),
+		streams:      make(map[string]mockStreams),
+		dirOutputs:   make(map[mockDirKey]string),
+		errors:       make(map[string]error),
+		exitCodes:    make(map[string]int),
+		delays:       make(map[string]time.Duration),
+		queuedDelays: make(map[string][]time.Duration),
+		queued:       make(map[string][]mockResponse),
+		failures:     make(map[string]mockFailure),
+		calls:        make(map[string]int),
 	}
 }
 
 // makeKey creates a lookup key from command name and args.
-// The key format is "name arg1 arg2 ..." with single space separators.
+// The key format is "name arg1 arg2 ..." with single space separators,
+// identical to ExecutedCommand.String.
+//
+// It runs on every mocked call, so the common cases avoid strings.Join:
+// no args returns name without allocating, one arg is a single concatenation,
+// and longer lists are written into a builder sized up front.
 func makeKey(name string, args ...string) string {
-	return ExecutedCommand{Name: name, Args: args}.String()
+	switch len(args) {
+	case 0:
+		return name
+	case 1:
+		return name + " " + args[0]
+	}
+
+	size := len(name) + len(args)
+	for _, arg := range args {
+		size += len(arg)
+	}
+
+	var b strings.Builder
+
+	b.Grow(size)
+	b.WriteString(name)
+
+	for _, arg := range args {
+		b.WriteByte(' ')
+		b.WriteString(arg)
+	}
+
+	return b.String()
 }
 
 // SetOutput configures the output to return for a specific command.
@@ -63,6 +158,50 @@ func (m *MockExecutor) SetOutput(cmd, output string) {
 	m.outputs[cmd] = output
 }
 
+// SetStreams configures the stdout and stderr returned by RunWithStreams, and
+// written by RunWithWriter, for a specific command. Without it, both use the
+// command's output (see SetOutput) as stdout and an empty stderr. Other
+// methods are not affected.
+func (m *MockExecutor) SetStreams(cmd, stdout, stderr string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.streams == nil {
+		m.streams = make(map[string]mockStreams)
+	}
+
+	m.streams[cmd] = mockStreams{stdout: stdout, stderr: stderr}
+}
+
+// SetDirOutput configures the output RunInDir returns for a specific command
+// run in dir, taking precedence over SetOutput for that directory only.
+// Other methods and directories are not affected.
+func (m *MockExecutor) SetDirOutput(dir, cmd, output string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.dirOutputs == nil {
+		m.dirOutputs = make(map[mockDirKey]string)
+	}
+
+	m.dirOutputs[mockDirKey{dir: dir, cmd: cmd}] = output
+}
+
+// SetExitCode configures a specific command to fail with a *CommandError
+// carrying code, as RealExecutor would for that exit status; its Stderr is
+// the stderr set with SetStreams, if any. Code 0 means success. An error
+// configured for the command in any other way takes precedence.
+func (m *MockExecutor) SetExitCode(cmd string, code int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.exitCodes == nil {
+		m.exitCodes = make(map[string]int)
+	}
+
+	m.exitCodes[cmd] = code
+}
+
 // SetError configures the error to return for a specific command.
 // The cmd parameter should match the full command string (e.g., "rm /protected").
 func (m *MockExecutor) SetError(cmd string, err error) {
@@ -76,6 +215,125 @@ func (m *MockExecutor) SetError(cmd string, err error) {
 	m.errors[cmd] = err
 }
 
+// SetDelay configures how long a specific command takes to complete.
+// The cmd parameter should match the full command string (e.g., "sleep 10").
+//
+// The delay simulates a long-running command: the call blocks without
+// holding the mutex and returns ctx.Err() if the context is canceled first.
+func (m *MockExecutor) SetDelay(cmd string, delay time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.delays == nil {
+		m.delays = make(map[string]time.Duration)
+	}
+
+	m.delays[cmd] = delay
+}
+
+// QueueDelay queues a one-shot delay for a specific command. Queued delays
+// are consumed in FIFO order, one per call, before the SetDelay value
+// applies, so a test can make only the first attempt of a command slow.
+// A queued delay of zero makes that call return immediately.
+func (m *MockExecutor) QueueDelay(cmd string, delay time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.queuedDelays == nil {
+		m.queuedDelays = make(map[string][]time.Duration)
+	}
+
+	m.queuedDelays[cmd] = append(m.queuedDelays[cmd], delay)
+}
+
+// QueueOutput queues a one-shot output for a specific command.
+// Queued responses are consumed in FIFO order, one per call, before the
+// values configured with SetOutput/SetError apply. This allows simulating
+// state that changes between calls (e.g., a file that appears later).
+func (m *MockExecutor) QueueOutput(cmd, output string) {
+	m.enqueue(cmd, mockResponse{output: output})
+}
+
+// QueueError queues a one-shot error for a specific command.
+// See QueueOutput for ordering semantics.
+func (m *MockExecutor) QueueError(cmd string, err error) {
+	m.enqueue(cmd, mockResponse{err: err})
+}
+
+// enqueue appends a response to the queue for cmd.
+func (m *MockExecutor) enqueue(cmd string, resp mockResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.queued == nil {
+		m.queued = make(map[string][]mockResponse)
+	}
+
+	m.queued[cmd] = append(m.queued[cmd], resp)
+}
+
+// SetErrorAfter configures a command to succeed for its first n calls and
+// return err on every call after that, simulating a resource that disappears
+// (e.g., a disk that is unplugged). The cmd parameter should match the full
+// command string.
+//
+// Every call to cmd counts towards n, including calls answered by a queued
+// response. Queued responses still take precedence when pending. Calls before
+// the threshold return the SetOutput/SetError values as usual.
+func (m *MockExecutor) SetErrorAfter(cmd string, n int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.failures == nil {
+		m.failures = make(map[string]mockFailure)
+	}
+
+	m.failures[cmd] = mockFailure{after: n, err: err}
+}
+
+// SetOutputFunc configures the output to return for any command for which
+// match returns true, for tests that need more than an exact command string
+// (e.g., any command with an argument under /tmp).
+//
+// Responses are resolved in this order:
+//  1. a pending queued response (QueueOutput/QueueError)
+//  2. the exact command string (SetOutput/SetError)
+//  3. the first matching predicate, in registration order
+//
+// Output and error are resolved independently, so an exact SetError does not
+// hide a predicate output and vice versa. SetErrorAfter still applies on top.
+//
+// match is called with a copy of the command while the mock is locked, so it
+// must not call methods of the MockExecutor.
+func (m *MockExecutor) SetOutputFunc(match func(cmd ExecutedCommand) bool, output string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.matchers = append(m.matchers, mockMatcher{match: match, output: output, hasOutput: true})
+}
+
+// SetErrorFunc configures the error to return for any command for which match
+// returns true. See SetOutputFunc for precedence.
+func (m *MockExecutor) SetErrorFunc(match func(cmd ExecutedCommand) bool, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.matchers = append(m.matchers, mockMatcher{match: match, err: err})
+}
+
+// SetClock configures the clock used to timestamp recorded commands
+// (ExecutedCommand.StartedAt), so tests can inject a fake clock and assert
+// on a deterministic timeline. A nil clock restores time.Now.
+//
+// The clock is called while the mock is locked, once per recorded command in
+// recording order, so it must not call methods of the MockExecutor.
+func (m *MockExecutor) SetClock(clock func() time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.clock = clock
+}
+
 // Commands returns all executed commands in order of execution.
 // Returns a deep copy to prevent external modification of internal state.
 func (m *MockExecutor) Commands() []ExecutedCommand {
@@ -93,16 +351,21 @@ func (m *MockExecutor) Commands() []ExecutedCommand {
 		}
 
 		result[i] = ExecutedCommand{
-			Name:  cmd.Name,
-			Args:  argsCopy,
-			Stdin: cmd.Stdin,
+			Name:       cmd.Name,
+			Args:       argsCopy,
+			Stdin:      cmd.Stdin,
+			Env:        slices.Clone(cmd.Env),
+			Dir:        cmd.Dir,
+			OutputFile: cmd.OutputFile,
+			StartedAt:  cmd.StartedAt,
 		}
 	}
 
 	return result
 }
 
-// Reset clears all recorded commands and configured responses.
+// Reset clears all recorded commands and configured responses, and restores
+// the default clock.
 // Useful for reusing a MockExecutor across multiple test cases.
 func (m *MockExecutor) Reset() {
 	m.mu.Lock()
@@ -110,66 +373,267 @@ func (m *MockExecutor) Reset() {
 
 	m.commands = nil
 	m.outputs = make(map[string]string)
+	m.streams = make(map[string]mockStreams)
+	m.dirOutputs = make(map[mockDirKey]string)
 	m.errors = make(map[string]error)
+	m.exitCodes = make(map[string]int)
+	m.delays = make(map[string]time.Duration)
+	m.queuedDelays = make(map[string][]time.Duration)
+	m.queued = make(map[string][]mockResponse)
+	m.failures = make(map[string]mockFailure)
+	m.calls = make(map[string]int)
+	m.matchers = nil
+	m.clock = nil
 }
 
-// record adds a command to the execution history.
-// Must be called while holding the mutex.
-func (m *MockExecutor) record(name string, args []string, stdin string) {
-	m.commands = append(m.commands, ExecutedCommand{
-		Name:  name,
-		Args:  args,
-		Stdin: stdin,
-	})
+// ResetCommands clears the recorded command history only. Configured outputs,
+// errors, delays, pending queued responses and SetErrorAfter call counts are
+// kept, so a test can assert on each phase separately with the same setup.
+func (m *MockExecutor) ResetCommands() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.commands = nil
+}
+
+// record adds a command to the execution history, timestamped with the
+// configured clock. Must be called while holding the mutex.
+func (m *MockExecutor) record(cmd ExecutedCommand) {
+	now := time.Now
+	if m.clock != nil {
+		now = m.clock
+	}
+
+	cmd.Env = slices.Clone(cmd.Env)
+	cmd.StartedAt = now()
+	m.commands = append(m.commands, cmd)
 }
 
-// response returns the configured output and error for a command key.
+// response returns the configured output and error for cmd, whose lookup key is key.
+// A queued response is consumed first if one is pending; otherwise exact values
+// take priority over predicate matchers, and a SetErrorAfter failure applies
+// once its threshold has been passed.
 // Must be called while holding the mutex.
-func (m *MockExecutor) response(key string) (string, error) {
-	output := m.outputs[key]
-	err := m.errors[key]
+func (m *MockExecutor) response(cmd ExecutedCommand, key string) (string, error) {
+	if m.calls == nil {
+		m.calls = make(map[string]int)
+	}
+
+	m.calls[key]++
+
+	if queue := m.queued[key]; len(queue) > 0 {
+		m.queued[key] = queue[1:]
+
+		return queue[0].output, queue[0].err
+	}
+
+	output, hasOutput := m.outputs[key]
+	err, hasErr := m.errors[key]
+
+	if dirOutput, ok := m.dirOutputs[mockDirKey{dir: cmd.Dir, cmd: key}]; ok && cmd.Dir != "" {
+		output, hasOutput = dirOutput, true
+	}
+
+	if (!hasOutput || !hasErr) && len(m.matchers) > 0 {
+		output, err = m.matchResponse(cmd, output, hasOutput, err, hasErr)
+	}
+
+	if failure, ok := m.failures[key]; ok && m.calls[key] > failure.after {
+		err = failure.err
+	}
+
+	if code := m.exitCodes[key]; err == nil && code != 0 {
+		err = &CommandError{
+			Name:     cmd.Name,
+			Args:     append([]string(nil), cmd.Args...),
+			ExitCode: code,
+			Stderr:   m.streams[key].stderr,
+			Err:      exitStatusError(code),
+		}
+	}
 
 	return output, err
 }
 
-// Run executes a command and returns an error if configured.
-// The command is recorded for later assertion.
-func (m *MockExecutor) Run(_ context.Context, name string, args ...string) error {
+// matchResponse fills in the output and error not set exactly from the first
+// matching predicate of each kind. Must be called while holding the mutex.
+func (m *MockExecutor) matchResponse(cmd ExecutedCommand, output string, hasOutput bool, err error, hasErr bool) (string, error) {
+	cmd.Args = append([]string(nil), cmd.Args...)
+	cmd.Env = slices.Clone(cmd.Env)
+
+	for _, matcher := range m.matchers {
+		if matcher.hasOutput {
+			if !hasOutput && matcher.match(cmd) {
+				output, hasOutput = matcher.output, true
+			}
+		} else if !hasErr && matcher.match(cmd) {
+			err, hasErr = matcher.err, true
+		}
+	}
+
+	return output, err
+}
+
+// delay returns the delay for the command with lookup key key, consuming a
+// queued delay if one is pending. Must be called while holding the mutex.
+func (m *MockExecutor) delay(key string) time.Duration {
+	if queue := m.queuedDelays[key]; len(queue) > 0 {
+		m.queuedDelays[key] = queue[1:]
+
+		return queue[0]
+	}
+
+	return m.delays[key]
+}
+
+// call records a command, looks up its configured response and waits for
+// its configured delay. The mutex is released before waiting so concurrent
+// calls and assertions are not blocked by a slow command.
+func (m *MockExecutor) call(ctx context.Context, cmd ExecutedCommand) (string, error) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
+	m.record(cmd)
+	key := makeKey(cmd.Name, cmd.Args...)
+	output, err := m.response(cmd, key)
+	delay := m.delay(key)
+	m.mu.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return output, err
+}
 
-	m.record(name, args, "")
-	key := makeKey(name, args...)
-	_, err := m.response(key)
+// Run executes a command and returns an error if configured.
+// The command is recorded for later assertion.
+func (m *MockExecutor) Run(ctx context.Context, name string, args ...string) error {
+	_, err := m.call(ctx, ExecutedCommand{Name: name, Args: args})
 
 	return err
 }
 
 // RunWithOutput executes a command and returns the configured output/error.
 // The command is recorded for later assertion.
-func (m *MockExecutor) RunWithOutput(_ context.Context, name string, args ...string) (string, error) {
+func (m *MockExecutor) RunWithOutput(ctx context.Context, name string, args ...string) (string, error) {
+	return m.call(ctx, ExecutedCommand{Name: name, Args: args})
+}
+
+// RunWithCombinedOutput executes a command and returns the configured
+// output/error, which stands for both streams.
+// The command is recorded for later assertion.
+func (m *MockExecutor) RunWithCombinedOutput(ctx context.Context, name string, args ...string) (string, error) {
+	return m.call(ctx, ExecutedCommand{Name: name, Args: args})
+}
+
+// RunWithStreams executes a command and returns the streams configured with
+// SetStreams, or else the configured output as stdout, with the configured
+// error. The command is recorded for later assertion.
+func (m *MockExecutor) RunWithStreams(ctx context.Context, name string, args ...string) (stdout, stderr string, err error) {
+	output, err := m.call(ctx, ExecutedCommand{Name: name, Args: args})
+
 	m.mu.Lock()
-	defer m.mu.Unlock()
+	streams, ok := m.streams[makeKey(name, args...)]
+	m.mu.Unlock()
 
-	m.record(name, args, "")
-	key := makeKey(name, args...)
+	if ok {
+		return streams.stdout, streams.stderr, err
+	}
 
-	return m.response(key)
+	return output, "", err
 }
 
 // RunWithStdin executes a command with stdin input.
 // The command and stdin are recorded for later assertion.
-func (m *MockExecutor) RunWithStdin(_ context.Context, stdin, name string, args ...string) error {
+func (m *MockExecutor) RunWithStdin(ctx context.Context, stdin, name string, args ...string) error {
+	_, err := m.call(ctx, ExecutedCommand{Name: name, Args: args, Stdin: stdin})
+
+	return err
+}
+
+// RunWithEnv executes a command with extra environment variables.
+// The command and env are recorded for later assertion; env does not affect
+// which configured response applies.
+func (m *MockExecutor) RunWithEnv(ctx context.Context, env []string, name string, args ...string) error {
+	_, err := m.call(ctx, ExecutedCommand{Name: name, Args: args, Env: env})
+
+	return err
+}
+
+// RunInDir executes a command and returns the output configured for it in
+// dir with SetDirOutput, or else its configured output/error. The command
+// and dir are recorded for later assertion; the directory is not checked.
+func (m *MockExecutor) RunInDir(ctx context.Context, dir, name string, args ...string) (string, error) {
+	return m.call(ctx, ExecutedCommand{Name: name, Args: args, Dir: dir})
+}
+
+// RunWithWriter executes a command and writes the streams configured with
+// SetStreams, or else the configured output, to stdout and stderr before
+// returning the configured error. Nil writers are skipped. The command is
+// recorded for later assertion.
+func (m *MockExecutor) RunWithWriter(ctx context.Context, stdout, stderr io.Writer, name string, args ...string) error {
+	output, runErr := m.call(ctx, ExecutedCommand{Name: name, Args: args})
+
 	m.mu.Lock()
-	defer m.mu.Unlock()
+	streams, ok := m.streams[makeKey(name, args...)]
+	m.mu.Unlock()
+
+	if !ok {
+		streams = mockStreams{stdout: output}
+	}
+
+	writeErr := writeStream(stdout, streams.stdout)
+	if err := writeStream(stderr, streams.stderr); writeErr == nil {
+		writeErr = err
+	}
+
+	if runErr != nil {
+		return runErr
+	}
+
+	return writeErr
+}
+
+// writeStream writes s to w unless w is nil or s is empty.
+func writeStream(w io.Writer, s string) error {
+	if w == nil || s == "" {
+		return nil
+	}
 
-	m.record(name, args, stdin)
-	key := makeKey(name, args...)
-	_, err := m.response(key)
+	_, err := io.WriteString(w, s)
 
 	return err
 }
 
+// RunToFile executes a command and writes the configured output to the file
+// at path, even if an error is also configured. The file is opened before the
+// command is recorded, so a bad path returns an error without recording it.
+func (m *MockExecutor) RunToFile(ctx context.Context, path, name string, args ...string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, outputFileMode)
+	if err != nil {
+		return err
+	}
+
+	output, runErr := m.call(ctx, ExecutedCommand{Name: name, Args: args, OutputFile: path})
+
+	_, writeErr := file.WriteString(output)
+	if closeErr := file.Close(); writeErr == nil {
+		writeErr = closeErr
+	}
+
+	if runErr != nil {
+		return runErr
+	}
+
+	return writeErr
+}
+
 // CommandCount returns the number of executed commands.
 // This is useful for verifying that the expected number of commands were run.
 func (m *MockExecutor) CommandCount() int {
@@ -195,6 +659,27 @@ func (m *MockExecutor) WasCalledWith(name string, args ...string) bool {
 	return false
 }
 
+// WasNotCalledWith checks that no command with the given name and args was executed.
+// It is the logical inverse of WasCalledWith and uses the same exact matching rules.
+func (m *MockExecutor) WasNotCalledWith(name string, args ...string) bool {
+	return !m.WasCalledWith(name, args...)
+}
+
+// WasNeverCalled checks that no command with the given name was executed,
+// regardless of its arguments. Useful for proving a destructive command never ran.
+func (m *MockExecutor) WasNeverCalled(name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, cmd := range m.commands {
+		if cmd.Name == name {
+			return false
+		}
+	}
+
+	return true
+}
+
 // LastCommand returns the most recently executed command, or nil if none.
 // The returned ExecutedCommand has its Args slice deep-copied to prevent
 // external modification. Name and Stdin are string value types that are
@@ -217,9 +702,13 @@ func (m *MockExecutor) LastCommand() *ExecutedCommand {
 	}
 
 	return &ExecutedCommand{
-		Name:  cmd.Name,
-		Args:  argsCopy,
-		Stdin: cmd.Stdin,
+		Name:       cmd.Name,
+		Args:       argsCopy,
+		Stdin:      cmd.Stdin,
+		Env:        slices.Clone(cmd.Env),
+		Dir:        cmd.Dir,
+		OutputFile: cmd.OutputFile,
+		StartedAt:  cmd.StartedAt,
 	}
 }
 
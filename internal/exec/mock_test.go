@@ -1,9 +1,16 @@
 package exec
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -210,6 +217,69 @@ func TestMockExecutorReset(t *testing.T) {
 	mock.mu.Unlock()
 }
 
+func TestMockExecutorResetCommandsKeepsConfiguration(t *testing.T) {
+	mock := NewMockExecutor()
+	ctx := t.Context()
+
+	mock.SetOutput("ls", testFileListOutput)
+	mock.SetError("rm", errors.New(testPermissionDenied))
+
+	_, err := mock.RunWithOutput(ctx, "ls")
+	require.NoError(t, err)
+	require.Error(t, mock.Run(ctx, "rm"))
+	require.Equal(t, 2, mock.CommandCount())
+
+	mock.ResetCommands()
+
+	assert.Empty(t, mock.Commands())
+	assert.Nil(t, mock.LastCommand())
+
+	output, err := mock.RunWithOutput(ctx, "ls")
+	require.NoError(t, err)
+	assert.Equal(t, testFileListOutput, output)
+	assert.EqualError(t, mock.Run(ctx, "rm"), testPermissionDenied)
+	assert.Equal(t, 2, mock.CommandCount())
+}
+
+func TestMockExecutorResetCommandsKeepsErrorAfterCount(t *testing.T) {
+	mock := NewMockExecutor()
+	ctx := t.Context()
+	mock.SetErrorAfter("lsblk", 1, errors.New("device vanished"))
+
+	require.NoError(t, mock.Run(ctx, "lsblk"))
+
+	mock.ResetCommands()
+
+	assert.Error(t, mock.Run(ctx, "lsblk"), "call count survives ResetCommands")
+}
+
+func TestMockExecutorResetCommandsConcurrent(t *testing.T) {
+	mock := NewMockExecutor()
+	ctx := t.Context()
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			_ = mock.Run(ctx, "echo") //nolint:errcheck // concurrency test only
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			mock.ResetCommands()
+		}()
+	}
+
+	wg.Wait()
+
+	assert.LessOrEqual(t, mock.CommandCount(), 10)
+}
+
 func TestMockExecutorRun(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -354,6 +424,201 @@ func TestMockExecutorRunWithOutput(t *testing.T) {
 	}
 }
 
+func TestMockExecutorRunWithCombinedOutput(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("sgdisk --zap-all /dev/sda", "Problem opening /dev/sda for reading!")
+	mock.SetError("sgdisk --zap-all /dev/sda", errors.New("exit status 2"))
+
+	output, err := mock.RunWithCombinedOutput(t.Context(), "sgdisk", "--zap-all", "/dev/sda")
+
+	require.Error(t, err)
+	assert.Equal(t, "Problem opening /dev/sda for reading!", output)
+	assert.True(t, mock.WasCalledWith("sgdisk", "--zap-all", "/dev/sda"))
+}
+
+func TestMockExecutorRunWithStreams(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetStreams("sh -c greet", "hi", "oops")
+	mock.SetError("sh -c greet", errors.New("exit status 1"))
+
+	stdout, stderr, err := mock.RunWithStreams(t.Context(), "sh", "-c", "greet")
+
+	require.Error(t, err)
+	assert.Equal(t, "hi", stdout)
+	assert.Equal(t, "oops", stderr)
+	assert.True(t, mock.WasCalledWith("sh", "-c", "greet"))
+}
+
+func TestMockExecutorRunWithStreamsFallsBackToOutput(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("hostname --short", "pve\n")
+
+	stdout, stderr, err := mock.RunWithStreams(t.Context(), "hostname", "--short")
+
+	require.NoError(t, err)
+	assert.Equal(t, "pve\n", stdout)
+	assert.Empty(t, stderr)
+}
+
+func TestMockExecutorSetStreamsOnlyAffectsRunWithStreams(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetStreams("zpool import rpool", "", "no such pool")
+
+	output, err := mock.RunWithOutput(t.Context(), "zpool", "import", "rpool")
+	require.NoError(t, err)
+	assert.Empty(t, output)
+
+	mock.Reset()
+
+	_, stderr, err := mock.RunWithStreams(t.Context(), "zpool", "import", "rpool")
+	require.NoError(t, err)
+	assert.Empty(t, stderr, "Reset clears configured streams")
+}
+
+func TestMockExecutorRunWithEnvRecordsEnv(t *testing.T) {
+	mock := NewMockExecutor()
+	env := []string{"DEBIAN_FRONTEND=noninteractive", "LC_ALL=C"}
+
+	require.NoError(t, mock.RunWithEnv(t.Context(), env, "apt-get", "install", "-y", "sudo"))
+
+	env[0] = "DEBIAN_FRONTEND=dialog"
+
+	last := mock.LastCommand()
+	require.NotNil(t, last)
+	assert.Equal(t, "apt-get install -y sudo", last.String())
+	assert.Equal(t, []string{"DEBIAN_FRONTEND=noninteractive", "LC_ALL=C"}, last.Env, "env is copied when recorded")
+
+	commands := mock.Commands()
+	require.Len(t, commands, 1)
+
+	commands[0].Env[1] = "LC_ALL=en_US.UTF-8"
+	assert.Equal(t, "LC_ALL=C", mock.Commands()[0].Env[1], "Commands returns a copy of env")
+}
+
+func TestMockExecutorRunWithEnvUsesConfiguredError(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetError("apt-get update", assert.AnError)
+
+	err := mock.RunWithEnv(t.Context(), []string{"DEBIAN_FRONTEND=noninteractive"}, "apt-get", "update")
+
+	require.ErrorIs(t, err, assert.AnError)
+}
+
+func TestMockExecutorRunWithoutEnvRecordsNil(t *testing.T) {
+	mock := NewMockExecutor()
+
+	require.NoError(t, mock.Run(t.Context(), "apt-get", "update"))
+
+	assert.Nil(t, mock.LastCommand().Env)
+}
+
+func TestMockExecutorRunInDirRecordsDir(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("git rev-parse HEAD", "default\n")
+
+	out, err := mock.RunInDir(t.Context(), "/srv/repo", "git", "rev-parse", "HEAD")
+
+	require.NoError(t, err)
+	assert.Equal(t, "default\n", out)
+
+	last := mock.LastCommand()
+	require.NotNil(t, last)
+	assert.Equal(t, "/srv/repo", last.Dir)
+	assert.Equal(t, "/srv/repo", mock.Commands()[0].Dir)
+}
+
+func TestMockExecutorSetDirOutput(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("git rev-parse HEAD", "default\n")
+	mock.SetDirOutput("/srv/repo", "git rev-parse HEAD", "4b825dc\n")
+
+	out, err := mock.RunInDir(t.Context(), "/srv/repo", "git", "rev-parse", "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, "4b825dc\n", out, "the directory output wins")
+
+	out, err = mock.RunInDir(t.Context(), "/srv/other", "git", "rev-parse", "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, "default\n", out, "other directories use SetOutput")
+
+	out, err = mock.RunWithOutput(t.Context(), "git", "rev-parse", "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, "default\n", out, "other methods use SetOutput")
+
+	mock.Reset()
+
+	out, err = mock.RunInDir(t.Context(), "/srv/repo", "git", "rev-parse", "HEAD")
+	require.NoError(t, err)
+	assert.Empty(t, out, "Reset clears directory outputs")
+}
+
+func TestMockExecutorRunInDirUsesConfiguredError(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetDirOutput("/srv/repo", "git pull", "fatal: not a git repository\n")
+	mock.SetExitCode("git pull", 128)
+
+	out, err := mock.RunInDir(t.Context(), "/srv/repo", "git", "pull")
+
+	assert.Equal(t, "fatal: not a git repository\n", out)
+
+	var cmdErr *CommandError
+	require.ErrorAs(t, err, &cmdErr)
+	assert.Equal(t, 128, cmdErr.ExitCode)
+}
+
+func TestMockExecutorRunWithWriterWritesOutput(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("pveceph install", "Reading package lists...\nDone\n")
+
+	var stdout, stderr bytes.Buffer
+
+	require.NoError(t, mock.RunWithWriter(t.Context(), &stdout, &stderr, "pveceph", "install"))
+
+	assert.Equal(t, "Reading package lists...\nDone\n", stdout.String())
+	assert.Empty(t, stderr.String())
+	assert.True(t, mock.WasCalledWith("pveceph", "install"))
+}
+
+func TestMockExecutorRunWithWriterWritesStreams(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetStreams("pveceph install", "Done\n", "W: deprecated option\n")
+	mock.SetExitCode("pveceph install", 100)
+
+	var stdout, stderr bytes.Buffer
+
+	err := mock.RunWithWriter(t.Context(), &stdout, &stderr, "pveceph", "install")
+
+	var cmdErr *CommandError
+	require.ErrorAs(t, err, &cmdErr)
+	assert.Equal(t, 100, cmdErr.ExitCode)
+	assert.Equal(t, "Done\n", stdout.String(), "output is written even when the command fails")
+	assert.Equal(t, "W: deprecated option\n", stderr.String())
+}
+
+func TestMockExecutorRunWithWriterNilWriters(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetStreams("pveceph install", "Done\n", "W: deprecated option\n")
+
+	require.NoError(t, mock.RunWithWriter(t.Context(), nil, nil, "pveceph", "install"))
+	assert.Equal(t, 1, mock.CommandCount())
+}
+
+func TestMockExecutorRunWithWriterWriteError(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("pveceph install", "Done\n")
+
+	err := mock.RunWithWriter(t.Context(), errWriter{}, nil, "pveceph", "install")
+
+	require.ErrorIs(t, err, errWriteFailed)
+}
+
+// errWriteFailed is returned by errWriter.
+var errWriteFailed = errors.New("write failed")
+
+// errWriter is an io.Writer that always fails.
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) { return 0, errWriteFailed }
+
 func TestMockExecutorRunWithStdin(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -547,6 +812,63 @@ func TestMakeKey(t *testing.T) {
 	}
 }
 
+// makeKeyJoin is the previous makeKey implementation, kept as the reference
+// for output compatibility and benchmarks.
+func makeKeyJoin(name string, args ...string) string {
+	return ExecutedCommand{Name: name, Args: args}.String()
+}
+
+// makeKeyBenchArgs returns n distinct arguments for makeKey tests and benchmarks.
+func makeKeyBenchArgs(n int) []string {
+	args := make([]string, n)
+	for i := range args {
+		args[i] = "--arg" + strconv.Itoa(i)
+	}
+
+	return args
+}
+
+func TestMakeKeyMatchesJoin(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 5, 20} {
+		args := makeKeyBenchArgs(n)
+
+		assert.Equal(t, makeKeyJoin("zfs", args...), makeKey("zfs", args...), "%d args", n)
+	}
+
+	assert.Equal(t, makeKeyJoin("echo", "", "x", ""), makeKey("echo", "", "x", ""), "empty args")
+	assert.Equal(t, makeKeyJoin(""), makeKey(""), "empty name")
+}
+
+func TestMakeKeyNoArgsDoesNotAllocate(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = makeKey("ls")
+	})
+
+	assert.Zero(t, allocs)
+}
+
+func BenchmarkMakeKey(b *testing.B) {
+	for _, n := range []int{0, 1, 5, 20} {
+		args := makeKeyBenchArgs(n)
+
+		b.Run(strconv.Itoa(n)+"ArgsJoin", func(b *testing.B) {
+			b.ReportAllocs()
+
+			for range b.N {
+				_ = makeKeyJoin("zfs", args...)
+			}
+		})
+
+		b.Run(strconv.Itoa(n)+"Args", func(b *testing.B) {
+			b.ReportAllocs()
+
+			for range b.N {
+				_ = makeKey("zfs", args...)
+			}
+		})
+	}
+}
+
 func TestMockExecutorMultipleCommands(t *testing.T) {
 	mock := NewMockExecutor()
 	ctx := t.Context()
@@ -773,6 +1095,74 @@ func TestMockExecutorWasCalledWithPartialArgs(t *testing.T) {
 	assert.True(t, mock.WasCalledWith("docker", "run", "-d", testArgName, "test"))
 }
 
+func TestMockExecutorWasNotCalledWith(t *testing.T) {
+	mock := NewMockExecutor()
+	ctx := t.Context()
+
+	require.NoError(t, mock.Run(ctx, "cp", testSourceFile, testDestFile))
+
+	assert.False(t, mock.WasNotCalledWith("cp", testSourceFile, testDestFile))
+	assert.True(t, mock.WasNotCalledWith("cp", testDestFile, testSourceFile))
+	assert.True(t, mock.WasNotCalledWith("cp"))
+	assert.True(t, mock.WasNotCalledWith("mv", testSourceFile, testDestFile))
+}
+
+func TestMockExecutorWasNeverCalled(t *testing.T) {
+	tests := []struct {
+		name         string
+		run          [][]string // Each inner slice is [name, args...]
+		neverCalled  bool
+		notCalledRmR bool
+	}{
+		{"no commands executed", nil, true, true},
+		{"configured but never invoked", [][]string{{"ls", "-la"}}, true, true},
+		{"invoked with args", [][]string{{"ls"}, {"rm", "-rf", "/tmp/data"}}, false, false},
+		{"invoked without args", [][]string{{"rm"}}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := NewMockExecutor()
+			mock.SetError("rm -rf /tmp/data", errors.New(testPermissionDenied))
+
+			for _, cmd := range tt.run {
+				//nolint:errcheck // only the recorded history matters here
+				_ = mock.Run(t.Context(), cmd[0], cmd[1:]...)
+			}
+
+			assert.Equal(t, tt.neverCalled, mock.WasNeverCalled("rm"))
+			assert.Equal(t, tt.notCalledRmR, mock.WasNotCalledWith("rm", "-rf", "/tmp/data"))
+		})
+	}
+}
+
+func TestMockExecutorWasNeverCalledConcurrent(t *testing.T) {
+	mock := NewMockExecutor()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			//nolint:errcheck // concurrency test, result not checked
+			_ = mock.Run(t.Context(), "ls")
+		}()
+
+		go func() {
+			defer wg.Done()
+			mock.WasNeverCalled("rm")
+			mock.WasNotCalledWith("ls")
+		}()
+	}
+
+	wg.Wait()
+
+	assert.True(t, mock.WasNeverCalled("rm"))
+	assert.False(t, mock.WasNeverCalled("ls"))
+}
+
 func TestMockExecutorWasCalledWithDifferentOrder(t *testing.T) {
 	mock := NewMockExecutor()
 	ctx := t.Context()
@@ -1027,3 +1417,410 @@ func TestMockExecutorHelperMethodsThreadSafety(t *testing.T) {
 	assert.True(t, mock.WasCalledWith("echo", "hello"))
 	assert.NotNil(t, mock.LastCommand())
 }
+
+func TestMockExecutorSetDelay(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetDelay("sleep 1", 30*time.Millisecond)
+	mock.SetOutput("sleep 1", "done")
+
+	start := time.Now()
+	output, err := mock.RunWithOutput(t.Context(), "sleep", "1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "done", output)
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+}
+
+func TestMockExecutorSetDelayContextCanceled(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetDelay("sleep 60", time.Minute)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+
+	err := mock.Run(ctx, "sleep", "60")
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, mock.WasCalledWith("sleep", "60"))
+}
+
+func TestMockExecutorSetDelayDoesNotBlockOtherCalls(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetDelay("slow", 100*time.Millisecond)
+
+	go func() {
+		//nolint:errcheck // only timing matters here
+		_ = mock.Run(t.Context(), "slow")
+	}()
+
+	start := time.Now()
+	require.NoError(t, mock.Run(t.Context(), "fast"))
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestMockExecutorResetClearsDelays(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetDelay("sleep", time.Minute)
+
+	mock.Reset()
+
+	require.NoError(t, mock.Run(t.Context(), "sleep"))
+}
+
+func TestMockExecutorQueueDelayAppliesOnce(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.QueueDelay("sleep 60", time.Minute)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, mock.Run(ctx, "sleep", "60"), context.DeadlineExceeded)
+	require.NoError(t, mock.Run(t.Context(), "sleep", "60"), "the queued delay is consumed")
+}
+
+func TestMockExecutorQueueDelayBeforeSetDelay(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetDelay("sleep 1", time.Minute)
+	mock.QueueDelay("sleep 1", 0)
+
+	require.NoError(t, mock.Run(t.Context(), "sleep", "1"))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, mock.Run(ctx, "sleep", "1"), context.DeadlineExceeded, "SetDelay applies once the queue is empty")
+}
+
+func TestMockExecutorResetClearsQueuedDelays(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.QueueDelay("sleep", time.Minute)
+
+	mock.Reset()
+
+	require.NoError(t, mock.Run(t.Context(), "sleep"))
+}
+
+func TestMockExecutorQueueOutputConsumedInOrder(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("cat /state", "final")
+	mock.QueueOutput("cat /state", "first")
+	mock.QueueOutput("cat /state", "second")
+
+	var outputs []string
+
+	for i := 0; i < 4; i++ {
+		out, err := mock.RunWithOutput(t.Context(), "cat", "/state")
+		require.NoError(t, err)
+
+		outputs = append(outputs, out)
+	}
+
+	assert.Equal(t, []string{"first", "second", "final", "final"}, outputs)
+}
+
+func TestMockExecutorQueueErrorThenSuccess(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.QueueError("apt-get update", errors.New(testCommandNotFound))
+
+	require.Error(t, mock.Run(t.Context(), "apt-get", "update"))
+	require.NoError(t, mock.Run(t.Context(), "apt-get", "update"))
+	assert.Equal(t, 2, mock.CommandCount())
+}
+
+func TestMockExecutorResetClearsQueue(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.QueueError("ls", errors.New(testPermissionDenied))
+
+	mock.Reset()
+
+	require.NoError(t, mock.Run(t.Context(), "ls"))
+}
+
+func TestMockExecutorSetErrorAfter(t *testing.T) {
+	mock := NewMockExecutor()
+	errGone := errors.New("device vanished")
+	mock.SetOutput("lsblk /dev/sdb", "sdb")
+	mock.SetErrorAfter("lsblk /dev/sdb", 2, errGone)
+
+	for i := 1; i <= 2; i++ {
+		out, err := mock.RunWithOutput(t.Context(), "lsblk", "/dev/sdb")
+		require.NoError(t, err, "call %d should succeed", i)
+		assert.Equal(t, "sdb", out)
+		assert.Equal(t, i, mock.CommandCount())
+	}
+
+	for i := 3; i <= 4; i++ {
+		_, err := mock.RunWithOutput(t.Context(), "lsblk", "/dev/sdb")
+		require.ErrorIs(t, err, errGone, "call %d should fail", i)
+		assert.Equal(t, i, mock.CommandCount())
+	}
+}
+
+func TestMockExecutorSetErrorAfterZeroFailsImmediately(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetErrorAfter("zpool import", 0, errors.New(testPermissionDenied))
+
+	assert.Error(t, mock.Run(t.Context(), "zpool", "import"))
+}
+
+func TestMockExecutorSetErrorAfterOnlyMatchingCommand(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetErrorAfter("ls /a", 1, errors.New(testPermissionDenied))
+
+	require.NoError(t, mock.Run(t.Context(), "ls", "/a"))
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, mock.Run(t.Context(), "ls", "/b"))
+	}
+
+	assert.Error(t, mock.Run(t.Context(), "ls", "/a"))
+}
+
+func TestMockExecutorSetErrorAfterWithQueue(t *testing.T) {
+	mock := NewMockExecutor()
+	errGone := errors.New("device vanished")
+	mock.QueueOutput("cat /state", "queued")
+	mock.SetOutput("cat /state", "steady")
+	mock.SetErrorAfter("cat /state", 2, errGone)
+
+	// The queued response is consumed first and counts as the first call.
+	out, err := mock.RunWithOutput(t.Context(), "cat", "/state")
+	require.NoError(t, err)
+	assert.Equal(t, "queued", out)
+
+	out, err = mock.RunWithOutput(t.Context(), "cat", "/state")
+	require.NoError(t, err)
+	assert.Equal(t, "steady", out)
+
+	_, err = mock.RunWithOutput(t.Context(), "cat", "/state")
+	require.ErrorIs(t, err, errGone)
+	assert.Equal(t, 3, mock.CommandCount())
+}
+
+func TestMockExecutorResetClearsErrorAfter(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetErrorAfter("ls", 1, errors.New(testPermissionDenied))
+	require.NoError(t, mock.Run(t.Context(), "ls"))
+
+	mock.Reset()
+
+	require.NoError(t, mock.Run(t.Context(), "ls"))
+	require.NoError(t, mock.Run(t.Context(), "ls"))
+}
+
+// underTmp matches commands with an argument under /tmp.
+func underTmp(cmd ExecutedCommand) bool {
+	for _, arg := range cmd.Args {
+		if strings.HasPrefix(arg, "/tmp/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestMockExecutorSetOutputFunc(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutputFunc(underTmp, "scratch")
+
+	out, err := mock.RunWithOutput(t.Context(), "cat", "/tmp/a.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "scratch", out)
+
+	out, err = mock.RunWithOutput(t.Context(), "stat", "-c", "%s", "/tmp/b/c")
+	require.NoError(t, err)
+	assert.Equal(t, "scratch", out)
+
+	out, err = mock.RunWithOutput(t.Context(), "cat", "/etc/hosts")
+	require.NoError(t, err)
+	assert.Empty(t, out)
+}
+
+func TestMockExecutorSetErrorFunc(t *testing.T) {
+	mock := NewMockExecutor()
+	errDenied := errors.New(testPermissionDenied)
+	mock.SetErrorFunc(underTmp, errDenied)
+
+	require.ErrorIs(t, mock.Run(t.Context(), "rm", "-f", "/tmp/lock"), errDenied)
+	require.NoError(t, mock.Run(t.Context(), "rm", "-f", "/var/lock"))
+}
+
+func TestMockExecutorExactTakesPriorityOverFunc(t *testing.T) {
+	mock := NewMockExecutor()
+	errDenied := errors.New(testPermissionDenied)
+	mock.SetOutputFunc(underTmp, "scratch")
+	mock.SetErrorFunc(underTmp, errDenied)
+	mock.SetOutput("cat /tmp/exact", "exact")
+	mock.SetError("rm /tmp/exact", nil)
+
+	out, err := mock.RunWithOutput(t.Context(), "cat", "/tmp/exact")
+	assert.Equal(t, "exact", out)
+	require.ErrorIs(t, err, errDenied, "output and error resolve independently")
+
+	require.NoError(t, mock.Run(t.Context(), "rm", "/tmp/exact"))
+}
+
+func TestMockExecutorQueueTakesPriorityOverFunc(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutputFunc(underTmp, "scratch")
+	mock.QueueOutput("cat /tmp/a", "queued")
+
+	out, err := mock.RunWithOutput(t.Context(), "cat", "/tmp/a")
+	require.NoError(t, err)
+	assert.Equal(t, "queued", out)
+
+	out, err = mock.RunWithOutput(t.Context(), "cat", "/tmp/a")
+	require.NoError(t, err)
+	assert.Equal(t, "scratch", out)
+}
+
+func TestMockExecutorFuncFirstMatchWins(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutputFunc(func(cmd ExecutedCommand) bool { return cmd.Name == "cat" }, "first")
+	mock.SetOutputFunc(underTmp, "second")
+
+	out, err := mock.RunWithOutput(t.Context(), "cat", "/tmp/a")
+	require.NoError(t, err)
+	assert.Equal(t, "first", out)
+}
+
+func TestMockExecutorFuncSeesStdinAndCannotModifyArgs(t *testing.T) {
+	mock := NewMockExecutor()
+	errDenied := errors.New(testPermissionDenied)
+	mock.SetErrorFunc(func(cmd ExecutedCommand) bool {
+		matched := cmd.Stdin == testInputData
+		cmd.Args[0] = "modified"
+
+		return matched
+	}, errDenied)
+
+	require.ErrorIs(t, mock.RunWithStdin(t.Context(), testInputData, "tee", "/etc/motd"), errDenied)
+	assert.Equal(t, []string{"/etc/motd"}, mock.LastCommand().Args)
+}
+
+func TestMockExecutorResetClearsFuncs(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetErrorFunc(underTmp, errors.New(testPermissionDenied))
+
+	mock.Reset()
+
+	require.NoError(t, mock.Run(t.Context(), "rm", "/tmp/a"))
+}
+
+func TestMockExecutorRunToFile(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("dmesg", "[    0.000000] Linux version 6.8.12-pve\n")
+	path := filepath.Join(t.TempDir(), "dmesg.log")
+
+	require.NoError(t, mock.RunToFile(context.Background(), path, "dmesg"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "[    0.000000] Linux version 6.8.12-pve\n", string(data))
+	assert.True(t, mock.WasCalledWith("dmesg"))
+	assert.Equal(t, path, mock.Commands()[0].OutputFile)
+}
+
+func TestMockExecutorRunToFileWritesOutputWithError(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("dmesg", "partial")
+	mock.SetError("dmesg", errors.New("exit status 1"))
+	path := filepath.Join(t.TempDir(), "dmesg.log")
+
+	require.EqualError(t, mock.RunToFile(context.Background(), path, "dmesg"), "exit status 1")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "partial", string(data))
+}
+
+func TestMockExecutorRunToFileBadPath(t *testing.T) {
+	mock := NewMockExecutor()
+	path := filepath.Join(t.TempDir(), "missing", "dmesg.log")
+
+	err := mock.RunToFile(context.Background(), path, "dmesg")
+
+	require.ErrorIs(t, err, os.ErrNotExist)
+	assert.Zero(t, mock.CommandCount(), "command must not be recorded when the file cannot be opened")
+}
+
+// fakeClock returns a time one second later on every call, starting at start.
+func fakeClock(start time.Time) func() time.Time {
+	var calls int64
+
+	return func() time.Time {
+		calls++
+
+		return start.Add(time.Duration(calls) * time.Second)
+	}
+}
+
+func TestMockExecutorClockTimestampsCommands(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock := NewMockExecutor()
+	mock.SetClock(fakeClock(start))
+	ctx := context.Background()
+
+	require.NoError(t, mock.Run(ctx, "zpool", "create", "rpool"))
+	_, err := mock.RunWithOutput(ctx, "zpool", "status")
+	require.NoError(t, err)
+	require.NoError(t, mock.RunWithStdin(ctx, "root:secret", "chpasswd")) // NOSONAR(go:S2068) test data
+
+	commands := mock.Commands()
+	require.Len(t, commands, 3)
+
+	for i, cmd := range commands {
+		assert.Equal(t, start.Add(time.Duration(i+1)*time.Second), cmd.StartedAt, "command %d", i)
+	}
+
+	assert.Equal(t, commands[2].StartedAt, mock.LastCommand().StartedAt)
+}
+
+func TestMockExecutorClockConcurrentCallsAreMonotonic(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetClock(fakeClock(time.Unix(0, 0)))
+
+	var wg sync.WaitGroup
+
+	for i := range 20 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_ = mock.Run(context.Background(), "worker", strconv.Itoa(i))
+		}()
+	}
+
+	wg.Wait()
+
+	commands := mock.Commands()
+	require.Len(t, commands, 20)
+
+	for i := 1; i < len(commands); i++ {
+		assert.True(t, commands[i].StartedAt.After(commands[i-1].StartedAt),
+			"command %d must start after command %d", i, i-1)
+	}
+}
+
+func TestMockExecutorClockDefaultsToNow(t *testing.T) {
+	mock := NewMockExecutor()
+	before := time.Now()
+
+	require.NoError(t, mock.Run(context.Background(), "true"))
+
+	startedAt := mock.LastCommand().StartedAt
+	assert.False(t, startedAt.Before(before))
+	assert.False(t, startedAt.After(time.Now()))
+}
+
+func TestMockExecutorResetRestoresDefaultClock(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetClock(func() time.Time { return time.Unix(0, 0) })
+	mock.Reset()
+	before := time.Now()
+
+	require.NoError(t, mock.Run(context.Background(), "true"))
+
+	assert.False(t, mock.LastCommand().StartedAt.Before(before))
+}
@@ -3,8 +3,12 @@
 package exec
 
 import (
+	"bytes"
 	"context"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -64,6 +68,44 @@ func TestRealExecutorRunWithOutputFailure(t *testing.T) {
 	assert.NotEmpty(t, output)
 }
 
+func TestRealExecutorRunWithCombinedOutput(t *testing.T) {
+	executor := NewRealExecutor()
+
+	output, err := executor.RunWithCombinedOutput(t.Context(), "sh", "-c", "echo out1; echo err1 >&2; echo out2")
+	require.NoError(t, err)
+	assert.Equal(t, "out1\nerr1\nout2\n", output, "streams are interleaved in write order")
+}
+
+func TestRealExecutorRunWithCombinedOutputFailure(t *testing.T) {
+	executor := NewRealExecutor()
+
+	output, err := executor.RunWithCombinedOutput(t.Context(), "sh", "-c", "echo partition table busy >&2; exit 3")
+	require.Error(t, err)
+	assert.Equal(t, "partition table busy\n", output, "stderr is returned with the error")
+}
+
+func TestRealExecutorRunWithStreams(t *testing.T) {
+	executor := NewRealExecutor()
+
+	stdout, stderr, err := executor.RunWithStreams(t.Context(), "sh", "-c", "printf hi; printf oops >&2")
+	require.NoError(t, err)
+	assert.Equal(t, "hi", stdout)
+	assert.Equal(t, "oops", stderr)
+}
+
+func TestRealExecutorRunWithStreamsFailure(t *testing.T) {
+	executor := NewRealExecutor()
+
+	stdout, stderr, err := executor.RunWithStreams(t.Context(), "sh", "-c", "printf hi; printf oops >&2; exit 3")
+	require.Error(t, err)
+	assert.Equal(t, "hi", stdout)
+	assert.Equal(t, "oops", stderr)
+
+	var exitErr interface{ ExitCode() int }
+	require.ErrorAs(t, err, &exitErr, "the exit error can be inspected")
+	assert.Equal(t, 3, exitErr.ExitCode())
+}
+
 func TestRealExecutorRunWithStdin(t *testing.T) {
 	executor := NewRealExecutor()
 
@@ -88,6 +130,164 @@ func TestRealExecutorRunWithStdinGrepNoMatch(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestRealExecutorRunWithEnvPassesVariables(t *testing.T) {
+	executor := NewRealExecutor()
+	outPath := filepath.Join(t.TempDir(), "foo.txt")
+
+	err := executor.RunWithEnv(t.Context(), []string{"FOO=bar", "OUT=" + outPath}, "sh", "-c", `echo "$FOO" > "$OUT"`)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(outPath) //nolint:gosec // test temp file
+	require.NoError(t, err)
+	assert.Equal(t, "bar\n", string(data))
+}
+
+func TestRealExecutorRunWithEnvAppendsToEnvironment(t *testing.T) {
+	t.Setenv("PVE_TEST_INHERITED", "kept")
+	t.Setenv("FOO", "inherited")
+
+	executor := NewRealExecutor()
+
+	err := executor.RunWithEnv(t.Context(), []string{"FOO=bar"},
+		"sh", "-c", `test "$PVE_TEST_INHERITED" = kept && test "$FOO" = bar`)
+	assert.NoError(t, err, "inherited variables are kept and env overrides them")
+}
+
+func TestRealExecutorRunWithEnvFailure(t *testing.T) {
+	executor := NewRealExecutor()
+
+	err := executor.RunWithEnv(t.Context(), []string{"FOO=bar"}, "sh", "-c", `test "$FOO" = baz`)
+
+	var cmdErr *CommandError
+	require.ErrorAs(t, err, &cmdErr)
+	assert.Equal(t, 1, cmdErr.ExitCode)
+}
+
+func TestRealExecutorRunInDir(t *testing.T) {
+	executor := NewRealExecutor()
+	dir := t.TempDir()
+
+	out, err := executor.RunInDir(t.Context(), dir, "pwd")
+	require.NoError(t, err)
+
+	want, err := filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+
+	got, err := filepath.EvalSymlinks(strings.TrimSpace(out))
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestRealExecutorRunInDirEmptyUsesCurrentDir(t *testing.T) {
+	executor := NewRealExecutor()
+
+	out, err := executor.RunInDir(t.Context(), "", "pwd")
+	require.NoError(t, err)
+
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, cwd, strings.TrimSpace(out))
+}
+
+func TestRealExecutorRunInDirMissingDir(t *testing.T) {
+	executor := NewRealExecutor()
+	dir := filepath.Join(t.TempDir(), "missing")
+
+	out, err := executor.RunInDir(t.Context(), dir, "pwd")
+
+	require.ErrorIs(t, err, os.ErrNotExist)
+	assert.ErrorContains(t, err, "invalid working directory")
+	assert.ErrorContains(t, err, dir)
+	assert.Empty(t, out)
+}
+
+func TestRealExecutorRunInDirNotDirectory(t *testing.T) {
+	executor := NewRealExecutor()
+	file := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(file, nil, 0o600))
+
+	_, err := executor.RunInDir(t.Context(), file, "pwd")
+
+	require.ErrorIs(t, err, ErrNotDirectory)
+	assert.ErrorContains(t, err, file)
+}
+
+func TestRealExecutorRunInDirFailureReturnsOutput(t *testing.T) {
+	executor := NewRealExecutor()
+
+	out, err := executor.RunInDir(t.Context(), t.TempDir(), "sh", "-c", "echo partial; echo oops >&2; exit 2")
+
+	assert.Equal(t, "partial\noops\n", out)
+
+	var cmdErr *CommandError
+	require.ErrorAs(t, err, &cmdErr)
+	assert.Equal(t, 2, cmdErr.ExitCode)
+}
+
+// timedWriter records what is written to it and when the first write happened.
+type timedWriter struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	first time.Time
+}
+
+func (w *timedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.first.IsZero() {
+		w.first = time.Now()
+	}
+
+	return w.buf.Write(p)
+}
+
+func TestRealExecutorRunWithWriterStreamsLines(t *testing.T) {
+	executor := NewRealExecutor()
+
+	var stdout, stderr bytes.Buffer
+
+	err := executor.RunWithWriter(t.Context(), &stdout, &stderr,
+		"sh", "-c", "echo line1; echo oops >&2; echo line2; echo line3")
+
+	require.NoError(t, err)
+	assert.Equal(t, "line1\nline2\nline3\n", stdout.String())
+	assert.Equal(t, "oops\n", stderr.String())
+}
+
+func TestRealExecutorRunWithWriterIsLive(t *testing.T) {
+	executor := NewRealExecutor()
+	writer := &timedWriter{}
+
+	err := executor.RunWithWriter(t.Context(), writer, nil, "sh", "-c", "echo started; sleep 0.3; echo done")
+	finished := time.Now()
+
+	require.NoError(t, err)
+	assert.Equal(t, "started\ndone\n", writer.buf.String())
+	assert.Greater(t, finished.Sub(writer.first), 200*time.Millisecond,
+		"the first line arrives while the command is still running")
+}
+
+func TestRealExecutorRunWithWriterSameWriter(t *testing.T) {
+	executor := NewRealExecutor()
+
+	var out bytes.Buffer
+
+	err := executor.RunWithWriter(t.Context(), &out, &out, "sh", "-c", "echo out; echo err >&2; exit 3")
+
+	assert.Equal(t, "out\nerr\n", out.String())
+
+	var cmdErr *CommandError
+	require.ErrorAs(t, err, &cmdErr)
+	assert.Equal(t, 3, cmdErr.ExitCode)
+}
+
+func TestRealExecutorRunWithWriterNilWriters(t *testing.T) {
+	executor := NewRealExecutor()
+
+	assert.NoError(t, executor.RunWithWriter(t.Context(), nil, nil, "sh", "-c", "echo discarded; echo discarded >&2"))
+}
+
 func TestRealExecutorTimeout(t *testing.T) {
 	// Create executor with 100ms timeout
 	executor := NewRealExecutorWithTimeout(100 * time.Millisecond)
@@ -166,3 +366,60 @@ func TestRealExecutorApplyTimeoutWithNonZeroTimeout(t *testing.T) {
 	assert.True(t, ok)
 	assert.False(t, deadline.IsZero())
 }
+
+func TestFormatCommandRoundTripsThroughShell(t *testing.T) {
+	args := []string{"plain", "with space", "it's", "", "$HOME", "a\"b", "tab\there", "*"}
+
+	// printf prints each argument terminated by NUL, so the shell's argv can be
+	// compared exactly with the original slice.
+	script := FormatCommand("printf", append([]string{`%s\0`}, args...)...)
+
+	output, err := NewRealExecutor().RunWithOutput(t.Context(), "sh", "-c", script)
+	require.NoError(t, err)
+
+	got := strings.Split(strings.TrimSuffix(output, "\x00"), "\x00")
+	assert.Equal(t, args, got)
+}
+
+func TestRunLinesRealExecutor(t *testing.T) {
+	lines, err := RunLines(t.Context(), NewRealExecutor(), "printf", "one\n\n two \nthree\n")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one", "two", "three"}, lines)
+}
+
+func TestRealExecutorRunToFile(t *testing.T) {
+	executor := NewRealExecutor()
+	path := filepath.Join(t.TempDir(), "out.txt")
+	require.NoError(t, os.WriteFile(path, []byte("stale content that is longer"), 0o600))
+
+	require.NoError(t, executor.RunToFile(t.Context(), path, "echo", "hello world"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world\n", string(data), "existing file is truncated")
+}
+
+func TestRealExecutorRunToFileDiscardsStderr(t *testing.T) {
+	executor := NewRealExecutor()
+	path := filepath.Join(t.TempDir(), "out.txt")
+
+	err := executor.RunToFile(t.Context(), path, "ls", "/nonexistent-path-12345")
+	require.Error(t, err)
+
+	data, readErr := os.ReadFile(path)
+	require.NoError(t, readErr)
+	assert.Empty(t, data)
+}
+
+func TestRealExecutorRunToFileBadPath(t *testing.T) {
+	executor := NewRealExecutor()
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	path := filepath.Join(dir, "missing", "out.txt")
+
+	err := executor.RunToFile(t.Context(), path, "touch", marker)
+
+	require.ErrorIs(t, err, os.ErrNotExist)
+	assert.NoFileExists(t, marker, "command must not run when the file cannot be opened")
+}
@@ -0,0 +1,121 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Backoff between attempts of RunWithRetryTimeout. The delay starts at
+// retryBaseDelay and doubles after each failed attempt, up to retryMaxDelay.
+// They are variables so tests can shorten them.
+var (
+	retryBaseDelay = time.Second
+	retryMaxDelay  = 30 * time.Second
+)
+
+// RunWithRetryTimeout runs a command through executor up to attempts times
+// until it succeeds, giving each attempt at most perAttemptTimeout, as suits
+// downloads that may stall or fail transiently.
+//
+// An attempt that exceeds its timeout counts as a failure and is retried.
+// Attempts are separated by an exponential backoff starting at one second
+// and capped at 30 seconds. A zero or negative perAttemptTimeout means
+// attempts are only bounded by ctx; attempts below 1 are treated as 1.
+//
+// Retrying stops as soon as ctx is done, returning an error that wraps
+// ctx.Err(). After the last failed attempt the returned error wraps the
+// error of that attempt.
+func RunWithRetryTimeout(
+	ctx context.Context,
+	executor Executor,
+	attempts int,
+	perAttemptTimeout time.Duration,
+	name string,
+	args ...string,
+) error {
+	policy := retryPolicy{
+		name:      name,
+		attempts:  attempts,
+		delay:     retryBaseDelay,
+		next:      func(delay time.Duration) time.Duration { return min(delay*2, retryMaxDelay) },
+		wrapFinal: true,
+	}
+
+	_, err := retryLoop(ctx, policy, func() (struct{}, error) {
+		return struct{}{}, runAttempt(ctx, executor, perAttemptTimeout, name, args)
+	}, nil)
+
+	return err
+}
+
+// retryPolicy configures retryLoop.
+type retryPolicy struct {
+	// name is the command name used in errors.
+	name string
+	// attempts is the maximum number of attempts; values below 1 mean 1.
+	attempts int
+	// delay is the wait before the second attempt.
+	delay time.Duration
+	// next returns the wait after the given one; nil keeps it constant.
+	next func(time.Duration) time.Duration
+	// wrapFinal wraps the error of the last attempt in a "failed after"
+	// error instead of returning it unchanged.
+	wrapFinal bool
+}
+
+// retryLoop runs call until it succeeds, at most policy.attempts times,
+// waiting the policy's backoff between attempts.
+//
+// A failure for which retryable reports false is returned at once; a nil
+// retryable retries every failure. Once ctx is done after a failed attempt,
+// the returned error wraps ctx.Err(). Otherwise the result and error of the
+// last attempt are returned.
+func retryLoop[T any](ctx context.Context, policy retryPolicy, call func() (T, error), retryable func(T, error) bool) (T, error) {
+	delay := policy.delay
+
+	for attempt := 1; ; attempt++ {
+		result, err := call()
+		if err == nil {
+			return result, nil
+		}
+
+		if ctx.Err() != nil {
+			return result, fmt.Errorf("%s canceled after %d attempt(s): %w", policy.name, attempt, ctx.Err())
+		}
+
+		if attempt >= policy.attempts || (retryable != nil && !retryable(result, err)) {
+			if policy.wrapFinal {
+				err = fmt.Errorf("%s failed after %d attempt(s): %w", policy.name, attempt, err)
+			}
+
+			return result, err
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return result, fmt.Errorf("%s canceled after %d attempt(s): %w", policy.name, attempt, ctx.Err())
+		case <-timer.C:
+		}
+
+		if policy.next != nil {
+			delay = policy.next(delay)
+		}
+	}
+}
+
+// runAttempt runs a single attempt of RunWithRetryTimeout with its timeout applied.
+func runAttempt(ctx context.Context, executor Executor, timeout time.Duration, name string, args []string) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return executor.Run(ctx, name, args...)
+}
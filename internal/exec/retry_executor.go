@@ -0,0 +1,186 @@
+package exec
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// RetryExecutor wraps another Executor and retries commands that failed
+// transiently.
+//
+// Each call runs the command up to the configured number of attempts until it
+// succeeds or fails in a way its RetryPredicate does not consider transient,
+// waiting the backoff between attempts. The backoff is constant unless
+// exponential backoff is enabled with SetExponential, in which case it
+// doubles after each failed attempt up to a maximum delay.
+//
+// The predicate defaults to DefaultRetryable, so only known transient
+// failures such as a held apt lock or a network timeout are retried: repeating
+// a command that failed for good, or one that is not idempotent (e.g., useradd
+// after a partial success), would only hide the real error. The predicate
+// receives the output of the methods that return it (stdout and stderr for
+// RunWithStreams); for the others it has only the error, whose message
+// carries the stderr of a *CommandError.
+//
+// Retrying stops as soon as the caller's context is done, returning an error
+// that wraps ctx.Err(). After the last failed attempt the error of that
+// attempt is returned unchanged, so callers can still inspect it with
+// errors.Is and errors.As.
+type RetryExecutor struct {
+	inner     Executor
+	attempts  int
+	backoff   time.Duration
+	maxDelay  time.Duration
+	retryable RetryPredicate
+}
+
+// Compile-time assertion that RetryExecutor implements Executor.
+var _ Executor = (*RetryExecutor)(nil)
+
+// NewRetryExecutor creates a RetryExecutor running commands through inner at
+// most attempts times, waiting backoff between attempts. Attempts below 1 are
+// treated as 1; a zero or negative backoff retries immediately.
+func NewRetryExecutor(inner Executor, attempts int, backoff time.Duration) *RetryExecutor {
+	return &RetryExecutor{
+		inner:     inner,
+		attempts:  max(attempts, 1),
+		backoff:   max(backoff, 0),
+		retryable: DefaultRetryable,
+	}
+}
+
+// SetRetryable sets the predicate deciding which failures are retried, e.g.
+// one built with NewRetryPredicate for extra signatures. A nil predicate
+// restores DefaultRetryable.
+func (e *RetryExecutor) SetRetryable(retryable RetryPredicate) {
+	if retryable == nil {
+		retryable = DefaultRetryable
+	}
+
+	e.retryable = retryable
+}
+
+// SetExponential makes the backoff double after each failed attempt, capped
+// at maxDelay. A zero or negative maxDelay restores the constant backoff.
+func (e *RetryExecutor) SetExponential(maxDelay time.Duration) {
+	e.maxDelay = max(maxDelay, 0)
+}
+
+// Attempts returns the maximum number of attempts per command.
+func (e *RetryExecutor) Attempts() int {
+	return e.attempts
+}
+
+// nextDelay returns the backoff to wait after the given delay.
+func (e *RetryExecutor) nextDelay(delay time.Duration) time.Duration {
+	if e.maxDelay <= 0 {
+		return delay
+	}
+
+	return min(delay*2, e.maxDelay)
+}
+
+// retryCall runs call through e's retry loop for the command named name.
+// output returns the command output of a result for the RetryPredicate.
+func retryCall[T any](ctx context.Context, e *RetryExecutor, name string, call func() (T, error), output func(T) string) (T, error) {
+	policy := retryPolicy{
+		name:     name,
+		attempts: e.attempts,
+		delay:    e.backoff,
+		next:     e.nextDelay,
+	}
+
+	return retryLoop(ctx, policy, call, func(result T, err error) bool {
+		return e.retryable(err, output(result))
+	})
+}
+
+// sameOutput is the output function of retryCall for methods returning the output itself.
+func sameOutput(output string) string { return output }
+
+// retry runs call through e's retry loop for a method without output.
+func (e *RetryExecutor) retry(ctx context.Context, name string, call func() error) error {
+	_, err := retryCall(ctx, e, name, func() (struct{}, error) {
+		return struct{}{}, call()
+	}, func(struct{}) string { return "" })
+
+	return err
+}
+
+// Run executes a command through the inner Executor, retrying on failure.
+func (e *RetryExecutor) Run(ctx context.Context, name string, args ...string) error {
+	return e.retry(ctx, name, func() error {
+		return e.inner.Run(ctx, name, args...)
+	})
+}
+
+// RunWithOutput executes a command through the inner Executor, retrying on
+// failure, and returns the stdout of the last attempt.
+func (e *RetryExecutor) RunWithOutput(ctx context.Context, name string, args ...string) (string, error) {
+	return retryCall(ctx, e, name, func() (string, error) {
+		return e.inner.RunWithOutput(ctx, name, args...)
+	}, sameOutput)
+}
+
+// RunWithCombinedOutput executes a command through the inner Executor,
+// retrying on failure, and returns the combined output of the last attempt.
+func (e *RetryExecutor) RunWithCombinedOutput(ctx context.Context, name string, args ...string) (string, error) {
+	return retryCall(ctx, e, name, func() (string, error) {
+		return e.inner.RunWithCombinedOutput(ctx, name, args...)
+	}, sameOutput)
+}
+
+// RunWithStreams executes a command through the inner Executor, retrying on
+// failure, and returns the stdout and stderr of the last attempt.
+func (e *RetryExecutor) RunWithStreams(ctx context.Context, name string, args ...string) (stdout, stderr string, err error) {
+	streams, err := retryCall(ctx, e, name, func() ([2]string, error) {
+		stdout, stderr, err := e.inner.RunWithStreams(ctx, name, args...)
+
+		return [2]string{stdout, stderr}, err
+	}, func(streams [2]string) string { return streams[0] + streams[1] })
+
+	return streams[0], streams[1], err
+}
+
+// RunWithStdin executes a command with stdin input through the inner
+// Executor, retrying on failure. Each attempt receives the full stdin.
+func (e *RetryExecutor) RunWithStdin(ctx context.Context, stdin, name string, args ...string) error {
+	return e.retry(ctx, name, func() error {
+		return e.inner.RunWithStdin(ctx, stdin, name, args...)
+	})
+}
+
+// RunWithEnv executes a command with extra environment variables through the
+// inner Executor, retrying on failure.
+func (e *RetryExecutor) RunWithEnv(ctx context.Context, env []string, name string, args ...string) error {
+	return e.retry(ctx, name, func() error {
+		return e.inner.RunWithEnv(ctx, env, name, args...)
+	})
+}
+
+// RunInDir executes a command in dir through the inner Executor, retrying on
+// failure, and returns the combined output of the last attempt.
+func (e *RetryExecutor) RunInDir(ctx context.Context, dir, name string, args ...string) (string, error) {
+	return retryCall(ctx, e, name, func() (string, error) {
+		return e.inner.RunInDir(ctx, dir, name, args...)
+	}, sameOutput)
+}
+
+// RunWithWriter executes a command through the inner Executor, retrying on
+// failure. Every attempt streams to stdout and stderr, so the writers also
+// receive the output of failed attempts.
+func (e *RetryExecutor) RunWithWriter(ctx context.Context, stdout, stderr io.Writer, name string, args ...string) error {
+	return e.retry(ctx, name, func() error {
+		return e.inner.RunWithWriter(ctx, stdout, stderr, name, args...)
+	})
+}
+
+// RunToFile executes a command through the inner Executor with its stdout
+// written to the file at path, retrying on failure. Each attempt truncates
+// the file, so it never holds output from an earlier attempt.
+func (e *RetryExecutor) RunToFile(ctx context.Context, path, name string, args ...string) error {
+	return e.retry(ctx, name, func() error {
+		return e.inner.RunToFile(ctx, path, name, args...)
+	})
+}
@@ -0,0 +1,272 @@
+package exec
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errAptLock is the transient failure the RetryExecutor tests simulate.
+var errAptLock = errors.New("E: Could not get lock /var/lib/dpkg/lock-frontend")
+
+func TestNewRetryExecutorClampsAttempts(t *testing.T) {
+	assert.Equal(t, 1, NewRetryExecutor(NewMockExecutor(), 0, 0).Attempts())
+	assert.Equal(t, 1, NewRetryExecutor(NewMockExecutor(), -3, 0).Attempts())
+	assert.Equal(t, 3, NewRetryExecutor(NewMockExecutor(), 3, 0).Attempts())
+}
+
+func TestRetryExecutorFailTwiceThenSucceed(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.QueueError("apt-get update", errAptLock)
+	mock.QueueError("apt-get update", errAptLock)
+
+	executor := NewRetryExecutor(mock, 3, time.Millisecond)
+
+	require.NoError(t, executor.Run(context.Background(), "apt-get", "update"))
+	assert.Equal(t, 3, mock.CommandCount())
+
+	for _, cmd := range mock.Commands() {
+		assert.Equal(t, "apt-get", cmd.Name)
+		assert.Equal(t, []string{"update"}, cmd.Args)
+	}
+}
+
+func TestRetryExecutorFirstAttemptSucceeds(t *testing.T) {
+	mock := NewMockExecutor()
+	executor := NewRetryExecutor(mock, 3, time.Hour)
+
+	require.NoError(t, executor.Run(context.Background(), "apt-get", "update"))
+	assert.Equal(t, 1, mock.CommandCount())
+}
+
+func TestRetryExecutorReturnsLastError(t *testing.T) {
+	errFirst := errors.New("Temporary failure in name resolution")
+
+	mock := NewMockExecutor()
+	mock.QueueError("apt-get update", errFirst)
+	mock.SetError("apt-get update", errAptLock)
+
+	executor := NewRetryExecutor(mock, 3, 0)
+	err := executor.Run(context.Background(), "apt-get", "update")
+
+	require.ErrorIs(t, err, errAptLock)
+	require.NotErrorIs(t, err, errFirst)
+	assert.Equal(t, 3, mock.CommandCount())
+}
+
+func TestRetryExecutorPreservesCommandError(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetExitCode("zpool import rpool", 1)
+
+	executor := NewRetryExecutor(mock, 2, 0)
+	executor.SetRetryable(func(error, string) bool { return true })
+	err := executor.Run(context.Background(), "zpool", "import", "rpool")
+
+	var cmdErr *CommandError
+	require.ErrorAs(t, err, &cmdErr)
+	assert.Equal(t, 1, cmdErr.ExitCode)
+	assert.Equal(t, 2, mock.CommandCount())
+}
+
+func TestRetryExecutorDoesNotRetryPermanentFailure(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetError("useradd -m admin", errors.New("useradd: user 'admin' already exists"))
+
+	executor := NewRetryExecutor(mock, 3, time.Hour)
+	err := executor.Run(context.Background(), "useradd", "-m", "admin")
+
+	require.ErrorContains(t, err, "already exists")
+	assert.Equal(t, 1, mock.CommandCount(), "a failure that is not transient is returned at once")
+}
+
+func TestRetryExecutorPredicateSeesOutput(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("apt-get update", "E: Could not get lock /var/lib/apt/lists/lock")
+	mock.SetExitCode("apt-get update", 100)
+
+	var outputs []string
+
+	executor := NewRetryExecutor(mock, 2, 0)
+	executor.SetRetryable(func(err error, output string) bool {
+		outputs = append(outputs, output)
+
+		return DefaultRetryable(err, output)
+	})
+
+	_, err := executor.RunWithOutput(context.Background(), "apt-get", "update")
+
+	require.Error(t, err)
+	assert.Equal(t, 2, mock.CommandCount(), "the lock message in the output is transient")
+	assert.Equal(t, []string{"E: Could not get lock /var/lib/apt/lists/lock"}, outputs)
+}
+
+func TestRetryExecutorSetRetryableNilRestoresDefault(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetError("zpool import rpool", errors.New("no such pool"))
+
+	executor := NewRetryExecutor(mock, 3, 0)
+	executor.SetRetryable(func(error, string) bool { return true })
+	executor.SetRetryable(nil)
+
+	require.Error(t, executor.Run(context.Background(), "zpool", "import", "rpool"))
+	assert.Equal(t, 1, mock.CommandCount())
+}
+
+func TestRetryExecutorCanceledContextStopsRetrying(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetError("apt-get update", errAptLock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	executor := NewRetryExecutor(mock, 5, time.Hour)
+	err := executor.Run(ctx, "apt-get", "update")
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, "apt-get canceled after 1 attempt(s)")
+	assert.Equal(t, 1, mock.CommandCount())
+}
+
+func TestRetryExecutorCancelDuringBackoff(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetError("apt-get update", errAptLock)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	executor := NewRetryExecutor(mock, 5, time.Hour)
+
+	start := time.Now()
+	err := executor.Run(ctx, "apt-get", "update")
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, mock.CommandCount())
+	assert.Less(t, time.Since(start), 10*time.Second, "the backoff is cut off by the context")
+}
+
+func TestRetryExecutorExponentialBackoff(t *testing.T) {
+	executor := NewRetryExecutor(NewMockExecutor(), 5, time.Second)
+
+	assert.Equal(t, time.Second, executor.nextDelay(time.Second), "constant by default")
+
+	executor.SetExponential(3 * time.Second)
+
+	assert.Equal(t, 2*time.Second, executor.nextDelay(time.Second))
+	assert.Equal(t, 3*time.Second, executor.nextDelay(2*time.Second), "capped at the maximum delay")
+}
+
+func TestRetryExecutorOutputMethods(t *testing.T) {
+	const (
+		curlCmd  = "curl -fsSL https://example.com/key.gpg"
+		curlName = "curl"
+	)
+
+	curlArgs := []string{"-fsSL", "https://example.com/key.gpg"}
+
+	t.Run("RunWithOutput", func(t *testing.T) {
+		mock := NewMockExecutor()
+		mock.QueueError(curlCmd, errAptLock)
+		mock.SetOutput(curlCmd, "key")
+
+		output, err := NewRetryExecutor(mock, 2, 0).RunWithOutput(context.Background(), curlName, curlArgs...)
+
+		require.NoError(t, err)
+		assert.Equal(t, "key", output)
+		assert.Equal(t, 2, mock.CommandCount())
+	})
+
+	t.Run("RunWithCombinedOutput", func(t *testing.T) {
+		mock := NewMockExecutor()
+		mock.QueueError(curlCmd, errAptLock)
+		mock.SetOutput(curlCmd, "key")
+
+		output, err := NewRetryExecutor(mock, 2, 0).RunWithCombinedOutput(context.Background(), curlName, curlArgs...)
+
+		require.NoError(t, err)
+		assert.Equal(t, "key", output)
+		assert.Equal(t, 2, mock.CommandCount())
+	})
+
+	t.Run("RunWithStreams", func(t *testing.T) {
+		mock := NewMockExecutor()
+		mock.QueueError(curlCmd, errAptLock)
+		mock.SetStreams(curlCmd, "key", "warning")
+
+		stdout, stderr, err := NewRetryExecutor(mock, 2, 0).RunWithStreams(context.Background(), curlName, curlArgs...)
+
+		require.NoError(t, err)
+		assert.Equal(t, "key", stdout)
+		assert.Equal(t, "warning", stderr)
+		assert.Equal(t, 2, mock.CommandCount())
+	})
+
+	t.Run("RunWithStdin", func(t *testing.T) {
+		mock := NewMockExecutor()
+		mock.QueueError("tee /etc/hosts", errAptLock)
+
+		err := NewRetryExecutor(mock, 2, 0).RunWithStdin(context.Background(), "127.0.0.1 localhost", "tee", "/etc/hosts")
+
+		require.NoError(t, err)
+		require.Equal(t, 2, mock.CommandCount())
+
+		for _, cmd := range mock.Commands() {
+			assert.Equal(t, "127.0.0.1 localhost", cmd.Stdin, "every attempt receives the full stdin")
+		}
+	})
+
+	t.Run("RunWithEnv", func(t *testing.T) {
+		mock := NewMockExecutor()
+		mock.QueueError("apt-get update", errAptLock)
+
+		err := NewRetryExecutor(mock, 2, 0).RunWithEnv(context.Background(), []string{"DEBIAN_FRONTEND=noninteractive"}, "apt-get", "update")
+
+		require.NoError(t, err)
+		require.Equal(t, 2, mock.CommandCount())
+
+		for _, cmd := range mock.Commands() {
+			assert.Equal(t, []string{"DEBIAN_FRONTEND=noninteractive"}, cmd.Env)
+		}
+	})
+
+	t.Run("RunInDir", func(t *testing.T) {
+		mock := NewMockExecutor()
+		mock.QueueError(curlCmd, errAptLock)
+		mock.SetDirOutput("/srv", curlCmd, "key")
+
+		output, err := NewRetryExecutor(mock, 2, 0).RunInDir(context.Background(), "/srv", curlName, curlArgs...)
+
+		require.NoError(t, err)
+		assert.Equal(t, "key", output)
+		assert.Equal(t, 2, mock.CommandCount())
+	})
+
+	t.Run("RunWithWriter", func(t *testing.T) {
+		mock := NewMockExecutor()
+		mock.QueueError(curlCmd, errAptLock)
+		mock.SetOutput(curlCmd, "key")
+
+		var stdout strings.Builder
+
+		err := NewRetryExecutor(mock, 2, 0).RunWithWriter(context.Background(), &stdout, nil, curlName, curlArgs...)
+
+		require.NoError(t, err)
+		assert.Equal(t, "key", stdout.String())
+		assert.Equal(t, 2, mock.CommandCount())
+	})
+
+	t.Run("RunToFile", func(t *testing.T) {
+		mock := NewMockExecutor()
+		mock.QueueError(curlCmd, errAptLock)
+
+		err := NewRetryExecutor(mock, 2, 0).RunToFile(context.Background(), filepath.Join(t.TempDir(), "key.gpg"), curlName, curlArgs...)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, mock.CommandCount())
+	})
+}
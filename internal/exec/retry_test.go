@@ -0,0 +1,121 @@
+package exec
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRetryCommand is the command the retry tests run.
+const testRetryCommand = "curl -fsSO https://example.com/pve.iso"
+
+// shortenRetryBackoff makes RunWithRetryTimeout wait only briefly between attempts.
+func shortenRetryBackoff(t *testing.T) {
+	t.Helper()
+
+	base, maxDelay := retryBaseDelay, retryMaxDelay
+	retryBaseDelay, retryMaxDelay = time.Millisecond, 4*time.Millisecond
+
+	t.Cleanup(func() {
+		retryBaseDelay, retryMaxDelay = base, maxDelay
+	})
+}
+
+// runTestRetry runs testRetryCommand with RunWithRetryTimeout.
+func runTestRetry(ctx context.Context, mock *MockExecutor, attempts int, timeout time.Duration) error {
+	return RunWithRetryTimeout(ctx, mock, attempts, timeout, "curl", "-fsSO", "https://example.com/pve.iso")
+}
+
+func TestRunWithRetryTimeoutFirstAttemptSucceeds(t *testing.T) {
+	shortenRetryBackoff(t)
+
+	mock := NewMockExecutor()
+
+	require.NoError(t, runTestRetry(context.Background(), mock, 3, time.Second))
+	assert.Equal(t, 1, mock.CommandCount())
+}
+
+func TestRunWithRetryTimeoutRetriesTimedOutAttempt(t *testing.T) {
+	shortenRetryBackoff(t)
+
+	mock := NewMockExecutor()
+	mock.QueueDelay(testRetryCommand, time.Minute)
+
+	start := time.Now()
+	err := runTestRetry(context.Background(), mock, 3, 20*time.Millisecond)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, mock.CommandCount(), "the timed-out attempt is retried")
+	assert.Less(t, time.Since(start), 10*time.Second, "the slow attempt is cut off by its timeout")
+}
+
+func TestRunWithRetryTimeoutDelayedFailThenSucceed(t *testing.T) {
+	shortenRetryBackoff(t)
+
+	// Attempt 1 stalls past its timeout, attempt 2 fails, attempt 3 succeeds.
+	mock := NewMockExecutor()
+	mock.QueueDelay(testRetryCommand, time.Minute)
+	mock.QueueError(testRetryCommand, nil)
+	mock.QueueError(testRetryCommand, errors.New("curl: (56) Recv failure"))
+
+	require.NoError(t, runTestRetry(context.Background(), mock, 3, 20*time.Millisecond))
+	assert.Equal(t, 3, mock.CommandCount())
+}
+
+func TestRunWithRetryTimeoutExhaustsAttempts(t *testing.T) {
+	shortenRetryBackoff(t)
+
+	errCurl := errors.New("curl: (6) Could not resolve host")
+	mock := NewMockExecutor()
+	mock.SetError(testRetryCommand, errCurl)
+
+	err := runTestRetry(context.Background(), mock, 3, time.Second)
+
+	require.ErrorIs(t, err, errCurl)
+	assert.ErrorContains(t, err, "failed after 3 attempt(s)")
+	assert.Equal(t, 3, mock.CommandCount())
+}
+
+func TestRunWithRetryTimeoutEveryAttemptTimesOut(t *testing.T) {
+	shortenRetryBackoff(t)
+
+	mock := NewMockExecutor()
+	mock.SetDelay(testRetryCommand, time.Minute)
+
+	err := runTestRetry(context.Background(), mock, 2, 10*time.Millisecond)
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 2, mock.CommandCount())
+}
+
+func TestRunWithRetryTimeoutAtLeastOneAttempt(t *testing.T) {
+	shortenRetryBackoff(t)
+
+	mock := NewMockExecutor()
+	mock.SetError(testRetryCommand, errors.New("exit status 22"))
+
+	require.Error(t, runTestRetry(context.Background(), mock, 0, 0))
+	assert.Equal(t, 1, mock.CommandCount())
+}
+
+func TestRunWithRetryTimeoutStopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The first attempt fails and cancels the context, as a user interrupt would.
+	mock := NewMockExecutor()
+	mock.SetErrorFunc(func(ExecutedCommand) bool {
+		cancel()
+
+		return true
+	}, errors.New("exit status 22"))
+
+	err := runTestRetry(ctx, mock, 5, time.Second)
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, mock.CommandCount(), "no retry after the context is canceled")
+}
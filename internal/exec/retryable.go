@@ -0,0 +1,98 @@
+package exec
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// RetryPredicate reports whether a failed command is worth retrying, given
+// its error and its output (which may be empty if it was not captured).
+type RetryPredicate func(err error, output string) bool
+
+// transientSignatures are output fragments of failures that usually go away
+// on their own: another apt/dpkg process holding the lock, resolver hiccups
+// and network timeouts while downloading.
+var transientSignatures = []string{
+	"Could not get lock",
+	"Unable to acquire the dpkg frontend lock",
+	"Unable to lock directory",
+	"Temporary failure in name resolution",
+	"Temporary failure resolving",
+	"Could not resolve host",
+	"Connection timed out",
+	"Operation timed out",
+	"Connection reset by peer",
+	"Connection refused",
+	"Network is unreachable",
+	"503 Service Unavailable",
+	"504 Gateway Timeout",
+	"Hash Sum mismatch",
+}
+
+// TransientSignatures returns a copy of the output fragments DefaultRetryable
+// treats as transient. Append to it and pass the result to NewRetryPredicate
+// to extend the defaults.
+func TransientSignatures() []string {
+	return slices.Clone(transientSignatures)
+}
+
+// NewRetryPredicate returns a RetryPredicate that retries when the output or
+// the error message contains one of signatures, compared case-insensitively.
+// The signatures are copied, so later changes by the caller have no effect.
+//
+// Independently of signatures, a nil error and a canceled context are never
+// retried, and a deadline exceeded (e.g., a per-attempt timeout) or an error
+// reporting Timeout() true always is.
+func NewRetryPredicate(signatures ...string) RetryPredicate {
+	lowered := make([]string, 0, len(signatures))
+
+	for _, signature := range signatures {
+		if signature != "" {
+			lowered = append(lowered, strings.ToLower(signature))
+		}
+	}
+
+	return func(err error, output string) bool {
+		if err == nil || errors.Is(err, context.Canceled) {
+			return false
+		}
+
+		if isTimeout(err) {
+			return true
+		}
+
+		output = strings.ToLower(output)
+		message := strings.ToLower(err.Error())
+
+		for _, signature := range lowered {
+			if strings.Contains(output, signature) || strings.Contains(message, signature) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// defaultRetryable is the predicate behind DefaultRetryable.
+var defaultRetryable = NewRetryPredicate(transientSignatures...)
+
+// DefaultRetryable reports whether a command that failed with err and printed
+// output hit a known transient failure (see TransientSignatures) or timed out.
+func DefaultRetryable(err error, output string) bool {
+	return defaultRetryable(err, output)
+}
+
+// isTimeout reports whether err is a deadline exceeded or, like net.Error,
+// reports itself as a timeout.
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var timeoutErr interface{ Timeout() bool }
+
+	return errors.As(err, &timeoutErr) && timeoutErr.Timeout()
+}
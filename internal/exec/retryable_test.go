@@ -0,0 +1,136 @@
+package exec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// errExitStatus100 is the error apt-get returns for most failures.
+var errExitStatus100 = errors.New("exit status 100")
+
+// timeoutError reports itself as a timeout, like a net.Error.
+type timeoutError struct{}
+
+func (timeoutError) Error() string { return "i/o timeout" }
+func (timeoutError) Timeout() bool { return true }
+
+func TestDefaultRetryable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		output   string
+		expected bool
+	}{
+		{
+			name:     "apt lock held",
+			err:      errExitStatus100,
+			output:   "E: Could not get lock /var/lib/dpkg/lock-frontend. It is held by process 1234 (apt-get)",
+			expected: true,
+		},
+		{
+			name:     "dpkg frontend lock",
+			err:      errExitStatus100,
+			output:   "E: Unable to acquire the dpkg frontend lock (/var/lib/dpkg/lock-frontend), is another process using it?",
+			expected: true,
+		},
+		{
+			name:     "dns failure",
+			err:      errors.New("exit status 6"),
+			output:   "curl: (6) Could not resolve host: download.proxmox.com",
+			expected: true,
+		},
+		{
+			name:     "resolver temporary failure in apt output",
+			err:      errExitStatus100,
+			output:   "W: Failed to fetch http://deb.debian.org/debian/dists/bookworm/InRelease  Temporary failure resolving 'deb.debian.org'",
+			expected: true,
+		},
+		{
+			name:     "signature in error message",
+			err:      errors.New("ssh: Temporary failure in name resolution"),
+			expected: true,
+		},
+		{
+			name:     "case-insensitive match",
+			err:      errors.New("exit status 28"),
+			output:   "curl: (28) connection timed out after 10001 milliseconds",
+			expected: true,
+		},
+		{
+			name:     "per-attempt timeout",
+			err:      fmt.Errorf("attempt 1: %w", context.DeadlineExceeded),
+			expected: true,
+		},
+		{
+			name:     "timeout error",
+			err:      timeoutError{},
+			expected: true,
+		},
+		{
+			name:     "missing package is terminal",
+			err:      errExitStatus100,
+			output:   "E: Unable to locate package proxmox-ve-missing",
+			expected: false,
+		},
+		{
+			name:     "permission denied is terminal",
+			err:      errors.New("exit status 1"),
+			output:   "tee: /etc/hosts: Permission denied",
+			expected: false,
+		},
+		{
+			name:     "canceled context is terminal",
+			err:      fmt.Errorf("apt-get: %w", context.Canceled),
+			output:   "E: Could not get lock /var/lib/dpkg/lock-frontend",
+			expected: false,
+		},
+		{
+			name:     "success is not retried",
+			err:      nil,
+			output:   "Could not get lock",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DefaultRetryable(tt.err, tt.output))
+		})
+	}
+}
+
+func TestNewRetryPredicateCustomSignatures(t *testing.T) {
+	signatures := append(TransientSignatures(), "zpool busy")
+	retryable := NewRetryPredicate(signatures...)
+
+	assert.True(t, retryable(errors.New("exit status 1"), "cannot import 'rpool': zpool busy"))
+	assert.True(t, retryable(errExitStatus100, "E: Could not get lock /var/lib/dpkg/lock"))
+	assert.False(t, DefaultRetryable(errors.New("exit status 1"), "cannot import 'rpool': zpool busy"))
+}
+
+func TestNewRetryPredicateOnlyGivenSignatures(t *testing.T) {
+	retryable := NewRetryPredicate("zpool busy", "")
+
+	assert.False(t, retryable(errExitStatus100, "E: Could not get lock /var/lib/dpkg/lock"))
+	assert.False(t, retryable(errExitStatus100, "any output"), "empty signature must not match everything")
+	assert.True(t, retryable(context.DeadlineExceeded, ""), "timeouts are retried regardless of signatures")
+}
+
+func TestNewRetryPredicateCopiesSignatures(t *testing.T) {
+	signatures := []string{"zpool busy"}
+	retryable := NewRetryPredicate(signatures...)
+	signatures[0] = "something else"
+
+	assert.True(t, retryable(errors.New("exit status 1"), "zpool busy"))
+}
+
+func TestTransientSignaturesReturnsCopy(t *testing.T) {
+	signatures := TransientSignatures()
+	signatures[0] = "modified"
+
+	assert.NotEqual(t, "modified", TransientSignatures()[0])
+}
@@ -0,0 +1,121 @@
+package exec
+
+import (
+	"context"
+	"io"
+	"os"
+)
+
+// SudoExecutor wraps another Executor and runs every command through sudo
+// when the process is not running as root, so the installer can be developed
+// and tested from an unprivileged account.
+//
+// A call such as Run(ctx, "zpool", "status") is executed by the inner
+// Executor as "sudo -n zpool status". The -n flag makes sudo fail instead of
+// prompting for a password, which keeps sudo away from stdin: RunWithStdin
+// passes stdin through to the command unchanged. When the process is root,
+// commands are passed to the inner Executor as is.
+type SudoExecutor struct {
+	inner  Executor
+	isRoot func() bool
+}
+
+// Compile-time assertion that SudoExecutor implements Executor.
+var _ Executor = (*SudoExecutor)(nil)
+
+// NewSudoExecutor creates a SudoExecutor running commands through inner.
+// The effective user ID decides whether sudo is needed.
+func NewSudoExecutor(inner Executor) *SudoExecutor {
+	return &SudoExecutor{
+		inner:  inner,
+		isRoot: func() bool { return os.Geteuid() == 0 },
+	}
+}
+
+// command returns the command and arguments that run name with args,
+// prefixed with sudo unless the process is root.
+func (e *SudoExecutor) command(name string, args []string) (string, []string) {
+	if e.isRoot() {
+		return name, args
+	}
+
+	return "sudo", append([]string{"-n", name}, args...)
+}
+
+// Run executes a command through the inner Executor, using sudo if needed.
+func (e *SudoExecutor) Run(ctx context.Context, name string, args ...string) error {
+	name, args = e.command(name, args)
+
+	return e.inner.Run(ctx, name, args...)
+}
+
+// RunWithOutput executes a command through the inner Executor, using sudo if needed.
+func (e *SudoExecutor) RunWithOutput(ctx context.Context, name string, args ...string) (string, error) {
+	name, args = e.command(name, args)
+
+	return e.inner.RunWithOutput(ctx, name, args...)
+}
+
+// RunWithCombinedOutput executes a command through the inner Executor, using sudo if needed.
+func (e *SudoExecutor) RunWithCombinedOutput(ctx context.Context, name string, args ...string) (string, error) {
+	name, args = e.command(name, args)
+
+	return e.inner.RunWithCombinedOutput(ctx, name, args...)
+}
+
+// RunWithStreams executes a command through the inner Executor, using sudo if needed.
+func (e *SudoExecutor) RunWithStreams(ctx context.Context, name string, args ...string) (stdout, stderr string, err error) {
+	name, args = e.command(name, args)
+
+	return e.inner.RunWithStreams(ctx, name, args...)
+}
+
+// RunWithStdin executes a command through the inner Executor, using sudo if
+// needed and passing stdin through unchanged.
+func (e *SudoExecutor) RunWithStdin(ctx context.Context, stdin, name string, args ...string) error {
+	name, args = e.command(name, args)
+
+	return e.inner.RunWithStdin(ctx, stdin, name, args...)
+}
+
+// RunWithEnv executes a command with extra environment variables through the
+// inner Executor, using sudo if needed. Because sudo resets the environment,
+// env is then passed on the command line through env(1), as in
+// "sudo -n env DEBIAN_FRONTEND=noninteractive apt-get update".
+func (e *SudoExecutor) RunWithEnv(ctx context.Context, env []string, name string, args ...string) error {
+	if e.isRoot() || len(env) == 0 {
+		name, args = e.command(name, args)
+
+		return e.inner.RunWithEnv(ctx, env, name, args...)
+	}
+
+	sudoArgs := append([]string{"-n", "env"}, env...)
+	sudoArgs = append(sudoArgs, name)
+
+	return e.inner.Run(ctx, "sudo", append(sudoArgs, args...)...)
+}
+
+// RunInDir executes a command in dir through the inner Executor, using sudo
+// if needed. sudo keeps the working directory, so dir applies either way.
+func (e *SudoExecutor) RunInDir(ctx context.Context, dir, name string, args ...string) (string, error) {
+	name, args = e.command(name, args)
+
+	return e.inner.RunInDir(ctx, dir, name, args...)
+}
+
+// RunWithWriter executes a command through the inner Executor, using sudo if
+// needed, streaming its output to stdout and stderr.
+func (e *SudoExecutor) RunWithWriter(ctx context.Context, stdout, stderr io.Writer, name string, args ...string) error {
+	name, args = e.command(name, args)
+
+	return e.inner.RunWithWriter(ctx, stdout, stderr, name, args...)
+}
+
+// RunToFile executes a command through the inner Executor, using sudo if
+// needed. Only the command is elevated: the file is opened by the current
+// user, so path must be writable without sudo.
+func (e *SudoExecutor) RunToFile(ctx context.Context, path, name string, args ...string) error {
+	name, args = e.command(name, args)
+
+	return e.inner.RunToFile(ctx, path, name, args...)
+}
@@ -0,0 +1,164 @@
+package exec
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSudoExecutor returns a SudoExecutor over mock with a fixed root check.
+func newTestSudoExecutor(mock *MockExecutor, root bool) *SudoExecutor {
+	executor := NewSudoExecutor(mock)
+	executor.isRoot = func() bool { return root }
+
+	return executor
+}
+
+func TestSudoExecutorRunAsUser(t *testing.T) {
+	mock := NewMockExecutor()
+	executor := newTestSudoExecutor(mock, false)
+
+	require.NoError(t, executor.Run(context.Background(), "zpool", "status", "rpool"))
+
+	assert.Equal(t, "sudo -n zpool status rpool", mock.LastCommand().String())
+}
+
+func TestSudoExecutorRunAsRoot(t *testing.T) {
+	mock := NewMockExecutor()
+	executor := newTestSudoExecutor(mock, true)
+
+	require.NoError(t, executor.Run(context.Background(), "zpool", "status", "rpool"))
+
+	last := mock.LastCommand()
+	assert.Equal(t, "zpool", last.Name)
+	assert.Equal(t, []string{"status", "rpool"}, last.Args)
+}
+
+func TestSudoExecutorRunWithOutput(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("sudo -n cat /etc/hostname", "pve\n")
+	executor := newTestSudoExecutor(mock, false)
+
+	out, err := executor.RunWithOutput(context.Background(), "cat", "/etc/hostname")
+
+	require.NoError(t, err)
+	assert.Equal(t, "pve\n", out)
+}
+
+func TestSudoExecutorRunWithCombinedOutput(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("sudo -n zpool status rpool", "pool: rpool\n")
+	executor := newTestSudoExecutor(mock, false)
+
+	out, err := executor.RunWithCombinedOutput(context.Background(), "zpool", "status", "rpool")
+
+	require.NoError(t, err)
+	assert.Equal(t, "pool: rpool\n", out)
+}
+
+func TestSudoExecutorRunWithStreams(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetStreams("sudo -n zpool import rpool", "", "no such pool")
+	executor := newTestSudoExecutor(mock, false)
+
+	stdout, stderr, err := executor.RunWithStreams(context.Background(), "zpool", "import", "rpool")
+
+	require.NoError(t, err)
+	assert.Empty(t, stdout)
+	assert.Equal(t, "no such pool", stderr)
+}
+
+func TestSudoExecutorRunWithStdin(t *testing.T) {
+	tests := []struct {
+		name     string
+		root     bool
+		wantName string
+		wantArgs []string
+	}{
+		{"as user", false, "sudo", []string{"-n", "chpasswd", "-e"}},
+		{"as root", true, "chpasswd", []string{"-e"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := NewMockExecutor()
+			executor := newTestSudoExecutor(mock, tt.root)
+
+			require.NoError(t, executor.RunWithStdin(context.Background(), "root:secret", "chpasswd", "-e")) // NOSONAR(go:S2068) test data
+
+			last := mock.LastCommand()
+			require.NotNil(t, last)
+			assert.Equal(t, tt.wantName, last.Name)
+			assert.Equal(t, tt.wantArgs, last.Args)
+			assert.Equal(t, "root:secret", last.Stdin) // NOSONAR(go:S2068) test data
+		})
+	}
+}
+
+func TestSudoExecutorRunToFile(t *testing.T) {
+	mock := NewMockExecutor()
+	executor := newTestSudoExecutor(mock, false)
+	path := filepath.Join(t.TempDir(), "dmesg.log")
+
+	require.NoError(t, executor.RunToFile(context.Background(), path, "dmesg"))
+
+	assert.Equal(t, "sudo -n dmesg", mock.LastCommand().String())
+	assert.FileExists(t, path)
+}
+
+func TestSudoExecutorRunWithEnv(t *testing.T) {
+	env := []string{"DEBIAN_FRONTEND=noninteractive"}
+
+	tests := []struct {
+		name    string
+		root    bool
+		env     []string
+		wantCmd string
+		wantEnv []string
+	}{
+		{"as user", false, env, "sudo -n env DEBIAN_FRONTEND=noninteractive apt-get update", nil},
+		{"as user without env", false, nil, "sudo -n apt-get update", nil},
+		{"as root", true, env, "apt-get update", env},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := NewMockExecutor()
+			executor := newTestSudoExecutor(mock, tt.root)
+
+			require.NoError(t, executor.RunWithEnv(context.Background(), tt.env, "apt-get", "update"))
+
+			last := mock.LastCommand()
+			require.NotNil(t, last)
+			assert.Equal(t, tt.wantCmd, last.String())
+			assert.Equal(t, tt.wantEnv, last.Env)
+		})
+	}
+}
+
+func TestSudoExecutorRunInDir(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetDirOutput("/srv/repo", "sudo -n git pull", "Already up to date.\n")
+	executor := newTestSudoExecutor(mock, false)
+
+	out, err := executor.RunInDir(context.Background(), "/srv/repo", "git", "pull")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Already up to date.\n", out)
+	assert.Equal(t, "/srv/repo", mock.LastCommand().Dir)
+}
+
+func TestSudoExecutorRunWithWriter(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("sudo -n pveceph install", "Done\n")
+	executor := newTestSudoExecutor(mock, false)
+
+	var stdout strings.Builder
+
+	require.NoError(t, executor.RunWithWriter(context.Background(), &stdout, nil, "pveceph", "install"))
+	assert.Equal(t, "Done\n", stdout.String())
+}
@@ -0,0 +1,130 @@
+package exec
+
+import (
+	"context"
+	"io"
+	"maps"
+	"time"
+)
+
+// TimeoutExecutor wraps another Executor and applies a timeout per command name.
+//
+// Each call derives a context from the caller's context using the timeout
+// configured for the command name (e.g., "apt-get") or, if none is configured,
+// the default timeout. A zero or negative timeout means no timeout is added,
+// so only the caller's deadline applies. The caller's deadline still applies
+// when it is shorter than the configured timeout.
+type TimeoutExecutor struct {
+	inner          Executor
+	defaultTimeout time.Duration
+	perCommand     map[string]time.Duration
+}
+
+// Compile-time assertion that TimeoutExecutor implements Executor.
+var _ Executor = (*TimeoutExecutor)(nil)
+
+// NewTimeoutExecutor creates a TimeoutExecutor running commands through inner.
+// The perCommand map is keyed by command name and is copied, so later changes
+// to it by the caller have no effect.
+func NewTimeoutExecutor(inner Executor, defaultTimeout time.Duration, perCommand map[string]time.Duration) *TimeoutExecutor {
+	return &TimeoutExecutor{
+		inner:          inner,
+		defaultTimeout: defaultTimeout,
+		perCommand:     maps.Clone(perCommand),
+	}
+}
+
+// Timeout returns the timeout applied to commands named name.
+func (e *TimeoutExecutor) Timeout(name string) time.Duration {
+	if timeout, ok := e.perCommand[name]; ok {
+		return timeout
+	}
+
+	return e.defaultTimeout
+}
+
+// applyTimeout derives a context with the timeout for name, if any.
+func (e *TimeoutExecutor) applyTimeout(ctx context.Context, name string) (context.Context, context.CancelFunc) {
+	if timeout := e.Timeout(name); timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+
+	return ctx, func() {
+		// no-op cancel: safe to call even when no timeout is configured
+	}
+}
+
+// Run executes a command through the inner Executor with its timeout applied.
+func (e *TimeoutExecutor) Run(ctx context.Context, name string, args ...string) error {
+	ctx, cancel := e.applyTimeout(ctx, name)
+	defer cancel()
+
+	return e.inner.Run(ctx, name, args...)
+}
+
+// RunWithOutput executes a command through the inner Executor with its timeout applied.
+func (e *TimeoutExecutor) RunWithOutput(ctx context.Context, name string, args ...string) (string, error) {
+	ctx, cancel := e.applyTimeout(ctx, name)
+	defer cancel()
+
+	return e.inner.RunWithOutput(ctx, name, args...)
+}
+
+// RunWithCombinedOutput executes a command through the inner Executor with its timeout applied.
+func (e *TimeoutExecutor) RunWithCombinedOutput(ctx context.Context, name string, args ...string) (string, error) {
+	ctx, cancel := e.applyTimeout(ctx, name)
+	defer cancel()
+
+	return e.inner.RunWithCombinedOutput(ctx, name, args...)
+}
+
+// RunWithStreams executes a command through the inner Executor with its timeout applied.
+func (e *TimeoutExecutor) RunWithStreams(ctx context.Context, name string, args ...string) (stdout, stderr string, err error) {
+	ctx, cancel := e.applyTimeout(ctx, name)
+	defer cancel()
+
+	return e.inner.RunWithStreams(ctx, name, args...)
+}
+
+// RunWithStdin executes a command through the inner Executor with its timeout applied.
+func (e *TimeoutExecutor) RunWithStdin(ctx context.Context, stdin, name string, args ...string) error {
+	ctx, cancel := e.applyTimeout(ctx, name)
+	defer cancel()
+
+	return e.inner.RunWithStdin(ctx, stdin, name, args...)
+}
+
+// RunWithEnv executes a command with extra environment variables through the
+// inner Executor with its timeout applied.
+func (e *TimeoutExecutor) RunWithEnv(ctx context.Context, env []string, name string, args ...string) error {
+	ctx, cancel := e.applyTimeout(ctx, name)
+	defer cancel()
+
+	return e.inner.RunWithEnv(ctx, env, name, args...)
+}
+
+// RunInDir executes a command in dir through the inner Executor with its
+// timeout applied.
+func (e *TimeoutExecutor) RunInDir(ctx context.Context, dir, name string, args ...string) (string, error) {
+	ctx, cancel := e.applyTimeout(ctx, name)
+	defer cancel()
+
+	return e.inner.RunInDir(ctx, dir, name, args...)
+}
+
+// RunWithWriter executes a command through the inner Executor with its
+// timeout applied, streaming its output to stdout and stderr.
+func (e *TimeoutExecutor) RunWithWriter(ctx context.Context, stdout, stderr io.Writer, name string, args ...string) error {
+	ctx, cancel := e.applyTimeout(ctx, name)
+	defer cancel()
+
+	return e.inner.RunWithWriter(ctx, stdout, stderr, name, args...)
+}
+
+// RunToFile executes a command through the inner Executor with its timeout applied.
+func (e *TimeoutExecutor) RunToFile(ctx context.Context, path, name string, args ...string) error {
+	ctx, cancel := e.applyTimeout(ctx, name)
+	defer cancel()
+
+	return e.inner.RunToFile(ctx, path, name, args...)
+}
@@ -0,0 +1,131 @@
+package exec
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeoutExecutorPerCommandTimeoutFires(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetDelay("apt-get update", time.Second)
+	mock.SetOutput("apt-get update", "done")
+
+	executor := NewTimeoutExecutor(mock, time.Minute, map[string]time.Duration{
+		"apt-get": 10 * time.Millisecond,
+	})
+
+	start := time.Now()
+	_, err := executor.RunWithOutput(context.Background(), "apt-get", "update")
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestTimeoutExecutorRunWithEnv(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetDelay("apt-get update", time.Second)
+
+	executor := NewTimeoutExecutor(mock, time.Minute, map[string]time.Duration{
+		"apt-get": 10 * time.Millisecond,
+	})
+
+	err := executor.RunWithEnv(context.Background(), []string{"DEBIAN_FRONTEND=noninteractive"}, "apt-get", "update")
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []string{"DEBIAN_FRONTEND=noninteractive"}, mock.LastCommand().Env)
+}
+
+func TestTimeoutExecutorRunInDir(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetDelay("git pull", time.Second)
+
+	executor := NewTimeoutExecutor(mock, time.Minute, map[string]time.Duration{
+		"git": 10 * time.Millisecond,
+	})
+
+	_, err := executor.RunInDir(context.Background(), "/srv/repo", "git", "pull")
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, "/srv/repo", mock.LastCommand().Dir)
+}
+
+func TestTimeoutExecutorRunWithWriter(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetDelay("pveceph install", time.Second)
+
+	executor := NewTimeoutExecutor(mock, time.Minute, map[string]time.Duration{
+		"pveceph": 10 * time.Millisecond,
+	})
+
+	err := executor.RunWithWriter(context.Background(), io.Discard, io.Discard, "pveceph", "install")
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestTimeoutExecutorOtherCommandsUseDefault(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetDelay("lsblk", 30*time.Millisecond)
+	mock.SetOutput("lsblk", "sda")
+
+	executor := NewTimeoutExecutor(mock, time.Minute, map[string]time.Duration{
+		"apt-get": 10 * time.Millisecond,
+	})
+
+	out, err := executor.RunWithOutput(context.Background(), "lsblk")
+
+	require.NoError(t, err)
+	assert.Equal(t, "sda", out)
+	assert.Equal(t, time.Minute, executor.Timeout("lsblk"))
+	assert.Equal(t, 10*time.Millisecond, executor.Timeout("apt-get"))
+}
+
+func TestTimeoutExecutorDefaultTimeoutFires(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetDelay("sleep 10", time.Second)
+
+	executor := NewTimeoutExecutor(mock, 10*time.Millisecond, nil)
+
+	err := executor.Run(context.Background(), "sleep", "10")
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestTimeoutExecutorZeroTimeoutDisablesLimit(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetDelay("zpool create", 30*time.Millisecond)
+
+	executor := NewTimeoutExecutor(mock, 10*time.Millisecond, map[string]time.Duration{
+		"zpool": 0,
+	})
+
+	assert.NoError(t, executor.Run(context.Background(), "zpool", "create"))
+}
+
+func TestTimeoutExecutorCancellation(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetDelay("tee /etc/hosts", time.Second)
+
+	executor := NewTimeoutExecutor(mock, time.Minute, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := executor.RunWithStdin(ctx, testInputData, "tee", "/etc/hosts")
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, testInputData, mock.LastCommand().Stdin)
+}
+
+func TestTimeoutExecutorCopiesPerCommandMap(t *testing.T) {
+	perCommand := map[string]time.Duration{"apt-get": time.Second}
+	executor := NewTimeoutExecutor(NewMockExecutor(), time.Minute, perCommand)
+
+	perCommand["apt-get"] = time.Hour
+
+	assert.Equal(t, time.Second, executor.Timeout("apt-get"))
+}
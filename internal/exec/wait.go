@@ -0,0 +1,37 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// defaultPollInterval is used by WaitForFile when a non-positive interval is given.
+const defaultPollInterval = 500 * time.Millisecond
+
+// WaitForFile polls for a regular file at path until it exists or ctx expires.
+//
+// Existence is checked by running "test -f <path>" through the Executor, so
+// the check happens wherever the executor runs commands (e.g., inside a chroot).
+// Any error from test is treated as "not there yet". If ctx is canceled or its
+// deadline passes first, the returned error wraps ctx.Err().
+func WaitForFile(ctx context.Context, executor Executor, path string, interval time.Duration) error {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := executor.Run(ctx, "test", "-f", path); err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for file %s: %w", path, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
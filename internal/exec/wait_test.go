@@ -0,0 +1,65 @@
+package exec
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testReadyFile    = "/var/lib/pve-cluster/.ready"
+	testReadyFileCmd = "test -f " + testReadyFile
+)
+
+// errTestExitStatus1 simulates test(1) reporting a missing file.
+var errTestExitStatus1 = errors.New("exit status 1")
+
+func TestWaitForFileAppearsOnThirdPoll(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.QueueError(testReadyFileCmd, errTestExitStatus1)
+	mock.QueueError(testReadyFileCmd, errTestExitStatus1)
+
+	err := WaitForFile(t.Context(), mock, testReadyFile, time.Millisecond)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, mock.CommandCount())
+	assert.True(t, mock.WasCalledWith("test", "-f", testReadyFile))
+}
+
+func TestWaitForFileAlreadyExists(t *testing.T) {
+	mock := NewMockExecutor()
+
+	require.NoError(t, WaitForFile(t.Context(), mock, testReadyFile, time.Hour))
+	assert.Equal(t, 1, mock.CommandCount())
+}
+
+func TestWaitForFileTimeout(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetError(testReadyFileCmd, errTestExitStatus1)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+
+	err := WaitForFile(ctx, mock, testReadyFile, 2*time.Millisecond)
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), testReadyFile)
+	assert.Greater(t, mock.CommandCount(), 1)
+}
+
+func TestWaitForFileDefaultInterval(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetError(testReadyFileCmd, errTestExitStatus1)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	err := WaitForFile(ctx, mock, testReadyFile, 0)
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, mock.CommandCount(), "default interval should not poll again within 50ms")
+}
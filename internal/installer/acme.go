@@ -0,0 +1,79 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// Let's Encrypt ACME directory URLs.
+const (
+	acmeDirectoryProduction = "https://acme-v02.api.letsencrypt.org/directory"
+	acmeDirectoryStaging    = "https://acme-staging-v02.api.letsencrypt.org/directory"
+)
+
+// acmeAccountName is the pvenode ACME account the step registers and uses.
+const acmeAccountName = "default"
+
+// acmeTOSAnswer accepts the terms of service prompt from
+// "pvenode acme account register".
+const acmeTOSAnswer = "y\n"
+
+// ACMEStep registers a Let's Encrypt account and orders a certificate for
+// the node's FQDN using the built-in standalone HTTP plugin. Registration
+// generates the account key, so the step warns first if entropy is low.
+//
+// The step is a no-op when ACME.Enabled is false.
+type ACMEStep struct {
+	config   *config.Config
+	executor exec.Executor
+	logger   *Logger
+}
+
+// NewACMEStep creates an ACMEStep for the given configuration.
+func NewACMEStep(cfg *config.Config, executor exec.Executor, logger *Logger) *ACMEStep {
+	return &ACMEStep{config: cfg, executor: executor, logger: logger}
+}
+
+// Name returns the step name.
+func (s *ACMEStep) Name() string { return "Configure ACME certificate" }
+
+// Execute registers the ACME account, sets the node domain and orders the certificate.
+func (s *ACMEStep) Execute(ctx context.Context) error {
+	if !s.config.ACME.Enabled {
+		s.logger.Log("ACME disabled, skipping")
+
+		return nil
+	}
+
+	directory := acmeDirectoryProduction
+	if s.config.ACME.Staging {
+		directory = acmeDirectoryStaging
+	}
+
+	WarnIfLowEntropy(ctx, s.executor, s.logger, MinEntropyBits)
+
+	s.logger.Log("Registering ACME account %s", s.config.ACMEEmail())
+
+	if err := s.executor.RunWithStdin(ctx, acmeTOSAnswer,
+		"pvenode", "acme", "account", "register", acmeAccountName, s.config.ACMEEmail(),
+		"--directory", directory); err != nil {
+		return fmt.Errorf("failed to register ACME account: %w", err)
+	}
+
+	fqdn := s.config.FQDN()
+
+	if err := s.executor.Run(ctx, "pvenode", "config", "set", "--acme", "domains="+fqdn); err != nil {
+		return fmt.Errorf("failed to set ACME domain %s: %w", fqdn, err)
+	}
+
+	s.logger.Log("Ordering certificate for %s", fqdn)
+
+	if err := s.executor.Run(ctx, "pvenode", "acme", "cert", "order"); err != nil {
+		return fmt.Errorf("failed to order ACME certificate: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const cmdACMERegister = "pvenode acme account register default admin@qoxi.cloud --directory " + acmeDirectoryProduction
+
+func TestACMEStepName(t *testing.T) {
+	step, _ := newTestStep(NewACMEStep, newValidTestConfig())
+
+	assert.Equal(t, "Configure ACME certificate", step.Name())
+}
+
+func TestACMEStepDisabledRunsNothing(t *testing.T) {
+	step, mock := newTestStep(NewACMEStep, newValidTestConfig())
+
+	require.NoError(t, step.Execute(context.Background()))
+	assert.Zero(t, mock.CommandCount())
+}
+
+func TestACMEStepRecordsCommands(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.ACME.Enabled = true
+
+	step, mock := newTestStep(NewACMEStep, cfg)
+	mock.SetOutput(cmdCatEntropy, "256\n")
+
+	require.NoError(t, step.Execute(context.Background()))
+
+	commands := mock.Commands()
+	require.Len(t, commands, 4)
+	assert.Equal(t, cmdCatEntropy, commands[0].String())
+	assert.Equal(t, cmdACMERegister, commands[1].String())
+	assert.Equal(t, acmeTOSAnswer, commands[1].Stdin)
+	assert.Equal(t, "pvenode config set --acme domains=pve1.example.com", commands[2].String())
+	assert.Equal(t, "pvenode acme cert order", commands[3].String())
+}
+
+func TestACMEStepUsesOverridesAndStaging(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.ACME.Enabled = true
+
+	step, mock := newTestStep(NewACMEStep, cfg)
+	mock.SetOutput(cmdCatEntropy, "256\n")
+	step.config.ACME.Email = "certs@example.com"
+	step.config.ACME.Staging = true
+
+	require.NoError(t, step.Execute(context.Background()))
+
+	assert.Equal(t,
+		"pvenode acme account register default certs@example.com --directory "+acmeDirectoryStaging,
+		mock.Commands()[1].String())
+}
+
+func TestACMEStepRegisterFailure(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.ACME.Enabled = true
+
+	step, mock := newTestStep(NewACMEStep, cfg)
+	mock.SetOutput(cmdCatEntropy, "256\n")
+	mock.SetError(cmdACMERegister, errors.New("exit status 255"))
+
+	err := step.Execute(context.Background())
+
+	require.ErrorContains(t, err, "failed to register ACME account")
+	assert.Equal(t, 2, mock.CommandCount(), "no certificate order after a failed registration")
+}
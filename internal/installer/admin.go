@@ -0,0 +1,115 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// adminGroup is the group granting sudo rights on Debian-based systems.
+const adminGroup = "sudo"
+
+// AdminUserStep creates the sudo-capable System.AdminUser and installs
+// System.AdminSSHKeys as its authorized_keys, so operators need not log in
+// as root. Proxmox VE does not ship sudo, so the step installs it.
+//
+// The step is a no-op when System.AdminUser is empty. An existing user is
+// kept and only has its group membership and keys updated, so the step can
+// be re-run.
+type AdminUserStep struct {
+	config   *config.Config
+	executor exec.Executor
+	logger   *Logger
+}
+
+// NewAdminUserStep creates an AdminUserStep for the given configuration.
+func NewAdminUserStep(cfg *config.Config, executor exec.Executor, logger *Logger) *AdminUserStep {
+	return &AdminUserStep{config: cfg, executor: executor, logger: logger}
+}
+
+// Name returns the step name.
+func (s *AdminUserStep) Name() string { return "Create admin user" }
+
+// Execute installs sudo, creates the user and installs its SSH keys.
+func (s *AdminUserStep) Execute(ctx context.Context) error {
+	user := s.config.System.AdminUser
+	if user == "" {
+		s.logger.Log("No admin user configured, skipping")
+
+		return nil
+	}
+
+	if err := config.ValidateAdminUser(user); err != nil {
+		return err
+	}
+
+	if err := s.executor.RunWithEnv(ctx, aptEnv, "apt-get", "install", "-y", "sudo"); err != nil {
+		return fmt.Errorf("failed to install sudo: %w", err)
+	}
+
+	if err := s.ensureUser(ctx, user); err != nil {
+		return err
+	}
+
+	return s.installKeys(ctx, user)
+}
+
+// ensureUser creates user in the admin group, or adds an existing user to it.
+func (s *AdminUserStep) ensureUser(ctx context.Context, user string) error {
+	// getent exits non-zero for unknown users.
+	if out, err := s.executor.RunWithOutput(ctx, "getent", "passwd", user); err == nil && strings.TrimSpace(out) != "" {
+		s.logger.Log("Admin user %s already exists, adding it to %s", user, adminGroup)
+
+		if err := s.executor.Run(ctx, "usermod", "--append", "--groups", adminGroup, user); err != nil {
+			return fmt.Errorf("failed to add %s to %s: %w", user, adminGroup, err)
+		}
+
+		return nil
+	}
+
+	s.logger.Log("Creating admin user %s", user)
+
+	if err := s.executor.Run(ctx, "useradd", "--create-home", "--shell", "/bin/bash",
+		"--groups", adminGroup, user); err != nil {
+		return fmt.Errorf("failed to create admin user %s: %w", user, err)
+	}
+
+	return nil
+}
+
+// installKeys writes the admin SSH keys to the user's authorized_keys with
+// the ownership and permissions sshd requires. Without keys it does nothing.
+func (s *AdminUserStep) installKeys(ctx context.Context, user string) error {
+	keys := s.config.System.AdminSSHKeys
+	if len(keys) == 0 {
+		return nil
+	}
+
+	sshDir := path.Join("/home", user, ".ssh")
+	authorizedKeys := path.Join(sshDir, "authorized_keys")
+	owner := user + ":" + user
+
+	s.logger.Log("Installing %d SSH key(s) for %s", len(keys), user)
+
+	if err := s.executor.Run(ctx, "install", "-d", "-m", "700", "-o", user, "-g", user, sshDir); err != nil {
+		return fmt.Errorf("failed to create %s: %w", sshDir, err)
+	}
+
+	if err := s.executor.RunWithStdin(ctx, strings.Join(keys, "\n")+"\n", "tee", authorizedKeys); err != nil {
+		return fmt.Errorf("failed to write %s: %w", authorizedKeys, err)
+	}
+
+	if err := s.executor.Run(ctx, "chown", owner, authorizedKeys); err != nil {
+		return fmt.Errorf("failed to set owner of %s: %w", authorizedKeys, err)
+	}
+
+	if err := s.executor.Run(ctx, "chmod", "600", authorizedKeys); err != nil {
+		return fmt.Errorf("failed to set permissions of %s: %w", authorizedKeys, err)
+	}
+
+	return nil
+}
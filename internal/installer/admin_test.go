@@ -0,0 +1,96 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+func TestAdminUserStepName(t *testing.T) {
+	step, _ := newTestStep(NewAdminUserStep, newValidTestConfig())
+
+	assert.Equal(t, "Create admin user", step.Name())
+}
+
+func TestAdminUserStepEmptyUserRunsNothing(t *testing.T) {
+	step, mock := newTestStep(NewAdminUserStep, newValidTestConfig())
+
+	require.NoError(t, step.Execute(context.Background()))
+	assert.Zero(t, mock.CommandCount())
+}
+
+func TestAdminUserStepCreatesUserAndKeys(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.System.AdminUser = "admin"
+	cfg.System.AdminSSHKeys = []string{testSSHKey, "ssh-rsa AAAAB3NzaC1yc2E ops@laptop"}
+
+	step, mock := newTestStep(NewAdminUserStep, cfg)
+	mock.SetError("getent passwd admin", errors.New("exit status 2"))
+
+	require.NoError(t, step.Execute(context.Background()))
+
+	commands := mock.Commands()
+	require.Len(t, commands, 7)
+	assert.Equal(t, "apt-get install -y sudo", commands[0].String())
+	assert.Equal(t, aptEnv, commands[0].Env)
+	assert.Equal(t, "getent passwd admin", commands[1].String())
+	assert.Equal(t, "useradd --create-home --shell /bin/bash --groups sudo admin", commands[2].String())
+	assert.Equal(t, "install -d -m 700 -o admin -g admin /home/admin/.ssh", commands[3].String())
+	assert.Equal(t, "tee /home/admin/.ssh/authorized_keys", commands[4].String())
+	assert.Equal(t, testSSHKey+"\nssh-rsa AAAAB3NzaC1yc2E ops@laptop\n", commands[4].Stdin)
+	assert.Equal(t, "chown admin:admin /home/admin/.ssh/authorized_keys", commands[5].String())
+	assert.Equal(t, "chmod 600 /home/admin/.ssh/authorized_keys", commands[6].String())
+}
+
+func TestAdminUserStepExistingUser(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.System.AdminUser = "admin"
+
+	step, mock := newTestStep(NewAdminUserStep, cfg)
+	mock.SetOutput("getent passwd admin", "admin:x:1000:1000::/home/admin:/bin/bash\n")
+
+	require.NoError(t, step.Execute(context.Background()))
+
+	assert.True(t, mock.WasCalledWith("usermod", "--append", "--groups", "sudo", "admin"))
+	assert.True(t, mock.WasNeverCalled("useradd"))
+	assert.True(t, mock.WasNeverCalled("tee"), "no keys configured")
+}
+
+func TestAdminUserStepInvalidUser(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.System.AdminUser = "root"
+
+	step, mock := newTestStep(NewAdminUserStep, cfg)
+
+	require.ErrorIs(t, step.Execute(context.Background()), config.ErrAdminUserReserved)
+	assert.Zero(t, mock.CommandCount())
+}
+
+func TestAdminUserStepCreateFailure(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.System.AdminUser = "admin"
+	cfg.System.AdminSSHKeys = []string{testSSHKey}
+
+	step, mock := newTestStep(NewAdminUserStep, cfg)
+	mock.SetError("getent passwd admin", errors.New("exit status 2"))
+	mock.SetError("useradd --create-home --shell /bin/bash --groups sudo admin", errors.New("exit status 9"))
+
+	err := step.Execute(context.Background())
+
+	require.ErrorContains(t, err, "failed to create admin user admin")
+	assert.True(t, mock.WasNeverCalled("tee"), "no keys after a failed useradd")
+}
+
+func TestPlanStepsAdminUser(t *testing.T) {
+	cfg := config.DefaultConfig()
+	assert.NotContains(t, stepNames(PlanSteps(cfg, exec.NewMockExecutor(), nil)), "Create admin user")
+
+	cfg.System.AdminUser = "admin"
+	assert.Contains(t, stepNames(PlanSteps(cfg, exec.NewMockExecutor(), nil)), "Create admin user")
+}
@@ -0,0 +1,320 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// Detection errors.
+var (
+	// ErrMemoryNotDetected is returned when /proc/meminfo has no MemTotal entry.
+	ErrMemoryNotDetected = errors.New("MemTotal not found in /proc/meminfo")
+	// ErrNoDefaultRoute is returned when the routing table has no default route.
+	ErrNoDefaultRoute = errors.New("no default route found")
+	// ErrNoPublicAddress is returned when the primary interface has no global IPv4 address.
+	ErrNoPublicAddress = errors.New("no public IPv4 address found")
+	// ErrDiskTooSmall is returned when a disk is smaller than the required minimum size.
+	ErrDiskTooSmall = errors.New("disk is too small")
+)
+
+// DetectMemoryMB returns the installed memory in megabytes.
+// It reads /proc/meminfo through the Executor and parses the MemTotal line.
+func DetectMemoryMB(ctx context.Context, executor exec.Executor) (int, error) {
+	out, err := executor.RunWithOutput(ctx, "cat", "/proc/meminfo")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read /proc/meminfo: %w", err)
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "MemTotal:" {
+			continue
+		}
+
+		kb, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse MemTotal %q: %w", fields[1], err)
+		}
+
+		return kb / 1024, nil
+	}
+
+	return 0, ErrMemoryNotDetected
+}
+
+// CPU vendors returned by DetectCPUVendor.
+const (
+	// CPUVendorIntel identifies Intel processors (vendor_id GenuineIntel).
+	CPUVendorIntel = "intel"
+	// CPUVendorAMD identifies AMD processors (vendor_id AuthenticAMD).
+	CPUVendorAMD = "amd"
+	// CPUVendorUnknown is returned when the vendor_id is missing or unrecognized.
+	CPUVendorUnknown = "unknown"
+)
+
+// DetectCPUVendor returns the CPU vendor as CPUVendorIntel, CPUVendorAMD or
+// CPUVendorUnknown. It reads /proc/cpuinfo through the Executor and uses the
+// first vendor_id line. Steps use it to pick the matching microcode package
+// and nested virtualization module options.
+func DetectCPUVendor(ctx context.Context, executor exec.Executor) (string, error) {
+	out, err := executor.RunWithOutput(ctx, "cat", "/proc/cpuinfo")
+	if err != nil {
+		return "", fmt.Errorf("failed to read /proc/cpuinfo: %w", err)
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(key) != "vendor_id" {
+			continue
+		}
+
+		switch strings.TrimSpace(value) {
+		case "GenuineIntel":
+			return CPUVendorIntel, nil
+		case "AuthenticAMD":
+			return CPUVendorAMD, nil
+		default:
+			return CPUVendorUnknown, nil
+		}
+	}
+
+	return CPUVendorUnknown, nil
+}
+
+// Boot modes returned by DetectBootMode.
+const (
+	// BootModeUEFI means the system booted through UEFI firmware and needs an ESP.
+	BootModeUEFI = "uefi"
+	// BootModeBIOS means the system booted through legacy BIOS.
+	BootModeBIOS = "bios"
+)
+
+// efiFirmwareDir exists only when the kernel was booted through UEFI.
+const efiFirmwareDir = "/sys/firmware/efi"
+
+// DetectBootMode returns BootModeUEFI or BootModeBIOS depending on whether
+// /sys/firmware/efi exists, checked with "test -d" through the Executor.
+// Steps use it to decide whether to set up an EFI system partition.
+//
+// Exit status 1 from test means the directory is missing; any other failure
+// (e.g., test not found or the context canceled) is returned as an error.
+func DetectBootMode(ctx context.Context, executor exec.Executor) (string, error) {
+	err := executor.Run(ctx, "test", "-d", efiFirmwareDir)
+	if err == nil {
+		return BootModeUEFI, nil
+	}
+
+	if exitedWith(err, 1) {
+		return BootModeBIOS, nil
+	}
+
+	return "", fmt.Errorf("failed to check %s: %w", efiFirmwareDir, err)
+}
+
+// exitedWith reports whether err, or an error it wraps, is a command exit
+// status equal to code (e.g., *os/exec.ExitError).
+func exitedWith(err error, code int) bool {
+	var exitErr interface{ ExitCode() int }
+
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == code
+}
+
+// DetectDisks returns the whole-disk block devices present on the system
+// as absolute paths (e.g., "/dev/sda", "/dev/nvme0n1").
+// Partitions, loop devices and optical drives are excluded.
+func DetectDisks(ctx context.Context, executor exec.Executor) ([]string, error) {
+	lines, err := exec.RunLines(ctx, executor, "lsblk", "-d", "-n", "-p", "-o", "NAME,TYPE")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list block devices: %w", err)
+	}
+
+	var disks []string
+
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[1] == "disk" {
+			disks = append(disks, fields[0])
+		}
+	}
+
+	return disks, nil
+}
+
+// DetectExistingInstall reports whether any of the target disks appears to
+// hold data, such as a previous Proxmox ZFS installation.
+//
+// Filesystem signatures are listed with lsblk for each disk and its partitions,
+// which covers the zfs_member partition of a Proxmox install as well as any
+// other filesystem, RAID or LVM signature. Callers should require explicit
+// confirmation before overwriting disks when this returns true.
+func DetectExistingInstall(ctx context.Context, executor exec.Executor, disks []string) (bool, error) {
+	for _, disk := range disks {
+		fsTypes, err := exec.RunLines(ctx, executor, "lsblk", "-n", "-o", "FSTYPE", disk)
+		if err != nil {
+			return false, fmt.Errorf("failed to read signatures of %s: %w", disk, err)
+		}
+
+		if len(fsTypes) > 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// DisksAreSSD reports whether all disks are non-rotational (SSD or NVMe),
+// which is when ZFS autotrim should be enabled on the pool.
+//
+// The rotational flag of each disk is read with "lsblk -d -n -o ROTA".
+// Returns false for an empty disk list, and stops at the first rotational disk.
+func DisksAreSSD(ctx context.Context, executor exec.Executor, disks []string) (bool, error) {
+	if len(disks) == 0 {
+		return false, nil
+	}
+
+	for _, disk := range disks {
+		out, err := executor.RunWithOutput(ctx, "lsblk", "-d", "-n", "-o", "ROTA", disk)
+		if err != nil {
+			return false, fmt.Errorf("failed to read rotational flag of %s: %w", disk, err)
+		}
+
+		switch rota := strings.TrimSpace(out); rota {
+		case "0":
+			continue
+		case "1":
+			return false, nil
+		default:
+			return false, fmt.Errorf("unexpected rotational flag %q for %s", rota, disk)
+		}
+	}
+
+	return true, nil
+}
+
+// VerifyMinDiskSize checks that every disk is at least minBytes in size.
+//
+// The size of each disk is read with "lsblk -b -d -n -o SIZE". All disks
+// below the minimum are reported together, joined into one error whose
+// entries wrap ErrDiskTooSmall and name the device and its size.
+// A disk whose size cannot be read or parsed fails immediately.
+func VerifyMinDiskSize(ctx context.Context, executor exec.Executor, disks []string, minBytes uint64) error {
+	var tooSmall []error
+
+	for _, disk := range disks {
+		out, err := executor.RunWithOutput(ctx, "lsblk", "-b", "-d", "-n", "-o", "SIZE", disk)
+		if err != nil {
+			return fmt.Errorf("failed to read size of %s: %w", disk, err)
+		}
+
+		size, err := strconv.ParseUint(strings.TrimSpace(out), 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse size of %s: %w", disk, err)
+		}
+
+		if size < minBytes {
+			tooSmall = append(tooSmall, fmt.Errorf("%w: %s has %d bytes, need at least %d", ErrDiskTooSmall, disk, size, minBytes))
+		}
+	}
+
+	return errors.Join(tooSmall...)
+}
+
+// DetectPrimaryInterface returns the network interface that carries the default route.
+// Returns ErrNoDefaultRoute if the routing table has no default route.
+func DetectPrimaryInterface(ctx context.Context, executor exec.Executor) (string, error) {
+	_, iface, err := detectDefaultRoute(ctx, executor)
+
+	return iface, err
+}
+
+// DetectHetznerNetwork discovers the public network settings of a Hetzner
+// dedicated server, suitable for prefilling NetworkConfig.
+//
+// Hetzner servers have a single public IPv4 address on the primary interface
+// and a default route via the datacenter gateway (often marked "onlink" because
+// the gateway lies outside the /32 or /26 assigned to the server). The primary
+// interface is taken from the default route and the public address is the first
+// global-scope IPv4 address on that interface, without its prefix length.
+//
+// Returns ErrNoDefaultRoute if there is no default route, or ErrNoPublicAddress
+// if the primary interface has no global IPv4 address.
+func DetectHetznerNetwork(
+	ctx context.Context,
+	executor exec.Executor,
+) (publicIP, gateway, iface string, err error) {
+	gateway, iface, err = detectDefaultRoute(ctx, executor)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	out, err := executor.RunWithOutput(ctx, "ip", "-4", "addr", "show", "dev", iface)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to read addresses of %s: %w", iface, err)
+	}
+
+	publicIP = parseGlobalIPv4(out)
+	if publicIP == "" {
+		return "", "", "", fmt.Errorf("%w on %s", ErrNoPublicAddress, iface)
+	}
+
+	return publicIP, gateway, iface, nil
+}
+
+// detectDefaultRoute returns the gateway and interface of the default route.
+// The gateway is empty for device routes without a "via" hop.
+func detectDefaultRoute(ctx context.Context, executor exec.Executor) (gateway, iface string, err error) {
+	out, err := executor.RunWithOutput(ctx, "ip", "route", "show", "default")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read default route: %w", err)
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] != "default" {
+			continue
+		}
+
+		gateway, iface = "", ""
+
+		for i := 1; i < len(fields)-1; i++ {
+			switch fields[i] {
+			case "via":
+				gateway = fields[i+1]
+			case "dev":
+				iface = fields[i+1]
+			}
+		}
+
+		if iface != "" {
+			return gateway, iface, nil
+		}
+	}
+
+	return "", "", ErrNoDefaultRoute
+}
+
+// parseGlobalIPv4 returns the first global-scope IPv4 address in "ip addr"
+// output, stripped of its prefix length, or an empty string if there is none.
+func parseGlobalIPv4(out string) string {
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "inet" {
+			continue
+		}
+
+		for i := 2; i < len(fields)-1; i++ {
+			if fields[i] == "scope" && fields[i+1] == "global" {
+				addr, _, _ := strings.Cut(fields[1], "/")
+
+				return addr
+			}
+		}
+	}
+
+	return ""
+}
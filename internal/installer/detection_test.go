@@ -0,0 +1,476 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// Test command keys for MockExecutor.
+const (
+	cmdCatMeminfo     = "cat /proc/meminfo"
+	cmdCatCpuinfo     = "cat /proc/cpuinfo"
+	cmdLsblkDisks     = "lsblk -d -n -p -o NAME,TYPE"
+	cmdIPRouteDefault = "ip route show default"
+	cmdIPAddrPrimary  = "ip -4 addr show dev enp0s31f6"
+	cmdTestEFI        = "test -d /sys/firmware/efi"
+)
+
+// testLsblkDisks is lsblk output with two disks, a loop device and a CD-ROM.
+const testLsblkDisks = `/dev/loop0 loop
+/dev/sda   disk
+/dev/sdb   disk
+/dev/sr0   rom
+`
+
+// testDefaultRoute is the default route on a Hetzner dedicated server.
+const testDefaultRoute = "default via 203.0.113.1 dev enp0s31f6 proto static onlink \n" // NOSONAR(go:S1313) RFC 5737 documentation range
+
+// testIPAddrPrimary is "ip -4 addr show" output of the primary interface in the
+// Hetzner rescue system, including a secondary address from an additional subnet.
+const testIPAddrPrimary = `2: enp0s31f6: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1500 qdisc fq_codel state UP group default qlen 1000
+    inet 203.0.113.45/26 brd 203.0.113.63 scope global enp0s31f6
+       valid_lft forever preferred_lft forever
+    inet 198.51.100.9/29 scope global secondary enp0s31f6
+       valid_lft forever preferred_lft forever
+` // NOSONAR(go:S1313) RFC 5737 documentation range
+
+// testMeminfo is a trimmed /proc/meminfo from a 64 GB Hetzner server.
+const testMeminfo = `MemTotal:       65751224 kB
+MemFree:        63114572 kB
+MemAvailable:   64012384 kB
+`
+
+func TestDetectMemoryMB(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	mock.SetOutput(cmdCatMeminfo, testMeminfo)
+
+	memoryMB, err := DetectMemoryMB(context.Background(), mock)
+
+	require.NoError(t, err)
+	assert.Equal(t, 64210, memoryMB)
+}
+
+func TestDetectMemoryMBErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		err    error
+	}{
+		{"command failure", "", errors.New("no such file")},
+		{"missing MemTotal", "MemFree: 1024 kB\n", nil},
+		{"malformed MemTotal", "MemTotal: lots kB\n", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := exec.NewMockExecutor()
+			mock.SetOutput(cmdCatMeminfo, tt.output)
+			mock.SetError(cmdCatMeminfo, tt.err)
+
+			_, err := DetectMemoryMB(context.Background(), mock)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+// cpuinfoWithVendor returns a trimmed two-core /proc/cpuinfo for the given vendor_id.
+func cpuinfoWithVendor(vendorID string) string {
+	core := "processor\t: %d\nvendor_id\t: " + vendorID + "\ncpu family\t: 6\nmodel name\t: Test CPU\n\n"
+
+	return fmt.Sprintf(core, 0) + fmt.Sprintf(core, 1)
+}
+
+func TestDetectCPUVendor(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{"intel", cpuinfoWithVendor("GenuineIntel"), CPUVendorIntel},
+		{"amd", cpuinfoWithVendor("AuthenticAMD"), CPUVendorAMD},
+		{"unrecognized vendor", cpuinfoWithVendor("HygonGenuine"), CPUVendorUnknown},
+		{"missing vendor_id", "processor\t: 0\nmodel name\t: ARMv8\n", CPUVendorUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := exec.NewMockExecutor()
+			mock.SetOutput(cmdCatCpuinfo, tt.output)
+
+			vendor, err := DetectCPUVendor(context.Background(), mock)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, vendor)
+		})
+	}
+}
+
+func TestDetectCPUVendorCommandFailure(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	mock.SetError(cmdCatCpuinfo, errors.New("no such file"))
+
+	_, err := DetectCPUVendor(context.Background(), mock)
+
+	assert.ErrorContains(t, err, "/proc/cpuinfo")
+}
+
+// exitStatusError mimics *os/exec.ExitError for a command exiting with code.
+type exitStatusError struct {
+	code int
+}
+
+func (e exitStatusError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+func (e exitStatusError) ExitCode() int {
+	return e.code
+}
+
+func TestDetectBootMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"efi directory exists", nil, BootModeUEFI},
+		{"efi directory missing", exitStatusError{code: 1}, BootModeBIOS},
+		{"wrapped exit status", fmt.Errorf("remote: %w", exitStatusError{code: 1}), BootModeBIOS},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := exec.NewMockExecutor()
+			mock.SetError(cmdTestEFI, tt.err)
+
+			mode, err := DetectBootMode(context.Background(), mock)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, mode)
+			assert.True(t, mock.WasCalledWith("test", "-d", "/sys/firmware/efi"))
+		})
+	}
+}
+
+func TestDetectBootModeCommandFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"command not found", errors.New(`exec: "test": executable file not found in $PATH`)},
+		{"unexpected exit status", exitStatusError{code: 2}},
+		{"context canceled", context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := exec.NewMockExecutor()
+			mock.SetError(cmdTestEFI, tt.err)
+
+			mode, err := DetectBootMode(context.Background(), mock)
+
+			require.ErrorIs(t, err, tt.err)
+			assert.ErrorContains(t, err, "/sys/firmware/efi")
+			assert.Empty(t, mode)
+		})
+	}
+}
+
+func TestDetectDisks(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	mock.SetOutput(cmdLsblkDisks, testLsblkDisks)
+
+	disks, err := DetectDisks(context.Background(), mock)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/dev/sda", "/dev/sdb"}, disks)
+}
+
+func TestDetectDisksCommandFailure(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	mock.SetError(cmdLsblkDisks, errors.New("lsblk: not found"))
+
+	_, err := DetectDisks(context.Background(), mock)
+
+	assert.Error(t, err)
+}
+
+func TestDetectPrimaryInterface(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    string
+		wantErr error
+	}{
+		{"hetzner default route", testDefaultRoute, "enp0s31f6", nil},
+		{"dhcp default route", "default via 192.0.2.1 dev eth0 proto dhcp metric 100\n", "eth0", nil}, // NOSONAR(go:S1313) RFC 5737 documentation range
+		{"no default route", "", "", ErrNoDefaultRoute},
+		{"default without dev", "default via 192.0.2.1\n", "", ErrNoDefaultRoute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := exec.NewMockExecutor()
+			mock.SetOutput(cmdIPRouteDefault, tt.output)
+
+			iface, err := DetectPrimaryInterface(context.Background(), mock)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, iface)
+		})
+	}
+}
+
+func TestDetectHetznerNetwork(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	mock.SetOutput(cmdIPRouteDefault, testDefaultRoute)
+	mock.SetOutput(cmdIPAddrPrimary, testIPAddrPrimary)
+
+	publicIP, gateway, iface, err := DetectHetznerNetwork(context.Background(), mock)
+
+	require.NoError(t, err)
+	assert.Equal(t, "203.0.113.45", publicIP) // NOSONAR(go:S1313) RFC 5737 documentation range
+	assert.Equal(t, "203.0.113.1", gateway)   // NOSONAR(go:S1313) RFC 5737 documentation range
+	assert.Equal(t, "enp0s31f6", iface)
+}
+
+func TestDetectHetznerNetworkErrors(t *testing.T) {
+	loopbackOnly := "2: enp0s31f6: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1500\n" +
+		"    inet 127.0.0.2/8 scope host enp0s31f6\n" // NOSONAR(go:S1313) loopback test data
+
+	tests := []struct {
+		name      string
+		route     string
+		addr      string
+		addrErr   error
+		wantErrIs error
+	}{
+		{"no default route", "", testIPAddrPrimary, nil, ErrNoDefaultRoute},
+		{"no global address", testDefaultRoute, loopbackOnly, nil, ErrNoPublicAddress},
+		{"address command failure", testDefaultRoute, "", errors.New("ip: not found"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := exec.NewMockExecutor()
+			mock.SetOutput(cmdIPRouteDefault, tt.route)
+			mock.SetOutput(cmdIPAddrPrimary, tt.addr)
+			mock.SetError(cmdIPAddrPrimary, tt.addrErr)
+
+			publicIP, gateway, iface, err := DetectHetznerNetwork(context.Background(), mock)
+
+			require.Error(t, err)
+
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+			}
+
+			assert.Empty(t, publicIP)
+			assert.Empty(t, gateway)
+			assert.Empty(t, iface)
+		})
+	}
+}
+
+func TestDetectExistingInstall(t *testing.T) {
+	disks := []string{"/dev/nvme0n1", "/dev/nvme1n1"}
+
+	tests := []struct {
+		name    string
+		outputs map[string]string
+		want    bool
+	}{
+		{
+			name: "clean disks",
+			outputs: map[string]string{
+				"lsblk -n -o FSTYPE /dev/nvme0n1": "\n",
+				"lsblk -n -o FSTYPE /dev/nvme1n1": "",
+			},
+			want: false,
+		},
+		{
+			name: "zfs member on second disk",
+			outputs: map[string]string{
+				"lsblk -n -o FSTYPE /dev/nvme0n1": "\n",
+				"lsblk -n -o FSTYPE /dev/nvme1n1": "\n\nvfat\nzfs_member\n",
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := exec.NewMockExecutor()
+			for cmd, out := range tt.outputs {
+				mock.SetOutput(cmd, out)
+			}
+
+			found, err := DetectExistingInstall(context.Background(), mock, disks)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, found)
+		})
+	}
+}
+
+func TestDetectExistingInstallNoDisks(t *testing.T) {
+	mock := exec.NewMockExecutor()
+
+	found, err := DetectExistingInstall(context.Background(), mock, nil)
+
+	require.NoError(t, err)
+	assert.False(t, found)
+	assert.True(t, mock.WasNeverCalled("lsblk"))
+}
+
+func TestDetectExistingInstallCommandFailure(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	mock.SetError("lsblk -n -o FSTYPE /dev/sdz", errors.New("lsblk: /dev/sdz: not a block device"))
+
+	found, err := DetectExistingInstall(context.Background(), mock, []string{"/dev/sdz"})
+
+	require.Error(t, err)
+	assert.False(t, found)
+	assert.Contains(t, err.Error(), "/dev/sdz")
+}
+
+func TestDisksAreSSD(t *testing.T) {
+	disks := []string{"/dev/sda", "/dev/sdb"}
+
+	tests := []struct {
+		name string
+		rota [2]string
+		want bool
+	}{
+		{"all ssd", [2]string{"0\n", "  0\n"}, true},
+		{"all hdd", [2]string{"1\n", "1\n"}, false},
+		{"mixed", [2]string{"0\n", "1\n"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := exec.NewMockExecutor()
+			mock.SetOutput("lsblk -d -n -o ROTA /dev/sda", tt.rota[0])
+			mock.SetOutput("lsblk -d -n -o ROTA /dev/sdb", tt.rota[1])
+
+			ssd, err := DisksAreSSD(context.Background(), mock, disks)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ssd)
+		})
+	}
+}
+
+func TestDisksAreSSDNoDisks(t *testing.T) {
+	mock := exec.NewMockExecutor()
+
+	ssd, err := DisksAreSSD(context.Background(), mock, nil)
+
+	require.NoError(t, err)
+	assert.False(t, ssd)
+	assert.True(t, mock.WasNeverCalled("lsblk"))
+}
+
+func TestDisksAreSSDErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		err    error
+	}{
+		{"command failure", "", errors.New("lsblk: /dev/sda: not a block device")},
+		{"unexpected output", "\n", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := exec.NewMockExecutor()
+			mock.SetOutput("lsblk -d -n -o ROTA /dev/sda", tt.output)
+			mock.SetError("lsblk -d -n -o ROTA /dev/sda", tt.err)
+
+			ssd, err := DisksAreSSD(context.Background(), mock, []string{"/dev/sda"})
+
+			require.ErrorContains(t, err, "/dev/sda")
+			assert.False(t, ssd)
+		})
+	}
+}
+
+// testMinDiskBytes is the minimum disk size used by the VerifyMinDiskSize tests (32 GiB).
+const testMinDiskBytes = 32 << 30
+
+func TestVerifyMinDiskSize(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	mock.SetOutput("lsblk -b -d -n -o SIZE /dev/sda", "480103981056\n")
+	mock.SetOutput("lsblk -b -d -n -o SIZE /dev/sdb", "  16013942784\n")
+	mock.SetOutput("lsblk -b -d -n -o SIZE /dev/nvme0n1", "34359738368\n")
+
+	err := VerifyMinDiskSize(context.Background(), mock, []string{"/dev/sda", "/dev/sdb", "/dev/nvme0n1"}, testMinDiskBytes)
+
+	require.ErrorIs(t, err, ErrDiskTooSmall)
+	assert.Contains(t, err.Error(), "/dev/sdb has 16013942784 bytes")
+	assert.NotContains(t, err.Error(), "/dev/sda")
+	assert.NotContains(t, err.Error(), "/dev/nvme0n1", "a disk exactly at the minimum is accepted")
+	assert.Equal(t, 3, mock.CommandCount())
+}
+
+func TestVerifyMinDiskSizeReportsAllSmallDisks(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	mock.SetOutput("lsblk -b -d -n -o SIZE /dev/sda", "1000\n")
+	mock.SetOutput("lsblk -b -d -n -o SIZE /dev/sdb", "2000\n")
+
+	err := VerifyMinDiskSize(context.Background(), mock, []string{"/dev/sda", "/dev/sdb"}, testMinDiskBytes)
+
+	require.ErrorIs(t, err, ErrDiskTooSmall)
+	assert.Contains(t, err.Error(), "/dev/sda has 1000 bytes")
+	assert.Contains(t, err.Error(), "/dev/sdb has 2000 bytes")
+}
+
+func TestVerifyMinDiskSizeAllLargeEnough(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	mock.SetOutput("lsblk -b -d -n -o SIZE /dev/sda", "480103981056\n")
+
+	require.NoError(t, VerifyMinDiskSize(context.Background(), mock, []string{"/dev/sda"}, testMinDiskBytes))
+	require.NoError(t, VerifyMinDiskSize(context.Background(), mock, nil, testMinDiskBytes))
+}
+
+func TestVerifyMinDiskSizeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		err     error
+		wantMsg string
+	}{
+		{"command failure", "", errors.New("lsblk: /dev/sda: not a block device"), "failed to read size of /dev/sda"},
+		{"empty output", "\n", nil, "failed to parse size of /dev/sda"},
+		{"human readable size", "447.1G\n", nil, "failed to parse size of /dev/sda"},
+		{"negative size", "-1\n", nil, "failed to parse size of /dev/sda"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := exec.NewMockExecutor()
+			mock.SetOutput("lsblk -b -d -n -o SIZE /dev/sda", tt.output)
+			mock.SetError("lsblk -b -d -n -o SIZE /dev/sda", tt.err)
+
+			err := VerifyMinDiskSize(context.Background(), mock, []string{"/dev/sda", "/dev/sdb"}, testMinDiskBytes)
+
+			require.ErrorContains(t, err, tt.wantMsg)
+			assert.NotErrorIs(t, err, ErrDiskTooSmall)
+			assert.Equal(t, 1, mock.CommandCount(), "stops at the first unreadable disk")
+		})
+	}
+}
@@ -40,8 +40,10 @@
 //
 // When Config.Verbose is false (the default), log messages are written only to
 // the log file, keeping the terminal clean. When Config.Verbose is true, log
-// messages are echoed to stdout as well, providing real-time feedback during
-// installation.
+// messages are echoed to the console as well, providing real-time feedback
+// during installation. Info entries go to stdout; Warn and Error entries go
+// to stderr so they remain visible when stdout is piped. Logger.SetOutput
+// replaces both streams, e.g. to capture them in tests.
 //
 // Log files are written to /var/log/proxmox-install.log by default,
 // with automatic fallback to /tmp/proxmox-install.log if /var/log
@@ -56,6 +58,29 @@
 //	}
 //	defer logger.Close()
 //
+// To keep the fallback behavior with different locations, for example in a
+// container with a read-only root filesystem, pass an ordered path list to
+// NewLoggerWithFallbacks:
+//
+//	logger, err := installer.NewLoggerWithFallbacks(verbose, []string{
+//	    "/data/logs/proxmox-install.log",
+//	    "/tmp/proxmox-install.log",
+//	})
+//
+// Entries can also be sent to the systemd journal, alongside the file, with
+// SetJournal. NewJournalSink uses the journald native socket, falling back to
+// systemd-cat through an Executor; when neither is available it returns
+// ErrJournalUnavailable and logging stays file-only:
+//
+//	if sink, err := installer.NewJournalSink(ctx, executor); err == nil {
+//	    logger.SetJournal(sink)
+//	}
+//
+// Command output and errors may echo a password or auth key. SetSecrets
+// replaces the given values with "[REDACTED]" in every entry:
+//
+//	logger.SetSecrets(cfg.SecretValues()...)
+//
 // # Log Format
 //
 // Each log entry follows this format:
@@ -65,6 +90,9 @@
 // Timestamps use ISO 8601 format (RFC3339) for consistent parsing and
 // timezone-independent logging. All timestamps are in UTC.
 //
+// Log and Info write entries as shown above. Warn and Error prefix the message
+// with "WARN: " and "ERROR: " respectively.
+//
 // # Log File Paths
 //
 // The default log path selection follows this priority:
@@ -101,4 +129,53 @@
 // After Close is called, subsequent Log calls become no-ops (they do not panic).
 // Close is idempotent - calling it multiple times is safe and returns nil after
 // the first successful close.
+//
+// # Running Steps
+//
+// A Runner executes the planned steps in order and stops at the first failure.
+// Steps implementing DoneChecker are skipped when their work is already done.
+// The returned RunResult records each step's status and duration:
+//
+//	runner := installer.NewRunner(installer.PlanSteps(cfg, executor, logger), logger)
+//	result, err := runner.Run(ctx)
+//	fmt.Print(result)
+//	if err != nil {
+//	    return err
+//	}
+//
+// A PreflightStep placed first validates the configuration and reconciles
+// it with the hardware, logging warnings. In strict mode the Runner stops
+// with ErrStopOnWarning after a step reporting warnings, before any
+// destructive step runs:
+//
+//	steps := append([]installer.Step{installer.NewPreflightStep(cfg, executor, logger)},
+//	    installer.PlanSteps(cfg, executor, logger)...)
+//	runner := installer.NewRunner(steps, logger)
+//	runner.SetStopOnWarning(true)
+//
+// # Sharing Data Between Steps
+//
+// Each step receives an InstallContext through its ctx. Steps exchange data
+// with typed keys declared once at package level:
+//
+//	var detectedInterfaceKey = installer.NewKey[string]("detected interface")
+//
+//	// In an early step:
+//	installer.Set(installer.InstallContextFrom(ctx), detectedInterfaceKey, iface)
+//
+//	// In a later step; ok is false if nothing was stored:
+//	iface, ok := installer.Get(installer.InstallContextFrom(ctx), detectedInterfaceKey)
+//
+// Runner.SetInstallContext supplies a context holding the Config, Logger and
+// Executor; otherwise Run creates an empty one.
+//
+// # Observing Progress
+//
+// A UI receives structured events by implementing Observer and registering it
+// with both the Runner (step start/end) and the Logger (log entries):
+//
+//	runner.SetObserver(ui)
+//	logger.SetObserver(ui)
+//
+// A nil Observer is a no-op.
 package installer
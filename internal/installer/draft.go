@@ -0,0 +1,113 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// ErrTimezoneNotDetected is returned when the system reports no timezone.
+var ErrTimezoneNotDetected = errors.New("no timezone reported by timedatectl")
+
+// genericHostnames are hostnames of rescue and live systems that say nothing
+// about the server and are not worth keeping for the installed system.
+var genericHostnames = []string{"rescue", "localhost", "debian", "ubuntu"}
+
+// DetectTimezone returns the system timezone as reported by timedatectl
+// (e.g., "Europe/Berlin"). Returns ErrTimezoneNotDetected if it is empty,
+// or the config validation error if it is not an IANA timezone.
+func DetectTimezone(ctx context.Context, executor exec.Executor) (string, error) {
+	out, err := executor.RunWithOutput(ctx, "timedatectl", "show", "--property=Timezone", "--value")
+	if err != nil {
+		return "", fmt.Errorf("failed to read timezone: %w", err)
+	}
+
+	timezone := strings.TrimSpace(out)
+	if timezone == "" {
+		return "", ErrTimezoneNotDetected
+	}
+
+	if err := config.ValidateTimezone(timezone); err != nil {
+		return "", fmt.Errorf("detected timezone %q: %w", timezone, err)
+	}
+
+	return timezone, nil
+}
+
+// DeriveHostname suggests a hostname for the installed system.
+//
+// The current short hostname is kept if it is valid and not a generic rescue
+// or live system name such as "rescue". Otherwise the hostname is derived
+// from the public IPv4 address (see DetectHetznerNetwork), e.g.
+// "pve-203-0-113-45", which is unique per server.
+func DeriveHostname(ctx context.Context, executor exec.Executor) (string, error) {
+	out, err := executor.RunWithOutput(ctx, "hostname", "--short")
+	if err != nil {
+		return "", fmt.Errorf("failed to read hostname: %w", err)
+	}
+
+	hostname := strings.ToLower(strings.TrimSpace(out))
+	if hostname != "" && !slices.Contains(genericHostnames, hostname) && config.ValidateHostname(hostname) == nil {
+		return hostname, nil
+	}
+
+	publicIP, _, _, err := DetectHetznerNetwork(ctx, executor)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive hostname from public address: %w", err)
+	}
+
+	return "pve-" + strings.ReplaceAll(publicIP, ".", "-"), nil
+}
+
+// DraftConfigFromHardware returns a ready-to-edit configuration for the
+// running system: DefaultConfig with the interface, disks, timezone and
+// hostname filled in by DetectPrimaryInterface, DetectDisks, DetectTimezone
+// and DeriveHostname. Secrets are left empty.
+//
+// A failing detector does not fail the draft: the field keeps its default
+// and the error is returned as a human-readable warning, as by
+// ReconcileWithHardware. An error is returned only if ctx is done.
+func DraftConfigFromHardware(ctx context.Context, executor exec.Executor) (*config.Config, []string, error) {
+	cfg := config.DefaultConfig()
+
+	var warnings []string
+
+	warn := func(field string, err error) {
+		warnings = append(warnings, fmt.Sprintf("could not detect %s: %v", field, err))
+	}
+
+	if iface, err := DetectPrimaryInterface(ctx, executor); err != nil {
+		warn("network interface", err)
+	} else {
+		cfg.Network.InterfaceName = iface
+	}
+
+	if disks, err := DetectDisks(ctx, executor); err != nil {
+		warn("disks", err)
+	} else if len(disks) > 0 {
+		cfg.Storage.Disks = disks
+	}
+
+	if timezone, err := DetectTimezone(ctx, executor); err != nil {
+		warn("timezone", err)
+	} else {
+		cfg.System.Timezone = timezone
+	}
+
+	if hostname, err := DeriveHostname(ctx, executor); err != nil {
+		warn("hostname", err)
+	} else {
+		cfg.System.Hostname = hostname
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return cfg, warnings, nil
+}
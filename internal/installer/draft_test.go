@@ -0,0 +1,146 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// Test command keys for the draft detectors.
+const (
+	cmdTimedatectl   = "timedatectl show --property=Timezone --value"
+	cmdHostnameShort = "hostname --short"
+)
+
+// newDraftMock returns a MockExecutor with canned output for every detector
+// used by DraftConfigFromHardware, as seen in the Hetzner rescue system.
+func newDraftMock() *exec.MockExecutor {
+	mock := exec.NewMockExecutor()
+	mock.SetOutput(cmdIPRouteDefault, testDefaultRoute)
+	mock.SetOutput(cmdIPAddrPrimary, testIPAddrPrimary)
+	mock.SetOutput(cmdLsblkDisks, testLsblkDisks)
+	mock.SetOutput(cmdTimedatectl, "Europe/Berlin\n")
+	mock.SetOutput(cmdHostnameShort, "rescue\n")
+
+	return mock
+}
+
+func TestDetectTimezone(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	mock.SetOutput(cmdTimedatectl, "Europe/Berlin\n")
+
+	timezone, err := DetectTimezone(context.Background(), mock)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", timezone)
+}
+
+func TestDetectTimezoneErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		err      error
+		expected error
+	}{
+		{"empty output", "\n", nil, ErrTimezoneNotDetected},
+		{"unknown zone", "Mars/Olympus_Mons\n", nil, config.ErrTimezoneInvalid},
+		{"command fails", "", errors.New("timedatectl: not found"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := exec.NewMockExecutor()
+			mock.SetOutput(cmdTimedatectl, tt.output)
+			mock.SetError(cmdTimedatectl, tt.err)
+
+			_, err := DetectTimezone(context.Background(), mock)
+
+			require.Error(t, err)
+
+			if tt.expected != nil {
+				assert.ErrorIs(t, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDeriveHostname(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{"keeps meaningful hostname", "PVE-FSN1-01\n", "pve-fsn1-01"},
+		{"rescue derives from public IP", "rescue\n", "pve-203-0-113-45"},
+		{"invalid derives from public IP", "-bad\n", "pve-203-0-113-45"},
+		{"empty derives from public IP", "", "pve-203-0-113-45"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newDraftMock()
+			mock.SetOutput(cmdHostnameShort, tt.output)
+
+			hostname, err := DeriveHostname(context.Background(), mock)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, hostname)
+		})
+	}
+}
+
+func TestDeriveHostnameNoPublicAddress(t *testing.T) {
+	mock := newDraftMock()
+	mock.SetOutput(cmdIPRouteDefault, "")
+
+	_, err := DeriveHostname(context.Background(), mock)
+
+	assert.ErrorIs(t, err, ErrNoDefaultRoute)
+}
+
+func TestDraftConfigFromHardware(t *testing.T) {
+	cfg, warnings, err := DraftConfigFromHardware(context.Background(), newDraftMock())
+
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	assert.Equal(t, "enp0s31f6", cfg.Network.InterfaceName)
+	assert.Equal(t, []string{"/dev/sda", "/dev/sdb"}, cfg.Storage.Disks)
+	assert.Equal(t, "Europe/Berlin", cfg.System.Timezone)
+	assert.Equal(t, "pve-203-0-113-45", cfg.System.Hostname)
+
+	assert.Empty(t, cfg.SecretValues(), "secrets are left empty")
+	assert.Equal(t, config.DefaultConfig().Network.BridgeMode, cfg.Network.BridgeMode)
+}
+
+func TestDraftConfigFromHardwarePartialFailure(t *testing.T) {
+	mock := newDraftMock()
+	mock.SetError(cmdLsblkDisks, errors.New("lsblk: not found"))
+
+	cfg, warnings, err := DraftConfigFromHardware(context.Background(), mock)
+
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "could not detect disks")
+	assert.Contains(t, warnings[0], "lsblk: not found")
+
+	assert.Equal(t, config.DefaultConfig().Storage.Disks, cfg.Storage.Disks, "failed field keeps its default")
+	assert.Equal(t, "enp0s31f6", cfg.Network.InterfaceName)
+	assert.Equal(t, "Europe/Berlin", cfg.System.Timezone)
+}
+
+func TestDraftConfigFromHardwareCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cfg, _, err := DraftConfigFromHardware(ctx, newDraftMock())
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, cfg)
+}
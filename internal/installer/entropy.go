@@ -0,0 +1,58 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// entropyAvailPath is the kernel's estimate of the entropy available, in bits.
+const entropyAvailPath = "/proc/sys/kernel/random/entropy_avail"
+
+// MinEntropyBits is the available entropy below which generating keys or
+// passwords may block on a freshly booted rescue system. Kernels since 5.18
+// always report 256 once the pool is initialized, so they never fall below it.
+const MinEntropyBits = 256
+
+// CheckEntropy returns the entropy available to the kernel's random number
+// generator, in bits. It reads /proc/sys/kernel/random/entropy_avail through
+// the Executor.
+func CheckEntropy(ctx context.Context, executor exec.Executor) (int, error) {
+	out, err := executor.RunWithOutput(ctx, "cat", entropyAvailPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", entropyAvailPath, err)
+	}
+
+	bits, err := strconv.Atoi(strings.TrimSpace(out))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse available entropy %q: %w", strings.TrimSpace(out), err)
+	}
+
+	return bits, nil
+}
+
+// WarnIfLowEntropy logs a warning when less than threshold bits of entropy
+// are available and reports whether it did. Steps call it before generating
+// secrets so a slow or stalled command has a visible cause.
+//
+// The check is best-effort: a failure to read the entropy is logged as a
+// warning and reported as not low, so it never aborts the installation.
+func WarnIfLowEntropy(ctx context.Context, executor exec.Executor, logger *Logger, threshold int) bool {
+	bits, err := CheckEntropy(ctx, executor)
+	if err != nil {
+		logger.Warn("Could not check available entropy: %v", err)
+
+		return false
+	}
+
+	if bits >= threshold {
+		return false
+	}
+
+	logger.Warn("Only %d bits of entropy available (want %d); key generation may be slow", bits, threshold)
+
+	return true
+}
@@ -0,0 +1,119 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+const cmdCatEntropy = "cat " + entropyAvailPath
+
+func TestCheckEntropy(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   int
+	}{
+		{name: "high entropy", output: "3764\n", want: 3764},
+		{name: "modern kernel", output: "256\n", want: 256},
+		{name: "low entropy", output: "42\n", want: 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := exec.NewMockExecutor()
+			mock.SetOutput(cmdCatEntropy, tt.output)
+
+			bits, err := CheckEntropy(context.Background(), mock)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, bits)
+		})
+	}
+}
+
+func TestCheckEntropyMalformedOutput(t *testing.T) {
+	for _, output := range []string{"", "lots\n", "12 34\n"} {
+		mock := exec.NewMockExecutor()
+		mock.SetOutput(cmdCatEntropy, output)
+
+		_, err := CheckEntropy(context.Background(), mock)
+
+		require.ErrorContains(t, err, "failed to parse available entropy", "output %q", output)
+	}
+}
+
+func TestCheckEntropyReadFailure(t *testing.T) {
+	errRead := errors.New("permission denied")
+
+	mock := exec.NewMockExecutor()
+	mock.SetError(cmdCatEntropy, errRead)
+
+	_, err := CheckEntropy(context.Background(), mock)
+
+	require.ErrorIs(t, err, errRead)
+	assert.ErrorContains(t, err, "failed to read "+entropyAvailPath)
+}
+
+func TestWarnIfLowEntropy(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		wantLow  bool
+		wantWarn string
+	}{
+		{name: "above threshold", output: "3764\n"},
+		{name: "at threshold", output: "256\n"},
+		{
+			name:     "below threshold",
+			output:   "42\n",
+			wantLow:  true,
+			wantWarn: "WARN: Only 42 bits of entropy available (want 256)",
+		},
+		{
+			name:     "unreadable",
+			output:   "lots\n",
+			wantWarn: "WARN: Could not check available entropy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, readLog := newHeartbeatTestLogger(t)
+
+			mock := exec.NewMockExecutor()
+			mock.SetOutput(cmdCatEntropy, tt.output)
+
+			low := WarnIfLowEntropy(context.Background(), mock, logger, MinEntropyBits)
+
+			assert.Equal(t, tt.wantLow, low)
+
+			if tt.wantWarn == "" {
+				assert.NotContains(t, readLog(), "WARN")
+			} else {
+				assert.Contains(t, readLog(), tt.wantWarn)
+			}
+		})
+	}
+}
+
+func TestACMEStepWarnsOnLowEntropy(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.ACME.Enabled = true
+
+	step, mock := newTestStep(NewACMEStep, cfg)
+	mock.SetOutput(cmdCatEntropy, "42\n")
+
+	logger, readLog := newHeartbeatTestLogger(t)
+	step.logger = logger
+
+	require.NoError(t, step.Execute(context.Background()))
+
+	assert.Contains(t, readLog(), "Only 42 bits of entropy available")
+	assert.True(t, mock.WasCalledWith("pvenode", "acme", "cert", "order"), "low entropy does not abort the step")
+}
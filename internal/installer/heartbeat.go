@@ -0,0 +1,49 @@
+package installer
+
+import (
+	"context"
+	"time"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// RunWithHeartbeat runs a command through the Executor and logs a
+// "still running" line every interval until the command completes.
+//
+// This lets users watching the log see that a multi-minute command
+// (e.g., an ISO download or ZFS pool creation) is progressing rather than hung.
+// No heartbeat is logged for commands that finish within the first interval.
+// A non-positive interval disables heartbeats. The command's error is returned unchanged.
+func RunWithHeartbeat(
+	ctx context.Context,
+	executor exec.Executor,
+	logger *Logger,
+	interval time.Duration,
+	name string,
+	args ...string,
+) error {
+	if interval <= 0 {
+		return executor.Run(ctx, name, args...)
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- executor.Run(ctx, name, args...)
+	}()
+
+	cmd := exec.FormatCommand(name, args...)
+	start := time.Now()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case err := <-done:
+			return err
+		case <-ticker.C:
+			logger.Log("Still running: %s (%ds)", cmd, int(time.Since(start).Seconds()))
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// newHeartbeatTestLogger creates a Logger writing to a temp file and returns
+// a function that reads the log contents.
+func newHeartbeatTestLogger(t *testing.T) (*Logger, func() string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), testLogFileName)
+	logger, err := NewLoggerWithPath(path, false)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = logger.Close() })
+
+	return logger, func() string {
+		data, err := os.ReadFile(path) //nolint:gosec // test temp file
+		require.NoError(t, err)
+
+		return string(data)
+	}
+}
+
+func TestRunWithHeartbeatLogsWhileRunning(t *testing.T) {
+	logger, readLog := newHeartbeatTestLogger(t)
+
+	mock := exec.NewMockExecutor()
+	mock.SetDelay("zpool create rpool", 60*time.Millisecond)
+
+	err := RunWithHeartbeat(context.Background(), mock, logger, 10*time.Millisecond, "zpool", "create", "rpool")
+
+	require.NoError(t, err)
+
+	beats := strings.Count(readLog(), "Still running: zpool create rpool")
+	assert.GreaterOrEqual(t, beats, 1)
+
+	// No heartbeats after completion.
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, beats, strings.Count(readLog(), "Still running"))
+}
+
+func TestRunWithHeartbeatFastCommandNoHeartbeat(t *testing.T) {
+	logger, readLog := newHeartbeatTestLogger(t)
+
+	err := RunWithHeartbeat(context.Background(), exec.NewMockExecutor(), logger, time.Second, "true")
+
+	require.NoError(t, err)
+	assert.NotContains(t, readLog(), "Still running")
+}
+
+func TestRunWithHeartbeatReturnsCommandError(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	wantErr := errors.New("download failed")
+	mock.SetError("wget iso", wantErr)
+
+	err := RunWithHeartbeat(context.Background(), mock, nil, 10*time.Millisecond, "wget", "iso")
+
+	assert.ErrorIs(t, err, wantErr)
+}
+
+func TestRunWithHeartbeatContextCanceled(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	mock.SetDelay("sleep", time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := RunWithHeartbeat(ctx, mock, nil, 5*time.Millisecond, "sleep")
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRunWithHeartbeatDisabledInterval(t *testing.T) {
+	mock := exec.NewMockExecutor()
+
+	require.NoError(t, RunWithHeartbeat(context.Background(), mock, nil, 0, "true"))
+	assert.True(t, mock.WasCalledWith("true"))
+}
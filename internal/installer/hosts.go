@@ -0,0 +1,66 @@
+package installer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+)
+
+// ErrHostsIPInvalid is returned when the address for /etc/hosts is not a valid IP.
+var ErrHostsIPInvalid = errors.New("hosts entry IP must be a valid IPv4 or IPv6 address")
+
+// hostsIPv6Entries are the standard Debian IPv6 loopback and multicast entries.
+const hostsIPv6Entries = `# The following lines are desirable for IPv6 capable hosts
+::1     ip6-localhost ip6-loopback
+fe00::0 ip6-localnet
+ff00::0 ip6-mcastprefix
+ff02::1 ip6-allnodes
+ff02::2 ip6-allrouters
+ff02::3 ip6-allhosts
+`
+
+// RenderHostsEntries returns the contents of /etc/hosts for the installed
+// system: the IPv4 localhost entry, the canonical "ip fqdn hostname" line
+// mapping cfg.FQDN() and the short hostname to ip, and the standard IPv6
+// entries. Proxmox VE requires the node name to resolve to a non-loopback
+// address, which the canonical line provides.
+//
+// The hostname and every label of the domain suffix must be valid per
+// RFC 1123, and ip must be a plain IPv4 or IPv6 address (without prefix
+// length); otherwise an error is returned. This is a pure function.
+func RenderHostsEntries(cfg *config.Config, ip string) (string, error) {
+	if cfg == nil {
+		return "", fmt.Errorf("config is nil")
+	}
+
+	if net.ParseIP(ip) == nil {
+		return "", fmt.Errorf("%w: %q", ErrHostsIPInvalid, ip)
+	}
+
+	if err := config.ValidateHostname(cfg.System.Hostname); err != nil {
+		return "", fmt.Errorf("invalid hostname %q: %w", cfg.System.Hostname, err)
+	}
+
+	names := cfg.System.Hostname
+
+	if suffix := cfg.System.DomainSuffix; suffix != "" {
+		for _, label := range strings.Split(suffix, ".") {
+			if err := config.ValidateHostname(label); err != nil {
+				return "", fmt.Errorf("invalid domain suffix %q: %w", suffix, err)
+			}
+		}
+
+		names = cfg.FQDN() + " " + cfg.System.Hostname
+	}
+
+	var b strings.Builder
+
+	b.WriteString("127.0.0.1 localhost.localdomain localhost\n")
+	fmt.Fprintf(&b, "%s %s\n\n", ip, names)
+	b.WriteString(hostsIPv6Entries)
+
+	return b.String(), nil
+}
@@ -0,0 +1,82 @@
+package installer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+)
+
+// testHostsIP is a private address used as the node address in hosts tests.
+const testHostsIP = "10.0.0.2" // NOSONAR(go:S1313) RFC 1918 private range
+
+func TestRenderHostsEntries(t *testing.T) {
+	hosts, err := RenderHostsEntries(newValidTestConfig(), testHostsIP)
+	require.NoError(t, err)
+
+	lines := strings.Split(hosts, "\n")
+	assert.Equal(t, "127.0.0.1 localhost.localdomain localhost", lines[0])
+	assert.Equal(t, "10.0.0.2 pve1.example.com pve1", lines[1])
+	assert.Contains(t, hosts, "::1     ip6-localhost ip6-loopback\n")
+	assert.True(t, strings.HasSuffix(hosts, "\n"))
+}
+
+func TestRenderHostsEntriesWithoutDomain(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.System.DomainSuffix = ""
+
+	hosts, err := RenderHostsEntries(cfg, testHostsIP)
+	require.NoError(t, err)
+
+	assert.Contains(t, hosts, "\n10.0.0.2 pve1\n")
+}
+
+func TestRenderHostsEntriesIPv6(t *testing.T) {
+	hosts, err := RenderHostsEntries(newValidTestConfig(), "2a01:4f8::2")
+	require.NoError(t, err)
+
+	assert.Contains(t, hosts, "\n2a01:4f8::2 pve1.example.com pve1\n")
+}
+
+func TestRenderHostsEntriesInvalidIP(t *testing.T) {
+	for _, ip := range []string{"", "10.0.0.256", "10.0.0.2/24", "pve1.example.com"} {
+		_, err := RenderHostsEntries(newValidTestConfig(), ip)
+
+		assert.ErrorIs(t, err, ErrHostsIPInvalid, "ip %q", ip)
+	}
+}
+
+func TestRenderHostsEntriesInvalidNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		hostname string
+		domain   string
+		wantErr  error
+	}{
+		{"empty hostname", "", "example.com", config.ErrHostnameEmpty},
+		{"invalid hostname", "-pve1", "example.com", config.ErrHostnameStartsWithHyphen},
+		{"invalid domain label", "pve1", "example..com", config.ErrHostnameEmpty},
+		{"invalid domain characters", "pve1", "exa_mple.com", config.ErrHostnameInvalidChars},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newValidTestConfig()
+			cfg.System.Hostname = tt.hostname
+			cfg.System.DomainSuffix = tt.domain
+
+			_, err := RenderHostsEntries(cfg, testHostsIP)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestRenderHostsEntriesNilConfig(t *testing.T) {
+	_, err := RenderHostsEntries(nil, testHostsIP)
+
+	assert.Error(t, err)
+}
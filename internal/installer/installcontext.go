@@ -0,0 +1,112 @@
+package installer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// InstallContext is the state shared by all steps of one installation run.
+//
+// Besides the configuration, logger and executor, it holds a typed key/value
+// store through which a step passes discovered data (e.g., the detected
+// interface) to later steps. The Runner attaches it to the context passed to
+// each Step.Execute; steps retrieve it with InstallContextFrom.
+//
+// The store is safe for concurrent use. A nil *InstallContext behaves as an
+// empty store: Get returns the zero value and false, and Set does nothing.
+type InstallContext struct {
+	// Config is the effective installation configuration. It may be nil.
+	Config *config.Config
+
+	// Logger receives progress messages. It may be nil.
+	Logger *Logger
+
+	// Executor runs system commands. It may be nil.
+	Executor exec.Executor
+
+	mu     sync.RWMutex
+	values map[any]any
+}
+
+// Key identifies a value of type T in an InstallContext.
+// Keys are compared by identity, so two keys created with the same name are
+// distinct; declare each key once as a package-level variable.
+type Key[T any] struct {
+	name string
+}
+
+// NewKey returns a new key for values of type T. The name is only used for
+// debugging output.
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+// String returns the key name.
+func (k *Key[T]) String() string {
+	return k.name
+}
+
+// NewInstallContext creates an InstallContext with an empty store.
+func NewInstallContext(cfg *config.Config, executor exec.Executor, logger *Logger) *InstallContext {
+	return &InstallContext{
+		Config:   cfg,
+		Logger:   logger,
+		Executor: executor,
+		values:   make(map[any]any),
+	}
+}
+
+// Set stores value under key, replacing any previous value.
+func Set[T any](ic *InstallContext, key *Key[T], value T) {
+	if ic == nil {
+		return
+	}
+
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	if ic.values == nil {
+		ic.values = make(map[any]any)
+	}
+
+	ic.values[key] = value
+}
+
+// Get returns the value stored under key and true, or the zero value of T
+// and false if no value has been set.
+func Get[T any](ic *InstallContext, key *Key[T]) (T, bool) {
+	var zero T
+
+	if ic == nil {
+		return zero, false
+	}
+
+	ic.mu.RLock()
+	defer ic.mu.RUnlock()
+
+	value, ok := ic.values[key]
+	if !ok {
+		return zero, false
+	}
+
+	return value.(T), true //nolint:forcetypeassert // Set only stores T under *Key[T]
+}
+
+// installContextKey is the context.Context key for the InstallContext.
+type installContextKey struct{}
+
+// WithInstallContext returns a copy of ctx carrying ic.
+func WithInstallContext(ctx context.Context, ic *InstallContext) context.Context {
+	return context.WithValue(ctx, installContextKey{}, ic)
+}
+
+// InstallContextFrom returns the InstallContext carried by ctx, or nil if
+// there is none. The nil result is usable as an empty store.
+func InstallContextFrom(ctx context.Context) *InstallContext {
+	ic, _ := ctx.Value(installContextKey{}).(*InstallContext)
+
+	return ic
+}
@@ -0,0 +1,173 @@
+package installer
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// Keys used by the InstallContext tests.
+var (
+	testInterfaceKey = NewKey[string]("interface")
+	testDiskCountKey = NewKey[int]("disk count")
+)
+
+// detectInterfaceStep stores a detected interface for later steps.
+type detectInterfaceStep struct{}
+
+func (detectInterfaceStep) Name() string { return "Detect interface" }
+
+func (detectInterfaceStep) Execute(ctx context.Context) error {
+	Set(InstallContextFrom(ctx), testInterfaceKey, "enp0s31f6")
+
+	return nil
+}
+
+// readInterfaceStep reads the interface stored by detectInterfaceStep.
+type readInterfaceStep struct {
+	got   string
+	found bool
+}
+
+func (s *readInterfaceStep) Name() string { return "Read interface" }
+
+func (s *readInterfaceStep) Execute(ctx context.Context) error {
+	s.got, s.found = Get(InstallContextFrom(ctx), testInterfaceKey)
+
+	return nil
+}
+
+// funcStep is a Step running fn.
+type funcStep struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+func (s *funcStep) Name() string { return s.name }
+
+func (s *funcStep) Execute(ctx context.Context) error { return s.fn(ctx) }
+
+func TestRunnerSharesInstallContextBetweenSteps(t *testing.T) {
+	reader := &readInterfaceStep{}
+	runner := NewRunner([]Step{detectInterfaceStep{}, reader}, nil)
+
+	_, err := runner.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, reader.found)
+	assert.Equal(t, "enp0s31f6", reader.got)
+}
+
+func TestRunnerUsesProvidedInstallContext(t *testing.T) {
+	cfg := config.DefaultConfig()
+	mock := exec.NewMockExecutor()
+	ic := NewInstallContext(cfg, mock, nil)
+
+	var seen *InstallContext
+
+	step := &funcStep{name: "Inspect", fn: func(ctx context.Context) error {
+		seen = InstallContextFrom(ctx)
+
+		return nil
+	}}
+
+	runner := NewRunner([]Step{detectInterfaceStep{}, step}, nil)
+	runner.SetInstallContext(ic)
+
+	_, err := runner.Run(context.Background())
+	require.NoError(t, err)
+
+	require.Same(t, ic, seen)
+	assert.Same(t, cfg, seen.Config)
+	assert.Same(t, mock, seen.Executor)
+
+	iface, ok := Get(ic, testInterfaceKey)
+	assert.True(t, ok)
+	assert.Equal(t, "enp0s31f6", iface)
+}
+
+func TestInstallContextMissingKey(t *testing.T) {
+	ic := NewInstallContext(nil, nil, nil)
+
+	iface, ok := Get(ic, testInterfaceKey)
+	assert.False(t, ok)
+	assert.Empty(t, iface)
+
+	count, ok := Get(ic, testDiskCountKey)
+	assert.False(t, ok)
+	assert.Zero(t, count)
+}
+
+func TestInstallContextKeysAreDistinct(t *testing.T) {
+	ic := NewInstallContext(nil, nil, nil)
+	other := NewKey[string]("interface")
+
+	Set(ic, testInterfaceKey, "eth0")
+
+	_, ok := Get(ic, other)
+	assert.False(t, ok, "keys with the same name must not collide")
+	assert.Equal(t, "interface", testInterfaceKey.String())
+}
+
+func TestInstallContextSetOverwrites(t *testing.T) {
+	ic := NewInstallContext(nil, nil, nil)
+
+	Set(ic, testDiskCountKey, 1)
+	Set(ic, testDiskCountKey, 2)
+
+	count, ok := Get(ic, testDiskCountKey)
+	assert.True(t, ok)
+	assert.Equal(t, 2, count)
+}
+
+func TestInstallContextNil(t *testing.T) {
+	var ic *InstallContext
+
+	assert.NotPanics(t, func() { Set(ic, testInterfaceKey, "eth0") })
+
+	iface, ok := Get(ic, testInterfaceKey)
+	assert.False(t, ok)
+	assert.Empty(t, iface)
+	assert.Nil(t, InstallContextFrom(context.Background()))
+}
+
+func TestInstallContextZeroValueUsable(t *testing.T) {
+	ic := &InstallContext{}
+
+	Set(ic, testDiskCountKey, 3)
+
+	count, ok := Get(ic, testDiskCountKey)
+	assert.True(t, ok)
+	assert.Equal(t, 3, count)
+}
+
+func TestInstallContextConcurrentAccess(t *testing.T) {
+	ic := NewInstallContext(nil, nil, nil)
+
+	var wg sync.WaitGroup
+
+	for i := range 50 {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			Set(ic, testDiskCountKey, i)
+		}()
+
+		go func() {
+			defer wg.Done()
+			Get(ic, testDiskCountKey)
+		}()
+	}
+
+	wg.Wait()
+
+	_, ok := Get(ic, testDiskCountKey)
+	assert.True(t, ok)
+}
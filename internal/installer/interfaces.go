@@ -0,0 +1,226 @@
+package installer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+)
+
+// Bridge names used in the rendered /etc/network/interfaces.
+const (
+	// externalBridge carries the public IP and bridges the physical interface.
+	externalBridge = "vmbr0"
+	// natBridge hosts the private NAT subnet.
+	natBridge = "vmbr1"
+	// routedBridge hosts the additional routed subnet.
+	routedBridge = "vmbr2"
+)
+
+// Interfaces rendering errors.
+var (
+	// ErrPublicIPInvalid is returned when the host public IP is not a valid IPv4 address.
+	ErrPublicIPInvalid = errors.New("public IP must be a valid IPv4 address")
+	// ErrGatewayInvalid is returned when the host gateway is not a valid IPv4 address.
+	ErrGatewayInvalid = errors.New("gateway must be a valid IPv4 address")
+)
+
+// HostNetwork describes the public network of the host,
+// as discovered by DetectHetznerNetwork.
+type HostNetwork struct {
+	// Interface is the physical interface carrying the default route.
+	Interface string
+	// PublicIP is the main public IPv4 address, without prefix length.
+	PublicIP string
+	// Gateway is the default gateway address.
+	Gateway string
+}
+
+// RenderInterfacesConfig returns the host network configuration for the
+// installed Proxmox host, in the format of network.NetworkBackend:
+//   - ifupdown2 (or empty): the contents of /etc/network/interfaces
+//   - networkd: systemd-networkd units, see renderNetworkd
+//
+// The public address is configured as a /32 with a point-to-point route to the
+// gateway, as required by Hetzner's routed setup. The bridge mode selects the layout:
+//   - internal: public IP on the physical interface, NAT bridge vmbr1
+//   - external: public IP on bridge vmbr0 enslaving the physical interface
+//   - both: vmbr0 as in external mode plus NAT bridge vmbr1
+//
+// When network.AdditionalSubnet is set, a routed bridge vmbr2 is added with the
+// first host address of that subnet, so guests can use the remaining addresses
+// with vmbr2 as their gateway. When network.BridgeMAC is set, it is used as the
+// hardware address of the interface carrying the public IP. network.InterfaceName
+// overrides host.Interface when set. This is a pure function; it does not run anything.
+func RenderInterfacesConfig(network config.NetworkConfig, host HostNetwork) (string, error) {
+	iface := network.InterfaceName
+	if iface == "" {
+		iface = host.Interface
+	}
+
+	if iface == "" {
+		return "", ErrInterfaceEmpty
+	}
+
+	if err := config.ValidateBridgeMode(network.BridgeMode); err != nil {
+		return "", err
+	}
+
+	if ip := net.ParseIP(host.PublicIP); ip == nil || ip.To4() == nil {
+		return "", fmt.Errorf("%w: got %q", ErrPublicIPInvalid, host.PublicIP)
+	}
+
+	if ip := net.ParseIP(host.Gateway); ip == nil || ip.To4() == nil {
+		return "", fmt.Errorf("%w: got %q", ErrGatewayInvalid, host.Gateway)
+	}
+
+	if network.BridgeMAC != "" {
+		if err := config.ValidateMAC(network.BridgeMAC); err != nil {
+			return "", err
+		}
+	}
+
+	switch network.NetworkBackend {
+	case "", config.NetworkBackendIfupdown2:
+		return renderIfupdown2(network, iface, host)
+	case config.NetworkBackendNetworkd:
+		return renderNetworkd(network, iface, host)
+	default:
+		return "", fmt.Errorf("%w: got %q", config.ErrNetworkBackendInvalid, network.NetworkBackend)
+	}
+}
+
+// renderIfupdown2 renders /etc/network/interfaces for the validated inputs
+// of RenderInterfacesConfig, with iface as the physical interface.
+func renderIfupdown2(network config.NetworkConfig, iface string, host HostNetwork) (string, error) {
+	var b strings.Builder
+
+	b.WriteString("# Generated by pve-install.\n\n")
+	b.WriteString("auto lo\niface lo inet loopback\n")
+
+	wanInterface := iface
+
+	if network.BridgeMode == config.BridgeModeInternal {
+		fmt.Fprintf(&b, "\nauto %s\niface %s inet static\n", iface, iface)
+		writePublicAddress(&b, host, network.BridgeMAC)
+	} else {
+		wanInterface = externalBridge
+
+		fmt.Fprintf(&b, "\niface %s inet manual\n", iface)
+		fmt.Fprintf(&b, "\nauto %s\niface %s inet static\n", externalBridge, externalBridge)
+		writePublicAddress(&b, host, network.BridgeMAC)
+		writeBridgeOptions(&b, iface)
+	}
+
+	if network.BridgeMode != config.BridgeModeExternal {
+		if err := writeNATBridge(&b, network.PrivateSubnet, wanInterface); err != nil {
+			return "", err
+		}
+	}
+
+	if network.AdditionalSubnet != "" {
+		if err := writeRoutedBridge(&b, network.AdditionalSubnet); err != nil {
+			return "", err
+		}
+	}
+
+	return b.String(), nil
+}
+
+// writePublicAddress writes the Hetzner point-to-point public address options,
+// preceded by a hwaddress line when mac is set.
+func writePublicAddress(b *strings.Builder, host HostNetwork, mac string) {
+	if mac != "" {
+		fmt.Fprintf(b, "\thwaddress ether %s\n", normalizeMAC(mac))
+	}
+
+	fmt.Fprintf(b, "\taddress %s/32\n", host.PublicIP)
+	fmt.Fprintf(b, "\tgateway %s\n", host.Gateway)
+	fmt.Fprintf(b, "\tpointopoint %s\n", host.Gateway)
+}
+
+// writeBridgeOptions writes the bridge options for a bridge enslaving ports.
+func writeBridgeOptions(b *strings.Builder, ports string) {
+	fmt.Fprintf(b, "\tbridge-ports %s\n", ports)
+	b.WriteString("\tbridge-stp off\n")
+	b.WriteString("\tbridge-fd 0\n")
+}
+
+// writeNATBridge writes the NAT bridge stanza masquerading privateSubnet out of wanInterface.
+func writeNATBridge(b *strings.Builder, privateSubnet, wanInterface string) error {
+	address, err := firstHostCIDR(privateSubnet)
+	if err != nil {
+		return err
+	}
+
+	rules, err := RenderNATRules(privateSubnet, wanInterface, NATBackendIPTables)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(b, "\nauto %s\niface %s inet static\n", natBridge, natBridge)
+	fmt.Fprintf(b, "\taddress %s\n", address)
+	writeBridgeOptions(b, "none")
+	b.WriteString("\tpost-up echo 1 > /proc/sys/net/ipv4/ip_forward\n")
+
+	for _, rule := range rules {
+		fmt.Fprintf(b, "\tpost-up %s\n", rule)
+		fmt.Fprintf(b, "\tpost-down %s\n", strings.Replace(rule, " -A ", " -D ", 1))
+	}
+
+	return nil
+}
+
+// writeRoutedBridge writes the routed bridge stanza for an additional subnet.
+func writeRoutedBridge(b *strings.Builder, subnet string) error {
+	if err := config.ValidateAdditionalSubnet(subnet); err != nil {
+		return err
+	}
+
+	address, err := firstHostCIDR(subnet)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(b, "\nauto %s\niface %s inet static\n", routedBridge, routedBridge)
+	fmt.Fprintf(b, "\taddress %s\n", address)
+	writeBridgeOptions(b, "none")
+	b.WriteString("\tpost-up echo 1 > /proc/sys/net/ipv4/ip_forward\n")
+
+	return nil
+}
+
+// firstHostCIDR returns the first host address of an IPv4 subnet with its
+// prefix length, e.g. "10.0.0.1/24" for "10.0.0.0/24". Subnets smaller
+// than /30 are rejected since they have no usable host addresses.
+func firstHostCIDR(subnet string) (string, error) {
+	_, ipNet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse subnet %s: %w", subnet, err)
+	}
+
+	ip := ipNet.IP.To4()
+	if ip == nil {
+		return "", fmt.Errorf("subnet %s is not IPv4", subnet)
+	}
+
+	ones, bits := ipNet.Mask.Size()
+	if bits-ones < 2 {
+		return "", fmt.Errorf("subnet %s has no usable host addresses", subnet)
+	}
+
+	// The network address has all host bits cleared, so incrementing the
+	// last octet cannot overflow.
+	host := make(net.IP, len(ip))
+	copy(host, ip)
+	host[3]++
+
+	return fmt.Sprintf("%s/%d", host, ones), nil
+}
+
+// normalizeMAC returns a validated MAC address in lowercase colon form.
+func normalizeMAC(mac string) string {
+	return strings.ToLower(strings.ReplaceAll(mac, "-", ":"))
+}
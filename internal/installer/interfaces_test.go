@@ -0,0 +1,234 @@
+package installer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+)
+
+// testHostNetwork is the public network of a Hetzner server (RFC 5737 documentation range).
+var testHostNetwork = HostNetwork{
+	Interface: "enp0s31f6",
+	PublicIP:  "203.0.113.45", // NOSONAR(go:S1313) RFC 5737 documentation range
+	Gateway:   "203.0.113.1",  // NOSONAR(go:S1313) RFC 5737 documentation range
+}
+
+// testNetworkConfig returns a NetworkConfig with the given bridge mode and default subnet.
+func testNetworkConfig(mode config.BridgeMode) config.NetworkConfig {
+	return config.NetworkConfig{
+		BridgeMode:    mode,
+		PrivateSubnet: buildSubnet(10, 0, 0, 0, 24),
+	}
+}
+
+func TestRenderInterfacesConfigInternal(t *testing.T) {
+	out, err := RenderInterfacesConfig(testNetworkConfig(config.BridgeModeInternal), testHostNetwork)
+
+	require.NoError(t, err)
+	assert.Contains(t, out, "auto lo\niface lo inet loopback\n")
+	assert.Contains(t, out, "auto enp0s31f6\niface enp0s31f6 inet static\n\taddress 203.0.113.45/32\n")
+	assert.Contains(t, out, "\tpointopoint 203.0.113.1\n")
+	assert.Contains(t, out, "auto vmbr1\niface vmbr1 inet static\n\taddress 10.0.0.1/24\n")
+	assert.Contains(t, out, "\tpost-up iptables -t nat -A POSTROUTING -s 10.0.0.0/24 -o enp0s31f6 -j MASQUERADE\n")
+	assert.Contains(t, out, "\tpost-down iptables -t nat -D POSTROUTING -s 10.0.0.0/24 -o enp0s31f6 -j MASQUERADE\n")
+	assert.NotContains(t, out, externalBridge)
+	assert.NotContains(t, out, routedBridge)
+}
+
+func TestRenderInterfacesConfigExternal(t *testing.T) {
+	out, err := RenderInterfacesConfig(testNetworkConfig(config.BridgeModeExternal), testHostNetwork)
+
+	require.NoError(t, err)
+	assert.Contains(t, out, "iface enp0s31f6 inet manual\n")
+	assert.Contains(t, out, "auto vmbr0\niface vmbr0 inet static\n\taddress 203.0.113.45/32\n")
+	assert.Contains(t, out, "\tbridge-ports enp0s31f6\n")
+	assert.NotContains(t, out, natBridge)
+	assert.NotContains(t, out, "MASQUERADE")
+}
+
+func TestRenderInterfacesConfigBoth(t *testing.T) {
+	out, err := RenderInterfacesConfig(testNetworkConfig(config.BridgeModeBoth), testHostNetwork)
+
+	require.NoError(t, err)
+	assert.Contains(t, out, "auto vmbr0\n")
+	assert.Contains(t, out, "auto vmbr1\n")
+	assert.Contains(t, out, "-o vmbr0 -j MASQUERADE\n")
+}
+
+func TestRenderInterfacesConfigInterfaceOverride(t *testing.T) {
+	network := testNetworkConfig(config.BridgeModeInternal)
+	network.InterfaceName = "eth1"
+
+	out, err := RenderInterfacesConfig(network, testHostNetwork)
+
+	require.NoError(t, err)
+	assert.Contains(t, out, "iface eth1 inet static\n")
+	assert.NotContains(t, out, testHostNetwork.Interface)
+}
+
+func TestRenderInterfacesConfigAdditionalSubnet(t *testing.T) {
+	tests := []struct {
+		name             string
+		additionalSubnet string
+		wantRouted       bool
+	}{
+		{"not set", "", false},
+		{"set /29", buildSubnet(203, 0, 113, 8, 29), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network := testNetworkConfig(config.BridgeModeInternal)
+			network.AdditionalSubnet = tt.additionalSubnet
+
+			out, err := RenderInterfacesConfig(network, testHostNetwork)
+
+			require.NoError(t, err)
+
+			if tt.wantRouted {
+				assert.Contains(t, out, "auto vmbr2\niface vmbr2 inet static\n\taddress 203.0.113.9/29\n\tbridge-ports none\n")
+			} else {
+				assert.NotContains(t, out, routedBridge)
+			}
+		})
+	}
+}
+
+func TestRenderInterfacesConfigBridgeMAC(t *testing.T) {
+	tests := []struct {
+		name   string
+		mode   config.BridgeMode
+		mac    string
+		stanza string
+	}{
+		{"external", config.BridgeModeExternal, "90:1b:0e:aa:bb:cc", "auto vmbr0\niface vmbr0 inet static\n"},
+		{"both", config.BridgeModeBoth, "90:1b:0e:aa:bb:cc", "auto vmbr0\niface vmbr0 inet static\n"},
+		{"internal", config.BridgeModeInternal, "90:1b:0e:aa:bb:cc", "auto enp0s31f6\niface enp0s31f6 inet static\n"},
+		{"dash form normalized", config.BridgeModeExternal, "90-1B-0E-AA-BB-CC", "auto vmbr0\niface vmbr0 inet static\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network := testNetworkConfig(tt.mode)
+			network.BridgeMAC = tt.mac
+
+			out, err := RenderInterfacesConfig(network, testHostNetwork)
+
+			require.NoError(t, err)
+			assert.Contains(t, out, tt.stanza+"\thwaddress ether 90:1b:0e:aa:bb:cc\n\taddress 203.0.113.45/32\n")
+			assert.Equal(t, 1, strings.Count(out, "hwaddress"))
+		})
+	}
+}
+
+func TestRenderInterfacesConfigNoBridgeMAC(t *testing.T) {
+	for _, mode := range []config.BridgeMode{config.BridgeModeInternal, config.BridgeModeExternal, config.BridgeModeBoth} {
+		t.Run(string(mode), func(t *testing.T) {
+			out, err := RenderInterfacesConfig(testNetworkConfig(mode), testHostNetwork)
+
+			require.NoError(t, err)
+			assert.NotContains(t, out, "hwaddress")
+		})
+	}
+}
+
+func TestRenderInterfacesConfigBackends(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend config.NetworkBackend
+		want    string
+		notWant string
+	}{
+		{"default", "", "auto vmbr0\niface vmbr0 inet static\n", "[Match]"},
+		{"ifupdown2", config.NetworkBackendIfupdown2, "auto vmbr0\niface vmbr0 inet static\n", "[Match]"},
+		{"networkd", config.NetworkBackendNetworkd, "[Match]\nName=vmbr0\n", "iface "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network := testNetworkConfig(config.BridgeModeBoth)
+			network.NetworkBackend = tt.backend
+
+			out, err := RenderInterfacesConfig(network, testHostNetwork)
+
+			require.NoError(t, err)
+			assert.Contains(t, out, tt.want)
+			assert.NotContains(t, out, tt.notWant)
+		})
+	}
+}
+
+func TestRenderInterfacesConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*config.NetworkConfig, *HostNetwork)
+		wantErr error
+	}{
+		{
+			name:    "no interface",
+			mutate:  func(_ *config.NetworkConfig, h *HostNetwork) { h.Interface = "" },
+			wantErr: ErrInterfaceEmpty,
+		},
+		{
+			name:    "invalid bridge mode",
+			mutate:  func(n *config.NetworkConfig, _ *HostNetwork) { n.BridgeMode = "bogus" },
+			wantErr: config.ErrBridgeModeInvalid,
+		},
+		{
+			name:    "invalid public IP",
+			mutate:  func(_ *config.NetworkConfig, h *HostNetwork) { h.PublicIP = "not-an-ip" },
+			wantErr: ErrPublicIPInvalid,
+		},
+		{
+			name:    "ipv6 gateway",
+			mutate:  func(_ *config.NetworkConfig, h *HostNetwork) { h.Gateway = "fe80::1" },
+			wantErr: ErrGatewayInvalid,
+		},
+		{
+			name:    "invalid private subnet",
+			mutate:  func(n *config.NetworkConfig, _ *HostNetwork) { n.PrivateSubnet = "invalid" },
+			wantErr: nil,
+		},
+		{
+			name:    "invalid network backend",
+			mutate:  func(n *config.NetworkConfig, _ *HostNetwork) { n.NetworkBackend = "netplan" },
+			wantErr: config.ErrNetworkBackendInvalid,
+		},
+		{
+			name:    "invalid bridge MAC",
+			mutate:  func(n *config.NetworkConfig, _ *HostNetwork) { n.BridgeMAC = "90:1b:0e" },
+			wantErr: config.ErrMACInvalid,
+		},
+		{
+			name:    "invalid additional subnet",
+			mutate:  func(n *config.NetworkConfig, _ *HostNetwork) { n.AdditionalSubnet = "invalid" },
+			wantErr: config.ErrAdditionalSubnetInvalid,
+		},
+		{
+			name:    "additional subnet without hosts",
+			mutate:  func(n *config.NetworkConfig, _ *HostNetwork) { n.AdditionalSubnet = buildSubnet(203, 0, 113, 8, 32) },
+			wantErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network := testNetworkConfig(config.BridgeModeInternal)
+			host := testHostNetwork
+			tt.mutate(&network, &host)
+
+			out, err := RenderInterfacesConfig(network, host)
+
+			require.Error(t, err)
+			assert.Empty(t, out)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
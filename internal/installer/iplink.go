@@ -0,0 +1,170 @@
+package installer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// Interface describes a network interface as reported by iproute2.
+type Interface struct {
+	// Name is the interface name (e.g., "enp0s31f6").
+	Name string
+
+	// MAC is the link-layer address (e.g., "90:1b:0e:aa:bb:cc").
+	MAC string
+
+	// State is the operational state (e.g., "UP", "DOWN", "UNKNOWN").
+	State string
+
+	// Addresses holds the interface addresses in CIDR notation.
+	// It is only populated by ParseIPAddrJSON.
+	Addresses []string
+}
+
+// ipLinkJSON is a single entry of "ip -j link show" or "ip -j addr show" output.
+type ipLinkJSON struct {
+	IfName    string `json:"ifname"`
+	Address   string `json:"address"`
+	OperState string `json:"operstate"`
+	LinkType  string `json:"link_type"`
+	AddrInfo  []struct {
+		Local     string `json:"local"`
+		PrefixLen int    `json:"prefixlen"`
+	} `json:"addr_info"`
+}
+
+// toInterface converts the entry to an Interface without addresses.
+func (l ipLinkJSON) toInterface() Interface {
+	return Interface{Name: l.IfName, MAC: l.Address, State: l.OperState}
+}
+
+// ParseIPLinkJSON parses the output of "ip -j link show" into interfaces,
+// in the order reported. Addresses are left empty.
+func ParseIPLinkJSON(data []byte) ([]Interface, error) {
+	var links []ipLinkJSON
+	if err := json.Unmarshal(data, &links); err != nil {
+		return nil, fmt.Errorf("failed to parse ip link JSON: %w", err)
+	}
+
+	interfaces := make([]Interface, 0, len(links))
+	for _, link := range links {
+		interfaces = append(interfaces, link.toInterface())
+	}
+
+	return interfaces, nil
+}
+
+// ParseIPAddrJSON parses the output of "ip -j addr show" into interfaces,
+// in the order reported, including their addresses in CIDR notation.
+func ParseIPAddrJSON(data []byte) ([]Interface, error) {
+	var links []ipLinkJSON
+	if err := json.Unmarshal(data, &links); err != nil {
+		return nil, fmt.Errorf("failed to parse ip addr JSON: %w", err)
+	}
+
+	interfaces := make([]Interface, 0, len(links))
+
+	for _, link := range links {
+		iface := link.toInterface()
+
+		for _, addr := range link.AddrInfo {
+			if addr.Local != "" {
+				iface.Addresses = append(iface.Addresses, addr.Local+"/"+strconv.Itoa(addr.PrefixLen))
+			}
+		}
+
+		interfaces = append(interfaces, iface)
+	}
+
+	return interfaces, nil
+}
+
+// DetectInterfaces returns the non-loopback network interfaces of the system.
+//
+// It prefers the JSON output of "ip -j link show" and falls back to parsing
+// "ip -o link show" text when the installed iproute2 does not support JSON
+// (the command fails or prints something that is not JSON).
+func DetectInterfaces(ctx context.Context, executor exec.Executor) ([]Interface, error) {
+	if out, err := executor.RunWithOutput(ctx, "ip", "-j", "link", "show"); err == nil {
+		var links []ipLinkJSON
+		if json.Unmarshal([]byte(out), &links) == nil {
+			interfaces := make([]Interface, 0, len(links))
+
+			for _, link := range links {
+				if link.LinkType != "loopback" {
+					interfaces = append(interfaces, link.toInterface())
+				}
+			}
+
+			return interfaces, nil
+		}
+	}
+
+	lines, err := exec.RunLines(ctx, executor, "ip", "-o", "link", "show")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network interfaces: %w", err)
+	}
+
+	return parseIPLinkText(lines), nil
+}
+
+// VerifyInterfaceExists reports whether the network interface name exists,
+// checked with "ip link show <name>" through the Executor.
+//
+// Exit status 1 from ip means the device does not exist; any other failure
+// (e.g., ip not found or the context canceled) is returned as an error.
+func VerifyInterfaceExists(ctx context.Context, executor exec.Executor, name string) (bool, error) {
+	err := executor.Run(ctx, "ip", "link", "show", name)
+	if err == nil {
+		return true, nil
+	}
+
+	if exitedWith(err, 1) {
+		return false, nil
+	}
+
+	return false, fmt.Errorf("failed to check interface %s: %w", name, err)
+}
+
+// parseIPLinkText parses "ip -o link show" lines, skipping loopback links.
+//
+// Each line looks like:
+//
+//	2: eth0: <BROADCAST,UP> mtu 1500 ... state UP mode DEFAULT ...\    link/ether 90:1b:0e:aa:bb:cc brd ff:ff:ff:ff:ff:ff
+func parseIPLinkText(lines []string) []Interface {
+	interfaces := make([]Interface, 0, len(lines))
+
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		// Names of stacked links carry their parent ("veth0@if3"); drop it.
+		name, _, _ := strings.Cut(strings.TrimSuffix(fields[1], ":"), "@")
+		iface := Interface{Name: name}
+		loopback := false
+
+		for i := 2; i < len(fields)-1; i++ {
+			switch fields[i] {
+			case "state":
+				iface.State = fields[i+1]
+			case "link/ether":
+				iface.MAC = fields[i+1]
+			case "link/loopback":
+				loopback = true
+			}
+		}
+
+		if !loopback {
+			interfaces = append(interfaces, iface)
+		}
+	}
+
+	return interfaces
+}
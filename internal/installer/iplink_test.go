@@ -0,0 +1,196 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// Test command keys for MockExecutor.
+const (
+	cmdIPLinkJSON = "ip -j link show"
+	cmdIPLinkText = "ip -o link show"
+)
+
+// testIPLinkJSON is trimmed "ip -j link show" output with a loopback, an
+// active uplink and an unused second NIC.
+const testIPLinkJSON = `[
+  {"ifindex":1,"ifname":"lo","flags":["LOOPBACK","UP","LOWER_UP"],"mtu":65536,
+   "operstate":"UNKNOWN","link_type":"loopback","address":"00:00:00:00:00:00","broadcast":"00:00:00:00:00:00"},
+  {"ifindex":2,"ifname":"enp0s31f6","flags":["BROADCAST","MULTICAST","UP","LOWER_UP"],"mtu":1500,
+   "operstate":"UP","link_type":"ether","address":"90:1b:0e:aa:bb:cc","broadcast":"ff:ff:ff:ff:ff:ff"},
+  {"ifindex":3,"ifname":"enp1s0","flags":["BROADCAST","MULTICAST"],"mtu":1500,
+   "operstate":"DOWN","link_type":"ether","address":"90:1b:0e:dd:ee:ff","broadcast":"ff:ff:ff:ff:ff:ff"}
+]`
+
+// testIPAddrJSON is trimmed "ip -j addr show" output for the uplink.
+const testIPAddrJSON = `[
+  {"ifindex":2,"ifname":"enp0s31f6","operstate":"UP","link_type":"ether","address":"90:1b:0e:aa:bb:cc",
+   "addr_info":[
+     {"family":"inet","local":"198.51.100.10","prefixlen":26,"scope":"global"},
+     {"family":"inet6","local":"2001:db8::2","prefixlen":64,"scope":"global"}
+   ]}
+]`
+
+// testIPLinkText is "ip -o link show" output matching testIPLinkJSON.
+const testIPLinkText = `1: lo: <LOOPBACK,UP,LOWER_UP> mtu 65536 qdisc noqueue state UNKNOWN mode DEFAULT group default qlen 1000\    link/loopback 00:00:00:00:00:00 brd 00:00:00:00:00:00
+2: enp0s31f6: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1500 qdisc fq_codel state UP mode DEFAULT group default qlen 1000\    link/ether 90:1b:0e:aa:bb:cc brd ff:ff:ff:ff:ff:ff
+3: enp1s0: <BROADCAST,MULTICAST> mtu 1500 qdisc noop state DOWN mode DEFAULT group default qlen 1000\    link/ether 90:1b:0e:dd:ee:ff brd ff:ff:ff:ff:ff:ff
+`
+
+// testPhysicalInterfaces are the non-loopback interfaces in the link fixtures.
+var testPhysicalInterfaces = []Interface{
+	{Name: "enp0s31f6", MAC: "90:1b:0e:aa:bb:cc", State: "UP"},
+	{Name: "enp1s0", MAC: "90:1b:0e:dd:ee:ff", State: "DOWN"},
+}
+
+func TestParseIPLinkJSON(t *testing.T) {
+	interfaces, err := ParseIPLinkJSON([]byte(testIPLinkJSON))
+
+	require.NoError(t, err)
+	require.Len(t, interfaces, 3)
+	assert.Equal(t, Interface{Name: "lo", MAC: "00:00:00:00:00:00", State: "UNKNOWN"}, interfaces[0])
+	assert.Equal(t, testPhysicalInterfaces, interfaces[1:])
+}
+
+func TestParseIPAddrJSON(t *testing.T) {
+	interfaces, err := ParseIPAddrJSON([]byte(testIPAddrJSON))
+
+	require.NoError(t, err)
+	require.Len(t, interfaces, 1)
+	assert.Equal(t, "enp0s31f6", interfaces[0].Name)
+	assert.Equal(t, "UP", interfaces[0].State)
+	assert.Equal(t, []string{
+		"198.51.100.10/26", // NOSONAR(go:S1313) RFC 5737 documentation range
+		"2001:db8::2/64",
+	}, interfaces[0].Addresses)
+}
+
+func TestParseIPJSONMalformed(t *testing.T) {
+	inputs := map[string]string{
+		"truncated":  `[{"ifname":"eth0"`,
+		"text":       "1: lo: <LOOPBACK> mtu 65536",
+		"not a list": `{"ifname":"eth0"}`,
+	}
+
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			links, err := ParseIPLinkJSON([]byte(input))
+			require.Error(t, err)
+			assert.Nil(t, links)
+
+			addrs, err := ParseIPAddrJSON([]byte(input))
+			require.Error(t, err)
+			assert.Nil(t, addrs)
+		})
+	}
+}
+
+func TestParseIPJSONEmptyList(t *testing.T) {
+	interfaces, err := ParseIPLinkJSON([]byte("[]"))
+
+	require.NoError(t, err)
+	assert.Empty(t, interfaces)
+}
+
+func TestDetectInterfacesPrefersJSON(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	mock.SetOutput(cmdIPLinkJSON, testIPLinkJSON)
+
+	interfaces, err := DetectInterfaces(context.Background(), mock)
+
+	require.NoError(t, err)
+	assert.Equal(t, testPhysicalInterfaces, interfaces)
+	assert.True(t, mock.WasNotCalledWith("ip", "-o", "link", "show"))
+}
+
+func TestDetectInterfacesFallsBackToText(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		err    error
+	}{
+		{"json unsupported", "", errors.New("exit status 255")},
+		{"json option ignored", testIPLinkText, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := exec.NewMockExecutor()
+			mock.SetOutput(cmdIPLinkJSON, tt.output)
+			mock.SetError(cmdIPLinkJSON, tt.err)
+			mock.SetOutput(cmdIPLinkText, testIPLinkText)
+
+			interfaces, err := DetectInterfaces(context.Background(), mock)
+
+			require.NoError(t, err)
+			assert.Equal(t, testPhysicalInterfaces, interfaces)
+		})
+	}
+}
+
+func TestDetectInterfacesTextStripsParent(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	mock.SetError(cmdIPLinkJSON, errors.New("exit status 255"))
+	mock.SetOutput(cmdIPLinkText,
+		`4: veth0@if3: <BROADCAST,UP> mtu 1500 qdisc noqueue state UP mode DEFAULT\    link/ether 02:42:ac:11:00:02 brd ff:ff:ff:ff:ff:ff`)
+
+	interfaces, err := DetectInterfaces(context.Background(), mock)
+
+	require.NoError(t, err)
+	assert.Equal(t, []Interface{{Name: "veth0", MAC: "02:42:ac:11:00:02", State: "UP"}}, interfaces)
+}
+
+func TestDetectInterfacesBothFail(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	mock.SetError(cmdIPLinkJSON, errors.New("exit status 255"))
+	mock.SetError(cmdIPLinkText, errors.New("ip: not found"))
+
+	_, err := DetectInterfaces(context.Background(), mock)
+
+	assert.ErrorContains(t, err, "failed to list network interfaces")
+}
+
+func TestVerifyInterfaceExists(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"interface present", nil, true},
+		{"device does not exist", exitStatusError{code: 1}, false},
+		{"wrapped exit status", fmt.Errorf("remote: %w", exitStatusError{code: 1}), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := exec.NewMockExecutor()
+			mock.SetError("ip link show eth0", tt.err)
+
+			exists, err := VerifyInterfaceExists(context.Background(), mock, "eth0")
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, exists)
+			assert.True(t, mock.WasCalledWith("ip", "link", "show", "eth0"))
+		})
+	}
+}
+
+func TestVerifyInterfaceExistsUnexpectedError(t *testing.T) {
+	for _, cmdErr := range []error{errors.New("executable file not found"), exitStatusError{code: 255}} {
+		mock := exec.NewMockExecutor()
+		mock.SetError("ip link show eth0", cmdErr)
+
+		exists, err := VerifyInterfaceExists(context.Background(), mock, "eth0")
+
+		require.ErrorIs(t, err, cmdErr)
+		assert.ErrorContains(t, err, "failed to check interface eth0")
+		assert.False(t, exists)
+	}
+}
@@ -0,0 +1,148 @@
+package installer
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// journalIdentifier is the SYSLOG_IDENTIFIER of journal entries, so they
+// can be queried with "journalctl -t pve-install".
+const journalIdentifier = "pve-install"
+
+// journalSocketPath is the journald native protocol socket. It is a variable
+// so tests can point it elsewhere.
+var journalSocketPath = "/run/systemd/journal/socket"
+
+// journalWriteTimeout bounds each JournalSink.Write, so a stuck journald
+// delays a log call by at most this long instead of blocking the installer.
+// It is a variable so tests can shorten it.
+var journalWriteTimeout = 2 * time.Second
+
+// ErrJournalUnavailable is returned by NewJournalSink when neither the
+// journal socket nor a running systemd-journald is found.
+var ErrJournalUnavailable = errors.New("systemd journal is not available")
+
+// JournalSink writes log entries to the systemd journal, so operators can
+// query them with journalctl alongside the log file.
+//
+// It sends entries over the journald native socket when it is reachable, and
+// otherwise pipes them to systemd-cat through an Executor. Writes are
+// best-effort: each is given at most journalWriteTimeout, and failures are
+// ignored and never interrupt the installation.
+type JournalSink struct {
+	// conn is the native journal socket; nil when systemd-cat is used.
+	conn net.Conn
+
+	// executor runs systemd-cat when conn is nil.
+	executor exec.Executor
+}
+
+// NewJournalSink connects to the systemd journal: through the native socket
+// if possible, otherwise through systemd-cat run by executor, provided
+// systemd-journald is active. Returns ErrJournalUnavailable when neither
+// works, in which case logging should continue file-only.
+func NewJournalSink(ctx context.Context, executor exec.Executor) (*JournalSink, error) {
+	addr := &net.UnixAddr{Name: journalSocketPath, Net: "unixgram"}
+	if conn, err := net.DialUnix("unixgram", nil, addr); err == nil {
+		return &JournalSink{conn: conn}, nil
+	}
+
+	if executor == nil {
+		return nil, ErrJournalUnavailable
+	}
+
+	if err := executor.Run(ctx, "systemctl", "is-active", "--quiet", "systemd-journald.service"); err != nil {
+		return nil, ErrJournalUnavailable
+	}
+
+	return &JournalSink{executor: executor}, nil
+}
+
+// journalPriority returns the syslog priority of level: 6 (info),
+// 4 (warning) or 3 (err).
+func journalPriority(level Level) int {
+	switch level {
+	case LevelWarn:
+		return 4
+	case LevelError:
+		return 3
+	default:
+		return 6
+	}
+}
+
+// systemdCatPriority returns the systemd-cat priority name of level.
+func systemdCatPriority(level Level) string {
+	switch level {
+	case LevelWarn:
+		return "warning"
+	case LevelError:
+		return "err"
+	default:
+		return "info"
+	}
+}
+
+// Write sends msg to the journal at the priority of level.
+// It is a no-op if the JournalSink is nil.
+func (j *JournalSink) Write(level Level, msg string) {
+	if j == nil {
+		return
+	}
+
+	if j.conn != nil {
+		//nolint:errcheck // journal output is best-effort
+		j.conn.SetWriteDeadline(time.Now().Add(journalWriteTimeout))
+		//nolint:errcheck // journal output is best-effort
+		j.conn.Write(encodeJournalEntry(level, msg))
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), journalWriteTimeout)
+	defer cancel()
+
+	//nolint:errcheck // journal output is best-effort
+	j.executor.RunWithStdin(ctx, msg, "systemd-cat",
+		"--identifier="+journalIdentifier, "--priority="+systemdCatPriority(level))
+}
+
+// Close closes the journal socket. It is a no-op for systemd-cat and for a
+// nil JournalSink.
+func (j *JournalSink) Close() error {
+	if j == nil || j.conn == nil {
+		return nil
+	}
+
+	return j.conn.Close()
+}
+
+// encodeJournalEntry encodes an entry in the journald native protocol.
+// Single-line messages use the "KEY=value" form; a message containing a
+// newline uses the binary form, with its length as a little-endian uint64.
+func encodeJournalEntry(level Level, msg string) []byte {
+	var b bytes.Buffer
+
+	b.WriteString("PRIORITY=" + strconv.Itoa(journalPriority(level)) + "\n")
+	b.WriteString("SYSLOG_IDENTIFIER=" + journalIdentifier + "\n")
+
+	if !strings.Contains(msg, "\n") {
+		b.WriteString("MESSAGE=" + msg + "\n")
+
+		return b.Bytes()
+	}
+
+	b.WriteString("MESSAGE\n")
+	binary.Write(&b, binary.LittleEndian, uint64(len(msg))) //nolint:errcheck // writes to a bytes.Buffer cannot fail
+	b.WriteString(msg + "\n")
+
+	return b.Bytes()
+}
@@ -0,0 +1,158 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// cmdJournaldActive is the MockExecutor key of the journald liveness check.
+const cmdJournaldActive = "systemctl is-active --quiet systemd-journald.service"
+
+// useJournalSocket points journalSocketPath at path for the duration of the test.
+func useJournalSocket(t *testing.T, path string) {
+	t.Helper()
+
+	orig := journalSocketPath
+	journalSocketPath = path
+
+	t.Cleanup(func() { journalSocketPath = orig })
+}
+
+func TestJournalSinkFallbackPriorities(t *testing.T) {
+	useJournalSocket(t, filepath.Join(t.TempDir(), "missing.sock"))
+
+	mock := exec.NewMockExecutor()
+
+	sink, err := NewJournalSink(context.Background(), mock)
+	require.NoError(t, err)
+
+	logger, logPath := createTestLogger(t, false)
+	logger.SetJournal(sink)
+
+	logger.Info("installing %s", "proxmox")
+	logger.Warn("disk %s is small", "sda")
+	logger.Error("failed")
+
+	var journal []exec.ExecutedCommand
+
+	for _, cmd := range mock.Commands() {
+		if cmd.Name == "systemd-cat" {
+			journal = append(journal, cmd)
+		}
+	}
+
+	require.Len(t, journal, 3)
+
+	for i, want := range []struct{ priority, msg string }{
+		{"info", "installing proxmox"},
+		{"warning", "disk sda is small"},
+		{"err", "failed"},
+	} {
+		assert.Equal(t, []string{"--identifier=pve-install", "--priority=" + want.priority}, journal[i].Args)
+		assert.Equal(t, want.msg, journal[i].Stdin)
+	}
+
+	content, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "installing proxmox", "file logging continues alongside the journal")
+}
+
+func TestJournalSinkFallbackWriteTimeout(t *testing.T) {
+	useJournalSocket(t, filepath.Join(t.TempDir(), "missing.sock"))
+
+	orig := journalWriteTimeout
+	journalWriteTimeout = 20 * time.Millisecond
+
+	t.Cleanup(func() { journalWriteTimeout = orig })
+
+	mock := exec.NewMockExecutor()
+	mock.SetDelay("systemd-cat --identifier=pve-install --priority=info", time.Minute)
+
+	sink, err := NewJournalSink(context.Background(), mock)
+	require.NoError(t, err)
+
+	start := time.Now()
+	sink.Write(LevelInfo, "journald is stuck")
+
+	assert.Less(t, time.Since(start), 10*time.Second, "a stuck systemd-cat does not block logging")
+	assert.True(t, mock.WasCalledWith("systemd-cat", "--identifier=pve-install", "--priority=info"))
+}
+
+func TestJournalSinkUnavailable(t *testing.T) {
+	useJournalSocket(t, filepath.Join(t.TempDir(), "missing.sock"))
+
+	mock := exec.NewMockExecutor()
+	mock.SetError(cmdJournaldActive, errors.New("exit status 3"))
+
+	sink, err := NewJournalSink(context.Background(), mock)
+	require.ErrorIs(t, err, ErrJournalUnavailable)
+	assert.Nil(t, sink)
+
+	logger, logPath := createTestLogger(t, false)
+	logger.SetJournal(sink)
+	logger.Info(testLogMessage)
+
+	content, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), testLogMessage)
+	assert.True(t, mock.WasNeverCalled("systemd-cat"))
+}
+
+func TestJournalSinkUnavailableWithoutExecutor(t *testing.T) {
+	useJournalSocket(t, filepath.Join(t.TempDir(), "missing.sock"))
+
+	_, err := NewJournalSink(context.Background(), nil)
+	assert.ErrorIs(t, err, ErrJournalUnavailable)
+}
+
+func TestJournalSinkNativeSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "journal.sock")
+	useJournalSocket(t, socketPath)
+
+	listener, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { listener.Close() })
+
+	mock := exec.NewMockExecutor()
+
+	sink, err := NewJournalSink(context.Background(), mock)
+	require.NoError(t, err)
+
+	logger, _ := createTestLogger(t, false)
+	logger.SetJournal(sink)
+	logger.Warn("disk %s is small", "sda")
+
+	buf := make([]byte, 4096)
+	n, _, err := listener.ReadFromUnix(buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, "PRIORITY=4\nSYSLOG_IDENTIFIER=pve-install\nMESSAGE=disk sda is small\n", string(buf[:n]))
+	assert.Equal(t, 0, mock.CommandCount(), "native socket does not use the executor")
+}
+
+func TestEncodeJournalEntryMultiline(t *testing.T) {
+	entry := string(encodeJournalEntry(LevelError, "line one\nline two"))
+
+	assert.True(t, strings.HasPrefix(entry, "PRIORITY=3\nSYSLOG_IDENTIFIER=pve-install\nMESSAGE\n"))
+	assert.True(t, strings.HasSuffix(entry, "line one\nline two\n"))
+	assert.Contains(t, entry, "\x11\x00\x00\x00\x00\x00\x00\x00", "message length as little-endian uint64")
+}
+
+func TestJournalSinkNil(t *testing.T) {
+	var sink *JournalSink
+
+	assert.NotPanics(t, func() { sink.Write(LevelInfo, "ignored") })
+	assert.NoError(t, sink.Close())
+}
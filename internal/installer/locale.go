@@ -0,0 +1,126 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// Paths of the Debian keyboard and locale configuration files.
+const (
+	keyboardConfigPath = "/etc/default/keyboard"
+	localeGenPath      = "/etc/locale.gen"
+)
+
+// defaultLocale is available without running locale-gen.
+const defaultLocale = "C.UTF-8"
+
+// LocaleStep sets the console keyboard layout and the system locale.
+//
+// The keyboard layout is written to /etc/default/keyboard and saved for the
+// console with setupcon. The locale is added to /etc/locale.gen if it is not
+// listed there yet, generated, and made the default LANG with update-locale.
+// Empty System.Keyboard or System.Locale values leave the respective setting
+// unchanged.
+type LocaleStep struct {
+	config   *config.Config
+	executor exec.Executor
+	logger   *Logger
+}
+
+// NewLocaleStep creates a LocaleStep for the given configuration.
+func NewLocaleStep(cfg *config.Config, executor exec.Executor, logger *Logger) *LocaleStep {
+	return &LocaleStep{config: cfg, executor: executor, logger: logger}
+}
+
+// Name returns the step name.
+func (s *LocaleStep) Name() string { return "Configure keyboard and locale" }
+
+// Execute applies the configured keyboard layout and locale.
+func (s *LocaleStep) Execute(ctx context.Context) error {
+	if keyboard := s.config.System.Keyboard; keyboard != "" {
+		if err := s.configureKeyboard(ctx, keyboard); err != nil {
+			return err
+		}
+	}
+
+	if locale := s.config.System.Locale; locale != "" {
+		if err := s.configureLocale(ctx, locale); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// configureKeyboard writes the XKB layout for keyboard and saves the console setup.
+func (s *LocaleStep) configureKeyboard(ctx context.Context, keyboard string) error {
+	layout, ok := config.LookupXKBLayout(keyboard)
+	if !ok {
+		return fmt.Errorf("%w: %q", config.ErrKeyboardInvalid, keyboard)
+	}
+
+	s.logger.Log("Setting keyboard layout to %s", keyboard)
+
+	if err := s.executor.RunWithStdin(ctx, renderKeyboardConfig(layout), "tee", keyboardConfigPath); err != nil {
+		return fmt.Errorf("failed to write %s: %w", keyboardConfigPath, err)
+	}
+
+	if err := s.executor.Run(ctx, "setupcon", "--save-only"); err != nil {
+		return fmt.Errorf("failed to save console keyboard setup: %w", err)
+	}
+
+	return nil
+}
+
+// enableLocale appends locale to /etc/locale.gen unless the file already
+// lists it, so that re-running the step does not duplicate the entry.
+// grep fails both when the line is missing and when the file does not
+// exist; in either case the line is appended, creating the file if needed.
+func (s *LocaleStep) enableLocale(ctx context.Context, locale string) error {
+	entry := locale + " UTF-8"
+
+	if err := s.executor.Run(ctx, "grep", "-qxF", entry, localeGenPath); err == nil {
+		return nil
+	}
+
+	if err := s.executor.RunWithStdin(ctx, entry+"\n", "tee", "-a", localeGenPath); err != nil {
+		return fmt.Errorf("failed to enable %s in %s: %w", locale, localeGenPath, err)
+	}
+
+	return nil
+}
+
+// configureLocale generates locale unless it is built in and makes it the default.
+func (s *LocaleStep) configureLocale(ctx context.Context, locale string) error {
+	if err := config.ValidateLocale(locale); err != nil {
+		return fmt.Errorf("%w: %q", err, locale)
+	}
+
+	s.logger.Log("Setting locale to %s", locale)
+
+	if locale != defaultLocale {
+		if err := s.enableLocale(ctx, locale); err != nil {
+			return err
+		}
+
+		if err := s.executor.Run(ctx, "locale-gen"); err != nil {
+			return fmt.Errorf("failed to generate locale %s: %w", locale, err)
+		}
+	}
+
+	if err := s.executor.Run(ctx, "update-locale", "LANG="+locale); err != nil {
+		return fmt.Errorf("failed to set default locale: %w", err)
+	}
+
+	return nil
+}
+
+// renderKeyboardConfig returns /etc/default/keyboard for layout on a
+// standard 105-key PC keyboard.
+func renderKeyboardConfig(layout config.XKBLayout) string {
+	return fmt.Sprintf("XKBMODEL=\"pc105\"\nXKBLAYOUT=%q\nXKBVARIANT=%q\nXKBOPTIONS=\"\"\nBACKSPACE=\"guess\"\n",
+		layout.Layout, layout.Variant)
+}
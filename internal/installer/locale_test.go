@@ -0,0 +1,118 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+)
+
+func TestLocaleStepName(t *testing.T) {
+	step, _ := newTestStep(NewLocaleStep, newValidTestConfig())
+
+	assert.Equal(t, "Configure keyboard and locale", step.Name())
+}
+
+func TestLocaleStepRecordsCommands(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.System.Keyboard = "fr-ch"
+	cfg.System.Locale = "fr_CH.UTF-8"
+
+	step, mock := newTestStep(NewLocaleStep, cfg)
+	mock.SetError("grep -qxF fr_CH.UTF-8 UTF-8 "+localeGenPath, errors.New("exit status 1"))
+
+	require.NoError(t, step.Execute(context.Background()))
+
+	commands := mock.Commands()
+	require.Len(t, commands, 6)
+	assert.Equal(t, "tee "+keyboardConfigPath, commands[0].String())
+	assert.Contains(t, commands[0].Stdin, `XKBLAYOUT="ch"`)
+	assert.Contains(t, commands[0].Stdin, `XKBVARIANT="fr"`)
+	assert.Equal(t, "setupcon --save-only", commands[1].String())
+	assert.Equal(t, "tee -a "+localeGenPath, commands[3].String())
+	assert.Equal(t, "fr_CH.UTF-8 UTF-8\n", commands[3].Stdin)
+	assert.Equal(t, "locale-gen", commands[4].String())
+	assert.Equal(t, "update-locale LANG=fr_CH.UTF-8", commands[5].String())
+}
+
+func TestLocaleStepListedLocaleNotAppended(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.System.Keyboard = ""
+	cfg.System.Locale = "de_DE.UTF-8"
+
+	step, mock := newTestStep(NewLocaleStep, cfg)
+
+	require.NoError(t, step.Execute(context.Background()))
+
+	assert.True(t, mock.WasCalledWith("grep", "-qxF", "de_DE.UTF-8 UTF-8", localeGenPath))
+	assert.False(t, mock.WasCalledWith("tee", "-a", localeGenPath), "an existing entry is not duplicated")
+	assert.True(t, mock.WasCalledWith("locale-gen"))
+}
+
+func TestLocaleStepBuiltInLocaleSkipsGeneration(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.System.Keyboard = ""
+	cfg.System.Locale = "C.UTF-8"
+
+	step, mock := newTestStep(NewLocaleStep, cfg)
+
+	require.NoError(t, step.Execute(context.Background()))
+
+	commands := mock.Commands()
+	require.Len(t, commands, 1)
+	assert.Equal(t, "update-locale LANG=C.UTF-8", commands[0].String())
+}
+
+func TestLocaleStepEmptyRunsNothing(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.System.Keyboard = ""
+	cfg.System.Locale = ""
+
+	step, mock := newTestStep(NewLocaleStep, cfg)
+
+	require.NoError(t, step.Execute(context.Background()))
+	assert.Zero(t, mock.CommandCount())
+}
+
+func TestLocaleStepRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		keyboard string
+		locale   string
+		wantErr  error
+	}{
+		{"keyboard", "us", "", config.ErrKeyboardInvalid},
+		{"locale", "", "en_US", config.ErrLocaleInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newValidTestConfig()
+			cfg.System.Keyboard = tt.keyboard
+			cfg.System.Locale = tt.locale
+
+			step, mock := newTestStep(NewLocaleStep, cfg)
+
+			require.ErrorIs(t, step.Execute(context.Background()), tt.wantErr)
+			assert.Zero(t, mock.CommandCount())
+		})
+	}
+}
+
+func TestLocaleStepLocaleGenFailure(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.System.Keyboard = ""
+	cfg.System.Locale = "de_DE.UTF-8"
+
+	step, mock := newTestStep(NewLocaleStep, cfg)
+	mock.SetError("locale-gen", errors.New("exit status 1"))
+
+	err := step.Execute(context.Background())
+
+	require.ErrorContains(t, err, "failed to generate locale de_DE.UTF-8")
+	assert.Equal(t, 2, mock.CommandCount(), "default locale not set after a failed generation")
+}
@@ -1,10 +1,15 @@
 package installer
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
 )
 
 // Default log file paths in priority order.
@@ -18,10 +23,41 @@ const (
 	fallbackLogPath = "/tmp/proxmox-install.log"
 )
 
-// Logger provides thread-safe logging to file with optional stdout output.
+// Level is the severity of a log entry.
+type Level int
+
+// Log levels in increasing severity.
+const (
+	// LevelInfo is used for normal progress messages.
+	LevelInfo Level = iota
+	// LevelWarn is used for recoverable problems.
+	LevelWarn
+	// LevelError is used for failures.
+	LevelError
+)
+
+// String returns the upper-case name of the level (e.g., "WARN").
+func (lv Level) String() string {
+	switch lv {
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int(lv))
+	}
+}
+
+// ErrLoggerNotOpen is returned by operations that need an open log file.
+var ErrLoggerNotOpen = errors.New("logger is not open")
+
+// Logger provides thread-safe logging to file with optional console output.
 //
-// Logger writes timestamped log entries to a file and optionally echoes them to stdout
-// when verbose mode is enabled. It uses ISO 8601 timestamps for consistent log formatting.
+// Logger writes timestamped log entries to a file and optionally echoes them to
+// stdout (info) or stderr (warnings and errors) when verbose mode is enabled.
+// It uses ISO 8601 timestamps for consistent log formatting.
 //
 // Logger is safe for concurrent use. All methods use mutex locking to ensure
 // thread-safe access to the underlying file handle.
@@ -41,11 +77,28 @@ type Logger struct {
 	// It is nil if the logger has not been initialized or has been closed.
 	file *os.File
 
-	// verbose enables output to stdout in addition to the log file.
-	// When true, all log entries are also written to stdout.
+	// verbose enables console output in addition to the log file.
+	// When true, info entries are also written to stdout and warning and
+	// error entries to stderr.
 	verbose bool
 
-	// mu protects concurrent access to the file handle.
+	// stdout and stderr receive verbose console output.
+	// When nil, os.Stdout and os.Stderr are used.
+	stdout io.Writer
+	stderr io.Writer
+
+	// observer receives every log entry. It is nil when no observer is registered.
+	observer Observer
+
+	// journal receives every log entry in addition to the file. It is nil
+	// when journal logging is not enabled.
+	journal *JournalSink
+
+	// secrets are replaced with config.RedactedPlaceholder in every entry,
+	// see SetSecrets.
+	secrets []string
+
+	// mu protects concurrent access to the file handle and observer.
 	mu sync.Mutex
 }
 
@@ -65,7 +118,21 @@ type Logger struct {
 //
 // Returns an error if neither log path is writable.
 func NewLogger(verbose bool) (*Logger, error) {
-	return newLoggerWithPaths(verbose, []string{defaultLogPath, fallbackLogPath})
+	return NewLoggerWithFallbacks(verbose, []string{defaultLogPath, fallbackLogPath})
+}
+
+// NewLoggerStrict creates a new Logger using only the primary log path.
+//
+// Unlike NewLogger, it does not fall back to /tmp/proxmox-install.log when
+// /var/log/proxmox-install.log is not writable. Automated environments can use
+// it to detect that logs would not end up where operators expect them.
+//
+// Parameters:
+//   - verbose: when true, log entries will also be written to stdout
+//
+// Returns an error if the primary log path is not writable.
+func NewLoggerStrict(verbose bool) (*Logger, error) {
+	return NewLoggerWithFallbacks(verbose, []string{defaultLogPath})
 }
 
 // NewLoggerWithPath creates a Logger with a custom log file path.
@@ -104,15 +171,26 @@ func NewLoggerWithPath(path string, verbose bool) (*Logger, error) {
 	return &Logger{file: file, verbose: verbose}, nil
 }
 
-// newLoggerWithPaths creates a Logger using the provided paths in order.
+// NewLoggerWithFallbacks creates a Logger using the first writable path from
+// an ordered list.
+//
+// NewLogger uses it with /var/log/proxmox-install.log followed by
+// /tmp/proxmox-install.log. Callers in containerized or read-only-root
+// environments can supply their own list instead, e.g. a mounted volume
+// followed by /tmp.
 //
-// This is an internal helper function that allows testing the path fallback logic
-// without requiring access to system directories like /var/log.
+// Each path is opened like in NewLogger (created if missing, appended to,
+// 0600 permissions); parent directories are not created. The first path that
+// opens successfully is used and the remaining paths are ignored; LogPath
+// reports which one was chosen.
 //
-// The function tries each path in order, returning a Logger using the first
-// path that can be opened successfully. If all paths fail, it returns an error
-// wrapping the last encountered error.
-func newLoggerWithPaths(verbose bool, paths []string) (*Logger, error) {
+// Parameters:
+//   - verbose: when true, log entries will also be written to stdout
+//   - paths: candidate log file paths, in order of preference
+//
+// Returns an error if paths is empty, or an error wrapping the last failure
+// if none of the paths is writable.
+func NewLoggerWithFallbacks(verbose bool, paths []string) (*Logger, error) {
 	if len(paths) == 0 {
 		return nil, fmt.Errorf("failed to open log file: no paths provided")
 	}
@@ -124,7 +202,7 @@ func newLoggerWithPaths(verbose bool, paths []string) (*Logger, error) {
 	for _, path := range paths {
 		var err error
 
-		//nolint:gosec // G304: paths are controlled constants in production
+		//nolint:gosec // G304: paths are chosen by the caller, constants for NewLogger
 		file, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
 		if err == nil {
 			break
@@ -143,6 +221,8 @@ func newLoggerWithPaths(verbose bool, paths []string) (*Logger, error) {
 // Log writes a formatted message to the log file with an ISO 8601 timestamp.
 //
 // If verbose mode is enabled, the message is also printed to stdout.
+// Warn and Error entries are printed to stderr instead, so they stay
+// visible when stdout is piped.
 // The format string and args follow fmt.Sprintf conventions.
 //
 // Example output format:
@@ -155,8 +235,81 @@ func newLoggerWithPaths(verbose bool, paths []string) (*Logger, error) {
 // to the file are intentionally ignored to avoid interrupting the
 // installation process.
 //
+// Log is equivalent to Info.
+//
 //nolint:goprintffuncname // Log is the intended API name per project spec
 func (l *Logger) Log(format string, args ...interface{}) {
+	l.logAt(LevelInfo, format, args...)
+}
+
+// Info writes a formatted message at LevelInfo. The output format is identical to Log.
+func (l *Logger) Info(format string, args ...interface{}) {
+	l.logAt(LevelInfo, format, args...)
+}
+
+// Warn writes a formatted message at LevelWarn, prefixed with "WARN: ".
+func (l *Logger) Warn(format string, args ...interface{}) {
+	l.logAt(LevelWarn, format, args...)
+}
+
+// Error writes a formatted message at LevelError, prefixed with "ERROR: ".
+func (l *Logger) Error(format string, args ...interface{}) {
+	l.logAt(LevelError, format, args...)
+}
+
+// SetOutput sets the writers used for verbose console output: info entries
+// go to stdout, warning and error entries to stderr. A nil writer restores
+// the default (os.Stdout or os.Stderr). It is a no-op if the Logger is nil.
+func (l *Logger) SetOutput(stdout, stderr io.Writer) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stdout = stdout
+	l.stderr = stderr
+}
+
+// consoleWriter returns the writer for verbose output at level.
+// Must be called while holding the mutex.
+func (l *Logger) consoleWriter(level Level) io.Writer {
+	if level >= LevelWarn {
+		if l.stderr != nil {
+			return l.stderr
+		}
+
+		return os.Stderr
+	}
+
+	if l.stdout != nil {
+		return l.stdout
+	}
+
+	return os.Stdout
+}
+
+// SetObserver registers an Observer that receives every subsequent log entry
+// through OnLog, replacing any previous observer. Passing nil removes it.
+// It is a no-op if the Logger is nil.
+func (l *Logger) SetObserver(observer Observer) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.observer = observer
+}
+
+// SetJournal makes the Logger also write every subsequent entry to the
+// systemd journal through journal, see NewJournalSink. Passing nil, e.g.
+// after NewJournalSink returned ErrJournalUnavailable, keeps logging
+// file-only. The Logger closes the JournalSink in Close.
+// It is a no-op if the Logger is nil.
+func (l *Logger) SetJournal(journal *JournalSink) {
 	if l == nil {
 		return
 	}
@@ -164,13 +317,55 @@ func (l *Logger) Log(format string, args ...interface{}) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	l.journal = journal
+}
+
+// SetSecrets makes the Logger redact secrets (typically
+// config.Config.SecretValues) from every subsequent entry with
+// config.RedactSecrets, so that command output or errors echoing a password
+// or auth key never reach the log file, the console, the journal or the
+// observer. It replaces any previously set secrets.
+// It is a no-op if the Logger is nil.
+func (l *Logger) SetSecrets(secrets ...string) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.secrets = append([]string(nil), secrets...)
+}
+
+// logAt writes a log entry at the given level and notifies the observer.
+// The journal and the observer are called after the lock is released, so
+// a slow journal does not block other goroutines and the observer may log itself.
+func (l *Logger) logAt(level Level, format string, args ...interface{}) {
+	if l == nil {
+		return
+	}
+
+	msg := fmt.Sprintf(format, args...)
+
+	l.mu.Lock()
+
 	if l.file == nil {
+		l.mu.Unlock()
+
 		return
 	}
 
+	observer := l.observer
+	journal := l.journal
+	msg = config.RedactSecrets(msg, l.secrets...)
+
+	prefix := ""
+	if level != LevelInfo {
+		prefix = level.String() + ": "
+	}
+
 	timestamp := time.Now().UTC().Format(time.RFC3339)
-	msg := fmt.Sprintf(format, args...)
-	line := fmt.Sprintf("[%s] %s\n", timestamp, msg)
+	line := fmt.Sprintf("[%s] %s%s\n", timestamp, prefix, msg)
 
 	// Write to file - errors are intentionally ignored as logging
 	// should not interrupt the installation process.
@@ -181,7 +376,15 @@ func (l *Logger) Log(format string, args ...interface{}) {
 	}
 
 	if l.verbose {
-		fmt.Print(line)
+		fmt.Fprint(l.consoleWriter(level), line) //nolint:errcheck // console output is best-effort
+	}
+
+	l.mu.Unlock()
+
+	journal.Write(level, msg)
+
+	if observer != nil {
+		observer.OnLog(level, msg)
 	}
 }
 
@@ -202,6 +405,10 @@ func (l *Logger) Close() error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	//nolint:errcheck // journal output is best-effort
+	l.journal.Close()
+	l.journal = nil
+
 	if l.file == nil {
 		return nil
 	}
@@ -251,3 +458,60 @@ func (l *Logger) LogPath() string {
 
 	return l.file.Name()
 }
+
+// CopyTo copies the current contents of the log file to dest.
+//
+// This is used to persist the installer log into the installed system
+// (e.g., /target/var/log/proxmox-install.log) before the rescue system reboots.
+// Buffered data is synced first, so every entry logged before the call is included.
+// Parent directories of dest are created with 0750 permissions and the copy is
+// written with 0600 permissions, replacing any existing file.
+//
+// CopyTo is safe for concurrent use and may be called before Close; logging
+// continues to the original file afterwards. It returns ErrLoggerNotOpen if the
+// Logger is nil or has been closed.
+func (l *Logger) CopyTo(dest string) error {
+	if l == nil {
+		return ErrLoggerNotOpen
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return ErrLoggerNotOpen
+	}
+
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync log file: %w", err)
+	}
+
+	//nolint:gosec // G304: path comes from the logger's own open file
+	src, err := os.Open(l.file.Name())
+	if err != nil {
+		return fmt.Errorf("failed to open log file for copy: %w", err)
+	}
+	defer src.Close() //nolint:errcheck // read-only file, close error is irrelevant
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o750); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", dest, err)
+	}
+
+	//nolint:gosec // G304: destination path is provided by caller
+	dst, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create log copy %s: %w", dest, err)
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+
+		return fmt.Errorf("failed to copy log to %s: %w", dest, err)
+	}
+
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("failed to close log copy %s: %w", dest, err)
+	}
+
+	return nil
+}
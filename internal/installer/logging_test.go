@@ -2,6 +2,7 @@ package installer
 
 import (
 	"bytes"
+	"errors"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -20,7 +21,7 @@ const (
 
 // Error message constants for test assertions.
 const (
-	errMsgUnexpectedError             = "newLoggerWithPaths() returned unexpected error: %v"
+	errMsgUnexpectedError             = "NewLoggerWithFallbacks() returned unexpected error: %v"
 	errMsgNewLoggerWithPathUnexpected = "NewLoggerWithPath() returned unexpected error: %v"
 	errMsgExpectedFileSet             = "Expected logger.file to be set"
 	errMsgExpectedVerboseFalse        = "Expected logger.verbose to be false"
@@ -77,7 +78,7 @@ func createTestLogger(t *testing.T, verbose bool) (logger *Logger, logPath strin
 
 	var err error
 
-	logger, err = newLoggerWithPaths(verbose, []string{logPath})
+	logger, err = NewLoggerWithFallbacks(verbose, []string{logPath})
 	if err != nil {
 		t.Fatalf(errMsgUnexpectedError, err)
 	}
@@ -191,7 +192,7 @@ func TestNewLoggerWithPathsFirstPathWritable(t *testing.T) {
 	firstPath := filepath.Join(tmpDir, testFirstLogFile)
 	secondPath := filepath.Join(tmpDir, testSecondLogFile)
 
-	logger, err := newLoggerWithPaths(false, []string{firstPath, secondPath})
+	logger, err := NewLoggerWithFallbacks(false, []string{firstPath, secondPath})
 	if err != nil {
 		t.Fatalf(errMsgUnexpectedError, err)
 	}
@@ -235,7 +236,7 @@ func TestNewLoggerWithPathsFallbackToSecondPath(t *testing.T) {
 	firstPath := filepath.Join(unwritableDir, testFirstLogFile)
 	secondPath := filepath.Join(tmpDir, testSecondLogFile)
 
-	logger, err := newLoggerWithPaths(true, []string{firstPath, secondPath})
+	logger, err := NewLoggerWithFallbacks(true, []string{firstPath, secondPath})
 	if err != nil {
 		t.Fatalf(errMsgUnexpectedError, err)
 	}
@@ -289,7 +290,7 @@ func TestNewLoggerWithPathsAllPathsUnwritable(t *testing.T) {
 	firstPath := filepath.Join(unwritableDir1, testFirstLogFile)
 	secondPath := filepath.Join(unwritableDir2, testSecondLogFile)
 
-	logger, err := newLoggerWithPaths(false, []string{firstPath, secondPath})
+	logger, err := NewLoggerWithFallbacks(false, []string{firstPath, secondPath})
 
 	if err == nil {
 		if logger != nil && logger.file != nil {
@@ -310,7 +311,7 @@ func TestNewLoggerWithPathsAllPathsUnwritable(t *testing.T) {
 
 // TestNewLoggerWithPathsEmptyPaths verifies error when no paths are provided.
 func TestNewLoggerWithPathsEmptyPaths(t *testing.T) {
-	logger, err := newLoggerWithPaths(false, []string{})
+	logger, err := NewLoggerWithFallbacks(false, []string{})
 
 	if err == nil {
 		if logger != nil && logger.file != nil {
@@ -358,7 +359,7 @@ func TestNewLoggerWithPathsFileAppendMode(t *testing.T) {
 		t.Fatalf("Failed to create initial file: %v", err)
 	}
 
-	logger, err := newLoggerWithPaths(false, []string{logPath})
+	logger, err := NewLoggerWithFallbacks(false, []string{logPath})
 	if err != nil {
 		t.Fatalf(errMsgUnexpectedError, err)
 	}
@@ -445,7 +446,7 @@ func TestNewLoggerWithPathsMultipleFallbacks(t *testing.T) {
 		filepath.Join(tmpDir, "final.log"), // This one should work
 	}
 
-	logger, err := newLoggerWithPaths(false, paths)
+	logger, err := NewLoggerWithFallbacks(false, paths)
 	if err != nil {
 		t.Fatalf(errMsgUnexpectedError, err)
 	}
@@ -523,6 +524,140 @@ func TestNewLoggerVerboseTrue(t *testing.T) {
 	}
 }
 
+// TestNewLoggerStrictUsesPrimaryPathOnly verifies that NewLoggerStrict either
+// opens the primary path or fails, but never falls back to /tmp.
+func TestNewLoggerStrictUsesPrimaryPathOnly(t *testing.T) {
+	logger, err := NewLoggerStrict(false)
+	if err != nil {
+		if logger != nil {
+			t.Error(errMsgExpectedLoggerNil)
+		}
+
+		if !strings.Contains(err.Error(), defaultLogPath) {
+			t.Errorf("Expected error to mention %q, got %q", defaultLogPath, err.Error())
+		}
+
+		return
+	}
+
+	t.Cleanup(func() {
+		logger.file.Close() //nolint:errcheck // best-effort cleanup in tests
+	})
+
+	if got := logger.LogPath(); got != defaultLogPath {
+		t.Errorf(errMsgLogPathExpectedPath, defaultLogPath, got)
+	}
+}
+
+// TestNewLoggerStrictVersusFallback verifies that a single unwritable path fails
+// (the strict behavior) while the same path followed by a fallback succeeds.
+func TestNewLoggerStrictVersusFallback(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// A regular file used as a parent directory is unwritable even for root.
+	notADir := filepath.Join(tmpDir, "not-a-dir")
+	if err := os.WriteFile(notADir, nil, 0o600); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	unwritablePath := filepath.Join(notADir, testFirstLogFile)
+	fallbackPath := filepath.Join(tmpDir, testSecondLogFile)
+
+	strict, err := NewLoggerWithFallbacks(false, []string{unwritablePath})
+	if err == nil {
+		strict.Close() //nolint:errcheck,gosec // best-effort cleanup in tests
+		t.Fatal("Expected error for single unwritable path, got nil")
+	}
+
+	if strict != nil {
+		t.Error(errMsgExpectedLoggerNil)
+	}
+
+	if !strings.HasPrefix(err.Error(), errMsgFailedToOpenLogFile) {
+		t.Errorf(errMsgExpectedErrorMsgStart, errMsgFailedToOpenLogFile, err.Error())
+	}
+
+	fallback, err := NewLoggerWithFallbacks(false, []string{unwritablePath, fallbackPath})
+	if err != nil {
+		t.Fatalf(errMsgUnexpectedError, err)
+	}
+
+	t.Cleanup(func() {
+		fallback.Close() //nolint:errcheck,gosec // best-effort cleanup in tests
+	})
+
+	if got := fallback.LogPath(); got != fallbackPath {
+		t.Errorf(errMsgLogPathExpectedPath, fallbackPath, got)
+	}
+}
+
+// TestNewLoggerWithFallbacksPicksFirstWritable verifies that unwritable paths
+// are skipped and later paths are ignored once a writable one is found.
+func TestNewLoggerWithFallbacksPicksFirstWritable(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// A regular file used as a parent directory is unwritable even for root.
+	notADir := filepath.Join(tmpDir, "not-a-dir")
+	if err := os.WriteFile(notADir, nil, 0o600); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	unwritablePath := filepath.Join(notADir, testFirstLogFile)
+	firstWritable := filepath.Join(tmpDir, testSecondLogFile)
+	laterPath := filepath.Join(tmpDir, "third.log")
+
+	logger, err := NewLoggerWithFallbacks(false, []string{unwritablePath, firstWritable, laterPath})
+	if err != nil {
+		t.Fatalf(errMsgUnexpectedError, err)
+	}
+
+	t.Cleanup(func() {
+		logger.Close() //nolint:errcheck,gosec // best-effort cleanup in tests
+	})
+
+	if got := logger.LogPath(); got != firstWritable {
+		t.Errorf(errMsgLogPathExpectedPath, firstWritable, got)
+	}
+
+	if _, err := os.Stat(laterPath); !os.IsNotExist(err) {
+		t.Errorf("Expected later path %q not to be created, stat error: %v", laterPath, err)
+	}
+}
+
+// TestNewLoggerWithFallbacksAllUnwritable verifies that an error is returned
+// when none of the provided paths can be opened.
+func TestNewLoggerWithFallbacksAllUnwritable(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	notADir := filepath.Join(tmpDir, "not-a-dir")
+	if err := os.WriteFile(notADir, nil, 0o600); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	paths := []string{
+		filepath.Join(notADir, testFirstLogFile),
+		filepath.Join(notADir, testSecondLogFile),
+	}
+
+	logger, err := NewLoggerWithFallbacks(false, paths)
+	if err == nil {
+		logger.Close() //nolint:errcheck,gosec // best-effort cleanup in tests
+		t.Fatal("Expected error when all paths are unwritable, got nil")
+	}
+
+	if logger != nil {
+		t.Error(errMsgExpectedLoggerNil)
+	}
+
+	if !strings.HasPrefix(err.Error(), errMsgFailedToOpenLogFile) {
+		t.Errorf(errMsgExpectedErrorMsgStart, errMsgFailedToOpenLogFile, err.Error())
+	}
+
+	if !strings.Contains(err.Error(), paths[1]) {
+		t.Errorf("Expected error to mention last path %q, got %q", paths[1], err.Error())
+	}
+}
+
 // TestLogWritesToFile verifies that Log writes messages to the log file.
 func TestLogWritesToFile(t *testing.T) {
 	logger, logPath := createTestLogger(t, false)
@@ -894,7 +1029,7 @@ func TestCloseValidLogger(t *testing.T) {
 	tmpDir := t.TempDir()
 	logPath := filepath.Join(tmpDir, testLogFileName)
 
-	logger, err := newLoggerWithPaths(false, []string{logPath})
+	logger, err := NewLoggerWithFallbacks(false, []string{logPath})
 	if err != nil {
 		t.Fatalf(errMsgUnexpectedError, err)
 	}
@@ -925,7 +1060,7 @@ func TestCloseIdempotent(t *testing.T) {
 	tmpDir := t.TempDir()
 	logPath := filepath.Join(tmpDir, testLogFileName)
 
-	logger, err := newLoggerWithPaths(false, []string{logPath})
+	logger, err := NewLoggerWithFallbacks(false, []string{logPath})
 	if err != nil {
 		t.Fatalf(errMsgUnexpectedError, err)
 	}
@@ -982,7 +1117,7 @@ func TestCloseFlushesData(t *testing.T) {
 	tmpDir := t.TempDir()
 	logPath := filepath.Join(tmpDir, testLogFileName)
 
-	logger, err := newLoggerWithPaths(false, []string{logPath})
+	logger, err := NewLoggerWithFallbacks(false, []string{logPath})
 	if err != nil {
 		t.Fatalf(errMsgUnexpectedError, err)
 	}
@@ -1022,7 +1157,7 @@ func TestCloseLogAfterClose(t *testing.T) {
 	tmpDir := t.TempDir()
 	logPath := filepath.Join(tmpDir, testLogFileName)
 
-	logger, err := newLoggerWithPaths(false, []string{logPath})
+	logger, err := NewLoggerWithFallbacks(false, []string{logPath})
 	if err != nil {
 		t.Fatalf(errMsgUnexpectedError, err)
 	}
@@ -1068,7 +1203,7 @@ func TestCloseConcurrent(t *testing.T) {
 	tmpDir := t.TempDir()
 	logPath := filepath.Join(tmpDir, testLogFileName)
 
-	logger, err := newLoggerWithPaths(false, []string{logPath})
+	logger, err := NewLoggerWithFallbacks(false, []string{logPath})
 	if err != nil {
 		t.Fatalf(errMsgUnexpectedError, err)
 	}
@@ -1108,7 +1243,7 @@ func TestCloseAndLogConcurrent(t *testing.T) {
 	tmpDir := t.TempDir()
 	logPath := filepath.Join(tmpDir, testLogFileName)
 
-	logger, err := newLoggerWithPaths(false, []string{logPath})
+	logger, err := NewLoggerWithFallbacks(false, []string{logPath})
 	if err != nil {
 		t.Fatalf(errMsgUnexpectedError, err)
 	}
@@ -1191,7 +1326,7 @@ func TestLogPathAfterClose(t *testing.T) {
 	tmpDir := t.TempDir()
 	logPath := filepath.Join(tmpDir, testLogFileName)
 
-	logger, err := newLoggerWithPaths(false, []string{logPath})
+	logger, err := NewLoggerWithFallbacks(false, []string{logPath})
 	if err != nil {
 		t.Fatalf(errMsgUnexpectedError, err)
 	}
@@ -1290,7 +1425,7 @@ func TestLogPathWithFallbackPath(t *testing.T) {
 	primaryPath := filepath.Join(unwritableDir, "primary.log")
 	fallbackPath := filepath.Join(tmpDir, "fallback.log")
 
-	logger, err := newLoggerWithPaths(false, []string{primaryPath, fallbackPath})
+	logger, err := NewLoggerWithFallbacks(false, []string{primaryPath, fallbackPath})
 	if err != nil {
 		t.Fatalf(errMsgUnexpectedError, err)
 	}
@@ -1359,7 +1494,7 @@ func TestLogPathAndCloseConcurrent(t *testing.T) {
 	tmpDir := t.TempDir()
 	logPath := filepath.Join(tmpDir, testLogFileName)
 
-	logger, err := newLoggerWithPaths(false, []string{logPath})
+	logger, err := NewLoggerWithFallbacks(false, []string{logPath})
 	if err != nil {
 		t.Fatalf(errMsgUnexpectedError, err)
 	}
@@ -1737,3 +1872,288 @@ func TestNewLoggerWithPathEmptyPath(t *testing.T) {
 		t.Error(errMsgExpectedLoggerNil)
 	}
 }
+
+// CopyTo method tests
+
+func TestLoggerCopyTo(t *testing.T) {
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, testLogFileName)
+
+	logger, err := NewLoggerWithPath(logPath, false)
+	if err != nil {
+		t.Fatalf(errMsgNewLoggerWithPathUnexpected, err)
+	}
+	defer logger.Close()
+
+	logger.Log("first entry")
+	logger.Log("second entry %d", 2)
+
+	dest := filepath.Join(tmpDir, "target", "var", "log", "proxmox-install.log")
+	if err := logger.CopyTo(dest); err != nil {
+		t.Fatalf("CopyTo() returned unexpected error: %v", err)
+	}
+
+	source, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf(errMsgLogFileReadFailed, err)
+	}
+
+	copied, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatalf("Failed to read copied log: %v", err)
+	}
+
+	if !bytes.Equal(source, copied) {
+		t.Errorf("Copied log content mismatch:\nsource: %q\ncopy:   %q", source, copied)
+	}
+
+	if !strings.Contains(string(copied), "second entry 2") {
+		t.Errorf(errMsgMessageNotFound, "second entry 2")
+	}
+}
+
+func TestLoggerCopyToKeepsLogging(t *testing.T) {
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, testLogFileName)
+
+	logger, err := NewLoggerWithPath(logPath, false)
+	if err != nil {
+		t.Fatalf(errMsgNewLoggerWithPathUnexpected, err)
+	}
+	defer logger.Close()
+
+	logger.Log("before copy")
+
+	dest := filepath.Join(tmpDir, "copy.log")
+	if err := logger.CopyTo(dest); err != nil {
+		t.Fatalf("CopyTo() returned unexpected error: %v", err)
+	}
+
+	logger.Log("after copy")
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf(errMsgLogFileReadFailed, err)
+	}
+
+	if !strings.Contains(string(content), "after copy") {
+		t.Errorf(errMsgMessageNotFound, "after copy")
+	}
+
+	copied, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatalf("Failed to read copied log: %v", err)
+	}
+
+	if strings.Contains(string(copied), "after copy") {
+		t.Error("Copy should only contain entries logged before CopyTo")
+	}
+}
+
+func TestLoggerCopyToNilOrClosed(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "copy.log")
+
+	var nilLogger *Logger
+	if err := nilLogger.CopyTo(dest); !errors.Is(err, ErrLoggerNotOpen) {
+		t.Errorf("CopyTo() on nil logger error = %v, want %v", err, ErrLoggerNotOpen)
+	}
+
+	logger, err := NewLoggerWithPath(filepath.Join(t.TempDir(), testLogFileName), false)
+	if err != nil {
+		t.Fatalf(errMsgNewLoggerWithPathUnexpected, err)
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf(errMsgCloseUnexpected, err)
+	}
+
+	if err := logger.CopyTo(dest); !errors.Is(err, ErrLoggerNotOpen) {
+		t.Errorf("CopyTo() on closed logger error = %v, want %v", err, ErrLoggerNotOpen)
+	}
+
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Error("CopyTo() should not create the destination when the logger is not open")
+	}
+}
+
+// Leveled logging and observer tests
+
+// TestLoggerLevelPrefixes verifies that Warn and Error prefix their level while Info does not.
+func TestLoggerLevelPrefixes(t *testing.T) {
+	logger, logPath := createTestLogger(t, false)
+
+	logger.Info("plain info")
+	logger.Warn("disk %s is slow", "sda")
+	logger.Error("step failed")
+
+	if err := logger.file.Sync(); err != nil {
+		t.Fatalf(errMsgSyncLogFileFailed, err)
+	}
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf(errMsgLogFileReadFailed, err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 log lines, got %d: %q", len(lines), lines)
+	}
+
+	wantSuffixes := []string{"] plain info", "] WARN: disk sda is slow", "] ERROR: step failed"}
+	for i, want := range wantSuffixes {
+		if !strings.HasSuffix(lines[i], want) {
+			t.Errorf("Line %d = %q, want suffix %q", i, lines[i], want)
+		}
+	}
+}
+
+// TestLoggerVerboseSplitsStreamsByLevel verifies that in verbose mode info
+// entries go to stdout while warnings and errors go to stderr.
+func TestLoggerVerboseSplitsStreamsByLevel(t *testing.T) {
+	logger, _ := createTestLogger(t, true)
+
+	var stdout, stderr bytes.Buffer
+
+	logger.SetOutput(&stdout, &stderr)
+
+	logger.Log("log entry")
+	logger.Info("info entry")
+	logger.Warn("warn entry")
+	logger.Error("error entry")
+
+	for _, msg := range []string{"log entry", "info entry"} {
+		if !strings.Contains(stdout.String(), msg) {
+			t.Errorf("Expected stdout to contain %q, got %q", msg, stdout.String())
+		}
+
+		if strings.Contains(stderr.String(), msg) {
+			t.Errorf("Expected stderr not to contain %q, got %q", msg, stderr.String())
+		}
+	}
+
+	for _, msg := range []string{"WARN: warn entry", "ERROR: error entry"} {
+		if !strings.Contains(stderr.String(), msg) {
+			t.Errorf("Expected stderr to contain %q, got %q", msg, stderr.String())
+		}
+
+		if strings.Contains(stdout.String(), msg) {
+			t.Errorf("Expected stdout not to contain %q, got %q", msg, stdout.String())
+		}
+	}
+}
+
+// TestLoggerNonVerboseWritesNoStreams verifies that SetOutput writers stay
+// empty when verbose mode is off.
+func TestLoggerNonVerboseWritesNoStreams(t *testing.T) {
+	logger, _ := createTestLogger(t, false)
+
+	var stdout, stderr bytes.Buffer
+
+	logger.SetOutput(&stdout, &stderr)
+
+	logger.Info("info entry")
+	logger.Error("error entry")
+
+	if stdout.Len() != 0 || stderr.Len() != 0 {
+		t.Errorf("Expected no console output, got stdout %q and stderr %q", stdout.String(), stderr.String())
+	}
+}
+
+// TestLoggerSetOutputNilLogger verifies SetOutput is safe on a nil Logger.
+func TestLoggerSetOutputNilLogger(t *testing.T) {
+	var logger *Logger
+
+	logger.SetOutput(&bytes.Buffer{}, &bytes.Buffer{})
+}
+
+// TestLevelString verifies the names of log levels.
+func TestLevelString(t *testing.T) {
+	tests := map[Level]string{
+		LevelInfo:  "INFO",
+		LevelWarn:  "WARN",
+		LevelError: "ERROR",
+		Level(42):  "LEVEL(42)",
+	}
+
+	for level, want := range tests {
+		if got := level.String(); got != want {
+			t.Errorf("Level(%d).String() = %q, want %q", int(level), got, want)
+		}
+	}
+}
+
+// logRecorder is an Observer that records log entries only.
+type logRecorder struct {
+	levels   []Level
+	messages []string
+}
+
+func (r *logRecorder) OnStepStart(string)      {}
+func (r *logRecorder) OnStepEnd(string, error) {}
+func (r *logRecorder) OnLog(level Level, msg string) {
+	r.levels = append(r.levels, level)
+	r.messages = append(r.messages, msg)
+}
+
+// TestLoggerSetObserver verifies that the observer receives each entry and can be removed.
+func TestLoggerSetObserver(t *testing.T) {
+	logger, _ := createTestLogger(t, false)
+	recorder := &logRecorder{}
+
+	logger.SetObserver(recorder)
+	logger.Log("via %s", "Log")
+	logger.Warn("careful")
+	logger.SetObserver(nil)
+	logger.Error("not observed")
+
+	wantMessages := []string{"via Log", "careful"}
+	if strings.Join(recorder.messages, "|") != strings.Join(wantMessages, "|") {
+		t.Errorf("Observed messages = %q, want %q", recorder.messages, wantMessages)
+	}
+
+	if len(recorder.levels) != 2 || recorder.levels[0] != LevelInfo || recorder.levels[1] != LevelWarn {
+		t.Errorf("Observed levels = %v, want [INFO WARN]", recorder.levels)
+	}
+}
+
+// TestLoggerSetSecrets verifies that secrets are redacted from the log file
+// and from the entries passed to the observer.
+func TestLoggerSetSecrets(t *testing.T) {
+	logger, logPath := createTestLogger(t, false)
+	recorder := &logRecorder{}
+
+	logger.SetObserver(recorder)
+	logger.SetSecrets("tskey-auth-abc123", "")
+	logger.Error("tailscale up failed: invalid key %s", "tskey-auth-abc123")
+
+	if err := logger.file.Sync(); err != nil {
+		t.Fatalf(errMsgSyncLogFileFailed, err)
+	}
+
+	//nolint:gosec // G304: test file path from t.TempDir()
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf(errMsgLogFileReadFailed, err)
+	}
+
+	want := "tailscale up failed: invalid key [REDACTED]"
+	if strings.Contains(string(content), "tskey-auth-abc123") || !strings.Contains(string(content), want) {
+		t.Errorf("Log file = %q, want the secret redacted", content)
+	}
+
+	if len(recorder.messages) != 1 || recorder.messages[0] != want {
+		t.Errorf("Observed messages = %q, want [%q]", recorder.messages, want)
+	}
+}
+
+// TestLoggerSetObserverNilLogger verifies SetObserver and leveled methods are nil-safe.
+func TestLoggerSetObserverNilLogger(t *testing.T) {
+	var logger *Logger
+
+	logger.SetObserver(&logRecorder{})
+	logger.SetSecrets("secret")
+	logger.Info("no panic")
+	logger.Warn("no panic")
+	logger.Error("no panic")
+}
@@ -0,0 +1,70 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// machineIDPath is the location of the systemd machine ID.
+const machineIDPath = "/etc/machine-id"
+
+// ErrMachineIDInvalid is returned when /etc/machine-id is still not usable after regeneration.
+var ErrMachineIDInvalid = errors.New("machine ID must be 32 lowercase hex characters")
+
+// machineIDRegex matches a machine ID as documented in machine-id(5).
+var machineIDRegex = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// knownBadMachineIDs are well-formed IDs that must never be kept. The
+// "uninitialized" first-boot marker is already rejected by machineIDRegex.
+var knownBadMachineIDs = map[string]bool{
+	"00000000000000000000000000000000": true,
+}
+
+// EnsureMachineID returns the machine ID of the system, regenerating
+// /etc/machine-id first if it is missing, empty, malformed or a known-bad
+// value. Cloned rescue images can share a machine ID, which makes DHCP and
+// other identity-based services collide.
+//
+// Regeneration removes the file and runs systemd-machine-id-setup, falling
+// back to dbus-uuidgen when systemd is not available. The result is read back
+// and an error wrapping ErrMachineIDInvalid is returned if it is still unusable.
+func EnsureMachineID(ctx context.Context, executor exec.Executor) (string, error) {
+	if id, ok := readMachineID(ctx, executor); ok {
+		return id, nil
+	}
+
+	if err := executor.Run(ctx, "rm", "-f", machineIDPath); err != nil {
+		return "", fmt.Errorf("failed to remove %s: %w", machineIDPath, err)
+	}
+
+	if err := executor.Run(ctx, "systemd-machine-id-setup"); err != nil {
+		if err := executor.Run(ctx, "dbus-uuidgen", "--ensure="+machineIDPath); err != nil {
+			return "", fmt.Errorf("failed to generate machine ID: %w", err)
+		}
+	}
+
+	id, ok := readMachineID(ctx, executor)
+	if !ok {
+		return "", fmt.Errorf("%w: got %q after regeneration", ErrMachineIDInvalid, id)
+	}
+
+	return id, nil
+}
+
+// readMachineID reads /etc/machine-id and reports whether it holds a usable ID.
+// A file that cannot be read is treated as missing.
+func readMachineID(ctx context.Context, executor exec.Executor) (string, bool) {
+	out, err := executor.RunWithOutput(ctx, "cat", machineIDPath)
+	if err != nil {
+		return "", false
+	}
+
+	id := strings.TrimSpace(out)
+
+	return id, machineIDRegex.MatchString(id) && !knownBadMachineIDs[id]
+}
@@ -0,0 +1,139 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// Test command keys for MockExecutor.
+const (
+	cmdCatMachineID   = "cat /etc/machine-id"
+	cmdMachineIDSetup = "systemd-machine-id-setup"
+	cmdDbusUUIDGen    = "dbus-uuidgen --ensure=/etc/machine-id"
+)
+
+// Machine IDs used by the EnsureMachineID tests.
+const (
+	testMachineID     = "4c4c4544004a3610804cb4c04f4d3432"
+	testNewMachineID  = "9f86d081884c4d659a2feaa0c55ad015"
+	testZeroMachineID = "00000000000000000000000000000000"
+)
+
+func TestEnsureMachineIDKeepsValid(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	mock.SetOutput(cmdCatMachineID, testMachineID+"\n")
+
+	id, err := EnsureMachineID(context.Background(), mock)
+
+	require.NoError(t, err)
+	assert.Equal(t, testMachineID, id)
+	assert.Equal(t, 1, mock.CommandCount())
+	assert.True(t, mock.WasNeverCalled("rm"))
+	assert.True(t, mock.WasNeverCalled("systemd-machine-id-setup"))
+}
+
+func TestEnsureMachineIDRegenerates(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		readErr error
+	}{
+		{"empty", "", nil},
+		{"whitespace only", "\n", nil},
+		{"all zeros", testZeroMachineID, nil},
+		{"uninitialized marker", "uninitialized", nil},
+		{"malformed", "not-a-machine-id", nil},
+		{"uppercase", "4C4C4544004A3610804CB4C04F4D3432", nil},
+		{"missing file", "", errors.New("cat: /etc/machine-id: No such file or directory")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := exec.NewMockExecutor()
+			if tt.readErr != nil {
+				mock.QueueError(cmdCatMachineID, tt.readErr)
+			} else {
+				mock.QueueOutput(cmdCatMachineID, tt.current)
+			}
+
+			mock.SetOutput(cmdCatMachineID, testNewMachineID+"\n")
+
+			id, err := EnsureMachineID(context.Background(), mock)
+
+			require.NoError(t, err)
+			assert.Equal(t, testNewMachineID, id)
+
+			commands := mock.Commands()
+			require.Len(t, commands, 4)
+			assert.Equal(t, cmdCatMachineID, commands[0].String())
+			assert.Equal(t, "rm -f /etc/machine-id", commands[1].String())
+			assert.Equal(t, cmdMachineIDSetup, commands[2].String())
+			assert.Equal(t, cmdCatMachineID, commands[3].String())
+		})
+	}
+}
+
+func TestEnsureMachineIDFallsBackToDbus(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	mock.QueueOutput(cmdCatMachineID, "")
+	mock.SetOutput(cmdCatMachineID, testNewMachineID)
+	mock.SetError(cmdMachineIDSetup, errors.New("systemd-machine-id-setup: not found"))
+
+	id, err := EnsureMachineID(context.Background(), mock)
+
+	require.NoError(t, err)
+	assert.Equal(t, testNewMachineID, id)
+	assert.True(t, mock.WasCalledWith("dbus-uuidgen", "--ensure=/etc/machine-id"))
+}
+
+func TestEnsureMachineIDErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(*exec.MockExecutor)
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name: "remove fails",
+			setup: func(m *exec.MockExecutor) {
+				m.SetError("rm -f /etc/machine-id", errors.New("read-only file system"))
+			},
+			wantMsg: "failed to remove /etc/machine-id",
+		},
+		{
+			name: "no generator available",
+			setup: func(m *exec.MockExecutor) {
+				m.SetError(cmdMachineIDSetup, errors.New("not found"))
+				m.SetError(cmdDbusUUIDGen, errors.New("not found"))
+			},
+			wantMsg: "failed to generate machine ID",
+		},
+		{
+			name:    "still invalid after regeneration",
+			setup:   func(m *exec.MockExecutor) { m.SetOutput(cmdCatMachineID, testZeroMachineID) },
+			wantErr: ErrMachineIDInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := exec.NewMockExecutor()
+			tt.setup(mock)
+
+			id, err := EnsureMachineID(context.Background(), mock)
+
+			assert.Empty(t, id)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.ErrorContains(t, err, tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package installer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+)
+
+// NAT firewall backends supported by RenderNATRules.
+const (
+	// NATBackendIPTables renders iptables commands.
+	NATBackendIPTables = "iptables"
+	// NATBackendNFTables renders nft commands.
+	NATBackendNFTables = "nftables"
+)
+
+// Network rendering errors.
+var (
+	// ErrInterfaceEmpty is returned when a required interface name is empty.
+	ErrInterfaceEmpty = errors.New("interface name is required")
+	// ErrNATBackendInvalid is returned when the NAT backend is not supported.
+	ErrNATBackendInvalid = errors.New("NAT backend must be one of: iptables, nftables")
+	// ErrPTRZoneUnaligned is returned when a prefix length does not fall on an
+	// octet (IPv4) or nibble (IPv6) boundary, so it has no single reverse zone.
+	ErrPTRZoneUnaligned = errors.New("prefix length must be a multiple of 8 for IPv4 or 4 for IPv6")
+)
+
+// RenderNATRules returns the firewall commands that masquerade traffic from
+// privateSubnet out of wanInterface, as used by the internal and both bridge modes.
+//
+// The backend selects the command syntax (NATBackendIPTables or NATBackendNFTables).
+// The subnet is normalized to its network address, so "10.0.0.1/24" renders as
+// "10.0.0.0/24". This is a pure function; it does not run anything.
+func RenderNATRules(privateSubnet, wanInterface, backend string) ([]string, error) {
+	if err := config.ValidateSubnet(privateSubnet); err != nil {
+		return nil, err
+	}
+
+	if wanInterface == "" {
+		return nil, ErrInterfaceEmpty
+	}
+
+	_, ipNet, err := net.ParseCIDR(privateSubnet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse subnet %s: %w", privateSubnet, err)
+	}
+
+	subnet := ipNet.String()
+
+	switch backend {
+	case NATBackendIPTables:
+		return []string{
+			fmt.Sprintf("iptables -t nat -A POSTROUTING -s %s -o %s -j MASQUERADE", subnet, wanInterface),
+		}, nil
+	case NATBackendNFTables:
+		return []string{
+			"nft add table ip nat",
+			"nft add chain ip nat postrouting { type nat hook postrouting priority srcnat ; }",
+			fmt.Sprintf("nft add rule ip nat postrouting ip saddr %s oifname %s masquerade", subnet, wanInterface),
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: got %q", ErrNATBackendInvalid, backend)
+	}
+}
+
+// ReversePTRZone returns the reverse DNS zone delegating PTR records for subnet,
+// e.g. "0.0.10.in-addr.arpa" for "10.0.0.0/24" and
+// "d.c.b.a.8.b.d.0.1.0.0.2.ip6.arpa" for "2001:db8:abcd::/48".
+//
+// IPv4 prefixes must be a multiple of 8 and IPv6 prefixes a multiple of 4;
+// other lengths return an error wrapping ErrPTRZoneUnaligned. Host bits in
+// subnet are ignored. This is a pure function; it does not run anything.
+func ReversePTRZone(subnet string) (string, error) {
+	_, ipNet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse subnet %s: %w", subnet, err)
+	}
+
+	ones, _ := ipNet.Mask.Size()
+
+	var labels []string
+
+	if ip := ipNet.IP.To4(); ip != nil {
+		if ones%8 != 0 {
+			return "", fmt.Errorf("%w: got /%d", ErrPTRZoneUnaligned, ones)
+		}
+
+		for _, octet := range ip[:ones/8] {
+			labels = append(labels, strconv.Itoa(int(octet)))
+		}
+
+		labels = append(labels, "in-addr", "arpa")
+	} else {
+		if ones%4 != 0 {
+			return "", fmt.Errorf("%w: got /%d", ErrPTRZoneUnaligned, ones)
+		}
+
+		for i := range ones / 4 {
+			nibble := ipNet.IP[i/2] >> 4
+			if i%2 == 1 {
+				nibble = ipNet.IP[i/2] & 0x0f
+			}
+
+			labels = append(labels, strconv.FormatUint(uint64(nibble), 16))
+		}
+
+		labels = append(labels, "ip6", "arpa")
+	}
+
+	slices.Reverse(labels[:len(labels)-2])
+
+	return strings.Join(labels, "."), nil
+}
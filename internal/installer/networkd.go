@@ -0,0 +1,144 @@
+package installer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+)
+
+// networkdUnitDir is the directory systemd-networkd reads local units from.
+const networkdUnitDir = "/etc/systemd/network"
+
+// networkdFileMarker starts the line naming each unit in renderNetworkd output.
+const networkdFileMarker = "### "
+
+// networkdUnit is a single systemd-networkd unit file.
+type networkdUnit struct {
+	// name is the file name within networkdUnitDir (e.g., "10-vmbr0.netdev").
+	name string
+	// body is the unit contents.
+	body string
+}
+
+// renderNetworkd renders systemd-networkd units for the validated inputs of
+// RenderInterfacesConfig, with iface as the physical interface.
+//
+// The units are returned as one document: each unit is introduced by a
+// "### /etc/systemd/network/<name>" line followed by its contents, so the
+// caller can split it into files. Bridges get a .netdev and a .network unit;
+// the NAT bridge uses IPMasquerade instead of explicit firewall rules.
+func renderNetworkd(network config.NetworkConfig, iface string, host HostNetwork) (string, error) {
+	var units []networkdUnit
+
+	if network.BridgeMode == config.BridgeModeInternal {
+		units = append(units, networkdUnit{
+			name: "20-" + iface + ".network",
+			body: networkdMatch(iface) + networkdLinkMAC(network.BridgeMAC) + networkdPublicAddress(host),
+		})
+	} else {
+		units = append(units,
+			networkdBridgeNetdev(externalBridge, network.BridgeMAC),
+			networkdUnit{
+				name: "20-" + iface + ".network",
+				body: networkdMatch(iface) + "\n[Network]\nBridge=" + externalBridge + "\n",
+			},
+			networkdUnit{
+				name: "20-" + externalBridge + ".network",
+				body: networkdMatch(externalBridge) + networkdPublicAddress(host),
+			},
+		)
+	}
+
+	if network.BridgeMode != config.BridgeModeExternal {
+		if err := config.ValidateSubnet(network.PrivateSubnet); err != nil {
+			return "", err
+		}
+
+		address, err := firstHostCIDR(network.PrivateSubnet)
+		if err != nil {
+			return "", err
+		}
+
+		units = append(units,
+			networkdBridgeNetdev(natBridge, ""),
+			networkdUnit{
+				name: "20-" + natBridge + ".network",
+				body: networkdMatch(natBridge) + networkdBridgeNetwork(address) + "IPMasquerade=ipv4\n",
+			},
+		)
+	}
+
+	if network.AdditionalSubnet != "" {
+		if err := config.ValidateAdditionalSubnet(network.AdditionalSubnet); err != nil {
+			return "", err
+		}
+
+		address, err := firstHostCIDR(network.AdditionalSubnet)
+		if err != nil {
+			return "", err
+		}
+
+		units = append(units,
+			networkdBridgeNetdev(routedBridge, ""),
+			networkdUnit{
+				name: "20-" + routedBridge + ".network",
+				body: networkdMatch(routedBridge) + networkdBridgeNetwork(address),
+			},
+		)
+	}
+
+	var b strings.Builder
+
+	b.WriteString("# Generated by pve-install.\n")
+
+	for _, unit := range units {
+		fmt.Fprintf(&b, "\n%s%s/%s\n%s", networkdFileMarker, networkdUnitDir, unit.name, unit.body)
+	}
+
+	return b.String(), nil
+}
+
+// networkdMatch returns a [Match] section selecting the named link.
+func networkdMatch(name string) string {
+	return "[Match]\nName=" + name + "\n"
+}
+
+// networkdLinkMAC returns a [Link] section setting the MAC address, or
+// nothing when mac is empty.
+func networkdLinkMAC(mac string) string {
+	if mac == "" {
+		return ""
+	}
+
+	return "\n[Link]\nMACAddress=" + normalizeMAC(mac) + "\n"
+}
+
+// networkdPublicAddress returns the Hetzner point-to-point public address
+// and on-link default route sections.
+func networkdPublicAddress(host HostNetwork) string {
+	return fmt.Sprintf("\n[Address]\nAddress=%s/32\nPeer=%s/32\n\n[Route]\nGateway=%s\nGatewayOnLink=yes\n",
+		host.PublicIP, host.Gateway, host.Gateway)
+}
+
+// networkdBridgeNetdev returns the .netdev unit creating a bridge without
+// STP or forwarding delay, with an optional fixed MAC address.
+func networkdBridgeNetdev(name, mac string) networkdUnit {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "[NetDev]\nName=%s\nKind=bridge\n", name)
+
+	if mac != "" {
+		fmt.Fprintf(&b, "MACAddress=%s\n", normalizeMAC(mac))
+	}
+
+	b.WriteString("\n[Bridge]\nSTP=no\nForwardDelaySec=0\n")
+
+	return networkdUnit{name: "10-" + name + ".netdev", body: b.String()}
+}
+
+// networkdBridgeNetwork returns the [Network] section of a guest-facing bridge
+// with the given address that forwards IPv4 and comes up without ports.
+func networkdBridgeNetwork(address string) string {
+	return "\n[Network]\nAddress=" + address + "\nIPForward=ipv4\nConfigureWithoutCarrier=yes\n"
+}
@@ -0,0 +1,111 @@
+package installer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+)
+
+// testNetworkdConfig returns a networkd NetworkConfig with the given bridge mode and default subnet.
+func testNetworkdConfig(mode config.BridgeMode) config.NetworkConfig {
+	network := testNetworkConfig(mode)
+	network.NetworkBackend = config.NetworkBackendNetworkd
+
+	return network
+}
+
+// testNetworkdPublicAddress is the public address and route rendered for testHostNetwork.
+const testNetworkdPublicAddress = "[Address]\nAddress=203.0.113.45/32\nPeer=203.0.113.1/32\n\n" +
+	"[Route]\nGateway=203.0.113.1\nGatewayOnLink=yes\n"
+
+func TestRenderNetworkdInternal(t *testing.T) {
+	out, err := RenderInterfacesConfig(testNetworkdConfig(config.BridgeModeInternal), testHostNetwork)
+
+	require.NoError(t, err)
+	assert.Contains(t, out, "### /etc/systemd/network/20-enp0s31f6.network\n[Match]\nName=enp0s31f6\n\n"+testNetworkdPublicAddress)
+	assert.Contains(t, out, "### /etc/systemd/network/10-vmbr1.netdev\n[NetDev]\nName=vmbr1\nKind=bridge\n")
+	assert.Contains(t, out, "### /etc/systemd/network/20-vmbr1.network\n[Match]\nName=vmbr1\n\n"+
+		"[Network]\nAddress=10.0.0.1/24\nIPForward=ipv4\nConfigureWithoutCarrier=yes\nIPMasquerade=ipv4\n")
+	assert.NotContains(t, out, externalBridge)
+	assert.NotContains(t, out, routedBridge)
+	assert.NotContains(t, out, "iface ")
+}
+
+func TestRenderNetworkdExternal(t *testing.T) {
+	out, err := RenderInterfacesConfig(testNetworkdConfig(config.BridgeModeExternal), testHostNetwork)
+
+	require.NoError(t, err)
+	assert.Contains(t, out, "### /etc/systemd/network/10-vmbr0.netdev\n[NetDev]\nName=vmbr0\nKind=bridge\n\n"+
+		"[Bridge]\nSTP=no\nForwardDelaySec=0\n")
+	assert.Contains(t, out, "### /etc/systemd/network/20-enp0s31f6.network\n[Match]\nName=enp0s31f6\n\n[Network]\nBridge=vmbr0\n")
+	assert.Contains(t, out, "### /etc/systemd/network/20-vmbr0.network\n[Match]\nName=vmbr0\n\n"+testNetworkdPublicAddress)
+	assert.NotContains(t, out, natBridge)
+	assert.NotContains(t, out, "IPMasquerade")
+}
+
+func TestRenderNetworkdBothWithAdditionalSubnet(t *testing.T) {
+	network := testNetworkdConfig(config.BridgeModeBoth)
+	network.AdditionalSubnet = buildSubnet(203, 0, 113, 8, 29)
+
+	out, err := RenderInterfacesConfig(network, testHostNetwork)
+
+	require.NoError(t, err)
+	assert.Contains(t, out, "### /etc/systemd/network/20-vmbr0.network\n")
+	assert.Contains(t, out, "### /etc/systemd/network/20-vmbr1.network\n")
+	assert.Contains(t, out, "### /etc/systemd/network/20-vmbr2.network\n[Match]\nName=vmbr2\n\n"+
+		"[Network]\nAddress=203.0.113.9/29\nIPForward=ipv4\nConfigureWithoutCarrier=yes\n")
+}
+
+func TestRenderNetworkdBridgeMAC(t *testing.T) {
+	tests := []struct {
+		name string
+		mode config.BridgeMode
+		want string
+	}{
+		{"bridge netdev", config.BridgeModeExternal, "[NetDev]\nName=vmbr0\nKind=bridge\nMACAddress=90:1b:0e:aa:bb:cc\n"},
+		{"physical link", config.BridgeModeInternal, "Name=enp0s31f6\n\n[Link]\nMACAddress=90:1b:0e:aa:bb:cc\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network := testNetworkdConfig(tt.mode)
+			network.BridgeMAC = "90-1B-0E-AA-BB-CC"
+
+			out, err := RenderInterfacesConfig(network, testHostNetwork)
+
+			require.NoError(t, err)
+			assert.Contains(t, out, tt.want)
+		})
+	}
+}
+
+func TestRenderNetworkdSubnetErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*config.NetworkConfig)
+		wantErr error
+	}{
+		{"invalid private subnet", func(n *config.NetworkConfig) { n.PrivateSubnet = "invalid" }, config.ErrSubnetInvalid},
+		{"invalid additional subnet", func(n *config.NetworkConfig) { n.AdditionalSubnet = "invalid" }, config.ErrAdditionalSubnetInvalid},
+		{"additional subnet without hosts", func(n *config.NetworkConfig) { n.AdditionalSubnet = buildSubnet(203, 0, 113, 8, 32) }, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network := testNetworkdConfig(config.BridgeModeInternal)
+			tt.mutate(&network)
+
+			out, err := RenderInterfacesConfig(network, testHostNetwork)
+
+			require.Error(t, err)
+			assert.Empty(t, out)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package installer
+
+// Observer receives structured installer events, allowing a UI to display
+// progress without parsing log text.
+//
+// Register an Observer with Runner.SetObserver for step events and with
+// Logger.SetObserver for log entries. Methods are called synchronously from
+// the goroutine producing the event, so implementations should return quickly
+// and must be safe for concurrent use if the Logger is shared across goroutines.
+type Observer interface {
+	// OnStepStart is called before a step executes.
+	OnStepStart(name string)
+
+	// OnStepEnd is called after a step finishes. err is nil on success.
+	OnStepEnd(name string, err error)
+
+	// OnLog is called for every entry written by the Logger.
+	OnLog(level Level, msg string)
+}
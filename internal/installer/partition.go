@@ -0,0 +1,192 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// Partition numbers of the Proxmox VE ZFS disk layout.
+const (
+	// biosBootPartition is the 1 MiB BIOS boot partition (GPT type EF02).
+	biosBootPartition = 1
+	// espPartition is the EFI System Partition (GPT type EF00).
+	espPartition = 2
+	// zfsPartition is the ZFS partition filling the rest of the disk (GPT type BF01).
+	zfsPartition = 3
+)
+
+// GPT type codes of the Proxmox VE ZFS disk layout, as printed by sgdisk.
+const (
+	typeCodeESP = "EF00"
+	typeCodeZFS = "BF01"
+)
+
+// ErrUnexpectedPartitionLayout is returned by PartitionCommands when
+// PreserveESP is set but a disk does not have the layout of a previous
+// installation, so recreating its ZFS partition could destroy other data.
+var ErrUnexpectedPartitionLayout = errors.New("unexpected partition layout")
+
+// Partition is an entry of a disk's GPT partition table.
+type Partition struct {
+	// Number is the partition number (e.g., 3 for /dev/sda3).
+	Number int
+	// TypeCode is the sgdisk type code (e.g., "EF00" for an ESP).
+	TypeCode string
+}
+
+// ReadPartitionTable returns the partitions of disk as listed by "sgdisk -p".
+// A disk without partitions yields an empty table.
+func ReadPartitionTable(ctx context.Context, executor exec.Executor, disk string) ([]Partition, error) {
+	lines, err := exec.RunLines(ctx, executor, "sgdisk", "-p", disk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read partition table of %s: %w", disk, err)
+	}
+
+	var table []Partition
+
+	inTable := false
+
+	for _, line := range lines {
+		fields := strings.Fields(line)
+
+		if !inTable {
+			inTable = len(fields) > 0 && fields[0] == "Number"
+
+			continue
+		}
+
+		// Number, start, end, size value, size unit, code, name...
+		if len(fields) < 6 {
+			return nil, fmt.Errorf("failed to parse partition table of %s: unexpected line %q", disk, line)
+		}
+
+		number, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse partition table of %s: %w", disk, err)
+		}
+
+		table = append(table, Partition{Number: number, TypeCode: strings.ToUpper(fields[5])})
+	}
+
+	return table, nil
+}
+
+// PartitionCommands returns the commands, as name followed by arguments,
+// that wipe and partition each disk of storage with the Proxmox VE ZFS
+// layout: a BIOS boot partition, a 1 GiB ESP and a ZFS partition.
+//
+// With PreserveESP on a BootModeUEFI system, the disks keep their partition
+// table and only the ZFS partition is wiped and recreated, leaving the BIOS
+// boot partition and the ESP intact. tables must then hold the current table
+// of every disk, as read by ReadPartitionTable: a disk needs an ESP (type
+// EF00, at any number) and partition 3 must be the ZFS partition (type BF01)
+// of a previous installation. Any other layout, such as a dual-boot disk with
+// another system in partition 3, fails with ErrUnexpectedPartitionLayout.
+// Otherwise, including PreserveESP in BIOS mode, every disk is wiped
+// completely and tables is not used.
+// This is a pure function; it does not run anything.
+func PartitionCommands(storage *config.StorageConfig, bootMode string, tables map[string][]Partition) ([][]string, error) {
+	if storage == nil {
+		return nil, errors.New("storage config is nil")
+	}
+
+	if bootMode != BootModeUEFI && bootMode != BootModeBIOS {
+		return nil, fmt.Errorf("unknown boot mode %q", bootMode)
+	}
+
+	if len(storage.Disks) == 0 {
+		return nil, fmt.Errorf("no disks to partition")
+	}
+
+	preserve := storage.PreserveESP && bootMode == BootModeUEFI
+
+	var commands [][]string
+
+	for _, disk := range storage.Disks {
+		if preserve {
+			table, ok := tables[disk]
+			if !ok {
+				return nil, fmt.Errorf("partition table of %s was not read", disk)
+			}
+
+			if err := checkPreservedLayout(disk, table); err != nil {
+				return nil, err
+			}
+
+			commands = append(commands,
+				[]string{"wipefs", "--all", partitionPath(disk, zfsPartition)},
+				[]string{"sgdisk", "--delete=" + strconv.Itoa(zfsPartition), disk},
+			)
+		} else {
+			commands = append(commands,
+				[]string{"wipefs", "--all", disk},
+				[]string{"sgdisk", "--zap-all", disk},
+				newPartition(disk, biosBootPartition, "34", "2047", "EF02"),
+				newPartition(disk, espPartition, "2048", "+1G", typeCodeESP),
+			)
+		}
+
+		commands = append(commands, newPartition(disk, zfsPartition, "0", "0", typeCodeZFS))
+	}
+
+	return commands, nil
+}
+
+// checkPreservedLayout verifies that the ZFS partition of disk can be
+// recreated without touching anything but a previous installation.
+func checkPreservedLayout(disk string, table []Partition) error {
+	hasESP := false
+
+	var zfs *Partition
+
+	for i, partition := range table {
+		if partition.TypeCode == typeCodeESP {
+			hasESP = true
+		}
+
+		if partition.Number == zfsPartition {
+			zfs = &table[i]
+		}
+	}
+
+	switch {
+	case !hasESP:
+		return fmt.Errorf("%w: %s has no EFI System Partition (%s)", ErrUnexpectedPartitionLayout, disk, typeCodeESP)
+	case zfs == nil:
+		return fmt.Errorf("%w: %s has no partition %d to replace", ErrUnexpectedPartitionLayout, disk, zfsPartition)
+	case zfs.TypeCode != typeCodeZFS:
+		return fmt.Errorf("%w: partition %d of %s has type %s, not a previous ZFS partition (%s)",
+			ErrUnexpectedPartitionLayout, zfsPartition, disk, zfs.TypeCode, typeCodeZFS)
+	}
+
+	return nil
+}
+
+// newPartition returns the sgdisk command creating partition number on disk
+// from start to end with the given GPT type code.
+func newPartition(disk string, number int, start, end, typeCode string) []string {
+	n := strconv.Itoa(number)
+
+	return []string{"sgdisk", "--new=" + n + ":" + start + ":" + end, "--typecode=" + n + ":" + typeCode, disk}
+}
+
+// partitionPath returns the device path of partition number on disk:
+// /dev/sda3, /dev/nvme0n1p3, or /dev/disk/by-id/...-part3 for stable links.
+func partitionPath(disk string, number int) string {
+	n := strconv.Itoa(number)
+
+	switch {
+	case strings.HasPrefix(disk, "/dev/disk/by-"):
+		return disk + "-part" + n
+	case disk != "" && disk[len(disk)-1] >= '0' && disk[len(disk)-1] <= '9':
+		return disk + "p" + n
+	default:
+		return disk + n
+	}
+}
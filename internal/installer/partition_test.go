@@ -0,0 +1,192 @@
+package installer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// testPreviousInstallTable is the partition table of a disk holding a
+// previous Proxmox VE ZFS installation.
+var testPreviousInstallTable = []Partition{{1, "EF02"}, {2, "EF00"}, {3, "BF01"}}
+
+// testSgdiskOutput is "sgdisk -p" output for a dual-boot disk.
+const testSgdiskOutput = `Disk /dev/sda: 3907029168 sectors, 1.8 TiB
+Sector size (logical/physical): 512/4096 bytes
+Disk identifier (GUID): 6B29C1B2-1F0A-4C1E-9A51-0D2B6F6F8D31
+Partition table holds up to 128 entries
+First usable sector is 34, last usable sector is 3907029134
+Total free space is 2014 sectors (1007.0 KiB)
+
+Number  Start (sector)    End (sector)  Size       Code  Name
+   1            2048          206847   100.0 MiB   EF00  EFI system partition
+   2          206848          239615   16.0 MiB    0C01  Microsoft reserved ...
+   3          239616      1048815615   500.0 GiB   0700  Basic data partition
+`
+
+func TestPartitionCommandsWipesDisks(t *testing.T) {
+	storage := &config.StorageConfig{Disks: []string{"/dev/sda"}}
+
+	commands, err := PartitionCommands(storage, BootModeUEFI, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"wipefs", "--all", "/dev/sda"},
+		{"sgdisk", "--zap-all", "/dev/sda"},
+		{"sgdisk", "--new=1:34:2047", "--typecode=1:EF02", "/dev/sda"},
+		{"sgdisk", "--new=2:2048:+1G", "--typecode=2:EF00", "/dev/sda"},
+		{"sgdisk", "--new=3:0:0", "--typecode=3:BF01", "/dev/sda"},
+	}, commands)
+}
+
+func TestPartitionCommandsPreserveESP(t *testing.T) {
+	storage := &config.StorageConfig{Disks: []string{"/dev/sda", "/dev/nvme0n1"}, PreserveESP: true}
+
+	tables := map[string][]Partition{
+		"/dev/sda":     testPreviousInstallTable,
+		"/dev/nvme0n1": testPreviousInstallTable,
+	}
+
+	commands, err := PartitionCommands(storage, BootModeUEFI, tables)
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"wipefs", "--all", "/dev/sda3"},
+		{"sgdisk", "--delete=3", "/dev/sda"},
+		{"sgdisk", "--new=3:0:0", "--typecode=3:BF01", "/dev/sda"},
+		{"wipefs", "--all", "/dev/nvme0n1p3"},
+		{"sgdisk", "--delete=3", "/dev/nvme0n1"},
+		{"sgdisk", "--new=3:0:0", "--typecode=3:BF01", "/dev/nvme0n1"},
+	}, commands)
+
+	wiped, err := PartitionCommands(&config.StorageConfig{Disks: storage.Disks}, BootModeUEFI, tables)
+	require.NoError(t, err)
+	assert.NotEqual(t, wiped, commands, "PreserveESP changes the plan")
+}
+
+func TestPartitionCommandsPreserveESPIgnoredOnBIOS(t *testing.T) {
+	disks := []string{"/dev/sda"}
+
+	preserved, err := PartitionCommands(&config.StorageConfig{Disks: disks, PreserveESP: true}, BootModeBIOS, nil)
+	require.NoError(t, err)
+
+	wiped, err := PartitionCommands(&config.StorageConfig{Disks: disks}, BootModeBIOS, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, wiped, preserved)
+}
+
+func TestPartitionCommandsErrors(t *testing.T) {
+	_, err := PartitionCommands(nil, BootModeUEFI, nil)
+	assert.Error(t, err)
+
+	_, err = PartitionCommands(&config.StorageConfig{}, BootModeUEFI, nil)
+	assert.ErrorContains(t, err, "no disks")
+
+	_, err = PartitionCommands(&config.StorageConfig{Disks: []string{"/dev/sda"}}, "coreboot", nil)
+	assert.ErrorContains(t, err, "unknown boot mode")
+}
+
+func TestPartitionCommandsPreserveESPRejectsUnexpectedLayout(t *testing.T) {
+	tests := []struct {
+		name  string
+		table []Partition
+		want  string
+	}{
+		{
+			name:  "dual-boot disk",
+			table: []Partition{{1, "EF00"}, {2, "0C01"}, {3, "0700"}},
+			want:  "partition 3 of /dev/sda has type 0700, not a previous ZFS partition (BF01)",
+		},
+		{
+			name:  "no ESP",
+			table: []Partition{{1, "EF02"}, {3, "BF01"}},
+			want:  "/dev/sda has no EFI System Partition (EF00)",
+		},
+		{
+			name:  "no partition 3",
+			table: []Partition{{1, "EF00"}},
+			want:  "/dev/sda has no partition 3 to replace",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &config.StorageConfig{Disks: []string{"/dev/sda"}, PreserveESP: true}
+
+			commands, err := PartitionCommands(storage, BootModeUEFI, map[string][]Partition{"/dev/sda": tt.table})
+
+			require.ErrorIs(t, err, ErrUnexpectedPartitionLayout)
+			assert.ErrorContains(t, err, tt.want)
+			assert.Nil(t, commands)
+		})
+	}
+}
+
+func TestPartitionCommandsPreserveESPRequiresTable(t *testing.T) {
+	storage := &config.StorageConfig{Disks: []string{"/dev/sda"}, PreserveESP: true}
+
+	_, err := PartitionCommands(storage, BootModeUEFI, nil)
+
+	assert.ErrorContains(t, err, "partition table of /dev/sda was not read")
+}
+
+func TestReadPartitionTable(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	mock.SetOutput("sgdisk -p /dev/sda", testSgdiskOutput)
+
+	table, err := ReadPartitionTable(context.Background(), mock, "/dev/sda")
+
+	require.NoError(t, err)
+	assert.Equal(t, []Partition{{1, "EF00"}, {2, "0C01"}, {3, "0700"}}, table)
+
+	_, err = PartitionCommands(&config.StorageConfig{Disks: []string{"/dev/sda"}, PreserveESP: true},
+		BootModeUEFI, map[string][]Partition{"/dev/sda": table})
+	assert.ErrorIs(t, err, ErrUnexpectedPartitionLayout, "the dual-boot disk is left alone")
+}
+
+func TestReadPartitionTableEmptyDisk(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	mock.SetOutput("sgdisk -p /dev/sdb", "Creating new GPT entries in memory.\n"+
+		"Disk /dev/sdb: 3907029168 sectors, 1.8 TiB\n\nNumber  Start (sector)    End (sector)  Size       Code  Name\n")
+
+	table, err := ReadPartitionTable(context.Background(), mock, "/dev/sdb")
+
+	require.NoError(t, err)
+	assert.Empty(t, table)
+}
+
+func TestReadPartitionTableErrors(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	mock.SetExitCode("sgdisk -p /dev/sda", 2)
+	mock.SetOutput("sgdisk -p /dev/sdb", "Number  Start (sector)    End (sector)  Size       Code  Name\n   1  2048\n")
+
+	_, err := ReadPartitionTable(context.Background(), mock, "/dev/sda")
+	assert.ErrorContains(t, err, "failed to read partition table of /dev/sda")
+
+	_, err = ReadPartitionTable(context.Background(), mock, "/dev/sdb")
+	assert.ErrorContains(t, err, "unexpected line")
+}
+
+func TestPartitionPath(t *testing.T) {
+	tests := []struct {
+		disk     string
+		expected string
+	}{
+		{"/dev/sda", "/dev/sda3"},
+		{"/dev/vdb", "/dev/vdb3"},
+		{"/dev/nvme0n1", "/dev/nvme0n1p3"},
+		{"/dev/disk/by-id/ata-SAMSUNG_123", "/dev/disk/by-id/ata-SAMSUNG_123-part3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.disk, func(t *testing.T) {
+			assert.Equal(t, tt.expected, partitionPath(tt.disk, zfsPartition))
+		})
+	}
+}
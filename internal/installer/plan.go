@@ -0,0 +1,144 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// ErrPlanHazard is returned by PlanCommands when a planned command looks
+// dangerous; the joined error names each offending command.
+var ErrPlanHazard = errors.New("planned command is hazardous")
+
+// StepPlanner builds the steps of an installation over executor.
+// PlanSteps is the StepPlanner of the real installation.
+type StepPlanner func(cfg *config.Config, executor exec.Executor, logger *Logger) []Step
+
+// planningKey marks the InstallContext of a PlanCommands run, see isPlanning.
+var planningKey = NewKey[bool]("planning")
+
+// isPlanning reports whether ctx belongs to a PlanCommands run, where
+// commands are recorded rather than executed and produce no output. Steps
+// that parse command output use it to skip checks that need real data.
+func isPlanning(ctx context.Context) bool {
+	planning, _ := Get(InstallContextFrom(ctx), planningKey)
+
+	return planning
+}
+
+// diskDeviceRegex matches whole-disk and partition device nodes, capturing
+// the disk: /dev/sda and /dev/sda1 name /dev/sda, /dev/nvme0n1p2 names
+// /dev/nvme0n1.
+var diskDeviceRegex = regexp.MustCompile(`^(/dev/(?:sd[a-z]+|vd[a-z]+|xvd[a-z]+|hd[a-z]+|nvme[0-9]+n[0-9]+))(?:p?[0-9]+)?$`)
+
+// diskByIDPartRegex matches the partition suffix of a /dev/disk/by-* link.
+var diskByIDPartRegex = regexp.MustCompile(`-part[0-9]+$`)
+
+// PlanCommands builds the steps with plan over an exec.DryRunExecutor and
+// executes them, so nothing real runs and no file is written, returning every
+// command in order for review before the actual installation.
+//
+// It takes a StepPlanner rather than the built steps because every step
+// captures its Executor when it is constructed (see PlanSteps): steps built
+// for the installation would run their commands for real, so PlanCommands
+// has to build its own over the dry-run executor.
+//
+// Commands run while planning get empty output and succeed, so steps follow
+// their default path. Steps see that they are being planned through the
+// InstallContext and skip checks that need real output (e.g., SwapStep
+// skips the memory bound on the swap size). DoneChecker is not consulted.
+// A step error stops planning and is returned with the commands recorded so
+// far.
+//
+// The recorded commands are then checked for hazards: an empty command name
+// or argument, and a disk device (e.g., /dev/sdb or /dev/nvme1n1p1) that is
+// not one of cfg.Storage.Disks, as an argument or as a RunToFile output file. Hazards are returned together as one error
+// wrapping ErrPlanHazard, along with the full command list.
+func PlanCommands(ctx context.Context, plan StepPlanner, cfg *config.Config) ([]exec.ExecutedCommand, error) {
+	if cfg == nil {
+		return nil, errors.New("config is nil")
+	}
+
+	recorder := exec.NewDryRunExecutor(nil)
+	install := NewInstallContext(cfg, recorder, nil)
+	Set(install, planningKey, true)
+	ctx = WithInstallContext(ctx, install)
+
+	for _, step := range plan(cfg, recorder, nil) {
+		if err := ctx.Err(); err != nil {
+			return recorder.Commands(), fmt.Errorf("planning canceled before step %q: %w", step.Name(), err)
+		}
+
+		if err := step.Execute(ctx); err != nil {
+			return recorder.Commands(), fmt.Errorf("planning step %q failed: %w", step.Name(), err)
+		}
+	}
+
+	commands := recorder.Commands()
+
+	return commands, checkPlanHazards(commands, cfg.Storage.Disks)
+}
+
+// checkPlanHazards returns an error wrapping ErrPlanHazard for each hazardous
+// command, or nil when there are none.
+func checkPlanHazards(commands []exec.ExecutedCommand, targets []string) error {
+	var errs []error
+
+	for i, cmd := range commands {
+		if reason := commandHazard(cmd, targets); reason != "" {
+			errs = append(errs, fmt.Errorf("command %d (%s): %s: %w", i+1, exec.FormatExecutedCommand(cmd), reason, ErrPlanHazard))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// commandHazard returns why cmd is hazardous, or "" if it is not.
+func commandHazard(cmd exec.ExecutedCommand, targets []string) string {
+	if strings.TrimSpace(cmd.Name) == "" {
+		return "empty command name"
+	}
+
+	for _, arg := range cmd.Args {
+		if strings.TrimSpace(arg) == "" {
+			return "empty argument"
+		}
+
+		// Check option values too, e.g. --device=/dev/sdb.
+		value := arg
+		if _, v, ok := strings.Cut(arg, "="); ok {
+			value = v
+		}
+
+		if disk := diskOf(value); disk != "" && !slices.Contains(targets, disk) {
+			return "operates on non-target disk " + disk
+		}
+	}
+
+	// A RunToFile target is written to, e.g. dd output redirected to /dev/sdb.
+	if disk := diskOf(cmd.OutputFile); disk != "" && !slices.Contains(targets, disk) {
+		return "writes to non-target disk " + disk
+	}
+
+	return ""
+}
+
+// diskOf returns the disk a device path belongs to, or "" if path is not a
+// disk or partition device.
+func diskOf(path string) string {
+	if strings.HasPrefix(path, "/dev/disk/by-") {
+		return diskByIDPartRegex.ReplaceAllString(path, "")
+	}
+
+	if match := diskDeviceRegex.FindStringSubmatch(path); match != nil {
+		return match[1]
+	}
+
+	return ""
+}
@@ -0,0 +1,204 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// commandStep runs fixed commands through its executor, then returns err.
+type commandStep struct {
+	name     string
+	executor exec.Executor
+	commands [][]string
+	err      error
+}
+
+func (s *commandStep) Name() string { return s.name }
+
+func (s *commandStep) Execute(ctx context.Context) error {
+	for _, cmd := range s.commands {
+		if err := s.executor.Run(ctx, cmd[0], cmd[1:]...); err != nil {
+			return err
+		}
+	}
+
+	return s.err
+}
+
+// commandPlanner returns a StepPlanner building one commandStep per entry of steps.
+func commandPlanner(steps ...commandStep) StepPlanner {
+	return func(_ *config.Config, executor exec.Executor, _ *Logger) []Step {
+		planned := make([]Step, 0, len(steps))
+
+		for _, s := range steps {
+			s.executor = executor
+			planned = append(planned, &s)
+		}
+
+		return planned
+	}
+}
+
+// commandStrings returns the String form of each command.
+func commandStrings(commands []exec.ExecutedCommand) []string {
+	out := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		out = append(out, cmd.String())
+	}
+
+	return out
+}
+
+func TestPlanCommandsCollectsInOrder(t *testing.T) {
+	plan := commandPlanner(
+		commandStep{name: "Partition disks", commands: [][]string{
+			{"sgdisk", "--zap-all", "/dev/sda"},
+			{"sgdisk", "--zap-all", "/dev/sdb"},
+		}},
+		commandStep{name: "Create pool", commands: [][]string{
+			{"zpool", "create", "rpool", "mirror", "/dev/sda1", "/dev/sdb1"},
+		}},
+	)
+
+	commands, err := PlanCommands(context.Background(), plan, newValidTestConfig())
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"sgdisk --zap-all /dev/sda",
+		"sgdisk --zap-all /dev/sdb",
+		"zpool create rpool mirror /dev/sda1 /dev/sdb1",
+	}, commandStrings(commands))
+}
+
+func TestPlanCommandsStepError(t *testing.T) {
+	stepErr := errors.New("no disks detected")
+	plan := commandPlanner(
+		commandStep{name: "Detect hardware", commands: [][]string{{"lsblk", "-d"}}},
+		commandStep{name: "Partition disks", err: stepErr},
+		commandStep{name: "Install Proxmox", commands: [][]string{{"qemu-system-x86_64"}}},
+	)
+
+	commands, err := PlanCommands(context.Background(), plan, newValidTestConfig())
+
+	require.ErrorIs(t, err, stepErr)
+	assert.ErrorContains(t, err, `planning step "Partition disks" failed`)
+	assert.Equal(t, []string{"lsblk -d"}, commandStrings(commands), "no steps planned after the failure")
+}
+
+// fileStep writes the output of a command to path with RunToFile.
+type fileStep struct {
+	executor exec.Executor
+	path     string
+}
+
+func (s *fileStep) Name() string { return "Download key" }
+
+func (s *fileStep) Execute(ctx context.Context) error {
+	return s.executor.RunToFile(ctx, s.path, "curl", "-fsSL", "https://example.com/key.gpg")
+}
+
+func TestPlanCommandsWritesNoFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.gpg")
+	plan := func(_ *config.Config, executor exec.Executor, _ *Logger) []Step {
+		return []Step{&fileStep{executor: executor, path: path}}
+	}
+
+	commands, err := PlanCommands(context.Background(), plan, newValidTestConfig())
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"curl -fsSL https://example.com/key.gpg"}, commandStrings(commands))
+	assert.NoFileExists(t, path)
+}
+
+func TestPlanCommandsHazards(t *testing.T) {
+	tests := []struct {
+		name    string
+		command []string
+		reason  string
+	}{
+		{"non-target disk", []string{"sgdisk", "--zap-all", "/dev/sdc"}, "non-target disk /dev/sdc"},
+		{"non-target partition", []string{"mkfs.ext4", "/dev/nvme1n1p2"}, "non-target disk /dev/nvme1n1"},
+		{"non-target option value", []string{"wipefs", "--device=/dev/sdc"}, "non-target disk /dev/sdc"},
+		{"non-target by-id link", []string{"wipefs", "-a", "/dev/disk/by-id/ata-OTHER-part1"}, "non-target disk /dev/disk/by-id/ata-OTHER"},
+		{"empty argument", []string{"zpool", "create", "", "/dev/sda"}, "empty argument"},
+		{"empty name", []string{" ", "--help"}, "empty command name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := commandPlanner(commandStep{name: "Hazard", commands: [][]string{tt.command}})
+
+			commands, err := PlanCommands(context.Background(), plan, newValidTestConfig())
+
+			require.ErrorIs(t, err, ErrPlanHazard)
+			assert.ErrorContains(t, err, tt.reason)
+			assert.Len(t, commands, 1, "the plan is returned for review")
+		})
+	}
+}
+
+func TestPlanCommandsHazardShowsShellQuotedCommand(t *testing.T) {
+	plan := commandPlanner(commandStep{name: "Hazard", commands: [][]string{{"wipefs", "-a", "", "/dev/sda"}}})
+
+	_, err := PlanCommands(context.Background(), plan, newValidTestConfig())
+
+	require.ErrorIs(t, err, ErrPlanHazard)
+	assert.ErrorContains(t, err, "command 1 (wipefs -a '' /dev/sda): empty argument")
+}
+
+func TestPlanCommandsHazardOutputFile(t *testing.T) {
+	plan := func(_ *config.Config, executor exec.Executor, _ *Logger) []Step {
+		return []Step{&fileStep{executor: executor, path: "/dev/sdc"}}
+	}
+
+	_, err := PlanCommands(context.Background(), plan, newValidTestConfig())
+
+	require.ErrorIs(t, err, ErrPlanHazard)
+	assert.ErrorContains(t, err, "command 1 (curl -fsSL https://example.com/key.gpg > /dev/sdc): writes to non-target disk /dev/sdc")
+}
+
+func TestPlanCommandsIgnoresNonDiskDevices(t *testing.T) {
+	plan := commandPlanner(commandStep{name: "Configure swap", commands: [][]string{
+		{"mkswap", swapDevice},
+		{"dd", "if=/dev/zero", "of=/dev/null"},
+	}})
+
+	_, err := PlanCommands(context.Background(), plan, newValidTestConfig())
+
+	assert.NoError(t, err)
+}
+
+func TestPlanCommandsPlanSteps(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.System.EnableUnattendedUpgrades = true
+
+	commands, err := PlanCommands(context.Background(), PlanSteps, cfg)
+
+	require.NoError(t, err)
+	assert.Contains(t, commandStrings(commands), "apt-get install -y unattended-upgrades")
+}
+
+func TestPlanCommandsPlanStepsWithSwap(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.Storage.SwapSizeMB = 8192
+
+	commands, err := PlanCommands(context.Background(), PlanSteps, cfg)
+
+	require.NoError(t, err)
+	assert.Contains(t, commandStrings(commands), "mkswap -f "+swapDevice)
+	assert.Contains(t, commandStrings(commands), "swapon "+swapDevice)
+}
+
+func TestPlanCommandsNilConfig(t *testing.T) {
+	_, err := PlanCommands(context.Background(), PlanSteps, nil)
+
+	assert.Error(t, err)
+}
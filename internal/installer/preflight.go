@@ -0,0 +1,82 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// ErrStopOnWarning is returned by Runner.Run in strict mode when a step
+// reports warnings. See Runner.SetStopOnWarning.
+var ErrStopOnWarning = errors.New("installation stopped because of warnings")
+
+// WarningReporter is implemented by steps that collect non-fatal warnings
+// while executing, such as PreflightStep. In strict mode the Runner stops
+// after a step that reports any.
+type WarningReporter interface {
+	// Warnings returns the warnings found by the last Execute, or nil.
+	Warnings() []string
+}
+
+// PreflightStep checks the configuration before anything is changed: it
+// fails on validation errors and collects the warnings from
+// config.Config.ValidateWithWarnings and ReconcileWithHardware, logging
+// each one.
+//
+// The step only reads the system. It must run before any step that touches
+// disks, so callers place it ahead of the steps from PlanSteps. It is not
+// part of PlanSteps, which also plans for configurations not yet complete.
+type PreflightStep struct {
+	config   *config.Config
+	executor exec.Executor
+	logger   *Logger
+	warnings []string
+}
+
+// Compile-time assertion that PreflightStep implements WarningReporter.
+var _ WarningReporter = (*PreflightStep)(nil)
+
+// NewPreflightStep creates a PreflightStep for the given configuration.
+func NewPreflightStep(cfg *config.Config, executor exec.Executor, logger *Logger) *PreflightStep {
+	return &PreflightStep{config: cfg, executor: executor, logger: logger}
+}
+
+// Name returns the step name.
+func (s *PreflightStep) Name() string { return "Run pre-flight checks" }
+
+// Execute validates the configuration and reconciles it with the hardware.
+// Warnings do not fail the step; they are logged and kept for Warnings.
+func (s *PreflightStep) Execute(ctx context.Context) error {
+	s.warnings = nil
+
+	findings, err := s.config.ValidateWithWarnings()
+	if err != nil {
+		return fmt.Errorf("configuration is invalid: %w", err)
+	}
+
+	for _, finding := range findings {
+		s.warnings = append(s.warnings, finding.Error())
+	}
+
+	hardware, err := ReconcileWithHardware(ctx, s.executor, s.config)
+	if err != nil {
+		return fmt.Errorf("failed to reconcile configuration with hardware: %w", err)
+	}
+
+	s.warnings = append(s.warnings, hardware...)
+
+	for _, warning := range s.warnings {
+		s.logger.Warn("%s", warning)
+	}
+
+	return nil
+}
+
+// Warnings returns the warnings found by the last Execute, or nil.
+func (s *PreflightStep) Warnings() []string {
+	return slices.Clone(s.warnings)
+}
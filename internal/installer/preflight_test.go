@@ -0,0 +1,154 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// testLsblkThreeDisks is lsblk output with a third disk the test configs leave out.
+const testLsblkThreeDisks = testLsblkDisks + "/dev/sdc   disk\n"
+
+// newPreflightTestMock returns a MockExecutor detecting the disks in testLsblkDisks.
+func newPreflightTestMock() *exec.MockExecutor {
+	mock := exec.NewMockExecutor()
+	mock.SetOutput(cmdLsblkDisks, testLsblkDisks)
+
+	return mock
+}
+
+// newStrictTestRunner returns a Runner executing a PreflightStep for cfg on
+// a system with the disks listed in lsblk, followed by a destructive fake
+// step, together with that fake step and a reader for the log.
+func newStrictTestRunner(t *testing.T, cfg *config.Config, lsblk string, strict bool) (*Runner, *fakeStep, func() string) {
+	t.Helper()
+
+	mock := exec.NewMockExecutor()
+	mock.SetOutput(cmdLsblkDisks, lsblk)
+
+	logger, readLog := newHeartbeatTestLogger(t)
+	partition := &fakeStep{name: "Partition disks"}
+
+	runner := NewRunner([]Step{NewPreflightStep(cfg, mock, logger), partition}, logger)
+	runner.SetStopOnWarning(strict)
+
+	return runner, partition, readLog
+}
+
+func TestPreflightStepName(t *testing.T) {
+	step := NewPreflightStep(newValidTestConfig(), newPreflightTestMock(), nil)
+
+	assert.Equal(t, "Run pre-flight checks", step.Name())
+}
+
+func TestPreflightStepNoWarnings(t *testing.T) {
+	step := NewPreflightStep(newValidTestConfig(), newPreflightTestMock(), nil)
+
+	require.NoError(t, step.Execute(context.Background()))
+	assert.Empty(t, step.Warnings())
+}
+
+func TestPreflightStepCollectsWarnings(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	mock.SetOutput(cmdLsblkDisks, testLsblkThreeDisks)
+
+	logger, readLog := newHeartbeatTestLogger(t)
+	cfg := newValidTestConfig()
+	cfg.Storage.ZFSRaid = config.ZFSRaid0
+
+	step := NewPreflightStep(cfg, mock, logger)
+
+	require.NoError(t, step.Execute(context.Background()))
+
+	warnings := step.Warnings()
+	require.Len(t, warnings, 2)
+	assert.Contains(t, warnings[0], "storage.zfs_raid", "validation warnings come first")
+	assert.Equal(t, "detected disk /dev/sdc is not included in the configuration", warnings[1])
+
+	for _, warning := range warnings {
+		assert.Contains(t, readLog(), "WARN: "+warning)
+	}
+}
+
+func TestPreflightStepWarningsAreCopied(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.Storage.ZFSRaid = config.ZFSRaid0
+
+	step := NewPreflightStep(cfg, newPreflightTestMock(), nil)
+	require.NoError(t, step.Execute(context.Background()))
+
+	step.Warnings()[0] = "changed"
+
+	assert.NotEqual(t, "changed", step.Warnings()[0])
+}
+
+func TestPreflightStepInvalidConfig(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.System.RootPassword = ""
+
+	mock := newPreflightTestMock()
+	step := NewPreflightStep(cfg, mock, nil)
+
+	err := step.Execute(context.Background())
+
+	require.ErrorContains(t, err, "configuration is invalid")
+	assert.Zero(t, mock.CommandCount(), "hardware is not inspected for an invalid configuration")
+}
+
+func TestPreflightStepDetectionFailure(t *testing.T) {
+	mock := exec.NewMockExecutor()
+	mock.SetError(cmdLsblkDisks, errors.New("lsblk: not found"))
+
+	step := NewPreflightStep(newValidTestConfig(), mock, nil)
+
+	assert.ErrorContains(t, step.Execute(context.Background()), "failed to reconcile configuration with hardware")
+}
+
+func TestRunnerStrictModeStopsBeforeDestructiveStep(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.Storage.ZFSRaid = config.ZFSRaid0
+
+	runner, partition, readLog := newStrictTestRunner(t, cfg, testLsblkThreeDisks, true)
+
+	result, err := runner.Run(context.Background())
+
+	require.ErrorIs(t, err, ErrStopOnWarning)
+	assert.ErrorContains(t, err, `step "Run pre-flight checks" reported 2 warning(s)`)
+	assert.ErrorContains(t, err, "\n  - detected disk /dev/sdc is not included in the configuration")
+	assert.False(t, partition.executed, "the destructive step does not run")
+	assert.False(t, result.Success)
+	require.Len(t, result.Steps, 1)
+	assert.Equal(t, StepStatusDone, result.Steps[0].Status)
+	assert.Contains(t, readLog(), "ERROR: "+ErrStopOnWarning.Error())
+}
+
+func TestRunnerNonStrictModeProceedsWithWarnings(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.Storage.ZFSRaid = config.ZFSRaid0
+
+	runner, partition, readLog := newStrictTestRunner(t, cfg, testLsblkThreeDisks, false)
+
+	result, err := runner.Run(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.True(t, partition.executed)
+	assert.Contains(t, readLog(), "WARN: storage.zfs_raid")
+	assert.Contains(t, readLog(), "WARN: detected disk /dev/sdc")
+}
+
+func TestRunnerStrictModeWithoutWarningsProceeds(t *testing.T) {
+	runner, partition, _ := newStrictTestRunner(t, newValidTestConfig(), testLsblkDisks, true)
+
+	result, err := runner.Run(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.True(t, partition.executed)
+}
@@ -0,0 +1,121 @@
+package installer
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// Progress event names.
+const (
+	// ProgressEventStepStart is emitted before a step executes.
+	ProgressEventStepStart = "step_start"
+	// ProgressEventStepEnd is emitted after a step finishes.
+	ProgressEventStepEnd = "step_end"
+)
+
+// Progress statuses.
+const (
+	// ProgressStatusRunning is the status of a started step.
+	ProgressStatusRunning = "running"
+	// ProgressStatusSucceeded is the status of a step that completed.
+	ProgressStatusSucceeded = "succeeded"
+	// ProgressStatusFailed is the status of a step that returned an error.
+	ProgressStatusFailed = "failed"
+)
+
+// ProgressEvent is a single machine-readable progress record.
+type ProgressEvent struct {
+	// Event is the transition, e.g. ProgressEventStepStart.
+	Event string `json:"event"`
+
+	// Step is the step name.
+	Step string `json:"step"`
+
+	// Index is the 1-based position of the step in the run.
+	Index int `json:"index"`
+
+	// Total is the number of steps in the run.
+	Total int `json:"total"`
+
+	// Status is the step status after the transition, e.g. ProgressStatusRunning.
+	Status string `json:"status"`
+
+	// Error is the error message of a failed step, empty otherwise.
+	Error string `json:"error,omitempty"`
+}
+
+// ProgressEmitter writes progress as JSON lines: one ProgressEvent object
+// per line, for CI systems wrapping the installer. It is kept separate from
+// the Logger so the human-readable log and the structured stream do not mix.
+//
+// ProgressEmitter implements Observer, so it can be registered with
+// Runner.SetObserver; log entries are ignored. Steps are numbered in the
+// order they start. It is safe for concurrent use.
+type ProgressEmitter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	total   int
+	index   int
+}
+
+// Compile-time assertion that ProgressEmitter implements Observer.
+var _ Observer = (*ProgressEmitter)(nil)
+
+// NewProgressEmitter creates a ProgressEmitter writing to w (typically
+// os.Stdout) for a run of total steps.
+func NewProgressEmitter(w io.Writer, total int) *ProgressEmitter {
+	return &ProgressEmitter{encoder: json.NewEncoder(w), total: total}
+}
+
+// Emit writes event as a single JSON line. Other components can use it to
+// report their own events on the same stream.
+func (p *ProgressEmitter) Emit(event ProgressEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.encoder.Encode(event)
+}
+
+// OnStepStart emits a step_start event for the next step.
+func (p *ProgressEmitter) OnStepStart(name string) {
+	p.mu.Lock()
+	p.index++
+	index := p.index
+	p.mu.Unlock()
+
+	//nolint:errcheck // Progress output is best-effort and must not fail the run
+	p.Emit(ProgressEvent{
+		Event:  ProgressEventStepStart,
+		Step:   name,
+		Index:  index,
+		Total:  p.total,
+		Status: ProgressStatusRunning,
+	})
+}
+
+// OnStepEnd emits a step_end event for the current step, with the error
+// message if it failed.
+func (p *ProgressEmitter) OnStepEnd(name string, err error) {
+	event := ProgressEvent{
+		Event:  ProgressEventStepEnd,
+		Step:   name,
+		Total:  p.total,
+		Status: ProgressStatusSucceeded,
+	}
+
+	if err != nil {
+		event.Status = ProgressStatusFailed
+		event.Error = err.Error()
+	}
+
+	p.mu.Lock()
+	event.Index = p.index
+	p.mu.Unlock()
+
+	//nolint:errcheck // Progress output is best-effort and must not fail the run
+	p.Emit(event)
+}
+
+// OnLog ignores log entries; they belong to the human-readable log.
+func (p *ProgressEmitter) OnLog(Level, string) {}
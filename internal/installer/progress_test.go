@@ -0,0 +1,99 @@
+package installer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseProgress decodes every line of out as a ProgressEvent.
+func parseProgress(t *testing.T, out *bytes.Buffer) []ProgressEvent {
+	t.Helper()
+
+	var events []ProgressEvent
+
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		var event ProgressEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event), "line %q", scanner.Text())
+
+		events = append(events, event)
+	}
+
+	require.NoError(t, scanner.Err())
+
+	return events
+}
+
+func TestProgressEmitterRunnerSuccess(t *testing.T) {
+	var out bytes.Buffer
+
+	steps := []Step{&fakeStep{name: "Configure swap"}, &fakeStep{name: "Reboot into installed system"}}
+	runner := NewRunner(steps, nil)
+	runner.SetObserver(NewProgressEmitter(&out, len(steps)))
+
+	_, err := runner.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []ProgressEvent{
+		{Event: ProgressEventStepStart, Step: "Configure swap", Index: 1, Total: 2, Status: ProgressStatusRunning},
+		{Event: ProgressEventStepEnd, Step: "Configure swap", Index: 1, Total: 2, Status: ProgressStatusSucceeded},
+		{Event: ProgressEventStepStart, Step: "Reboot into installed system", Index: 2, Total: 2, Status: ProgressStatusRunning},
+		{Event: ProgressEventStepEnd, Step: "Reboot into installed system", Index: 2, Total: 2, Status: ProgressStatusSucceeded},
+	}, parseProgress(t, &out))
+}
+
+func TestProgressEmitterRunnerFailure(t *testing.T) {
+	var out bytes.Buffer
+
+	steps := []Step{
+		&fakeStep{name: "Detect hardware"},
+		&fakeStep{name: "Partition disks", err: errors.New("disk busy")},
+		&fakeStep{name: "Install Proxmox"},
+	}
+	runner := NewRunner(steps, nil)
+	runner.SetObserver(NewProgressEmitter(&out, len(steps)))
+
+	_, err := runner.Run(context.Background())
+	require.Error(t, err)
+
+	events := parseProgress(t, &out)
+	require.Len(t, events, 4, "no events for steps after the failure")
+
+	last := events[3]
+	assert.Equal(t, ProgressEventStepEnd, last.Event)
+	assert.Equal(t, "Partition disks", last.Step)
+	assert.Equal(t, 2, last.Index)
+	assert.Equal(t, 3, last.Total)
+	assert.Equal(t, ProgressStatusFailed, last.Status)
+	assert.Equal(t, "disk busy", last.Error)
+}
+
+func TestProgressEmitterOmitsEmptyError(t *testing.T) {
+	var out bytes.Buffer
+
+	emitter := NewProgressEmitter(&out, 1)
+	emitter.OnStepStart("Configure swap")
+	emitter.OnLog(LevelInfo, "not part of the progress stream")
+
+	assert.Equal(t,
+		`{"event":"step_start","step":"Configure swap","index":1,"total":1,"status":"running"}`+"\n",
+		out.String())
+}
+
+func TestProgressEmitterEmitCustomEvent(t *testing.T) {
+	var out bytes.Buffer
+
+	emitter := NewProgressEmitter(&out, 0)
+	require.NoError(t, emitter.Emit(ProgressEvent{Event: "download", Step: "Fetch ISO", Status: "running"}))
+
+	events := parseProgress(t, &out)
+	require.Len(t, events, 1)
+	assert.Equal(t, "download", events[0].Event)
+}
@@ -0,0 +1,45 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// RebootStep reboots the server into the freshly installed system.
+//
+// It must be the last step: the SSH session to the rescue system ends once
+// the reboot is issued. The step is a no-op unless System.RebootAfterInstall
+// is set, so it stays safe even if added to a plan unconditionally.
+type RebootStep struct {
+	config   *config.Config
+	executor exec.Executor
+	logger   *Logger
+}
+
+// NewRebootStep creates a RebootStep for the given configuration.
+func NewRebootStep(cfg *config.Config, executor exec.Executor, logger *Logger) *RebootStep {
+	return &RebootStep{config: cfg, executor: executor, logger: logger}
+}
+
+// Name returns the step name.
+func (s *RebootStep) Name() string { return "Reboot into installed system" }
+
+// Execute issues the reboot through the Executor.
+func (s *RebootStep) Execute(ctx context.Context) error {
+	if !s.config.System.RebootAfterInstall {
+		s.logger.Log("Reboot after install disabled, skipping")
+
+		return nil
+	}
+
+	s.logger.Log("Rebooting into the installed system")
+
+	if err := s.executor.Run(ctx, "reboot"); err != nil {
+		return fmt.Errorf("failed to reboot: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebootStepName(t *testing.T) {
+	step, _ := newTestStep(NewRebootStep, newValidTestConfig())
+
+	assert.Equal(t, "Reboot into installed system", step.Name())
+}
+
+func TestRebootStepDisabledRunsNothing(t *testing.T) {
+	step, mock := newTestStep(NewRebootStep, newValidTestConfig())
+
+	require.NoError(t, step.Execute(context.Background()))
+	assert.True(t, mock.WasNeverCalled("reboot"))
+}
+
+func TestRebootStepDryRunRecordsReboot(t *testing.T) {
+	// MockExecutor records commands without executing them, which is what a
+	// dry run needs: the reboot is visible in the plan but never happens.
+	cfg := newValidTestConfig()
+	cfg.System.RebootAfterInstall = true
+
+	step, mock := newTestStep(NewRebootStep, cfg)
+
+	require.NoError(t, step.Execute(context.Background()))
+	require.Equal(t, 1, mock.CommandCount())
+	assert.True(t, mock.WasCalledWith("reboot"))
+}
+
+func TestRebootStepError(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.System.RebootAfterInstall = true
+
+	step, mock := newTestStep(NewRebootStep, cfg)
+	mock.SetError("reboot", errors.New("exit status 1"))
+
+	err := step.Execute(context.Background())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to reboot")
+}
@@ -0,0 +1,103 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// ReconcileWithHardware compares the configured disks and network interface
+// against the hardware detected on the running system.
+//
+// It returns human-readable warnings for configured devices that do not exist,
+// for a configured interface that is not the primary one, and for detected
+// disks that are not part of the configuration, and for Storage.PreserveESP
+// on a system booted in BIOS mode, where there is no ESP to keep. Empty
+// configuration values mean "auto-detect" and produce no warnings.
+// An error is returned only when detection itself fails.
+func ReconcileWithHardware(ctx context.Context, executor exec.Executor, cfg *config.Config) ([]string, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config is nil")
+	}
+
+	var warnings []string
+
+	if len(cfg.Storage.Disks) > 0 {
+		detected, err := DetectDisks(ctx, executor)
+		if err != nil {
+			return nil, err
+		}
+
+		warnings = append(warnings, reconcileDisks(cfg.Storage.Disks, detected)...)
+	}
+
+	if cfg.Network.InterfaceName != "" {
+		warning, err := reconcileInterface(ctx, executor, cfg.Network.InterfaceName)
+		if err != nil {
+			return nil, err
+		}
+
+		if warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+
+	if cfg.Storage.PreserveESP {
+		bootMode, err := DetectBootMode(ctx, executor)
+		if err != nil {
+			return nil, err
+		}
+
+		if bootMode != BootModeUEFI {
+			warnings = append(warnings, "preserve_esp is set but the system booted in BIOS mode; disks will be wiped completely")
+		}
+	}
+
+	return warnings, nil
+}
+
+// reconcileInterface returns a warning if the configured interface does not
+// exist or is not the primary interface, or "" if it matches.
+func reconcileInterface(ctx context.Context, executor exec.Executor, name string) (string, error) {
+	exists, err := VerifyInterfaceExists(ctx, executor, name)
+	if err != nil {
+		return "", err
+	}
+
+	primary, err := DetectPrimaryInterface(ctx, executor)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case !exists:
+		return fmt.Sprintf("configured interface %s does not exist (detected primary interface is %s)", name, primary), nil
+	case primary != name:
+		return fmt.Sprintf("configured interface %s does not match detected primary interface %s", name, primary), nil
+	default:
+		return "", nil
+	}
+}
+
+// reconcileDisks returns warnings for configured disks missing from detected
+// and for detected disks missing from configured.
+func reconcileDisks(configured, detected []string) []string {
+	var warnings []string
+
+	for _, disk := range configured {
+		if !slices.Contains(detected, disk) {
+			warnings = append(warnings, fmt.Sprintf("configured disk %s was not detected", disk))
+		}
+	}
+
+	for _, disk := range detected {
+		if !slices.Contains(configured, disk) {
+			warnings = append(warnings, fmt.Sprintf("detected disk %s is not included in the configuration", disk))
+		}
+	}
+
+	return warnings
+}
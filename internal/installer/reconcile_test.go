@@ -0,0 +1,142 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+func newReconcileMock() *exec.MockExecutor {
+	mock := exec.NewMockExecutor()
+	mock.SetOutput(cmdLsblkDisks, testLsblkDisks)
+	mock.SetOutput(cmdIPRouteDefault, testDefaultRoute)
+
+	return mock
+}
+
+func TestReconcileWithHardwareMatching(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.Disks = []string{"/dev/sda", "/dev/sdb"}
+	cfg.Network.InterfaceName = "enp0s31f6"
+
+	warnings, err := ReconcileWithHardware(context.Background(), newReconcileMock(), cfg)
+
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+}
+
+func TestReconcileWithHardwareMissingAndOmittedDisks(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.Disks = []string{"/dev/sda", "/dev/sdc"}
+
+	warnings, err := ReconcileWithHardware(context.Background(), newReconcileMock(), cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"configured disk /dev/sdc was not detected",
+		"detected disk /dev/sdb is not included in the configuration",
+	}, warnings)
+}
+
+func TestReconcileWithHardwareInterfaceMismatch(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Network.InterfaceName = "eth0"
+
+	warnings, err := ReconcileWithHardware(context.Background(), newReconcileMock(), cfg)
+
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "eth0")
+	assert.Contains(t, warnings[0], "enp0s31f6")
+}
+
+func TestReconcileWithHardwareInterfaceMissing(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Network.InterfaceName = "eth0"
+
+	mock := newReconcileMock()
+	mock.SetError("ip link show eth0", exitStatusError{code: 1})
+
+	warnings, err := ReconcileWithHardware(context.Background(), mock, cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"configured interface eth0 does not exist (detected primary interface is enp0s31f6)",
+	}, warnings)
+}
+
+func TestReconcileWithHardwareInterfaceCheckFailure(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Network.InterfaceName = "eth0"
+
+	mock := newReconcileMock()
+	mock.SetError("ip link show eth0", errors.New("executable file not found"))
+
+	_, err := ReconcileWithHardware(context.Background(), mock, cfg)
+
+	assert.ErrorContains(t, err, "failed to check interface eth0")
+}
+
+func TestReconcileWithHardwareAutoDetectSkipsChecks(t *testing.T) {
+	mock := newReconcileMock()
+
+	warnings, err := ReconcileWithHardware(context.Background(), mock, config.DefaultConfig())
+
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	assert.Zero(t, mock.CommandCount())
+}
+
+func TestReconcileWithHardwareDetectionFailure(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.Disks = []string{"/dev/sda"}
+
+	mock := newReconcileMock()
+	mock.SetError(cmdLsblkDisks, errors.New("lsblk failed"))
+
+	_, err := ReconcileWithHardware(context.Background(), mock, cfg)
+
+	assert.Error(t, err)
+}
+
+func TestReconcileWithHardwareNilConfig(t *testing.T) {
+	_, err := ReconcileWithHardware(context.Background(), newReconcileMock(), nil)
+
+	assert.Error(t, err)
+}
+
+func TestReconcileWithHardwarePreserveESP(t *testing.T) {
+	tests := []struct {
+		name     string
+		testErr  error
+		expected []string
+	}{
+		{"uefi", nil, nil},
+		{"bios", exitStatusError{code: 1}, []string{
+			"preserve_esp is set but the system booted in BIOS mode; disks will be wiped completely",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Storage.PreserveESP = true
+
+			mock := newReconcileMock()
+			if tt.testErr != nil {
+				mock.SetError("test -d "+efiFirmwareDir, tt.testErr)
+			}
+
+			warnings, err := ReconcileWithHardware(context.Background(), mock, cfg)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, warnings)
+		})
+	}
+}
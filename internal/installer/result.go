@@ -0,0 +1,98 @@
+package installer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StepStatus is the outcome of a step in a run.
+type StepStatus string
+
+// Step outcomes recorded in a RunResult.
+const (
+	// StepStatusDone means the step executed successfully.
+	StepStatusDone StepStatus = "done"
+	// StepStatusFailed means the step, or its AlreadyDone check, returned an error.
+	StepStatusFailed StepStatus = "failed"
+	// StepStatusSkipped means the step reported AlreadyDone and did not execute.
+	StepStatusSkipped StepStatus = "skipped"
+)
+
+// StepResult is the outcome of a single step.
+type StepResult struct {
+	// Name is the step name.
+	Name string
+
+	// Status is the step outcome.
+	Status StepStatus
+
+	// Duration is the time spent in the step, including its AlreadyDone check.
+	Duration time.Duration
+
+	// Err is the step error, nil unless Status is StepStatusFailed.
+	Err error
+}
+
+// RunResult summarizes a Runner.Run for callers that display or inspect the
+// outcome, such as the CLI or tests.
+type RunResult struct {
+	// Steps holds the outcome of every step that was reached, in order. Steps
+	// after a failure or a cancellation are not run and have no entry.
+	Steps []StepResult
+
+	// Success is true when every step was done or skipped and the run was not canceled.
+	Success bool
+
+	// Duration is the total run time.
+	Duration time.Duration
+}
+
+// Count returns the number of steps with the given status.
+func (r *RunResult) Count(status StepStatus) int {
+	count := 0
+
+	for _, step := range r.Steps {
+		if step.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+// String returns a human-readable summary: an overall line followed by one
+// line per step, e.g.
+//
+//	Installation succeeded in 1m2s: 2 done, 1 skipped, 0 failed
+//	  done     Configure swap (1.5s)
+//	  skipped  Configure locale
+//	  done     Configure ACME certificate (1m0.5s)
+func (r *RunResult) String() string {
+	var b strings.Builder
+
+	outcome := "succeeded"
+	if !r.Success {
+		outcome = "failed"
+	}
+
+	fmt.Fprintf(&b, "Installation %s in %s: %d done, %d skipped, %d failed\n",
+		outcome, r.Duration.Round(time.Millisecond),
+		r.Count(StepStatusDone), r.Count(StepStatusSkipped), r.Count(StepStatusFailed))
+
+	for _, step := range r.Steps {
+		fmt.Fprintf(&b, "  %-8s %s", step.Status, step.Name)
+
+		if step.Status != StepStatusSkipped {
+			fmt.Fprintf(&b, " (%s)", step.Duration.Round(time.Millisecond))
+		}
+
+		if step.Err != nil {
+			fmt.Fprintf(&b, ": %v", step.Err)
+		}
+
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,145 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkedStep is a fakeStep implementing DoneChecker.
+type checkedStep struct {
+	fakeStep
+	done     bool
+	checkErr error
+}
+
+func (s *checkedStep) AlreadyDone(_ context.Context) (bool, error) {
+	return s.done, s.checkErr
+}
+
+// slowStep is a Step that sleeps for delay before returning nil.
+type slowStep struct {
+	name  string
+	delay time.Duration
+}
+
+func (s *slowStep) Name() string { return s.name }
+
+func (s *slowStep) Execute(_ context.Context) error {
+	time.Sleep(s.delay)
+
+	return nil
+}
+
+func TestRunnerRunResult(t *testing.T) {
+	errDisk := errors.New("disk busy")
+	skipped := &checkedStep{fakeStep: fakeStep{name: "Configure locale"}, done: true}
+	failing := &fakeStep{name: "Partition disks", err: errDisk}
+	notReached := &fakeStep{name: "Install Proxmox"}
+
+	runner := NewRunner([]Step{
+		&slowStep{name: "Detect hardware", delay: 10 * time.Millisecond},
+		skipped,
+		failing,
+		notReached,
+	}, nil)
+
+	result, err := runner.Run(context.Background())
+
+	require.ErrorIs(t, err, errDisk)
+	require.NotNil(t, result)
+	assert.False(t, result.Success)
+	assert.False(t, skipped.executed)
+	assert.False(t, notReached.executed)
+
+	require.Len(t, result.Steps, 3, "steps after the failure are not recorded")
+
+	assert.Equal(t, "Detect hardware", result.Steps[0].Name)
+	assert.Equal(t, StepStatusDone, result.Steps[0].Status)
+	assert.GreaterOrEqual(t, result.Steps[0].Duration, 10*time.Millisecond)
+	assert.NoError(t, result.Steps[0].Err)
+
+	assert.Equal(t, "Configure locale", result.Steps[1].Name)
+	assert.Equal(t, StepStatusSkipped, result.Steps[1].Status)
+	assert.NoError(t, result.Steps[1].Err)
+
+	assert.Equal(t, "Partition disks", result.Steps[2].Name)
+	assert.Equal(t, StepStatusFailed, result.Steps[2].Status)
+	assert.ErrorIs(t, result.Steps[2].Err, errDisk)
+
+	assert.GreaterOrEqual(t, result.Duration, result.Steps[0].Duration)
+	assert.Equal(t, 1, result.Count(StepStatusDone))
+	assert.Equal(t, 1, result.Count(StepStatusSkipped))
+	assert.Equal(t, 1, result.Count(StepStatusFailed))
+}
+
+func TestRunnerRunResultSuccess(t *testing.T) {
+	notDone := &checkedStep{fakeStep: fakeStep{name: "Configure swap"}}
+
+	result, err := NewRunner([]Step{&fakeStep{name: "Detect hardware"}, notDone}, nil).Run(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.True(t, notDone.executed, "AlreadyDone false runs the step")
+	assert.Equal(t, 2, result.Count(StepStatusDone))
+}
+
+func TestRunnerRunResultAlreadyDoneError(t *testing.T) {
+	errCheck := errors.New("zpool not readable")
+	step := &checkedStep{fakeStep: fakeStep{name: "Create pool"}, checkErr: errCheck}
+	observer := &recordingObserver{}
+
+	runner := NewRunner([]Step{step}, nil)
+	runner.SetObserver(observer)
+
+	result, err := runner.Run(context.Background())
+
+	require.ErrorIs(t, err, errCheck)
+	assert.False(t, step.executed)
+	assert.False(t, result.Success)
+	require.Len(t, result.Steps, 1)
+	assert.Equal(t, StepStatusFailed, result.Steps[0].Status)
+	assert.Equal(t, []string{
+		"start Create pool",
+		"end Create pool: failed to check whether step is done: zpool not readable",
+	}, observer.events)
+}
+
+func TestRunnerRunResultCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := NewRunner([]Step{&fakeStep{name: "never"}}, nil).Run(ctx)
+
+	require.ErrorIs(t, err, context.Canceled)
+	require.NotNil(t, result)
+	assert.False(t, result.Success)
+	assert.Empty(t, result.Steps)
+}
+
+func TestRunResultString(t *testing.T) {
+	result := &RunResult{
+		Steps: []StepResult{
+			{Name: "Detect hardware", Status: StepStatusDone, Duration: 1500 * time.Millisecond},
+			{Name: "Configure locale", Status: StepStatusSkipped, Duration: time.Millisecond},
+			{Name: "Partition disks", Status: StepStatusFailed, Duration: 250 * time.Microsecond, Err: errors.New("disk busy")},
+		},
+		Duration: 2*time.Second + 123456*time.Microsecond,
+	}
+
+	assert.Equal(t, "Installation failed in 2.123s: 1 done, 1 skipped, 1 failed\n"+
+		"  done     Detect hardware (1.5s)\n"+
+		"  skipped  Configure locale\n"+
+		"  failed   Partition disks (0s): disk busy\n", result.String())
+
+	result.Success = true
+	result.Steps = result.Steps[:2]
+
+	assert.Equal(t, "Installation succeeded in 2.123s: 1 done, 1 skipped, 0 failed\n"+
+		"  done     Detect hardware (1.5s)\n"+
+		"  skipped  Configure locale\n", result.String())
+}
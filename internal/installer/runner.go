@@ -0,0 +1,173 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Runner executes installation steps in order.
+//
+// Runner stops at the first failing step and, in strict mode, after the
+// first step reporting warnings. Progress is written to the Logger and
+// reported to the registered Observer, if any.
+type Runner struct {
+	// steps are executed in order.
+	steps []Step
+
+	// logger receives progress messages. It may be nil.
+	logger *Logger
+
+	// observer receives step events. It may be nil.
+	observer Observer
+
+	// install is the state shared between steps. It may be nil.
+	install *InstallContext
+
+	// stopOnWarning stops the run after a step reporting warnings.
+	stopOnWarning bool
+}
+
+// NewRunner creates a Runner for the given steps, typically from PlanSteps.
+// The logger may be nil.
+func NewRunner(steps []Step, logger *Logger) *Runner {
+	return &Runner{steps: steps, logger: logger}
+}
+
+// SetObserver registers an Observer that receives OnStepStart and OnStepEnd
+// for each executed step. Passing nil removes it. To also receive log entries,
+// register the Observer with the Logger.
+func (r *Runner) SetObserver(observer Observer) {
+	r.observer = observer
+}
+
+// SetInstallContext sets the InstallContext passed to every step. Without
+// one, each Run creates an empty InstallContext with the Runner's logger so
+// steps can always share data.
+func (r *Runner) SetInstallContext(ic *InstallContext) {
+	r.install = ic
+}
+
+// SetStopOnWarning enables strict mode: the run stops with ErrStopOnWarning
+// after a step implementing WarningReporter reports any warnings, such as a
+// PreflightStep placed before the destructive steps. Without it, warnings
+// are only logged and the run proceeds.
+func (r *Runner) SetStopOnWarning(stop bool) {
+	r.stopOnWarning = stop
+}
+
+// Run executes all steps in order and returns a summary of the run together
+// with the first error. The RunResult is never nil.
+//
+// Each step receives ctx carrying the InstallContext, see InstallContextFrom.
+// Before each step, ctx is checked for cancellation; a canceled context stops
+// the run without starting further steps. A step implementing DoneChecker is
+// skipped when AlreadyDone reports true. A failing step is reported to the
+// Observer with its error, and the returned error wraps it with the step name.
+// In strict mode, a step reporting warnings ends the run once it completes,
+// with an error wrapping ErrStopOnWarning.
+func (r *Runner) Run(ctx context.Context) (*RunResult, error) {
+	install := r.install
+	if install == nil {
+		install = NewInstallContext(nil, nil, r.logger)
+	}
+
+	ctx = WithInstallContext(ctx, install)
+
+	result := &RunResult{}
+	start := time.Now()
+
+	defer func() {
+		result.Duration = time.Since(start)
+	}()
+
+	for i, step := range r.steps {
+		if err := ctx.Err(); err != nil {
+			return result, fmt.Errorf("installation canceled before step %q: %w", step.Name(), err)
+		}
+
+		name := step.Name()
+
+		if r.observer != nil {
+			r.observer.OnStepStart(name)
+		}
+
+		r.logger.Info("Step %d/%d: %s", i+1, len(r.steps), name)
+
+		stepStart := time.Now()
+		status, err := r.runStep(ctx, step)
+
+		result.Steps = append(result.Steps, StepResult{
+			Name:     name,
+			Status:   status,
+			Duration: time.Since(stepStart),
+			Err:      err,
+		})
+
+		switch status {
+		case StepStatusFailed:
+			r.logger.Error("Step %q failed: %v", name, err)
+		case StepStatusSkipped:
+			r.logger.Info("Step %q already done, skipped", name)
+		default:
+			r.logger.Info("Step %q completed", name)
+		}
+
+		if r.observer != nil {
+			r.observer.OnStepEnd(name, err)
+		}
+
+		if err != nil {
+			return result, fmt.Errorf("step %q failed: %w", name, err)
+		}
+
+		if err := r.checkWarnings(step); err != nil {
+			r.logger.Error("%v", err)
+
+			return result, err
+		}
+	}
+
+	result.Success = true
+
+	return result, nil
+}
+
+// checkWarnings returns an error wrapping ErrStopOnWarning that lists the
+// warnings of step, if the Runner is in strict mode and step reported any.
+func (r *Runner) checkWarnings(step Step) error {
+	reporter, ok := step.(WarningReporter)
+	if !r.stopOnWarning || !ok {
+		return nil
+	}
+
+	warnings := reporter.Warnings()
+	if len(warnings) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: step %q reported %d warning(s):\n  - %s",
+		ErrStopOnWarning, step.Name(), len(warnings), strings.Join(warnings, "\n  - "))
+}
+
+// runStep executes step unless it reports AlreadyDone, returning its status
+// and error. A failed AlreadyDone check fails the step.
+func (r *Runner) runStep(ctx context.Context, step Step) (StepStatus, error) {
+	if checker, ok := step.(DoneChecker); ok {
+		done, err := checker.AlreadyDone(ctx)
+		if err != nil {
+			return StepStatusFailed, fmt.Errorf("failed to check whether step is done: %w", err)
+		}
+
+		if done {
+			return StepStatusSkipped, nil
+		}
+	}
+
+	if err := step.Execute(ctx); err != nil {
+		return StepStatusFailed, err
+	}
+
+	return StepStatusDone, nil
+}
@@ -0,0 +1,132 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStep is a Step that records its execution and returns err.
+type fakeStep struct {
+	name     string
+	err      error
+	executed bool
+}
+
+func (s *fakeStep) Name() string { return s.name }
+
+func (s *fakeStep) Execute(_ context.Context) error {
+	s.executed = true
+
+	return s.err
+}
+
+// recordingObserver records every event as a string, in order.
+type recordingObserver struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (o *recordingObserver) record(format string, args ...interface{}) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.events = append(o.events, fmt.Sprintf(format, args...))
+}
+
+func (o *recordingObserver) OnStepStart(name string) { o.record("start %s", name) }
+
+func (o *recordingObserver) OnStepEnd(name string, err error) { o.record("end %s: %v", name, err) }
+
+func (o *recordingObserver) OnLog(level Level, msg string) { o.record("log %s %s", level, msg) }
+
+func TestRunnerRunObserverEventSequence(t *testing.T) {
+	logger, err := NewLoggerWithPath(filepath.Join(t.TempDir(), testLogFileName), false)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = logger.Close() })
+
+	errDisk := errors.New("disk busy")
+	first := &fakeStep{name: "Detect hardware"}
+	failing := &fakeStep{name: "Partition disks", err: errDisk}
+	skipped := &fakeStep{name: "Install Proxmox"}
+
+	observer := &recordingObserver{}
+	logger.SetObserver(observer)
+
+	runner := NewRunner([]Step{first, failing, skipped}, logger)
+	runner.SetObserver(observer)
+
+	_, err = runner.Run(context.Background())
+
+	require.ErrorIs(t, err, errDisk)
+	assert.Contains(t, err.Error(), "Partition disks")
+	assert.True(t, first.executed)
+	assert.True(t, failing.executed)
+	assert.False(t, skipped.executed)
+
+	assert.Equal(t, []string{
+		"start Detect hardware",
+		"log INFO Step 1/3: Detect hardware",
+		`log INFO Step "Detect hardware" completed`,
+		"end Detect hardware: <nil>",
+		"start Partition disks",
+		"log INFO Step 2/3: Partition disks",
+		`log ERROR Step "Partition disks" failed: disk busy`,
+		"end Partition disks: disk busy",
+	}, observer.events)
+
+	content, err := os.ReadFile(logger.LogPath())
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `ERROR: Step "Partition disks" failed: disk busy`)
+}
+
+func TestRunnerRunAllSucceed(t *testing.T) {
+	steps := []Step{&fakeStep{name: "one"}, &fakeStep{name: "two"}}
+	observer := &recordingObserver{}
+
+	runner := NewRunner(steps, nil)
+	runner.SetObserver(observer)
+
+	_, err := runner.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"start one",
+		"end one: <nil>",
+		"start two",
+		"end two: <nil>",
+	}, observer.events)
+}
+
+func TestRunnerRunNilObserverAndLogger(t *testing.T) {
+	step := &fakeStep{name: "only"}
+
+	_, err := NewRunner([]Step{step}, nil).Run(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, step.executed)
+}
+
+func TestRunnerRunCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	step := &fakeStep{name: "never"}
+	observer := &recordingObserver{}
+
+	runner := NewRunner([]Step{step}, nil)
+	runner.SetObserver(observer)
+
+	_, err := runner.Run(ctx)
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.False(t, step.executed)
+	assert.Empty(t, observer.events)
+}
@@ -0,0 +1,98 @@
+package installer
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// scriptHeader starts every script from ExportCommandsAsScript.
+const scriptHeader = "#!/bin/sh\nset -e\n\n"
+
+// heredocDelimiter terminates stdin heredocs; a numeric suffix is added when
+// the stdin contains it as a line.
+const heredocDelimiter = "PVE_EOF"
+
+// ExportCommandsAsScript renders recorded commands (e.g., from PlanCommands
+// or exec.DryRunExecutor.Commands) as a POSIX shell script that stops at the
+// first failing command, for reproducing or manually resuming an installation.
+//
+// Each command is quoted with exec.FormatExecutedCommand, which keeps the
+// extra environment of RunWithEnv (e.g., DEBIAN_FRONTEND=noninteractive) as
+// KEY=value assignments and runs a RunInDir command in a subshell that
+// changes into its directory; a RunToFile command redirects its stdout to
+// its output file. A command with stdin gets it from a quoted heredoc,
+// so no expansion happens inside it; a final newline is added if the stdin
+// lacks one.
+//
+// Unless includeSecrets is true, every occurrence of the given secrets
+// (typically Config.SecretValues) in arguments, environment and stdin is
+// replaced with config.RedactedPlaceholder, and the script is for review only.
+func ExportCommandsAsScript(commands []exec.ExecutedCommand, includeSecrets bool, secrets ...string) string {
+	redact := func(s string) string {
+		if includeSecrets {
+			return s
+		}
+
+		return config.RedactSecrets(s, secrets...)
+	}
+
+	var b strings.Builder
+
+	b.WriteString(scriptHeader)
+
+	for _, cmd := range commands {
+		redacted := exec.ExecutedCommand{
+			Name:       redact(cmd.Name),
+			Args:       make([]string, len(cmd.Args)),
+			Env:        make([]string, len(cmd.Env)),
+			Dir:        redact(cmd.Dir),
+			OutputFile: redact(cmd.OutputFile),
+		}
+
+		for i, arg := range cmd.Args {
+			redacted.Args[i] = redact(arg)
+		}
+
+		for i, kv := range cmd.Env {
+			redacted.Env[i] = redact(kv)
+		}
+
+		b.WriteString(exec.FormatExecutedCommand(redacted))
+
+		if cmd.Stdin == "" {
+			b.WriteByte('\n')
+
+			continue
+		}
+
+		stdin := redact(cmd.Stdin)
+		if !strings.HasSuffix(stdin, "\n") {
+			stdin += "\n"
+		}
+
+		delimiter := heredocDelimiterFor(stdin)
+
+		b.WriteString(" <<'" + delimiter + "'\n")
+		b.WriteString(stdin)
+		b.WriteString(delimiter + "\n")
+	}
+
+	return b.String()
+}
+
+// heredocDelimiterFor returns a heredoc delimiter that does not occur as a
+// line of stdin.
+func heredocDelimiterFor(stdin string) string {
+	lines := strings.Split(stdin, "\n")
+	delimiter := heredocDelimiter
+
+	for n := 1; slices.Contains(lines, delimiter); n++ {
+		delimiter = heredocDelimiter + "_" + strconv.Itoa(n)
+	}
+
+	return delimiter
+}
@@ -0,0 +1,111 @@
+package installer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// testScriptPassword is a secret that must not appear in redacted scripts.
+const testScriptPassword = "hunter2 it's secret" // NOSONAR(go:S2068) test data
+
+func TestExportCommandsAsScript(t *testing.T) {
+	commands := []exec.ExecutedCommand{
+		{Name: "zpool", Args: []string{"create", "-o", "ashift=12", "rpool", "mirror", "/dev/sda", "/dev/sdb"}},
+		{Name: "echo", Args: []string{"hello world", "it's"}},
+		{Name: "tee", Args: []string{autoUpgradesPath}, Stdin: autoUpgradesConfig},
+		{Name: "chpasswd", Stdin: "root:pw"}, // NOSONAR(go:S2068) test data
+	}
+
+	script := ExportCommandsAsScript(commands, true)
+
+	expected := "#!/bin/sh\nset -e\n\n" +
+		"zpool create -o ashift=12 rpool mirror /dev/sda /dev/sdb\n" +
+		"echo 'hello world' 'it'\\''s'\n" +
+		"tee /etc/apt/apt.conf.d/20auto-upgrades <<'PVE_EOF'\n" +
+		autoUpgradesConfig +
+		"PVE_EOF\n" +
+		"chpasswd <<'PVE_EOF'\n" +
+		"root:pw\n" +
+		"PVE_EOF\n"
+	assert.Equal(t, expected, script)
+}
+
+func TestExportCommandsAsScriptEmpty(t *testing.T) {
+	assert.Equal(t, "#!/bin/sh\nset -e\n\n", ExportCommandsAsScript(nil, false))
+}
+
+func TestExportCommandsAsScriptRedactsSecrets(t *testing.T) {
+	commands := []exec.ExecutedCommand{
+		{Name: "chpasswd", Stdin: "root:" + testScriptPassword + "\n"},
+		{Name: "tailscale", Args: []string{"up", "--auth-key=tskey-auth-abc123"}},
+	}
+	secrets := []string{testScriptPassword, "tskey-auth-abc123"}
+
+	script := ExportCommandsAsScript(commands, false, secrets...)
+
+	for _, secret := range secrets {
+		assert.NotContains(t, script, secret)
+	}
+
+	assert.Contains(t, script, "root:[REDACTED]\n")
+	assert.Contains(t, script, "tailscale up '--auth-key=[REDACTED]'\n")
+}
+
+func TestExportCommandsAsScriptEnv(t *testing.T) {
+	commands := []exec.ExecutedCommand{
+		{Name: "apt-get", Args: []string{"install", "-y", "sudo"}, Env: aptEnv},
+		{Name: "tailscale", Args: []string{"up"}, Env: []string{"TS_AUTHKEY=tskey-auth-abc123"}},
+	}
+
+	script := ExportCommandsAsScript(commands, false, "tskey-auth-abc123")
+
+	assert.Equal(t, "#!/bin/sh\nset -e\n\n"+
+		"DEBIAN_FRONTEND=noninteractive apt-get install -y sudo\n"+
+		"TS_AUTHKEY='[REDACTED]' tailscale up\n", script)
+}
+
+func TestExportCommandsAsScriptDir(t *testing.T) {
+	commands := []exec.ExecutedCommand{
+		{Name: "git", Args: []string{"rev-parse", "HEAD"}, Dir: "/mnt/target/etc"},
+		{Name: "tee", Args: []string{"hosts"}, Dir: "/mnt/target/etc", Stdin: "127.0.0.1 localhost\n"},
+	}
+
+	assert.Equal(t, "#!/bin/sh\nset -e\n\n"+
+		"(cd /mnt/target/etc && git rev-parse HEAD)\n"+
+		"(cd /mnt/target/etc && tee hosts) <<'PVE_EOF'\n"+
+		"127.0.0.1 localhost\n"+
+		"PVE_EOF\n", ExportCommandsAsScript(commands, true))
+}
+
+func TestExportCommandsAsScriptOutputFile(t *testing.T) {
+	recorder := exec.NewDryRunExecutor(nil)
+	require.NoError(t, recorder.RunToFile(context.Background(), "/target/etc/hostid", "zgenhostid", "-f"))
+
+	assert.Equal(t, "#!/bin/sh\nset -e\n\n"+
+		"zgenhostid -f > /target/etc/hostid\n", ExportCommandsAsScript(recorder.Commands(), true))
+}
+
+func TestExportCommandsAsScriptIncludesSecrets(t *testing.T) {
+	commands := []exec.ExecutedCommand{
+		{Name: "chpasswd", Stdin: "root:" + testScriptPassword + "\n"},
+	}
+
+	script := ExportCommandsAsScript(commands, true, testScriptPassword)
+
+	assert.Contains(t, script, "root:"+testScriptPassword+"\n")
+}
+
+func TestExportCommandsAsScriptDelimiterCollision(t *testing.T) {
+	commands := []exec.ExecutedCommand{
+		{Name: "cat", Stdin: "line\nPVE_EOF\nPVE_EOF_1\n"},
+	}
+
+	script := ExportCommandsAsScript(commands, true)
+
+	assert.Contains(t, script, "cat <<'PVE_EOF_2'\nline\nPVE_EOF\nPVE_EOF_1\nPVE_EOF_2\n")
+}
@@ -0,0 +1,51 @@
+package installer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+)
+
+// ErrSSHLockout is returned when password authentication would be disabled
+// without an SSH public key, leaving no way to log in over SSH.
+var ErrSSHLockout = errors.New("cannot disable SSH password authentication without an SSH public key")
+
+// RenderSSHDConfig returns an sshd_config drop-in (for /etc/ssh/sshd_config.d/)
+// applying the SSH hardening options of system:
+//   - PermitRootLogin, as returned by system.PermitRootLogin
+//   - PasswordAuthentication, disabled when SSHDisablePasswordAuth is set,
+//     together with KbdInteractiveAuthentication so PAM cannot prompt for a
+//     password instead
+//
+// An invalid SSHPermitRootLogin returns config.ErrPermitRootLoginInvalid, and
+// disabling password authentication without a public key returns
+// ErrSSHLockout. This is a pure function; it does not run anything.
+func RenderSSHDConfig(system *config.SystemConfig) (string, error) {
+	if system == nil {
+		return "", fmt.Errorf("system config is nil")
+	}
+
+	if err := config.ValidatePermitRootLogin(system.SSHPermitRootLogin); err != nil {
+		return "", err
+	}
+
+	if system.SSHDisablePasswordAuth && system.SSHPublicKey == "" {
+		return "", ErrSSHLockout
+	}
+
+	var b strings.Builder
+
+	b.WriteString("# SSH hardening applied by pve-install\n")
+	fmt.Fprintf(&b, "PermitRootLogin %s\n", system.PermitRootLogin())
+
+	if system.SSHDisablePasswordAuth {
+		b.WriteString("PasswordAuthentication no\n")
+		b.WriteString("KbdInteractiveAuthentication no\n")
+	} else {
+		b.WriteString("PasswordAuthentication yes\n")
+	}
+
+	return b.String(), nil
+}
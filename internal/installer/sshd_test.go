@@ -0,0 +1,84 @@
+package installer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+)
+
+// testSSHKey is a syntactically valid SSH public key for sshd tests.
+const testSSHKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAI user@host"
+
+func TestRenderSSHDConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		system   config.SystemConfig
+		expected string
+	}{
+		{
+			name:   "defaults without key",
+			system: config.SystemConfig{},
+			expected: "# SSH hardening applied by pve-install\n" +
+				"PermitRootLogin yes\n" +
+				"PasswordAuthentication yes\n",
+		},
+		{
+			name:   "key derives prohibit-password",
+			system: config.SystemConfig{SSHPublicKey: testSSHKey},
+			expected: "# SSH hardening applied by pve-install\n" +
+				"PermitRootLogin prohibit-password\n" +
+				"PasswordAuthentication yes\n",
+		},
+		{
+			name: "password auth disabled",
+			system: config.SystemConfig{
+				SSHPublicKey:           testSSHKey,
+				SSHDisablePasswordAuth: true,
+			},
+			expected: "# SSH hardening applied by pve-install\n" +
+				"PermitRootLogin prohibit-password\n" +
+				"PasswordAuthentication no\n" +
+				"KbdInteractiveAuthentication no\n",
+		},
+		{
+			name: "explicit permit root login overrides derived value",
+			system: config.SystemConfig{
+				SSHPublicKey:       testSSHKey,
+				SSHPermitRootLogin: config.PermitRootLoginNo,
+			},
+			expected: "# SSH hardening applied by pve-install\n" +
+				"PermitRootLogin no\n" +
+				"PasswordAuthentication yes\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := RenderSSHDConfig(&tt.system)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rendered)
+		})
+	}
+}
+
+func TestRenderSSHDConfigInvalidPermitRootLogin(t *testing.T) {
+	_, err := RenderSSHDConfig(&config.SystemConfig{SSHPermitRootLogin: "without-password"})
+
+	assert.ErrorIs(t, err, config.ErrPermitRootLoginInvalid)
+}
+
+func TestRenderSSHDConfigLockout(t *testing.T) {
+	_, err := RenderSSHDConfig(&config.SystemConfig{SSHDisablePasswordAuth: true})
+
+	assert.ErrorIs(t, err, ErrSSHLockout)
+}
+
+func TestRenderSSHDConfigNil(t *testing.T) {
+	_, err := RenderSSHDConfig(nil)
+
+	assert.Error(t, err)
+}
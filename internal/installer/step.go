@@ -0,0 +1,72 @@
+package installer
+
+import (
+	"context"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// Step is a single unit of work in the installation process.
+//
+// Steps run in the order returned by PlanSteps. Each step receives its
+// dependencies (config, executor, logger) at construction time and must use
+// the Executor for all system commands so it can be tested with MockExecutor.
+// Data discovered by earlier steps is shared through the InstallContext
+// returned by InstallContextFrom(ctx).
+type Step interface {
+	// Name returns a short human-readable step name (e.g., "Configure swap").
+	Name() string
+
+	// Execute runs the step. It must honor ctx cancellation.
+	Execute(ctx context.Context) error
+}
+
+// DoneChecker is implemented by steps that can tell whether their work is
+// already in place, e.g. after an interrupted run was restarted. The Runner
+// calls AlreadyDone before Execute and skips the step when it reports true.
+type DoneChecker interface {
+	// AlreadyDone reports whether the step has nothing left to do.
+	AlreadyDone(ctx context.Context) (bool, error)
+}
+
+// PlanSteps returns the ordered list of steps required for cfg.
+//
+// Optional steps are only included when the configuration enables them,
+// so the returned plan reflects exactly what will run.
+// config.Config.PostInstallPlanDescription describes the same steps for
+// users, so changes to which steps are selected must be mirrored there.
+func PlanSteps(cfg *config.Config, executor exec.Executor, logger *Logger) []Step {
+	if cfg == nil {
+		return nil
+	}
+
+	var steps []Step
+
+	if cfg.System.Keyboard != "" || cfg.System.Locale != "" {
+		steps = append(steps, NewLocaleStep(cfg, executor, logger))
+	}
+
+	if cfg.Storage.SwapSizeMB > 0 {
+		steps = append(steps, NewSwapStep(cfg, executor, logger))
+	}
+
+	if cfg.System.EnableUnattendedUpgrades {
+		steps = append(steps, NewUnattendedUpgradesStep(cfg, executor, logger))
+	}
+
+	if cfg.System.AdminUser != "" {
+		steps = append(steps, NewAdminUserStep(cfg, executor, logger))
+	}
+
+	if cfg.ACME.Enabled {
+		steps = append(steps, NewACMEStep(cfg, executor, logger))
+	}
+
+	// The reboot ends the session, so it must always run last.
+	if cfg.System.RebootAfterInstall {
+		steps = append(steps, NewRebootStep(cfg, executor, logger))
+	}
+
+	return steps
+}
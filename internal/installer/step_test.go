@@ -0,0 +1,230 @@
+package installer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// newValidTestConfig returns a valid configuration for pve1.example.com on
+// the two disks in testLsblkDisks. Tests change the fields they exercise.
+func newValidTestConfig() *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.System.Hostname = "pve1"
+	cfg.System.DomainSuffix = "example.com"
+	cfg.System.RootPassword = "correct-horse-battery" // NOSONAR(go:S2068) test data
+	cfg.System.SSHPublicKey = testSSHKey
+	cfg.Storage.Disks = []string{"/dev/sda", "/dev/sdb"}
+
+	return cfg
+}
+
+// newTestStep builds a step for cfg with newStep on a fresh MockExecutor
+// and returns the step together with that executor.
+func newTestStep[S Step](newStep func(*config.Config, exec.Executor, *Logger) S, cfg *config.Config) (S, *exec.MockExecutor) {
+	mock := exec.NewMockExecutor()
+
+	return newStep(cfg, mock, nil), mock
+}
+
+// stepNames returns the names of the given steps in order.
+func stepNames(steps []Step) []string {
+	names := make([]string, 0, len(steps))
+	for _, s := range steps {
+		names = append(names, s.Name())
+	}
+
+	return names
+}
+
+func TestPlanStepsNilConfig(t *testing.T) {
+	assert.Nil(t, PlanSteps(nil, exec.NewMockExecutor(), nil))
+}
+
+func TestPlanStepsSwap(t *testing.T) {
+	tests := []struct {
+		name       string
+		swapSizeMB int
+		wantSwap   bool
+	}{
+		{"disabled", 0, false},
+		{"negative treated as disabled", -1, false},
+		{"enabled", 4096, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Storage.SwapSizeMB = tt.swapSizeMB
+
+			steps := PlanSteps(cfg, exec.NewMockExecutor(), nil)
+
+			if tt.wantSwap {
+				require.Contains(t, stepNames(steps), "Configure swap")
+			} else {
+				assert.NotContains(t, stepNames(steps), "Configure swap")
+			}
+		})
+	}
+}
+
+func TestPlanStepsReboot(t *testing.T) {
+	tests := []struct {
+		name       string
+		reboot     bool
+		wantReboot bool
+	}{
+		{"disabled by default", false, false},
+		{"enabled", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Storage.SwapSizeMB = 4096
+			cfg.System.RebootAfterInstall = tt.reboot
+
+			names := stepNames(PlanSteps(cfg, exec.NewMockExecutor(), nil))
+
+			if tt.wantReboot {
+				require.NotEmpty(t, names)
+				assert.Equal(t, "Reboot into installed system", names[len(names)-1], "reboot must be the final step")
+			} else {
+				assert.NotContains(t, names, "Reboot into installed system")
+			}
+		})
+	}
+}
+
+func TestPlanStepsLocale(t *testing.T) {
+	tests := []struct {
+		name       string
+		keyboard   string
+		locale     string
+		wantLocale bool
+	}{
+		{"defaults", "en-us", "en_US.UTF-8", true},
+		{"keyboard only", "de", "", true},
+		{"locale only", "", "de_DE.UTF-8", true},
+		{"neither", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.System.Keyboard = tt.keyboard
+			cfg.System.Locale = tt.locale
+
+			names := stepNames(PlanSteps(cfg, exec.NewMockExecutor(), nil))
+
+			if tt.wantLocale {
+				assert.Contains(t, names, "Configure keyboard and locale")
+			} else {
+				assert.NotContains(t, names, "Configure keyboard and locale")
+			}
+		})
+	}
+}
+
+func TestPlanStepsUnattendedUpgrades(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		cfg := config.DefaultConfig()
+		cfg.System.EnableUnattendedUpgrades = enabled
+
+		names := stepNames(PlanSteps(cfg, exec.NewMockExecutor(), nil))
+
+		if enabled {
+			assert.Contains(t, names, "Configure unattended upgrades")
+		} else {
+			assert.NotContains(t, names, "Configure unattended upgrades")
+		}
+	}
+}
+
+func TestPlanStepsACME(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		cfg := config.DefaultConfig()
+		cfg.ACME.Enabled = enabled
+		cfg.System.RebootAfterInstall = true
+
+		names := stepNames(PlanSteps(cfg, exec.NewMockExecutor(), nil))
+
+		if enabled {
+			assert.Contains(t, names, "Configure ACME certificate")
+			assert.Equal(t, "Reboot into installed system", names[len(names)-1], "reboot stays last")
+		} else {
+			assert.NotContains(t, names, "Configure ACME certificate")
+		}
+	}
+}
+
+// describedAs returns the start of the config.Config.PostInstallPlanDescription
+// sentence for step.
+func describedAs(t *testing.T, step Step) string {
+	t.Helper()
+
+	switch step.(type) {
+	case *LocaleStep:
+		return "Set "
+	case *SwapStep:
+		return "Create a "
+	case *UnattendedUpgradesStep:
+		return "Enable automatic security updates"
+	case *AdminUserStep:
+		return "Create sudo admin user "
+	case *ACMEStep:
+		return "Order a Let's Encrypt certificate"
+	case *RebootStep:
+		return "Reboot into the installed system"
+	default:
+		t.Fatalf("step %q has no description; add it to PostInstallPlanDescription", step.Name())
+
+		return ""
+	}
+}
+
+func TestPlanStepsMatchPostInstallPlanDescription(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *config.Config)
+	}{
+		{"defaults", func(*config.Config) {}},
+		{"nothing enabled", func(cfg *config.Config) {
+			cfg.System.Keyboard = ""
+			cfg.System.Locale = ""
+		}},
+		{"swap and reboot", func(cfg *config.Config) {
+			cfg.Storage.SwapSizeMB = 4096
+			cfg.System.RebootAfterInstall = true
+		}},
+		{"all steps", func(cfg *config.Config) {
+			cfg.Storage.SwapSizeMB = 4096
+			cfg.System.EnableUnattendedUpgrades = true
+			cfg.System.AdminUser = "alice"
+			cfg.ACME.Enabled = true
+			cfg.System.RebootAfterInstall = true
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			tt.modify(cfg)
+
+			steps := PlanSteps(cfg, exec.NewMockExecutor(), nil)
+			descriptions := cfg.PostInstallPlanDescription()
+
+			require.Len(t, descriptions, len(steps), "steps %q", stepNames(steps))
+
+			for i, step := range steps {
+				assert.True(t, strings.HasPrefix(descriptions[i], describedAs(t, step)),
+					"step %d %q described as %q", i+1, step.Name(), descriptions[i])
+			}
+		})
+	}
+}
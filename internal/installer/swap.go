@@ -0,0 +1,93 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// swapZvol is the ZFS volume backing the swap device.
+const swapZvol = "rpool/swap"
+
+// swapDevice is the block device path of the swap zvol.
+const swapDevice = "/dev/zvol/" + swapZvol
+
+// SwapStep creates a swap zvol on the root pool and enables it.
+//
+// The zvol uses the properties recommended by OpenZFS for swap
+// (no caching of data, synchronous writes, no snapshots).
+// The step is a no-op when Storage.SwapSizeMB is 0.
+type SwapStep struct {
+	config   *config.Config
+	executor exec.Executor
+	logger   *Logger
+}
+
+// NewSwapStep creates a SwapStep for the given configuration.
+func NewSwapStep(cfg *config.Config, executor exec.Executor, logger *Logger) *SwapStep {
+	return &SwapStep{config: cfg, executor: executor, logger: logger}
+}
+
+// Name returns the step name.
+func (s *SwapStep) Name() string { return "Configure swap" }
+
+// Execute creates, formats and activates the swap zvol, then persists it in /etc/fstab.
+// The configured size is checked against detected memory before anything is created.
+// While planning (see PlanCommands), /proc/meminfo yields no output and the
+// memory bound is skipped.
+func (s *SwapStep) Execute(ctx context.Context) error {
+	sizeMB := s.config.Storage.SwapSizeMB
+	if sizeMB <= 0 {
+		s.logger.Log("Swap disabled, skipping")
+
+		return nil
+	}
+
+	memoryMB, err := DetectMemoryMB(ctx, s.executor)
+
+	switch {
+	case errors.Is(err, ErrMemoryNotDetected) && isPlanning(ctx):
+		memoryMB = 0
+	case err != nil:
+		return fmt.Errorf("failed to detect memory: %w", err)
+	}
+
+	if err := config.ValidateSwapSize(sizeMB, memoryMB); err != nil {
+		return fmt.Errorf("swap size %d MB with %d MB memory: %w", sizeMB, memoryMB, err)
+	}
+
+	s.logger.Log("Creating %d MB swap zvol %s", sizeMB, swapZvol)
+
+	if err := s.executor.Run(ctx, "zfs", "create",
+		"-V", strconv.Itoa(sizeMB)+"M",
+		"-b", "4096",
+		"-o", "compression=zle",
+		"-o", "logbias=throughput",
+		"-o", "sync=always",
+		"-o", "primarycache=metadata",
+		"-o", "secondarycache=none",
+		"-o", "com.sun:auto-snapshot=false",
+		swapZvol,
+	); err != nil {
+		return fmt.Errorf("failed to create swap zvol: %w", err)
+	}
+
+	if err := s.executor.Run(ctx, "mkswap", "-f", swapDevice); err != nil {
+		return fmt.Errorf("failed to format swap device: %w", err)
+	}
+
+	if err := s.executor.Run(ctx, "swapon", swapDevice); err != nil {
+		return fmt.Errorf("failed to enable swap: %w", err)
+	}
+
+	fstabLine := swapDevice + " none swap discard 0 0\n"
+	if err := s.executor.RunWithStdin(ctx, fstabLine, "tee", "-a", "/etc/fstab"); err != nil {
+		return fmt.Errorf("failed to persist swap in /etc/fstab: %w", err)
+	}
+
+	return nil
+}
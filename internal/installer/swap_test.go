@@ -0,0 +1,86 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+)
+
+func TestSwapStepName(t *testing.T) {
+	step, _ := newTestStep(NewSwapStep, newValidTestConfig())
+
+	assert.Equal(t, "Configure swap", step.Name())
+}
+
+func TestSwapStepDisabledRunsNothing(t *testing.T) {
+	step, mock := newTestStep(NewSwapStep, newValidTestConfig())
+
+	require.NoError(t, step.Execute(context.Background()))
+	assert.Zero(t, mock.CommandCount())
+}
+
+func TestSwapStepCreatesSwap(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.Storage.SwapSizeMB = 4096
+
+	step, mock := newTestStep(NewSwapStep, cfg)
+	mock.SetOutput(cmdCatMeminfo, testMeminfo)
+
+	require.NoError(t, step.Execute(context.Background()))
+
+	commands := mock.Commands()
+	require.Len(t, commands, 5)
+	assert.Equal(t, "zfs", commands[1].Name)
+	assert.Contains(t, commands[1].Args, "4096M")
+	assert.Equal(t, swapZvol, commands[1].Args[len(commands[1].Args)-1])
+	assert.True(t, mock.WasCalledWith("mkswap", "-f", swapDevice))
+	assert.True(t, mock.WasCalledWith("swapon", swapDevice))
+	assert.Equal(t, "tee", commands[4].Name)
+	assert.Contains(t, commands[4].Stdin, swapDevice+" none swap")
+}
+
+func TestSwapStepRejectsTooLargeForMemory(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.Storage.SwapSizeMB = 200000
+
+	step, mock := newTestStep(NewSwapStep, cfg)
+	mock.SetOutput(cmdCatMeminfo, testMeminfo)
+
+	err := step.Execute(context.Background())
+
+	require.ErrorIs(t, err, config.ErrSwapSizeTooLarge)
+	assert.Equal(t, 1, mock.CommandCount(), "only memory detection should run")
+}
+
+func TestSwapStepCommandFailure(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.Storage.SwapSizeMB = 4096
+
+	step, mock := newTestStep(NewSwapStep, cfg)
+	mock.SetOutput(cmdCatMeminfo, testMeminfo)
+	mock.SetError("mkswap -f "+swapDevice, errors.New("device busy"))
+
+	err := step.Execute(context.Background())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "format swap device")
+	assert.False(t, mock.WasCalledWith("swapon", swapDevice))
+}
+
+func TestSwapStepRequiresMemoryOutsidePlanning(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.Storage.SwapSizeMB = 4096
+
+	step, mock := newTestStep(NewSwapStep, cfg)
+	mock.SetOutput(cmdCatMeminfo, "")
+
+	err := step.Execute(context.Background())
+
+	require.ErrorIs(t, err, ErrMemoryNotDetected)
+	assert.Equal(t, 1, mock.CommandCount())
+}
@@ -0,0 +1,66 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/config"
+	"github.com/qoxi-cloud/proxmox-hetzner-go/internal/exec"
+)
+
+// aptEnv is the environment apt-get runs with, so package installation never
+// waits for debconf prompts on the unattended installer.
+var aptEnv = []string{"DEBIAN_FRONTEND=noninteractive"}
+
+// autoUpgradesPath is the APT configuration file that schedules unattended upgrades.
+const autoUpgradesPath = "/etc/apt/apt.conf.d/20auto-upgrades"
+
+// autoUpgradesConfig refreshes package lists and runs unattended-upgrade daily.
+// Which origins are upgraded (Debian security by default) is left to the
+// package's own 50unattended-upgrades configuration.
+const autoUpgradesConfig = `APT::Periodic::Update-Package-Lists "1";
+APT::Periodic::Unattended-Upgrade "1";
+`
+
+// UnattendedUpgradesStep installs unattended-upgrades and enables daily
+// automatic security updates.
+//
+// The step is a no-op when System.EnableUnattendedUpgrades is false.
+type UnattendedUpgradesStep struct {
+	config   *config.Config
+	executor exec.Executor
+	logger   *Logger
+}
+
+// NewUnattendedUpgradesStep creates an UnattendedUpgradesStep for the given configuration.
+func NewUnattendedUpgradesStep(cfg *config.Config, executor exec.Executor, logger *Logger) *UnattendedUpgradesStep {
+	return &UnattendedUpgradesStep{config: cfg, executor: executor, logger: logger}
+}
+
+// Name returns the step name.
+func (s *UnattendedUpgradesStep) Name() string { return "Configure unattended upgrades" }
+
+// Execute installs the package, writes the APT schedule and enables the service.
+func (s *UnattendedUpgradesStep) Execute(ctx context.Context) error {
+	if !s.config.System.EnableUnattendedUpgrades {
+		s.logger.Log("Unattended upgrades disabled, skipping")
+
+		return nil
+	}
+
+	s.logger.Log("Installing unattended-upgrades")
+
+	if err := s.executor.RunWithEnv(ctx, aptEnv, "apt-get", "install", "-y", "unattended-upgrades"); err != nil {
+		return fmt.Errorf("failed to install unattended-upgrades: %w", err)
+	}
+
+	if err := s.executor.RunWithStdin(ctx, autoUpgradesConfig, "tee", autoUpgradesPath); err != nil {
+		return fmt.Errorf("failed to write %s: %w", autoUpgradesPath, err)
+	}
+
+	if err := s.executor.Run(ctx, "systemctl", "enable", "--now", "unattended-upgrades"); err != nil {
+		return fmt.Errorf("failed to enable unattended-upgrades: %w", err)
+	}
+
+	return nil
+}
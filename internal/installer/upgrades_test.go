@@ -0,0 +1,54 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnattendedUpgradesStepName(t *testing.T) {
+	step, _ := newTestStep(NewUnattendedUpgradesStep, newValidTestConfig())
+
+	assert.Equal(t, "Configure unattended upgrades", step.Name())
+}
+
+func TestUnattendedUpgradesStepDisabledRunsNothing(t *testing.T) {
+	step, mock := newTestStep(NewUnattendedUpgradesStep, newValidTestConfig())
+
+	require.NoError(t, step.Execute(context.Background()))
+	assert.Zero(t, mock.CommandCount())
+}
+
+func TestUnattendedUpgradesStepRecordsCommands(t *testing.T) {
+	// MockExecutor records without executing, as a dry run would.
+	cfg := newValidTestConfig()
+	cfg.System.EnableUnattendedUpgrades = true
+
+	step, mock := newTestStep(NewUnattendedUpgradesStep, cfg)
+
+	require.NoError(t, step.Execute(context.Background()))
+
+	commands := mock.Commands()
+	require.Len(t, commands, 3)
+	assert.Equal(t, "apt-get install -y unattended-upgrades", commands[0].String())
+	assert.Equal(t, aptEnv, commands[0].Env)
+	assert.Equal(t, "tee "+autoUpgradesPath, commands[1].String())
+	assert.Contains(t, commands[1].Stdin, `APT::Periodic::Unattended-Upgrade "1";`)
+	assert.Equal(t, "systemctl enable --now unattended-upgrades", commands[2].String())
+}
+
+func TestUnattendedUpgradesStepInstallFailure(t *testing.T) {
+	cfg := newValidTestConfig()
+	cfg.System.EnableUnattendedUpgrades = true
+
+	step, mock := newTestStep(NewUnattendedUpgradesStep, cfg)
+	mock.SetError("apt-get install -y unattended-upgrades", errors.New("exit status 100"))
+
+	err := step.Execute(context.Background())
+
+	require.ErrorContains(t, err, "failed to install unattended-upgrades")
+	assert.Equal(t, 1, mock.CommandCount(), "no configuration after a failed install")
+}
@@ -0,0 +1,55 @@
+package testutil
+
+import (
+	"os"
+	"testing"
+)
+
+// SnapshotEnv records the current values of the named environment variables
+// and returns a function that restores them. Variables that were unset when
+// the snapshot was taken are unset again, and variables set to the empty
+// string are restored as set-but-empty.
+//
+// Prefer ClearEnv in tests; SnapshotEnv is for callers that manage the
+// restore themselves (e.g., TestMain).
+func SnapshotEnv(names ...string) (restore func()) {
+	type entry struct {
+		value string
+		set   bool
+	}
+
+	saved := make(map[string]entry, len(names))
+
+	for _, name := range names {
+		value, set := os.LookupEnv(name)
+		saved[name] = entry{value: value, set: set}
+	}
+
+	return func() {
+		for name, e := range saved {
+			if e.set {
+				os.Setenv(name, e.value) //nolint:errcheck,usetesting // best-effort restore
+			} else {
+				os.Unsetenv(name) //nolint:errcheck // best-effort restore
+			}
+		}
+	}
+}
+
+// ClearEnv unsets the named environment variables for the duration of the
+// test and restores their previous values when the test completes.
+//
+// Unlike t.Setenv(name, ""), the variables are truly unset, which matters
+// for code using os.LookupEnv. Like t.Setenv, it must not be used in
+// parallel tests.
+func ClearEnv(t *testing.T, names ...string) {
+	t.Helper()
+
+	t.Cleanup(SnapshotEnv(names...))
+
+	for _, name := range names {
+		if err := os.Unsetenv(name); err != nil {
+			t.Fatalf("failed to unset %s: %v", name, err)
+		}
+	}
+}
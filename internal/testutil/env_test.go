@@ -0,0 +1,66 @@
+package testutil
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Environment variables used by the env helper tests.
+const (
+	envSet   = "TESTUTIL_ENV_SET"
+	envEmpty = "TESTUTIL_ENV_EMPTY"
+	envUnset = "TESTUTIL_ENV_UNSET"
+)
+
+// assertEnv checks that name is set to value, or unset when set is false.
+func assertEnv(t *testing.T, name, value string, set bool) {
+	t.Helper()
+
+	got, ok := os.LookupEnv(name)
+	assert.Equal(t, set, ok, "%s set", name)
+	assert.Equal(t, value, got, "%s value", name)
+}
+
+func TestSnapshotEnvRestores(t *testing.T) {
+	t.Setenv(envSet, "original")
+	t.Setenv(envEmpty, "")
+	ClearEnv(t, envUnset)
+
+	restore := SnapshotEnv(envSet, envEmpty, envUnset)
+
+	require.NoError(t, os.Setenv(envSet, "changed"))   //nolint:usetesting // exercising restore
+	require.NoError(t, os.Unsetenv(envEmpty))          //nolint:usetesting // exercising restore
+	require.NoError(t, os.Setenv(envUnset, "created")) //nolint:usetesting // exercising restore
+
+	restore()
+
+	assertEnv(t, envSet, "original", true)
+	assertEnv(t, envEmpty, "", true)
+	assertEnv(t, envUnset, "", false)
+}
+
+func TestSnapshotEnvNoNames(t *testing.T) {
+	restore := SnapshotEnv()
+
+	assert.NotPanics(t, restore)
+}
+
+func TestClearEnv(t *testing.T) {
+	t.Setenv(envSet, "original")
+	t.Setenv(envEmpty, "")
+
+	t.Run("clears", func(t *testing.T) {
+		ClearEnv(t, envSet, envEmpty, envUnset)
+
+		assertEnv(t, envSet, "", false)
+		assertEnv(t, envEmpty, "", false)
+		assertEnv(t, envUnset, "", false)
+	})
+
+	assertEnv(t, envSet, "original", true)
+	assertEnv(t, envEmpty, "", true)
+	assertEnv(t, envUnset, "", false)
+}
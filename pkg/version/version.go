@@ -1,6 +1,8 @@
 // Package version provides build version information.
 package version
 
+import "runtime"
+
 // Build information set via ldflags.
 var (
 	// Version is the semantic version (set by goreleaser).
@@ -13,7 +15,36 @@ var (
 	Date = "unknown"
 )
 
+// Info holds build information in a machine-readable form.
+type Info struct {
+	// Version is the semantic version.
+	Version string `json:"version"`
+
+	// Commit is the git commit SHA.
+	Commit string `json:"commit"`
+
+	// Date is the build date.
+	Date string `json:"date"`
+
+	// GoVersion is the Go toolchain version used for the build.
+	GoVersion string `json:"go_version"`
+
+	// Full is the human-readable version string returned by Full.
+	Full string `json:"full"`
+}
+
 // Full returns the full version string.
 func Full() string {
 	return Version + " (" + Commit + ") built on " + Date
 }
+
+// Get returns the build information as an Info value.
+func Get() Info {
+	return Info{
+		Version:   Version,
+		Commit:    Commit,
+		Date:      Date,
+		GoVersion: runtime.Version(),
+		Full:      Full(),
+	}
+}